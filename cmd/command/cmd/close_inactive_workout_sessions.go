@@ -0,0 +1,28 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	_ "github.com/joho/godotenv/autoload"
+	"github.com/spf13/cobra"
+)
+
+// Run periodically, e.g. every 15 minutes from cron, so abandoned sessions still end up as workouts
+var closeInactiveWorkoutSessionsCmd = &cobra.Command{
+	Use:   "close_inactive_workout_sessions",
+	Short: "Close workout sessions without activity for longer than WORKOUT_SESSION_TIMEOUT",
+	Run: func(cmd *cobra.Command, args []string) {
+		closed, err := container.WorkoutSessionService.CloseInactiveSessions(context.Background())
+		if err != nil {
+			log.Fatalf("closed %d workout sessions before failing: %v", closed, err)
+		}
+
+		fmt.Printf("Closed %d inactive workout sessions\n", closed)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(closeInactiveWorkoutSessionsCmd)
+}
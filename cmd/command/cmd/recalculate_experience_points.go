@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+
+	_ "github.com/joho/godotenv/autoload"
+	"github.com/spf13/cobra"
+)
+
+// Run for a profile whose total looks wrong, or with --all after changing the level curve
+var recalculateExperiencePointsCmd = &cobra.Command{
+	Use:   "recalculate_experience_points [profile_id]",
+	Short: "Recalculate the experience points and level of a profile, or every profile, from the ledger",
+	Args:  cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		all, _ := cmd.Flags().GetBool("all")
+
+		if all {
+			recalculated, err := container.ExperiencePointService.RecalculateAll(context.Background())
+			if err != nil {
+				log.Fatalf("recalculated %d profiles before failing: %v", recalculated, err)
+			}
+
+			fmt.Printf("Recalculated the experience points of %d profiles\n", recalculated)
+			return
+		}
+
+		if len(args) == 0 {
+			log.Fatal("Either a profile_id or --all is required")
+		}
+
+		profileID, err := strconv.Atoi(args[0])
+		if err != nil {
+			log.Fatalf("profile_id must be an integer: %v", err)
+		}
+
+		points, level, err := container.ExperiencePointService.Recalculate(context.Background(), profileID)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		fmt.Printf("Profile %d has %d experience points, level %d\n", profileID, points, level)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(recalculateExperiencePointsCmd)
+
+	recalculateExperiencePointsCmd.Flags().Bool("all", false, "Recalculate every profile")
+}
@@ -4,6 +4,8 @@ Copyright © 2025 NAME HERE <EMAIL ADDRESS>
 package main
 
 import (
+	"log"
+
 	"github.com/brightside-dev/ronin-fitness-be/cmd/command/cmd"
 	"github.com/brightside-dev/ronin-fitness-be/database/client"
 	"github.com/brightside-dev/ronin-fitness-be/http"
@@ -15,7 +17,10 @@ func main() {
 	dbService := client.NewMySQL()
 
 	// Initialize the container
-	container := http.NewContainer(dbService)
+	container, err := http.NewContainer(dbService)
+	if err != nil {
+		log.Fatal(err)
+	}
 
 	// Pass the container to the root command
 	cmd.SetContainer(container)
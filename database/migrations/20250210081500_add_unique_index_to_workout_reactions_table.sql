@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+DELETE r1 FROM workout_reactions r1
+INNER JOIN workout_reactions r2
+    ON r1.workout_id = r2.workout_id
+    AND r1.profile_id = r2.profile_id
+    AND r1.reaction = r2.reaction
+    AND r1.id > r2.id;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE UNIQUE INDEX idx_workout_reactions_workout_profile_reaction ON workout_reactions (workout_id, profile_id, reaction);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX idx_workout_reactions_workout_profile_reaction ON workout_reactions;
+-- +goose StatementEnd
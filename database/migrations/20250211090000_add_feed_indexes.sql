@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE INDEX idx_workouts_profile_start_date ON workouts (profile_id, start_date, id);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX idx_profile_follows_follower_profile ON profile_follows (follower_profile_id, profile_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX idx_profile_follows_follower_profile ON profile_follows;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP INDEX idx_workouts_profile_start_date ON workouts;
+-- +goose StatementEnd
@@ -0,0 +1,66 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE profiles
+    ADD COLUMN one_rep_max_formula ENUM('epley', 'brzycki') NOT NULL DEFAULT 'epley' AFTER fitness_experience;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE sets
+    ADD COLUMN estimated_one_rep_max_kg DECIMAL(10, 2) NOT NULL DEFAULT 0 AFTER reps;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+-- Backfill existing sets with the default (Epley) estimate
+UPDATE sets
+SET estimated_one_rep_max_kg = CASE
+    WHEN reps <= 0 OR weight_kg <= 0 THEN 0
+    WHEN reps = 1 THEN weight_kg
+    ELSE ROUND(weight_kg * (30 + reps) / 30, 2)
+END;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TABLE personal_records (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    profile_id BIGINT UNSIGNED NOT NULL,
+    exercise_id BIGINT UNSIGNED NOT NULL,
+    workout_id BIGINT UNSIGNED NOT NULL,
+    set_id BIGINT UNSIGNED NOT NULL,
+    record_type ENUM('heaviest_weight', 'best_e1rm', 'most_reps', 'best_volume') NOT NULL,
+    weight_kg DECIMAL(10, 2) NOT NULL,
+    reps INT NOT NULL,
+    value DECIMAL(12, 2) NOT NULL,
+    previous_value DECIMAL(12, 2) NULL,
+    achieved_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_personal_records_profile FOREIGN KEY (profile_id) REFERENCES profiles(id) ON DELETE CASCADE,
+    CONSTRAINT fk_personal_records_exercise FOREIGN KEY (exercise_id) REFERENCES exercises(id) ON DELETE CASCADE,
+    CONSTRAINT fk_personal_records_workout FOREIGN KEY (workout_id) REFERENCES workouts(id) ON DELETE CASCADE,
+    CONSTRAINT fk_personal_records_set FOREIGN KEY (set_id) REFERENCES sets(id) ON DELETE CASCADE
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX idx_personal_records_profile_exercise ON personal_records (profile_id, exercise_id, record_type, achieved_at);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX idx_sets_exercise_workout ON sets (exercise_id, workout_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX idx_sets_exercise_workout ON sets;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TABLE personal_records;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE sets DROP COLUMN estimated_one_rep_max_kg;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE profiles DROP COLUMN one_rep_max_formula;
+-- +goose StatementEnd
@@ -0,0 +1,76 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE exercises
+    ADD COLUMN muscle_group VARCHAR(50) NOT NULL DEFAULT 'other' AFTER icon_name;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TABLE profile_daily_stats (
+    profile_id BIGINT UNSIGNED NOT NULL,
+    stat_date DATE NOT NULL,
+    workout_count INT NOT NULL DEFAULT 0,
+    total_duration_seconds INT NOT NULL DEFAULT 0,
+    total_volume_kg DECIMAL(14, 2) NOT NULL DEFAULT 0,
+    total_sets INT NOT NULL DEFAULT 0,
+    mental_energy_sum INT NOT NULL DEFAULT 0,
+    physical_energy_sum INT NOT NULL DEFAULT 0,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY (profile_id, stat_date),
+    CONSTRAINT fk_profile_daily_stats_profile FOREIGN KEY (profile_id) REFERENCES profiles(id) ON DELETE CASCADE
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TABLE profile_daily_muscle_group_stats (
+    profile_id BIGINT UNSIGNED NOT NULL,
+    stat_date DATE NOT NULL,
+    muscle_group VARCHAR(50) NOT NULL,
+    set_count INT NOT NULL DEFAULT 0,
+    PRIMARY KEY (profile_id, stat_date, muscle_group),
+    CONSTRAINT fk_profile_daily_muscle_group_stats_profile FOREIGN KEY (profile_id) REFERENCES profiles(id) ON DELETE CASCADE
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+-- Backfill the aggregates from the workouts logged so far
+INSERT INTO profile_daily_stats
+    (profile_id, stat_date, workout_count, total_duration_seconds, total_volume_kg, total_sets, mental_energy_sum, physical_energy_sum)
+SELECT
+    w.profile_id,
+    DATE(w.start_date),
+    COUNT(*),
+    SUM(TIMESTAMPDIFF(SECOND, w.start_date, w.end_date)),
+    SUM(COALESCE(ws.volume_kg, 0)),
+    SUM(COALESCE(ws.set_count, 0)),
+    SUM(w.mental_energy_level),
+    SUM(w.physical_energy_level)
+FROM workouts w
+LEFT JOIN (
+    SELECT workout_id, SUM(weight_kg * reps) AS volume_kg, COUNT(*) AS set_count
+    FROM sets
+    GROUP BY workout_id
+) ws ON ws.workout_id = w.id
+GROUP BY w.profile_id, DATE(w.start_date);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+INSERT INTO profile_daily_muscle_group_stats (profile_id, stat_date, muscle_group, set_count)
+SELECT w.profile_id, DATE(w.start_date), e.muscle_group, COUNT(*)
+FROM sets s
+INNER JOIN workouts w ON w.id = s.workout_id
+INNER JOIN exercises e ON e.id = s.exercise_id
+GROUP BY w.profile_id, DATE(w.start_date), e.muscle_group;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE profile_daily_muscle_group_stats;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TABLE profile_daily_stats;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE exercises DROP COLUMN muscle_group;
+-- +goose StatementEnd
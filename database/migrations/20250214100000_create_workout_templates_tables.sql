@@ -0,0 +1,47 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE workout_templates (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    profile_id BIGINT UNSIGNED NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    description TEXT NULL,
+    share_token VARCHAR(64) NULL UNIQUE,
+    source_template_id BIGINT UNSIGNED NULL, -- Template this one was copied from
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    CONSTRAINT fk_workout_templates_profile FOREIGN KEY (profile_id) REFERENCES profiles(id) ON DELETE CASCADE,
+    CONSTRAINT fk_workout_templates_source FOREIGN KEY (source_template_id) REFERENCES workout_templates(id) ON DELETE SET NULL
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX idx_workout_templates_profile_id ON workout_templates (profile_id);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TABLE workout_template_sets (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    template_id BIGINT UNSIGNED NOT NULL,
+    exercise_id BIGINT UNSIGNED NOT NULL,
+    exercise_order INT NOT NULL,
+    set_number INT NOT NULL,
+    target_reps INT NOT NULL DEFAULT 0,
+    target_weight_kg DECIMAL(10, 2) NOT NULL DEFAULT 0,
+    target_duration INT NOT NULL DEFAULT 0,
+    CONSTRAINT fk_workout_template_sets_template FOREIGN KEY (template_id) REFERENCES workout_templates(id) ON DELETE CASCADE,
+    CONSTRAINT fk_workout_template_sets_exercise FOREIGN KEY (exercise_id) REFERENCES exercises(id) ON DELETE CASCADE
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX idx_workout_template_sets_template_id ON workout_template_sets (template_id, exercise_order, set_number);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE workout_template_sets;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TABLE workout_templates;
+-- +goose StatementEnd
@@ -0,0 +1,111 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE profiles ADD COLUMN role ENUM('member', 'coach', 'admin') NOT NULL DEFAULT 'member' AFTER privacy;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TABLE programs (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    profile_id BIGINT UNSIGNED NOT NULL, -- Author
+    name VARCHAR(255) NOT NULL,
+    description TEXT NULL,
+    duration_weeks INT NOT NULL,
+    status ENUM('draft', 'published') NOT NULL DEFAULT 'draft',
+    published_at TIMESTAMP NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    CONSTRAINT fk_programs_profile FOREIGN KEY (profile_id) REFERENCES profiles(id) ON DELETE CASCADE
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX idx_programs_status_published_at ON programs (status, published_at);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX idx_programs_profile_id ON programs (profile_id);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TABLE program_days (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    program_id BIGINT UNSIGNED NOT NULL,
+    week_number INT NOT NULL,
+    day_number INT NOT NULL,
+    template_id BIGINT UNSIGNED NOT NULL,
+    CONSTRAINT fk_program_days_program FOREIGN KEY (program_id) REFERENCES programs(id) ON DELETE CASCADE,
+    CONSTRAINT fk_program_days_template FOREIGN KEY (template_id) REFERENCES workout_templates(id) ON DELETE RESTRICT,
+    UNIQUE KEY idx_program_days_program_week_day (program_id, week_number, day_number)
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TABLE program_progression_rules (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    program_id BIGINT UNSIGNED NOT NULL,
+    exercise_id BIGINT UNSIGNED NOT NULL,
+    rule_type ENUM('linear', 'percent_of_training_max') NOT NULL,
+    increment_kg DECIMAL(10, 2) NOT NULL DEFAULT 0, -- Per successful session (linear) or per completed cycle (percent)
+    training_max_percent DECIMAL(5, 2) NOT NULL DEFAULT 90,
+    week_percentages JSON NULL, -- Per week of the cycle, the percent of training max for each set
+    CONSTRAINT fk_program_progression_rules_program FOREIGN KEY (program_id) REFERENCES programs(id) ON DELETE CASCADE,
+    CONSTRAINT fk_program_progression_rules_exercise FOREIGN KEY (exercise_id) REFERENCES exercises(id) ON DELETE CASCADE,
+    UNIQUE KEY idx_program_progression_rules_program_exercise (program_id, exercise_id)
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TABLE program_enrollments (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    profile_id BIGINT UNSIGNED NOT NULL,
+    program_id BIGINT UNSIGNED NOT NULL,
+    status ENUM('active', 'paused', 'completed') NOT NULL DEFAULT 'active',
+    current_session INT NOT NULL DEFAULT 0, -- Index into the program days ordered by week and day
+    started_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    paused_at TIMESTAMP NULL,
+    completed_at TIMESTAMP NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    CONSTRAINT fk_program_enrollments_profile FOREIGN KEY (profile_id) REFERENCES profiles(id) ON DELETE CASCADE,
+    CONSTRAINT fk_program_enrollments_program FOREIGN KEY (program_id) REFERENCES programs(id) ON DELETE CASCADE,
+    UNIQUE KEY idx_program_enrollments_profile_program (profile_id, program_id)
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TABLE program_enrollment_sessions (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    enrollment_id BIGINT UNSIGNED NOT NULL,
+    program_day_id BIGINT UNSIGNED NOT NULL,
+    workout_id BIGINT UNSIGNED NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_program_enrollment_sessions_enrollment FOREIGN KEY (enrollment_id) REFERENCES program_enrollments(id) ON DELETE CASCADE,
+    CONSTRAINT fk_program_enrollment_sessions_day FOREIGN KEY (program_day_id) REFERENCES program_days(id) ON DELETE CASCADE,
+    CONSTRAINT fk_program_enrollment_sessions_workout FOREIGN KEY (workout_id) REFERENCES workouts(id) ON DELETE CASCADE
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE program_enrollment_sessions;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TABLE program_enrollments;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TABLE program_progression_rules;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TABLE program_days;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TABLE programs;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE profiles DROP COLUMN role;
+-- +goose StatementEnd
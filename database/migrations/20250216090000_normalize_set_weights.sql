@@ -0,0 +1,178 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE profiles
+    ADD COLUMN weight_unit ENUM('kg', 'lb') NOT NULL DEFAULT 'kg' AFTER one_rep_max_formula,
+    ADD COLUMN weight_increment DECIMAL(6, 2) NOT NULL DEFAULT 2.5 AFTER weight_unit; -- In weight_unit
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+-- Keep enough precision for weights entered in pounds to convert back exactly
+ALTER TABLE sets MODIFY weight_kg DECIMAL(12, 4) NOT NULL;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE personal_records
+    MODIFY weight_kg DECIMAL(12, 4) NOT NULL,
+    MODIFY value DECIMAL(14, 4) NOT NULL,
+    MODIFY previous_value DECIMAL(14, 4) NULL;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE workout_template_sets MODIFY target_weight_kg DECIMAL(12, 4) NOT NULL DEFAULT 0;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE program_progression_rules MODIFY increment_kg DECIMAL(10, 4) NOT NULL DEFAULT 0;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TABLE set_weight_repairs (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    set_id BIGINT UNSIGNED NOT NULL,
+    reason ENUM('missing_kg', 'missing_lb', 'swapped', 'kg_mismatch') NOT NULL,
+    old_weight_kg DECIMAL(12, 4) NOT NULL,
+    old_weight_lb DECIMAL(10, 2) NOT NULL,
+    old_estimated_one_rep_max_kg DECIMAL(10, 2) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_set_weight_repairs_set FOREIGN KEY (set_id) REFERENCES sets(id) ON DELETE CASCADE
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+-- Record every pair whose pound value does not match the kilogram value, with a best guess at what went wrong:
+-- only one side filled in, the two values written to the wrong columns, or otherwise trust the kilograms
+INSERT INTO set_weight_repairs (set_id, reason, old_weight_kg, old_weight_lb, old_estimated_one_rep_max_kg)
+SELECT
+    id,
+    CASE
+        WHEN weight_kg = 0 THEN 'missing_kg'
+        WHEN weight_lb = 0 THEN 'missing_lb'
+        WHEN ABS(weight_kg - weight_lb / 0.45359237) <= 0.05 THEN 'swapped'
+        ELSE 'kg_mismatch'
+    END,
+    weight_kg,
+    weight_lb,
+    estimated_one_rep_max_kg
+FROM sets
+WHERE ABS(weight_lb - ROUND(weight_kg / 0.45359237, 2)) > 0.05;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+-- A swapped pair already holds the kilograms in the pound column, only a missing one needs converting.
+-- Manual check: a 100 kg set stored as weight_kg = 220.4623, weight_lb = 100.00 is recorded as
+-- 'swapped' and ends up as weight_kg = 100.0000, weight_lb = 220.46; a set stored as weight_kg = 0,
+-- weight_lb = 225.00 is 'missing_kg' and ends up as weight_kg = 102.0583, weight_lb = 225.00.
+UPDATE sets s
+INNER JOIN set_weight_repairs swr ON swr.set_id = s.id
+SET s.weight_kg = CASE
+    WHEN swr.reason = 'swapped' THEN swr.old_weight_lb
+    ELSE ROUND(swr.old_weight_lb * 0.45359237, 4)
+END
+WHERE swr.reason IN ('missing_kg', 'swapped');
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+UPDATE sets s
+INNER JOIN set_weight_repairs swr ON swr.set_id = s.id
+SET s.weight_lb = ROUND(s.weight_kg / 0.45359237, 2);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+-- Repaired kilograms change the estimated one-rep max of those sets
+UPDATE sets s
+INNER JOIN set_weight_repairs swr ON swr.set_id = s.id
+INNER JOIN workouts w ON w.id = s.workout_id
+INNER JOIN profiles p ON p.id = w.profile_id
+SET s.estimated_one_rep_max_kg = CASE
+    WHEN s.reps <= 0 OR s.weight_kg <= 0 THEN 0
+    WHEN s.reps = 1 THEN ROUND(s.weight_kg, 2)
+    WHEN p.one_rep_max_formula = 'brzycki' AND s.reps < 37 THEN ROUND(s.weight_kg * 36 / (37 - s.reps), 2)
+    ELSE ROUND(s.weight_kg * (30 + s.reps) / 30, 2)
+END
+WHERE swr.reason IN ('missing_kg', 'swapped');
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TABLE profile_daily_stat_repairs (
+    profile_id BIGINT UNSIGNED NOT NULL,
+    stat_date DATE NOT NULL,
+    old_total_volume_kg DECIMAL(14, 2) NOT NULL,
+    PRIMARY KEY (profile_id, stat_date)
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+-- Keep the volume of the affected days so that the Down can put it back
+INSERT INTO profile_daily_stat_repairs (profile_id, stat_date, old_total_volume_kg)
+SELECT pds.profile_id, pds.stat_date, pds.total_volume_kg
+FROM profile_daily_stats pds
+WHERE (pds.profile_id, pds.stat_date) IN (
+    SELECT w.profile_id, DATE(w.start_date)
+    FROM set_weight_repairs swr
+    INNER JOIN sets s ON s.id = swr.set_id
+    INNER JOIN workouts w ON w.id = s.workout_id
+    WHERE swr.reason IN ('missing_kg', 'swapped')
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+-- Rebuild the daily volume of the affected days from the repaired sets
+UPDATE profile_daily_stats pds
+INNER JOIN (
+    SELECT w.profile_id, DATE(w.start_date) AS stat_date, SUM(s.weight_kg * s.reps) AS volume_kg
+    FROM sets s
+    INNER JOIN workouts w ON w.id = s.workout_id
+    WHERE (w.profile_id, DATE(w.start_date)) IN (
+        SELECT profile_id, stat_date FROM profile_daily_stat_repairs
+    )
+    GROUP BY w.profile_id, DATE(w.start_date)
+) dv ON dv.profile_id = pds.profile_id AND dv.stat_date = pds.stat_date
+SET pds.total_volume_kg = dv.volume_kg;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+UPDATE profile_daily_stats pds
+INNER JOIN profile_daily_stat_repairs pdsr ON pdsr.profile_id = pds.profile_id AND pdsr.stat_date = pds.stat_date
+SET pds.total_volume_kg = pdsr.old_total_volume_kg;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TABLE profile_daily_stat_repairs;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+UPDATE sets s
+INNER JOIN set_weight_repairs swr ON swr.set_id = s.id
+SET
+    s.weight_kg = swr.old_weight_kg,
+    s.weight_lb = swr.old_weight_lb,
+    s.estimated_one_rep_max_kg = swr.old_estimated_one_rep_max_kg;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TABLE set_weight_repairs;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE program_progression_rules MODIFY increment_kg DECIMAL(10, 2) NOT NULL DEFAULT 0;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE workout_template_sets MODIFY target_weight_kg DECIMAL(10, 2) NOT NULL DEFAULT 0;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE personal_records
+    MODIFY weight_kg DECIMAL(10, 2) NOT NULL,
+    MODIFY value DECIMAL(12, 2) NOT NULL,
+    MODIFY previous_value DECIMAL(12, 2) NULL;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE sets MODIFY weight_kg DECIMAL(10, 2) NOT NULL;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE profiles DROP COLUMN weight_increment, DROP COLUMN weight_unit;
+-- +goose StatementEnd
@@ -0,0 +1,25 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE profiles ADD COLUMN bar_weight_kg DECIMAL(10, 4) NOT NULL DEFAULT 20 AFTER weight_increment;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TABLE profile_plates (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    profile_id BIGINT UNSIGNED NOT NULL,
+    weight_kg DECIMAL(10, 4) NOT NULL,
+    pair_count INT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_profile_plates_profile FOREIGN KEY (profile_id) REFERENCES profiles(id) ON DELETE CASCADE,
+    UNIQUE KEY idx_profile_plates_profile_weight (profile_id, weight_kg)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE profile_plates;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE profiles DROP COLUMN bar_weight_kg;
+-- +goose StatementEnd
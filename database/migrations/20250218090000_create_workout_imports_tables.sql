@@ -0,0 +1,60 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE workouts ADD COLUMN import_key CHAR(64) NULL AFTER end_date;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE UNIQUE INDEX idx_workouts_profile_import_key ON workouts (profile_id, import_key);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TABLE workout_imports (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    profile_id BIGINT UNSIGNED NOT NULL,
+    source ENUM('strong', 'hevy') NOT NULL,
+    status ENUM('processing', 'completed', 'failed') NOT NULL DEFAULT 'processing',
+    file_name VARCHAR(255) NOT NULL,
+    file_hash CHAR(64) NOT NULL,
+    weight_unit ENUM('kg', 'lb') NOT NULL,
+    total_rows INT NOT NULL DEFAULT 0,
+    imported_workouts INT NOT NULL DEFAULT 0,
+    imported_sets INT NOT NULL DEFAULT 0,
+    skipped_workouts INT NOT NULL DEFAULT 0,
+    created_exercises INT NOT NULL DEFAULT 0,
+    error_count INT NOT NULL DEFAULT 0,
+    failure_reason VARCHAR(255) NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    completed_at TIMESTAMP NULL,
+    CONSTRAINT fk_workout_imports_profile FOREIGN KEY (profile_id) REFERENCES profiles(id) ON DELETE CASCADE,
+    INDEX idx_workout_imports_profile_created (profile_id, created_at, id)
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TABLE workout_import_errors (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    import_id BIGINT UNSIGNED NOT NULL,
+    line_number INT NOT NULL,
+    message VARCHAR(255) NOT NULL,
+    raw_row TEXT NOT NULL,
+    CONSTRAINT fk_workout_import_errors_import FOREIGN KEY (import_id) REFERENCES workout_imports(id) ON DELETE CASCADE,
+    INDEX idx_workout_import_errors_import_line (import_id, line_number)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE workout_import_errors;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TABLE workout_imports;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP INDEX idx_workouts_profile_import_key ON workouts;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE workouts DROP COLUMN import_key;
+-- +goose StatementEnd
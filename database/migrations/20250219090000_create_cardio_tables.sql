@@ -0,0 +1,40 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE cardio_sessions (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    workout_id BIGINT UNSIGNED NOT NULL,
+    activity_type ENUM('run', 'ride', 'walk', 'hike', 'swim', 'row', 'other') NOT NULL,
+    source_format ENUM('fit', 'tcx', 'gpx') NOT NULL,
+    distance_m DECIMAL(10, 2) NOT NULL DEFAULT 0,
+    duration_seconds INT UNSIGNED NOT NULL,
+    pace_seconds_per_km DECIMAL(8, 2) NULL, -- NULL when no distance was recorded
+    elevation_gain_m DECIMAL(8, 2) NULL,
+    elevation_loss_m DECIMAL(8, 2) NULL,
+    avg_heart_rate SMALLINT UNSIGNED NULL,
+    max_heart_rate SMALLINT UNSIGNED NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    CONSTRAINT fk_cardio_sessions_workout FOREIGN KEY (workout_id) REFERENCES workouts(id) ON DELETE CASCADE,
+    UNIQUE KEY idx_cardio_sessions_workout (workout_id)
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+-- Simplified GPS tracks as [lon, lat, elevation] positions, kept apart so summaries stay small
+CREATE TABLE cardio_tracks (
+    cardio_session_id BIGINT UNSIGNED NOT NULL PRIMARY KEY,
+    coordinates JSON NOT NULL,
+    original_point_count INT UNSIGNED NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_cardio_tracks_session FOREIGN KEY (cardio_session_id) REFERENCES cardio_sessions(id) ON DELETE CASCADE
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE cardio_tracks;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TABLE cardio_sessions;
+-- +goose StatementEnd
@@ -0,0 +1,57 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE workout_sessions (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    profile_id BIGINT UNSIGNED NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    description TEXT NULL,
+    program_enrollment_id BIGINT UNSIGNED NULL, -- completes the enrollment's current session on finish
+    status ENUM('active', 'paused', 'finished', 'abandoned') NOT NULL DEFAULT 'active',
+    started_at TIMESTAMP NOT NULL,
+    paused_at TIMESTAMP NULL,
+    paused_seconds INT UNSIGNED NOT NULL DEFAULT 0,
+    last_activity_at TIMESTAMP NOT NULL,
+    finished_at TIMESTAMP NULL,
+    workout_id BIGINT UNSIGNED NULL,
+    -- Only set while the session is open, so the unique index allows one open session per profile
+    open_profile_id BIGINT UNSIGNED AS (IF(status IN ('active', 'paused'), profile_id, NULL)) STORED,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    CONSTRAINT fk_workout_sessions_profile FOREIGN KEY (profile_id) REFERENCES profiles(id) ON DELETE CASCADE,
+    CONSTRAINT fk_workout_sessions_workout FOREIGN KEY (workout_id) REFERENCES workouts(id) ON DELETE SET NULL,
+    UNIQUE KEY idx_workout_sessions_open_profile (open_profile_id)
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX idx_workout_sessions_status_activity ON workout_sessions (status, last_activity_at);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TABLE workout_session_sets (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    session_id BIGINT UNSIGNED NOT NULL,
+    exercise_id BIGINT UNSIGNED NOT NULL,
+    set_number INT NOT NULL,
+    duration INT NOT NULL DEFAULT 0,
+    weight_kg DECIMAL(12, 4) NOT NULL DEFAULT 0,
+    reps INT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    CONSTRAINT fk_workout_session_sets_session FOREIGN KEY (session_id) REFERENCES workout_sessions(id) ON DELETE CASCADE,
+    CONSTRAINT fk_workout_session_sets_exercise FOREIGN KEY (exercise_id) REFERENCES exercises(id) ON DELETE CASCADE
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX idx_workout_session_sets_session ON workout_session_sets (session_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE workout_session_sets;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TABLE workout_sessions;
+-- +goose StatementEnd
@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE workouts
+    ADD COLUMN visibility ENUM('public', 'followers', 'private') NOT NULL DEFAULT 'public' AFTER description;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+-- Existing workouts keep the audience they had through their owner's profile privacy
+UPDATE workouts w
+INNER JOIN profiles p ON p.id = w.profile_id
+SET w.visibility = 'followers'
+WHERE p.privacy = 'private';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE workouts DROP COLUMN visibility;
+-- +goose StatementEnd
@@ -0,0 +1,34 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE body_measurements (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    profile_id BIGINT UNSIGNED NOT NULL,
+    measured_at DATETIME NOT NULL,
+    weight_kg DECIMAL(12, 4) NULL,
+    weight_lb DECIMAL(10, 2) NULL,
+    body_fat_percent DECIMAL(5, 2) NULL,
+    neck_cm DECIMAL(10, 4) NULL,
+    shoulders_cm DECIMAL(10, 4) NULL,
+    chest_cm DECIMAL(10, 4) NULL,
+    waist_cm DECIMAL(10, 4) NULL,
+    hips_cm DECIMAL(10, 4) NULL,
+    left_arm_cm DECIMAL(10, 4) NULL,
+    right_arm_cm DECIMAL(10, 4) NULL,
+    left_thigh_cm DECIMAL(10, 4) NULL,
+    right_thigh_cm DECIMAL(10, 4) NULL,
+    left_calf_cm DECIMAL(10, 4) NULL,
+    right_calf_cm DECIMAL(10, 4) NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    CONSTRAINT fk_body_measurements_profile FOREIGN KEY (profile_id) REFERENCES profiles(id) ON DELETE CASCADE
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX idx_body_measurements_profile_measured ON body_measurements (profile_id, measured_at, id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE body_measurements;
+-- +goose StatementEnd
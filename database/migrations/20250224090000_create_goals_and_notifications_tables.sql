@@ -0,0 +1,52 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE goals (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    profile_id BIGINT UNSIGNED NOT NULL,
+    type ENUM('lift_weight', 'lift_e1rm', 'workouts_per_week', 'bodyweight') NOT NULL,
+    exercise_id BIGINT UNSIGNED NULL,
+    target_value DECIMAL(12, 4) NOT NULL,
+    target_weeks INT UNSIGNED NOT NULL DEFAULT 1,
+    start_value DECIMAL(12, 4) NULL,
+    current_value DECIMAL(12, 4) NOT NULL DEFAULT 0,
+    progress DECIMAL(5, 2) NOT NULL DEFAULT 0,
+    deadline DATE NULL,
+    status ENUM('active', 'completed', 'expired') NOT NULL DEFAULT 'active',
+    completed_at DATETIME NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    CONSTRAINT fk_goals_profile FOREIGN KEY (profile_id) REFERENCES profiles(id) ON DELETE CASCADE,
+    CONSTRAINT fk_goals_exercise FOREIGN KEY (exercise_id) REFERENCES exercises(id) ON DELETE CASCADE
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX idx_goals_profile_status ON goals (profile_id, status);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TABLE notifications (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    profile_id BIGINT UNSIGNED NOT NULL,
+    type VARCHAR(50) NOT NULL,
+    title VARCHAR(255) NOT NULL,
+    body VARCHAR(1000) NOT NULL,
+    data JSON NULL,
+    read_at DATETIME NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_notifications_profile FOREIGN KEY (profile_id) REFERENCES profiles(id) ON DELETE CASCADE
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX idx_notifications_profile_created ON notifications (profile_id, created_at, id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE notifications;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TABLE goals;
+-- +goose StatementEnd
@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE calendar_feeds (
+    profile_id BIGINT UNSIGNED NOT NULL PRIMARY KEY,
+    token VARCHAR(64) NOT NULL UNIQUE,
+    -- version fingerprints the feed's content, changed_at is when it last changed
+    version VARCHAR(64) NULL,
+    changed_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_calendar_feeds_profile FOREIGN KEY (profile_id) REFERENCES profiles(id) ON DELETE CASCADE
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE calendar_feeds;
+-- +goose StatementEnd
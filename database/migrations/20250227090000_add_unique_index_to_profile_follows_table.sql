@@ -0,0 +1,34 @@
+-- +goose Up
+-- +goose StatementBegin
+DELETE f1 FROM profile_follows f1
+INNER JOIN profile_follows f2
+    ON f1.profile_id = f2.profile_id
+    AND f1.follower_profile_id = f2.follower_profile_id
+    AND f1.id > f2.id;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DELETE FROM profile_follows WHERE profile_id = follower_profile_id;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE UNIQUE INDEX idx_profile_follows_profile_follower ON profile_follows (profile_id, follower_profile_id);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+-- Follower lists are paged by follow time
+UPDATE profile_follows SET created_at = CURRENT_TIMESTAMP WHERE created_at IS NULL;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE profile_follows MODIFY created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE profile_follows MODIFY created_at TIMESTAMP NULL DEFAULT CURRENT_TIMESTAMP;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP INDEX idx_profile_follows_profile_follower ON profile_follows;
+-- +goose StatementEnd
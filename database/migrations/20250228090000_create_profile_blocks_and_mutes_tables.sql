@@ -0,0 +1,51 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE profile_blocks (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    profile_id BIGINT UNSIGNED NOT NULL,
+    blocked_profile_id BIGINT UNSIGNED NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE KEY uq_profile_blocks_profile_blocked (profile_id, blocked_profile_id),
+    CONSTRAINT fk_profile_blocks_profile FOREIGN KEY (profile_id) REFERENCES profiles(id) ON DELETE CASCADE,
+    CONSTRAINT fk_profile_blocks_blocked FOREIGN KEY (blocked_profile_id) REFERENCES profiles(id) ON DELETE CASCADE
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+-- Blocks hide profiles in both directions, so they are also looked up by the blocked profile
+CREATE INDEX idx_profile_blocks_blocked_profile ON profile_blocks (blocked_profile_id, profile_id);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TABLE profile_mutes (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    profile_id BIGINT UNSIGNED NOT NULL,
+    muted_profile_id BIGINT UNSIGNED NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE KEY uq_profile_mutes_profile_muted (profile_id, muted_profile_id),
+    CONSTRAINT fk_profile_mutes_profile FOREIGN KEY (profile_id) REFERENCES profiles(id) ON DELETE CASCADE,
+    CONSTRAINT fk_profile_mutes_muted FOREIGN KEY (muted_profile_id) REFERENCES profiles(id) ON DELETE CASCADE
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+-- The profile whose action caused the notification, hidden from recipients who mute or block it
+ALTER TABLE notifications
+    ADD COLUMN actor_profile_id BIGINT UNSIGNED NULL AFTER profile_id,
+    ADD CONSTRAINT fk_notifications_actor_profile FOREIGN KEY (actor_profile_id) REFERENCES profiles(id) ON DELETE CASCADE;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE notifications
+    DROP FOREIGN KEY fk_notifications_actor_profile,
+    DROP COLUMN actor_profile_id;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TABLE profile_mutes;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TABLE profile_blocks;
+-- +goose StatementEnd
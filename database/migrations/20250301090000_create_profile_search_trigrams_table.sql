@@ -0,0 +1,33 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Trigrams of each lowercased display name, padded with two spaces in front and one behind,
+-- for typo tolerant profile search. Kept in sync by the profile repository.
+CREATE TABLE profile_search_trigrams (
+    trigram VARCHAR(3) NOT NULL,
+    profile_id BIGINT UNSIGNED NOT NULL,
+    PRIMARY KEY (trigram, profile_id),
+    CONSTRAINT fk_profile_search_trigrams_profile FOREIGN KEY (profile_id) REFERENCES profiles(id) ON DELETE CASCADE
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX idx_profile_search_trigrams_profile ON profile_search_trigrams (profile_id);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+INSERT IGNORE INTO profile_search_trigrams (trigram, profile_id)
+WITH RECURSIVE positions (n) AS (
+    SELECT 1
+    UNION ALL
+    SELECT n + 1 FROM positions WHERE n < 256
+)
+SELECT SUBSTRING(CONCAT('  ', LOWER(TRIM(p.display_name)), ' '), positions.n, 3), p.id
+FROM profiles p
+INNER JOIN positions ON positions.n <= CHAR_LENGTH(TRIM(p.display_name)) + 1
+WHERE TRIM(p.display_name) != '';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE profile_search_trigrams;
+-- +goose StatementEnd
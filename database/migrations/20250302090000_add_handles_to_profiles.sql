@@ -0,0 +1,48 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Handles are stored lowercased. Profiles start with a generated athlete<id> handle that they can
+-- change, handle_changed_at is only set by such a change.
+ALTER TABLE profiles
+    ADD COLUMN handle VARCHAR(30) NULL AFTER display_name,
+    ADD COLUMN handle_changed_at TIMESTAMP NULL AFTER handle;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+UPDATE profiles SET handle = CONCAT('athlete', id);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE profiles
+    MODIFY COLUMN handle VARCHAR(30) NOT NULL,
+    ADD UNIQUE KEY uq_profiles_handle (handle);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+-- Handles a profile changed away from. They keep pointing at the profile and cannot be taken by
+-- anyone else until expires_at.
+CREATE TABLE profile_handle_history (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    profile_id BIGINT UNSIGNED NOT NULL,
+    handle VARCHAR(30) NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE KEY uq_profile_handle_history_handle (handle),
+    CONSTRAINT fk_profile_handle_history_profile FOREIGN KEY (profile_id) REFERENCES profiles(id) ON DELETE CASCADE
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX idx_profile_handle_history_profile ON profile_handle_history (profile_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE profile_handle_history;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE profiles
+    DROP INDEX uq_profiles_handle,
+    DROP COLUMN handle_changed_at,
+    DROP COLUMN handle;
+-- +goose StatementEnd
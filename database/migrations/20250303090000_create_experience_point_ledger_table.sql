@@ -0,0 +1,32 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Every experience point award, so points earned within a window can be summed. profiles.experience_points
+-- stays the running total.
+CREATE TABLE experience_point_ledger (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    profile_id BIGINT UNSIGNED NOT NULL,
+    points INT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_experience_point_ledger_profile FOREIGN KEY (profile_id) REFERENCES profiles(id) ON DELETE CASCADE
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+-- Weekly and monthly leaderboards sum the points of a window across profiles
+CREATE INDEX idx_experience_point_ledger_created_profile ON experience_point_ledger (created_at, profile_id, points);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX idx_experience_point_ledger_profile_created ON experience_point_ledger (profile_id, created_at);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+-- Points awarded before the ledger existed, dated when the profile was created so they only count all-time
+INSERT INTO experience_point_ledger (profile_id, points, created_at)
+SELECT id, experience_points, created_at FROM profiles WHERE experience_points != 0;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE experience_point_ledger;
+-- +goose StatementEnd
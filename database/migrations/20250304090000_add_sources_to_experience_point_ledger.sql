@@ -0,0 +1,44 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Each award records the event that earned it. The source identifies that event, e.g. "workout:42",
+-- and is unique per profile so replaying an event never awards its points twice.
+ALTER TABLE experience_point_ledger
+    ADD COLUMN event_type VARCHAR(50) NOT NULL DEFAULT 'legacy' AFTER profile_id,
+    ADD COLUMN source VARCHAR(100) NULL AFTER event_type,
+    ADD COLUMN reason VARCHAR(255) NOT NULL DEFAULT '' AFTER source;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+UPDATE experience_point_ledger
+SET source = CONCAT('legacy:', id), reason = 'Points earned before awards recorded their source';
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE experience_point_ledger
+    MODIFY COLUMN source VARCHAR(100) NOT NULL,
+    ALTER COLUMN event_type DROP DEFAULT,
+    ALTER COLUMN reason DROP DEFAULT;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE UNIQUE INDEX uq_experience_point_ledger_profile_source ON experience_point_ledger (profile_id, source);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+-- Derived from experience_points by the level curve. Run "recalculate_experience_points --all" after
+-- deploying, and whenever XP_LEVEL_BASE or XP_LEVEL_GROWTH change, to bring existing profiles in line.
+ALTER TABLE profiles ADD COLUMN level INT NOT NULL DEFAULT 1 AFTER experience_points;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE profiles DROP COLUMN level;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP INDEX uq_experience_point_ledger_profile_source ON experience_point_ledger;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TABLE experience_point_ledger DROP COLUMN event_type, DROP COLUMN source, DROP COLUMN reason;
+-- +goose StatementEnd
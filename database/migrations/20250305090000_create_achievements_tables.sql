@@ -0,0 +1,57 @@
+-- +goose Up
+-- +goose StatementBegin
+-- The achievement catalogue. Each achievement unlocks once the profile's metric reaches the threshold,
+-- so new achievements are added as rows. A threshold of lift_weight_kg is in kg and applies to the
+-- exercise named exercise_name, matched case-insensitively.
+CREATE TABLE achievements (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    code VARCHAR(50) NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    description VARCHAR(255) NOT NULL,
+    metric ENUM('workout_count', 'personal_record_count', 'streak_days', 'lift_weight_kg', 'follower_count', 'following_count') NOT NULL,
+    threshold DECIMAL(12, 4) NOT NULL,
+    exercise_name VARCHAR(100) NULL,
+    sort_order INT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT uq_achievements_code UNIQUE (code)
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TABLE profile_achievements (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    profile_id BIGINT UNSIGNED NOT NULL,
+    achievement_id BIGINT UNSIGNED NOT NULL,
+    unlocked_at DATETIME NOT NULL,
+    CONSTRAINT uq_profile_achievements_profile_achievement UNIQUE (profile_id, achievement_id),
+    CONSTRAINT fk_profile_achievements_profile FOREIGN KEY (profile_id) REFERENCES profiles(id) ON DELETE CASCADE,
+    CONSTRAINT fk_profile_achievements_achievement FOREIGN KEY (achievement_id) REFERENCES achievements(id) ON DELETE CASCADE
+);
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+INSERT INTO achievements (code, name, description, metric, threshold, exercise_name, sort_order) VALUES
+    ('first_workout', 'First Rep', 'Log your first workout', 'workout_count', 1, NULL, 10),
+    ('workouts_10', 'Getting Started', 'Log 10 workouts', 'workout_count', 10, NULL, 20),
+    ('workouts_100', 'Centurion', 'Log 100 workouts', 'workout_count', 100, NULL, 30),
+    ('workouts_500', 'Iron Regular', 'Log 500 workouts', 'workout_count', 500, NULL, 40),
+    ('first_personal_record', 'New Best', 'Set your first personal record', 'personal_record_count', 1, NULL, 50),
+    ('personal_records_50', 'Record Breaker', 'Set 50 personal records', 'personal_record_count', 50, NULL, 60),
+    ('streak_7', 'Week Warrior', 'Train 7 days in a row', 'streak_days', 7, NULL, 70),
+    ('streak_30', 'Unbreakable', 'Train 30 days in a row', 'streak_days', 30, NULL, 80),
+    ('squat_100kg', 'Triple Digits', 'Squat 100 kg for the first time', 'lift_weight_kg', 100, 'Squat', 90),
+    ('bench_press_100kg', 'Bench Centurion', 'Bench press 100 kg for the first time', 'lift_weight_kg', 100, 'Bench Press', 100),
+    ('deadlift_200kg', 'Double Plate Club', 'Deadlift 200 kg for the first time', 'lift_weight_kg', 200, 'Deadlift', 110),
+    ('first_follower', 'Making Friends', 'Get your first follower', 'follower_count', 1, NULL, 120),
+    ('followers_100', 'Crowd Favourite', 'Get 100 followers', 'follower_count', 100, NULL, 130),
+    ('following_10', 'Training Partners', 'Follow 10 athletes', 'following_count', 10, NULL, 140);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE profile_achievements;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+DROP TABLE achievements;
+-- +goose StatementEnd
@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+DROP INDEX idx_profiles_experience_points ON profiles;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX idx_profiles_experience_points ON profiles (experience_points, id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX idx_profiles_experience_points ON profiles;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE INDEX idx_profiles_experience_points ON profiles (experience_points DESC);
+-- +goose StatementEnd
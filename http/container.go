@@ -2,6 +2,7 @@ package http
 
 import (
 	"database/sql"
+	"fmt"
 	"log/slog"
 	"os"
 	"time"
@@ -14,6 +15,7 @@ import (
 	"github.com/brightside-dev/ronin-fitness-be/internal/service/email"
 	"github.com/brightside-dev/ronin-fitness-be/internal/service/logger"
 	"github.com/brightside-dev/ronin-fitness-be/internal/service/oauth"
+	"github.com/brightside-dev/ronin-fitness-be/internal/storage"
 	"github.com/go-playground/validator/v10"
 
 	"github.com/alexedwards/scs/mysqlstore"
@@ -31,6 +33,7 @@ type Container struct {
 	SessionManager *scs.SessionManager
 	JWTAuth        *jwtauth.JWTAuth
 	DBLogger       *slog.Logger
+	Storage        storage.Storage
 
 	APIResponseManager response.APIResponseManager
 
@@ -41,15 +44,36 @@ type Container struct {
 	AuthHandler       handler.AuthHandler
 	SocialAuthHandler handler.SocialAuthHandler
 	// AdminUserHandler *handler.AdminUserHandler
-	UserHandler    handler.UserHandler
-	ProfileHandler handler.ProfileHandler
+	UserHandler            handler.UserHandler
+	ProfileHandler         handler.ProfileHandler
+	WorkoutHandler         handler.WorkoutHandler
+	PersonalRecordHandler  handler.PersonalRecordHandler
+	StatsHandler           handler.StatsHandler
+	TemplateHandler        handler.TemplateHandler
+	ProgramHandler         handler.ProgramHandler
+	CalculatorHandler      handler.CalculatorHandler
+	WorkoutImportHandler   handler.WorkoutImportHandler
+	WorkoutExportHandler   handler.WorkoutExportHandler
+	CardioHandler          handler.CardioHandler
+	WorkoutSessionHandler  handler.WorkoutSessionHandler
+	WorkoutPhotoHandler    handler.WorkoutPhotoHandler
+	BodyMeasurementHandler handler.BodyMeasurementHandler
+	GoalHandler            handler.GoalHandler
+	NotificationHandler    handler.NotificationHandler
+	CalendarFeedHandler    handler.CalendarFeedHandler
+	ProfileBlockHandler    handler.ProfileBlockHandler
+	LeaderboardHandler     handler.LeaderboardHandler
+	ExperiencePointHandler handler.ExperiencePointHandler
+	AchievementHandler     handler.AchievementHandler
 
 	// Services
-	EmailService     email.EmailService
-	AdminUserService service.AdminUserService
+	EmailService           email.EmailService
+	AdminUserService       service.AdminUserService
+	WorkoutSessionService  service.WorkoutSessionService
+	ExperiencePointService service.ExperiencePointService
 }
 
-func NewContainer(db client.DatabaseService) *Container {
+func NewContainer(db client.DatabaseService) (*Container, error) {
 	// Get the environment
 	env := os.Getenv("APP_ENV")
 	// Chi Router
@@ -68,6 +92,32 @@ func NewContainer(db client.DatabaseService) *Container {
 	profileRepository := repository.NewProfileRepository(db)
 	profileFollowsRepository := repository.NewProfileFollowRepository(db)
 	verificationCodeRepository := repository.NewVerificationCodeRepository(db)
+	workoutRepository := repository.NewWorkoutRepository(db)
+	workoutReactionRepository := repository.NewWorkoutReactionRepository(db)
+	workoutCommentRepository := repository.NewWorkoutCommentRepository(db)
+	setRepository := repository.NewSetRepository(db)
+	exerciseRepository := repository.NewExerciseRepository(db)
+	personalRecordRepository := repository.NewPersonalRecordRepository(db)
+	profileStatsRepository := repository.NewProfileStatsRepository(db)
+	workoutTemplateRepository := repository.NewWorkoutTemplateRepository(db)
+	programRepository := repository.NewProgramRepository(db)
+	programEnrollmentRepository := repository.NewProgramEnrollmentRepository(db)
+	profilePlateRepository := repository.NewProfilePlateRepository(db)
+	workoutImportRepository := repository.NewWorkoutImportRepository(db)
+	cardioSessionRepository := repository.NewCardioSessionRepository(db)
+	workoutSessionRepository := repository.NewWorkoutSessionRepository(db)
+	workoutPhotoRepository := repository.NewWorkoutPhotoRepository(db)
+	bodyMeasurementRepository := repository.NewBodyMeasurementRepository(db)
+	goalRepository := repository.NewGoalRepository(db)
+	notificationRepository := repository.NewNotificationRepository(db)
+	calendarFeedRepository := repository.NewCalendarFeedRepository(db)
+	followRequestRepository := repository.NewFollowRequestRepository(db)
+	profileBlockRepository := repository.NewProfileBlockRepository(db)
+	profileMuteRepository := repository.NewProfileMuteRepository(db)
+	profileHandleRepository := repository.NewProfileHandleRepository(db)
+	leaderboardRepository := repository.NewLeaderboardRepository(db)
+	experiencePointRepository := repository.NewExperiencePointRepository(db)
+	achievementRepository := repository.NewAchievementRepository(db)
 
 	// DB Logger
 	logger := NewLogger(db.GetDB())
@@ -75,13 +125,38 @@ func NewContainer(db client.DatabaseService) *Container {
 	// Request Validator
 	validator := validator.New()
 
+	// File Storage
+	fileStorage, err := storage.New()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create file storage: %w", err)
+	}
+
 	// Services
 	emailService := email.NewEmailService(logger)
 	oAuthService := oauth.NewOAuthService(logger)
 	authService := service.NewAuthService(db, logger, validator, tokenAuth, emailService, userRepository, refreshTokenRepository, profileRepository, verificationCodeRepository)
 	userService := service.NewUserService(db, logger, userRepository)
 	adminUserService := service.NewAdminUserService(adminUserRepository)
-	profileService := service.NewProfileService(db, logger, validator, profileRepository, profileFollowsRepository, userRepository)
+	notificationService := service.NewNotificationService(db, logger, notificationRepository, profileRepository)
+	achievementService := service.NewAchievementService(db, logger, achievementRepository, profileRepository, profileFollowsRepository, profileBlockRepository, notificationService)
+	personalRecordService := service.NewPersonalRecordService(db, logger, personalRecordRepository, setRepository, exerciseRepository, profileRepository)
+	profileService := service.NewProfileService(db, logger, validator, profileRepository, profileFollowsRepository, userRepository, followRequestRepository, profileBlockRepository, profileHandleRepository, notificationService, achievementService, personalRecordService)
+	experiencePointService := service.NewExperiencePointService(db, logger, experiencePointRepository, profileRepository, profileStatsRepository)
+	statsService := service.NewStatsService(db, logger, validator, profileStatsRepository, exerciseRepository, profileRepository, setRepository, bodyMeasurementRepository)
+	programService := service.NewProgramService(db, logger, validator, programRepository, programEnrollmentRepository, workoutTemplateRepository, setRepository, exerciseRepository, profileRepository, profileBlockRepository)
+	goalService := service.NewGoalService(db, logger, validator, goalRepository, setRepository, profileStatsRepository, bodyMeasurementRepository, exerciseRepository, profileRepository, notificationService, experiencePointService)
+	workoutPhotoService := service.NewWorkoutPhotoService(db, logger, workoutPhotoRepository, workoutRepository, profileRepository, profileFollowsRepository, profileBlockRepository, fileStorage)
+	workoutService := service.NewWorkoutService(db, logger, validator, workoutRepository, workoutReactionRepository, workoutCommentRepository, setRepository, exerciseRepository, profileRepository, profileFollowsRepository, profileBlockRepository, cardioSessionRepository, workoutPhotoRepository, personalRecordService, statsService, programService, workoutPhotoService, goalService, experiencePointService, achievementService)
+	templateService := service.NewTemplateService(db, logger, validator, workoutTemplateRepository, workoutRepository, setRepository, exerciseRepository, profileRepository, profileBlockRepository)
+	calculatorService := service.NewCalculatorService(db, logger, validator, profilePlateRepository, profileRepository)
+	workoutSessionService := service.NewWorkoutSessionService(db, logger, validator, workoutSessionRepository, exerciseRepository, profileRepository, programEnrollmentRepository, workoutService, programService, goalService, experiencePointService, achievementService)
+	cardioService := service.NewCardioService(db, logger, validator, cardioSessionRepository, workoutRepository, profileRepository, profileFollowsRepository, profileBlockRepository, workoutService, goalService, experiencePointService, achievementService)
+	workoutExportService := service.NewWorkoutExportService(db, logger, validator, workoutRepository, profileRepository)
+	bodyMeasurementService := service.NewBodyMeasurementService(db, logger, validator, bodyMeasurementRepository, profileRepository, goalService)
+	workoutImportService := service.NewWorkoutImportService(db, logger, validator, workoutImportRepository, workoutRepository, exerciseRepository, profileRepository, workoutService, goalService, achievementService)
+	calendarFeedService := service.NewCalendarFeedService(db, logger, validator, calendarFeedRepository, profileRepository, workoutRepository, programRepository, programEnrollmentRepository, workoutTemplateRepository)
+	leaderboardService := service.NewLeaderboardService(db, logger, validator, leaderboardRepository, profileRepository)
+	profileBlockService := service.NewProfileBlockService(db, logger, validator, profileBlockRepository, profileMuteRepository, profileFollowsRepository, followRequestRepository, profileRepository)
 	// // pushService, err := push.NewPushService(logger)
 	// // if err != nil {
 	// // 	logger.Error("Failed to create push service")
@@ -92,6 +167,25 @@ func NewContainer(db client.DatabaseService) *Container {
 	authHandler := handler.NewAuthHandler(apiResponseManager, emailService, authService)
 	socialAuthHandler := handler.NewSocialAuthHandler(apiResponseManager, oAuthService)
 	profileHandler := handler.NewProfileHandler(apiResponseManager, logger, profileService)
+	workoutHandler := handler.NewWorkoutHandler(apiResponseManager, logger, workoutService)
+	personalRecordHandler := handler.NewPersonalRecordHandler(apiResponseManager, logger, personalRecordService)
+	statsHandler := handler.NewStatsHandler(apiResponseManager, logger, statsService)
+	templateHandler := handler.NewTemplateHandler(apiResponseManager, logger, templateService)
+	programHandler := handler.NewProgramHandler(apiResponseManager, logger, programService)
+	calculatorHandler := handler.NewCalculatorHandler(apiResponseManager, logger, calculatorService)
+	workoutImportHandler := handler.NewWorkoutImportHandler(apiResponseManager, logger, workoutImportService)
+	workoutExportHandler := handler.NewWorkoutExportHandler(apiResponseManager, logger, workoutExportService)
+	cardioHandler := handler.NewCardioHandler(apiResponseManager, logger, cardioService)
+	workoutSessionHandler := handler.NewWorkoutSessionHandler(apiResponseManager, logger, workoutSessionService)
+	workoutPhotoHandler := handler.NewWorkoutPhotoHandler(apiResponseManager, logger, workoutPhotoService)
+	bodyMeasurementHandler := handler.NewBodyMeasurementHandler(apiResponseManager, logger, bodyMeasurementService)
+	goalHandler := handler.NewGoalHandler(apiResponseManager, logger, goalService)
+	notificationHandler := handler.NewNotificationHandler(apiResponseManager, logger, notificationService)
+	calendarFeedHandler := handler.NewCalendarFeedHandler(apiResponseManager, logger, calendarFeedService)
+	profileBlockHandler := handler.NewProfileBlockHandler(apiResponseManager, logger, profileBlockService)
+	leaderboardHandler := handler.NewLeaderboardHandler(apiResponseManager, logger, leaderboardService)
+	experiencePointHandler := handler.NewExperiencePointHandler(apiResponseManager, logger, experiencePointService)
+	achievementHandler := handler.NewAchievementHandler(apiResponseManager, logger, achievementService)
 	// testAPIHandler := handler.NewTestAPIHandler()
 	// authAdminHandler := handler.NewAuthAdminHandler(adminUserRepo, *sessionManager)
 	// adminUserHandler := handler.NewAdminUserHandler(adminUserRepo)
@@ -104,6 +198,7 @@ func NewContainer(db client.DatabaseService) *Container {
 		SessionManager:     sessionManager,
 		JWTAuth:            tokenAuth,
 		DBLogger:           logger,
+		Storage:            fileStorage,
 		APIResponseManager: apiResponseManager,
 
 		// // Handlers
@@ -112,13 +207,34 @@ func NewContainer(db client.DatabaseService) *Container {
 		AuthHandler:       authHandler,
 		SocialAuthHandler: socialAuthHandler,
 		// AdminUserHandler: adminUserHandler,
-		UserHandler:    userHandler,
-		ProfileHandler: profileHandler,
+		UserHandler:            userHandler,
+		ProfileHandler:         profileHandler,
+		WorkoutHandler:         workoutHandler,
+		PersonalRecordHandler:  personalRecordHandler,
+		StatsHandler:           statsHandler,
+		TemplateHandler:        templateHandler,
+		ProgramHandler:         programHandler,
+		CalculatorHandler:      calculatorHandler,
+		WorkoutImportHandler:   workoutImportHandler,
+		WorkoutExportHandler:   workoutExportHandler,
+		CardioHandler:          cardioHandler,
+		WorkoutSessionHandler:  workoutSessionHandler,
+		WorkoutPhotoHandler:    workoutPhotoHandler,
+		BodyMeasurementHandler: bodyMeasurementHandler,
+		GoalHandler:            goalHandler,
+		NotificationHandler:    notificationHandler,
+		CalendarFeedHandler:    calendarFeedHandler,
+		ProfileBlockHandler:    profileBlockHandler,
+		LeaderboardHandler:     leaderboardHandler,
+		ExperiencePointHandler: experiencePointHandler,
+		AchievementHandler:     achievementHandler,
 
 		// Services
-		EmailService:     emailService,
-		AdminUserService: adminUserService,
-	}
+		EmailService:           emailService,
+		AdminUserService:       adminUserService,
+		WorkoutSessionService:  workoutSessionService,
+		ExperiencePointService: experiencePointService,
+	}, nil
 
 }
 
@@ -31,11 +31,137 @@ func (s *Server) RegisterRoutes(c *Container) http.Handler {
 
 		// Profile
 		r.Get("/api/profile/me", c.ProfileHandler.GetMyProfile())
+		r.Patch("/api/profile/me", c.ProfileHandler.UpdateProfile())
+		r.Put("/api/profile/me/handle", c.ProfileHandler.ChangeHandle())
+		r.Get("/api/profile/me/experience-points", c.ExperiencePointHandler.GetMyExperiencePoints())
+		r.Get("/api/profile/me/plates", c.CalculatorHandler.GetPlateInventory())
+		r.Put("/api/profile/me/plates", c.CalculatorHandler.UpdatePlateInventory())
+		r.Get("/api/profile/me/calendar-feed", c.CalendarFeedHandler.GetFeed())
+		r.Post("/api/profile/me/calendar-feed", c.CalendarFeedHandler.CreateFeed())
+		r.Delete("/api/profile/me/calendar-feed", c.CalendarFeedHandler.DeleteFeed())
+		r.Get("/api/profile/me/follow-requests", c.ProfileHandler.GetFollowRequests())
+		r.Get("/api/profile/me/follow-requests/sent", c.ProfileHandler.GetSentFollowRequests())
+		r.Delete("/api/profile/me/followers/{profileId}", c.ProfileHandler.RemoveFollower())
+		r.Get("/api/profile/me/blocks", c.ProfileBlockHandler.GetBlockedProfiles())
+		r.Post("/api/profile/me/blocks", c.ProfileBlockHandler.BlockProfile())
+		r.Delete("/api/profile/me/blocks/{profileId}", c.ProfileBlockHandler.UnblockProfile())
+		r.Get("/api/profile/me/mutes", c.ProfileBlockHandler.GetMutedProfiles())
+		r.Post("/api/profile/me/mutes", c.ProfileBlockHandler.MuteProfile())
+		r.Delete("/api/profile/me/mutes/{profileId}", c.ProfileBlockHandler.UnmuteProfile())
+		r.Get("/api/profile/by-handle/{handle}", c.ProfileHandler.GetProfileByHandle())
 		r.Get("/api/profile/{userId}", c.ProfileHandler.GetProfile())
+		r.Get("/api/profile/{profileId}/followers", c.ProfileHandler.GetFollowers())
+		r.Get("/api/profile/{profileId}/following", c.ProfileHandler.GetFollowing())
+		r.Get("/api/profile/{profileId}/achievements", c.AchievementHandler.GetProfileAchievements())
 		r.Post("/api/profile/follow", c.ProfileHandler.FollowProfile())
 		r.Post("/api/profile/unfollow", c.ProfileHandler.UnfollowProfile())
+		r.Post("/api/profile/follow-requests/{requestId}/approve", c.ProfileHandler.ApproveFollowRequest())
+		r.Post("/api/profile/follow-requests/{requestId}/reject", c.ProfileHandler.RejectFollowRequest())
+		r.Delete("/api/profile/follow-requests/{requestId}", c.ProfileHandler.CancelFollowRequest())
+		r.Get("/api/profiles/search", c.ProfileHandler.SearchProfiles())
+
+		// Feed
+		r.Get("/api/feed", c.WorkoutHandler.GetFeed())
+
+		// Workout
+		r.Post("/api/workouts", c.WorkoutHandler.CreateWorkout())
+		r.Get("/api/workouts/export", c.WorkoutExportHandler.ExportWorkouts())
+		r.Post("/api/workouts/cardio", c.CardioHandler.UploadCardio())
+		r.Post("/api/workouts/sessions", c.WorkoutSessionHandler.StartSession())
+		r.Get("/api/workouts/sessions/active", c.WorkoutSessionHandler.GetActiveSession())
+		r.Delete("/api/workouts/sessions/{sessionId}", c.WorkoutSessionHandler.DiscardSession())
+		r.Post("/api/workouts/sessions/{sessionId}/sets", c.WorkoutSessionHandler.AddSet())
+		r.Put("/api/workouts/sessions/{sessionId}/sets/{setId}", c.WorkoutSessionHandler.UpdateSet())
+		r.Delete("/api/workouts/sessions/{sessionId}/sets/{setId}", c.WorkoutSessionHandler.DeleteSet())
+		r.Post("/api/workouts/sessions/{sessionId}/pause", c.WorkoutSessionHandler.PauseSession())
+		r.Post("/api/workouts/sessions/{sessionId}/resume", c.WorkoutSessionHandler.ResumeSession())
+		r.Post("/api/workouts/sessions/{sessionId}/finish", c.WorkoutSessionHandler.FinishSession())
+		r.Get("/api/workouts/{workoutId}", c.WorkoutHandler.GetWorkout())
+		r.Delete("/api/workouts/{workoutId}", c.WorkoutHandler.DeleteWorkout())
+		r.Put("/api/workouts/{workoutId}/visibility", c.WorkoutHandler.UpdateVisibility())
+		r.Post("/api/workouts/{workoutId}/photos", c.WorkoutPhotoHandler.UploadPhoto())
+		r.Get("/api/workouts/{workoutId}/photos", c.WorkoutPhotoHandler.GetPhotos())
+		r.Delete("/api/workouts/{workoutId}/photos/{photoId}", c.WorkoutPhotoHandler.DeletePhoto())
+		r.Get("/api/workouts/{workoutId}/reactions", c.WorkoutHandler.GetReactions())
+		r.Put("/api/workouts/{workoutId}/reactions/{reaction}", c.WorkoutHandler.PutReaction())
+		r.Delete("/api/workouts/{workoutId}/reactions/{reaction}", c.WorkoutHandler.RemoveReaction())
+		r.Post("/api/workouts/{workoutId}/template", c.TemplateHandler.CreateTemplateFromWorkout())
+		r.Get("/api/workouts/{workoutId}/track", c.CardioHandler.GetCardioTrack())
+
+		// Import
+		r.Post("/api/imports", c.WorkoutImportHandler.ImportWorkouts())
+		r.Get("/api/imports", c.WorkoutImportHandler.GetImports())
+		r.Get("/api/imports/{importId}", c.WorkoutImportHandler.GetImport())
+
+		// Template
+		r.Post("/api/templates", c.TemplateHandler.CreateTemplate())
+		r.Get("/api/templates", c.TemplateHandler.GetTemplates())
+		r.Get("/api/templates/shared/{shareToken}", c.TemplateHandler.GetSharedTemplate())
+		r.Post("/api/templates/shared/{shareToken}/copy", c.TemplateHandler.CopySharedTemplate())
+		r.Get("/api/templates/{templateId}", c.TemplateHandler.GetTemplate())
+		r.Put("/api/templates/{templateId}", c.TemplateHandler.UpdateTemplate())
+		r.Delete("/api/templates/{templateId}", c.TemplateHandler.DeleteTemplate())
+		r.Post("/api/templates/{templateId}/share", c.TemplateHandler.ShareTemplate())
+		r.Delete("/api/templates/{templateId}/share", c.TemplateHandler.UnshareTemplate())
+		r.Post("/api/templates/{templateId}/start", c.TemplateHandler.StartWorkout())
+
+		// Program
+		r.Post("/api/programs", c.ProgramHandler.CreateProgram())
+		r.Get("/api/programs", c.ProgramHandler.GetPrograms())
+		r.Get("/api/programs/enrollments", c.ProgramHandler.GetEnrollments())
+		r.Post("/api/programs/enrollments/{enrollmentId}/pause", c.ProgramHandler.PauseEnrollment())
+		r.Post("/api/programs/enrollments/{enrollmentId}/resume", c.ProgramHandler.ResumeEnrollment())
+		r.Post("/api/programs/enrollments/{enrollmentId}/restart", c.ProgramHandler.RestartEnrollment())
+		r.Get("/api/programs/enrollments/{enrollmentId}/next-session", c.ProgramHandler.GetNextSession())
+		r.Get("/api/programs/{programId}", c.ProgramHandler.GetProgram())
+		r.Put("/api/programs/{programId}", c.ProgramHandler.UpdateProgram())
+		r.Post("/api/programs/{programId}/publish", c.ProgramHandler.PublishProgram())
+		r.Post("/api/programs/{programId}/enroll", c.ProgramHandler.Enroll())
+
+		// Calculator
+		r.Get("/api/calculator/plates", c.CalculatorHandler.CalculatePlates())
+		r.Get("/api/calculator/warmup", c.CalculatorHandler.GenerateWarmup())
+
+		// Stats
+		r.Get("/api/stats", c.StatsHandler.GetStats())
+
+		// Body Measurements
+		r.Post("/api/measurements", c.BodyMeasurementHandler.CreateMeasurement())
+		r.Get("/api/measurements", c.BodyMeasurementHandler.GetMeasurements())
+		r.Get("/api/measurements/series", c.BodyMeasurementHandler.GetMetricSeries())
+		r.Get("/api/measurements/{measurementId}", c.BodyMeasurementHandler.GetMeasurement())
+		r.Put("/api/measurements/{measurementId}", c.BodyMeasurementHandler.UpdateMeasurement())
+		r.Delete("/api/measurements/{measurementId}", c.BodyMeasurementHandler.DeleteMeasurement())
+
+		// Goals
+		r.Post("/api/goals", c.GoalHandler.CreateGoal())
+		r.Get("/api/goals", c.GoalHandler.GetGoals())
+		r.Get("/api/goals/{goalId}", c.GoalHandler.GetGoal())
+		r.Delete("/api/goals/{goalId}", c.GoalHandler.DeleteGoal())
+
+		// Leaderboards
+		r.Get("/api/leaderboards", c.LeaderboardHandler.GetLeaderboard())
+
+		// Achievements
+		r.Get("/api/achievements", c.AchievementHandler.GetAchievements())
+
+		// Notifications
+		r.Get("/api/notifications", c.NotificationHandler.GetNotifications())
+		r.Post("/api/notifications/read", c.NotificationHandler.MarkAllRead())
+		r.Post("/api/notifications/{notificationId}/read", c.NotificationHandler.MarkRead())
+
+		// Exercise
+		r.Get("/api/exercises/{exerciseId}/records", c.PersonalRecordHandler.GetExerciseRecords())
 	})
 
+	// Media, authorized by the signature in the URL rather than a token
+	if mediaHandler, ok := c.Storage.(http.Handler); ok {
+		r.Handle("/media/*", mediaHandler)
+	}
+
+	// Calendar feed, authorized by the secret token in the URL
+	r.Get("/ical/{token}.ics", c.CalendarFeedHandler.ServeFeed())
+
 	// Web
 	fileServer := http.FileServer(http.Dir("./ui/assets/"))
 	r.Handle("/admin/assets/*", http.StripPrefix("/admin/assets/", fileServer))
@@ -17,14 +17,17 @@ type Server struct {
 	db   client.DatabaseService
 }
 
-func New() *http.Server {
+func New() (*http.Server, error) {
 	port, _ := strconv.Atoi(os.Getenv("HTTP_PORT"))
 	NewServer := &Server{
 		port: port,
 		db:   database.New(),
 	}
 
-	container := NewContainer(NewServer.db)
+	container, err := NewContainer(NewServer.db)
+	if err != nil {
+		return nil, err
+	}
 
 	// Declare Server config
 	server := &http.Server{
@@ -35,5 +38,5 @@ func New() *http.Server {
 		WriteTimeout: 30 * time.Second,
 	}
 
-	return server
+	return server, nil
 }
@@ -0,0 +1,225 @@
+// Package cardio reads recorded activities from FIT, TCX and GPX files and derives the summary
+// and simplified GPS track stored for cardio workouts.
+package cardio
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"time"
+)
+
+const (
+	FormatFIT = "fit"
+	FormatTCX = "tcx"
+	FormatGPX = "gpx"
+
+	ActivityRun   = "run"
+	ActivityRide  = "ride"
+	ActivityWalk  = "walk"
+	ActivityHike  = "hike"
+	ActivitySwim  = "swim"
+	ActivityRow   = "row"
+	ActivityOther = "other"
+
+	earthRadiusM = 6371008.8
+	// elevationNoiseM is the climb or descent needed before elevation changes count, GPS altitude
+	// jitters by a few metres between points
+	elevationNoiseM = 3.0
+	// maxPoints bounds the samples read from a file, over a day of recording once a second. Files
+	// with more are rejected rather than held in memory whole
+	maxPoints = 100000
+)
+
+var (
+	ErrUnknownFormat = errors.New("file is not a FIT, TCX or GPX activity")
+	ErrTooManyPoints = errors.New("activity file has too many points")
+)
+
+// Point is a sample of a recorded activity. Position, elevation and heart rate are optional.
+type Point struct {
+	Time         time.Time
+	Lat          float64
+	Lon          float64
+	HasPosition  bool
+	Elevation    float64
+	HasElevation bool
+	HeartRate    int
+}
+
+// Activity is what was read from a file. Totals the file does not provide are zero until Summarize
+// derives them from the points.
+type Activity struct {
+	Format          string
+	ActivityType    string
+	Name            string
+	StartTime       time.Time
+	EndTime         time.Time
+	DistanceM       float64
+	DurationSeconds int
+	ElevationGainM  float64
+	ElevationLossM  float64
+	HasElevation    bool
+	AvgHeartRate    int
+	MaxHeartRate    int
+	Points          []Point
+}
+
+// DetectFormat recognises a file from its content rather than its name.
+func DetectFormat(data []byte) string {
+	if len(data) >= 12 && string(data[8:12]) == ".FIT" {
+		return FormatFIT
+	}
+
+	head := data[:min(len(data), 1024)]
+	switch {
+	case bytes.Contains(head, []byte("<TrainingCenterDatabase")):
+		return FormatTCX
+	case bytes.Contains(head, []byte("<gpx")):
+		return FormatGPX
+	}
+
+	return ""
+}
+
+// Parse reads an activity file of any supported format and fills in the totals it lacks.
+func Parse(data []byte) (*Activity, error) {
+	var activity *Activity
+	var err error
+
+	switch DetectFormat(data) {
+	case FormatFIT:
+		activity, err = parseFIT(data)
+	case FormatTCX:
+		activity, err = parseTCX(data)
+	case FormatGPX:
+		activity, err = parseGPX(data)
+	default:
+		return nil, ErrUnknownFormat
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	activity.summarize()
+
+	if activity.StartTime.IsZero() {
+		return nil, errors.New("activity has no start time")
+	}
+
+	return activity, nil
+}
+
+// summarize derives the totals the file did not provide from the points.
+func (a *Activity) summarize() {
+	if len(a.Points) > 0 {
+		if a.StartTime.IsZero() {
+			a.StartTime = a.Points[0].Time
+		}
+		if a.EndTime.IsZero() {
+			a.EndTime = a.Points[len(a.Points)-1].Time
+		}
+	}
+
+	if a.DurationSeconds == 0 && a.EndTime.After(a.StartTime) {
+		a.DurationSeconds = int(a.EndTime.Sub(a.StartTime).Seconds())
+	}
+	// Moving time never exceeds the elapsed time
+	if minEndTime := a.StartTime.Add(time.Duration(a.DurationSeconds) * time.Second); a.EndTime.Before(minEndTime) {
+		a.EndTime = minEndTime
+	}
+
+	if a.DistanceM == 0 {
+		var previous *Point
+		for i := range a.Points {
+			if !a.Points[i].HasPosition {
+				continue
+			}
+			if previous != nil {
+				a.DistanceM += Distance(previous.Lat, previous.Lon, a.Points[i].Lat, a.Points[i].Lon)
+			}
+			previous = &a.Points[i]
+		}
+	}
+
+	if !a.HasElevation {
+		a.ElevationGainM, a.ElevationLossM, a.HasElevation = elevationChange(a.Points)
+	}
+
+	if a.AvgHeartRate == 0 {
+		sum, count := 0, 0
+		for _, point := range a.Points {
+			if point.HeartRate > 0 {
+				sum += point.HeartRate
+				count++
+				a.MaxHeartRate = max(a.MaxHeartRate, point.HeartRate)
+			}
+		}
+		if count > 0 {
+			a.AvgHeartRate = int(math.Round(float64(sum) / float64(count)))
+		}
+	}
+
+	if a.ActivityType == "" {
+		a.ActivityType = ActivityOther
+	}
+}
+
+// elevationChange adds up climbs and descents, ignoring changes smaller than elevationNoiseM.
+func elevationChange(points []Point) (float64, float64, bool) {
+	gain, loss := 0.0, 0.0
+	reference, hasReference := 0.0, false
+	for _, point := range points {
+		if !point.HasElevation {
+			continue
+		}
+		if !hasReference {
+			reference, hasReference = point.Elevation, true
+			continue
+		}
+
+		change := point.Elevation - reference
+		if math.Abs(change) < elevationNoiseM {
+			continue
+		}
+		if change > 0 {
+			gain += change
+		} else {
+			loss -= change
+		}
+		reference = point.Elevation
+	}
+
+	return gain, loss, hasReference
+}
+
+// Distance returns the great-circle distance in metres between two positions.
+func Distance(lat1 float64, lon1 float64, lat2 float64, lon2 float64) float64 {
+	phi1, phi2 := lat1*math.Pi/180, lat2*math.Pi/180
+	dPhi := phi2 - phi1
+	dLambda := (lon2 - lon1) * math.Pi / 180
+
+	a := math.Sin(dPhi/2)*math.Sin(dPhi/2) + math.Cos(phi1)*math.Cos(phi2)*math.Sin(dLambda/2)*math.Sin(dLambda/2)
+
+	return 2 * earthRadiusM * math.Asin(math.Min(1, math.Sqrt(a)))
+}
+
+// activityTypeFromName maps the sport names used by the file formats onto our activity types.
+func activityTypeFromName(name string) string {
+	switch name {
+	case "running", "run", "Running", "trail_running", "treadmill_running":
+		return ActivityRun
+	case "cycling", "biking", "ride", "Biking", "road_biking", "mountain_biking", "indoor_cycling":
+		return ActivityRide
+	case "walking", "walk":
+		return ActivityWalk
+	case "hiking", "hike":
+		return ActivityHike
+	case "swimming", "swim", "lap_swimming", "open_water_swimming":
+		return ActivitySwim
+	case "rowing", "row", "indoor_rowing":
+		return ActivityRow
+	}
+
+	return ""
+}
@@ -0,0 +1,255 @@
+package cardio
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func readFixture(t *testing.T, name string) []byte {
+	t.Helper()
+
+	data, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatalf("reading fixture %s: %v", name, err)
+	}
+
+	return data
+}
+
+// fitFile wraps the messages in a FIT header, followed by a CRC the parser does not check.
+func fitFile(messages []byte) []byte {
+	header := []byte{14, 0x10, 0x2D, 0x08, 0, 0, 0, 0, '.', 'F', 'I', 'T', 0, 0}
+	binary.LittleEndian.PutUint32(header[4:8], uint32(len(messages)))
+
+	return append(append(header, messages...), 0, 0)
+}
+
+func TestParse(t *testing.T) {
+	start := time.Date(2024, 6, 1, 7, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name            string
+		file            string
+		format          string
+		activityType    string
+		activityName    string
+		endTime         time.Time
+		durationSeconds int
+		distanceM       float64
+		elevationGainM  float64
+		avgHeartRate    int
+		maxHeartRate    int
+		points          int
+	}{
+		{
+			name:            "fit takes the session totals",
+			file:            "run.fit",
+			format:          FormatFIT,
+			activityType:    ActivityRun,
+			endTime:         start.Add(time.Minute),
+			durationSeconds: 58,
+			distanceM:       223,
+			elevationGainM:  3,
+			avgHeartRate:    130,
+			maxHeartRate:    140,
+			points:          3,
+		},
+		{
+			name:            "tcx takes the lap totals",
+			file:            "run.tcx",
+			format:          FormatTCX,
+			activityType:    ActivityRun,
+			endTime:         start.Add(time.Minute),
+			durationSeconds: 60,
+			distanceM:       250,
+			elevationGainM:  3,
+			avgHeartRate:    130,
+			maxHeartRate:    140,
+			points:          3,
+		},
+		{
+			name:            "gpx totals are derived from the points",
+			file:            "ride.gpx",
+			format:          FormatGPX,
+			activityType:    ActivityRide,
+			activityName:    "Morning Ride",
+			endTime:         start.Add(10 * time.Minute),
+			durationSeconds: 600,
+			distanceM:       2223.9,
+			elevationGainM:  10,
+			avgHeartRate:    130,
+			maxHeartRate:    150,
+			points:          3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			activity, err := Parse(readFixture(t, tt.file))
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+
+			if activity.Format != tt.format {
+				t.Errorf("Format = %q, want %q", activity.Format, tt.format)
+			}
+			if activity.ActivityType != tt.activityType {
+				t.Errorf("ActivityType = %q, want %q", activity.ActivityType, tt.activityType)
+			}
+			if activity.Name != tt.activityName {
+				t.Errorf("Name = %q, want %q", activity.Name, tt.activityName)
+			}
+			if !activity.StartTime.Equal(start) {
+				t.Errorf("StartTime = %v, want %v", activity.StartTime, start)
+			}
+			if !activity.EndTime.Equal(tt.endTime) {
+				t.Errorf("EndTime = %v, want %v", activity.EndTime, tt.endTime)
+			}
+			if activity.DurationSeconds != tt.durationSeconds {
+				t.Errorf("DurationSeconds = %d, want %d", activity.DurationSeconds, tt.durationSeconds)
+			}
+			if math.Abs(activity.DistanceM-tt.distanceM) > 1 {
+				t.Errorf("DistanceM = %.1f, want %.1f", activity.DistanceM, tt.distanceM)
+			}
+			if math.Abs(activity.ElevationGainM-tt.elevationGainM) > 0.01 {
+				t.Errorf("ElevationGainM = %.2f, want %.2f", activity.ElevationGainM, tt.elevationGainM)
+			}
+			if activity.AvgHeartRate != tt.avgHeartRate {
+				t.Errorf("AvgHeartRate = %d, want %d", activity.AvgHeartRate, tt.avgHeartRate)
+			}
+			if activity.MaxHeartRate != tt.maxHeartRate {
+				t.Errorf("MaxHeartRate = %d, want %d", activity.MaxHeartRate, tt.maxHeartRate)
+			}
+			if len(activity.Points) != tt.points {
+				t.Errorf("len(Points) = %d, want %d", len(activity.Points), tt.points)
+			}
+		})
+	}
+}
+
+func TestParseFITPoints(t *testing.T) {
+	activity, err := Parse(readFixture(t, "run.fit"))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	point := activity.Points[1]
+	if !point.HasPosition || math.Abs(point.Lat-51.501) > 1e-6 || math.Abs(point.Lon+0.12) > 1e-6 {
+		t.Errorf("position = (%v, %v, %v), want (51.501, -0.12, true)", point.Lat, point.Lon, point.HasPosition)
+	}
+	if !point.HasElevation || point.Elevation != 18 {
+		t.Errorf("elevation = (%v, %v), want (18, true)", point.Elevation, point.HasElevation)
+	}
+	if point.HeartRate != 130 {
+		t.Errorf("HeartRate = %d, want 130", point.HeartRate)
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	fit := readFixture(t, "run.fit")
+	tcx := readFixture(t, "run.tcx")
+	gpx := readFixture(t, "ride.gpx")
+
+	// A record definition without fields makes every following byte a point
+	emptyRecordDefinition := []byte{0x40, 0, 0, 20, 0, 0}
+	tooManyFITPoints := fitFile(append(emptyRecordDefinition, bytes.Repeat([]byte{0x00}, maxPoints+1)...))
+	// A definition without fields of an ignored message makes every following byte a message
+	emptyEventDefinition := []byte{0x40, 0, 0, 21, 0, 0}
+	tooManyFITMessages := fitFile(append(emptyEventDefinition, bytes.Repeat([]byte{0x00}, maxFITMessages)...))
+
+	gpxPoint := `<trkpt lat="51.5" lon="-0.12"><time>2024-06-01T07:00:00Z</time></trkpt>`
+	tooManyGPXPoints := `<gpx><trk><trkseg>` + strings.Repeat(gpxPoint, maxPoints+1) + `</trkseg></trk></gpx>`
+
+	tests := []struct {
+		name string
+		data []byte
+		err  error
+	}{
+		{name: "empty", data: nil, err: ErrUnknownFormat},
+		{name: "unknown format", data: []byte("date,exercise,reps\n"), err: ErrUnknownFormat},
+		{name: "fit cut inside the header", data: fit[:12]},
+		{name: "fit cut inside a definition", data: fit[:16], err: errFITTruncated},
+		{name: "fit cut inside a record", data: fit[:40], err: errFITTruncated},
+		{name: "fit cut between records", data: fit[:len(fit)-40], err: errFITTruncated},
+		{name: "fit header larger than the file", data: append([]byte{200}, fit[1:14]...)},
+		{name: "fit data without definition", data: fitFile([]byte{0x00, 1, 2, 3})},
+		{name: "fit without activity data", data: fitFile(nil)},
+		{name: "fit with too many points", data: tooManyFITPoints, err: ErrTooManyPoints},
+		{name: "fit with too many messages", data: tooManyFITMessages},
+		{name: "tcx cut short", data: tcx[:len(tcx)/2]},
+		{name: "tcx without activity", data: []byte(`<TrainingCenterDatabase><Activities></Activities></TrainingCenterDatabase>`)},
+		{name: "tcx with a malformed time", data: bytes.Replace(tcx, []byte("2024-06-01T07:00:30Z"), []byte("yesterday"), 1)},
+		{name: "gpx cut short", data: gpx[:len(gpx)/2]},
+		{name: "gpx without points", data: []byte(`<gpx><trk><trkseg></trkseg></trk></gpx>`)},
+		{name: "gpx with too many points", data: []byte(tooManyGPXPoints), err: ErrTooManyPoints},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			activity, err := Parse(tt.data)
+			if err == nil {
+				t.Fatalf("Parse() = %+v, want an error", activity)
+			}
+			if tt.err != nil && !errors.Is(err, tt.err) {
+				t.Errorf("Parse() error = %v, want %v", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestSimplifyTrack(t *testing.T) {
+	at := func(lat float64, lon float64) Point {
+		return Point{Lat: lat, Lon: lon, HasPosition: true}
+	}
+
+	// About 11 metres between consecutive points
+	straight := []Point{}
+	for i := 0; i < 50; i++ {
+		straight = append(straight, at(51.5+float64(i)*0.0001, -0.12))
+	}
+
+	// Every other point steps about 70 metres to the side
+	zigzag := []Point{}
+	for i := 0; i < 50; i++ {
+		zigzag = append(zigzag, at(51.5+float64(i)*0.0001, -0.12+float64(i%2)*0.001))
+	}
+
+	tests := []struct {
+		name      string
+		points    []Point
+		maxPoints int
+		want      int
+	}{
+		{name: "empty", points: nil, maxPoints: 10, want: 0},
+		{name: "points without position are dropped", points: []Point{{}, at(51.5, -0.12), {}, at(51.6, -0.12)}, maxPoints: 10, want: 2},
+		{name: "straight line keeps its ends", points: straight, maxPoints: 10, want: 2},
+		{name: "zigzag under the limit is kept", points: zigzag, maxPoints: 50, want: 50},
+		{name: "zigzag over the limit is reduced", points: zigzag, maxPoints: 20, want: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			track := SimplifyTrack(tt.points, tt.maxPoints)
+			if len(track) != tt.want {
+				t.Fatalf("len(SimplifyTrack()) = %d, want %d", len(track), tt.want)
+			}
+
+			positioned := []Point{}
+			for _, point := range tt.points {
+				if point.HasPosition {
+					positioned = append(positioned, point)
+				}
+			}
+			if len(track) > 0 && (track[0] != positioned[0] || track[len(track)-1] != positioned[len(positioned)-1]) {
+				t.Errorf("SimplifyTrack() does not keep the first and last points")
+			}
+		})
+	}
+}
@@ -0,0 +1,333 @@
+package cardio
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+)
+
+const (
+	fitMessageSession = 18
+	fitMessageRecord  = 20
+	// maxFITMessages bounds the messages read from a file. Messages can be a single byte, so the
+	// file size alone does not bound the work
+	maxFITMessages = 1 << 20
+
+	// fitEpoch is 1989-12-31T00:00:00Z, FIT timestamps count seconds from it
+	fitEpoch = 631065600
+	// fitSemicirclesToDegrees converts FIT's 32-bit angles to degrees
+	fitSemicirclesToDegrees = 180 / float64(1<<31)
+)
+
+// fitSports maps FIT sport numbers onto our activity types.
+var fitSports = map[uint64]string{
+	1:  ActivityRun,
+	2:  ActivityRide,
+	5:  ActivitySwim,
+	11: ActivityWalk,
+	15: ActivityRow,
+	17: ActivityHike,
+}
+
+type fitFieldDefinition struct {
+	Number   byte
+	Size     int
+	BaseType byte
+}
+
+type fitDefinition struct {
+	GlobalMessage   uint16
+	ByteOrder       binary.ByteOrder
+	Fields          []fitFieldDefinition
+	DeveloperLength int
+}
+
+var errFITTruncated = errors.New("invalid FIT file: unexpected end of data")
+
+// parseFIT decodes the record and session messages of a FIT activity file. Only the fields needed
+// for the summary and the track are read; everything else is skipped using the message definitions.
+func parseFIT(data []byte) (*Activity, error) {
+	if len(data) < 12 {
+		return nil, fmt.Errorf("invalid FIT file header")
+	}
+
+	headerSize := int(data[0])
+	if headerSize < 12 || len(data) < headerSize {
+		return nil, fmt.Errorf("invalid FIT file header")
+	}
+
+	dataSize := int(binary.LittleEndian.Uint32(data[4:8]))
+	if len(data) < headerSize+dataSize {
+		return nil, errFITTruncated
+	}
+	end := headerSize + dataSize
+
+	activity := &Activity{Format: FormatFIT}
+	definitions := map[byte]*fitDefinition{}
+	var lastTimestamp uint32
+	// The field values are only needed until the next message
+	values := map[byte]uint64{}
+	signed := map[byte]int64{}
+
+	for offset, messages := headerSize, 0; offset < end; messages++ {
+		if messages == maxFITMessages {
+			return nil, fmt.Errorf("invalid FIT file: more than %d messages", maxFITMessages)
+		}
+
+		header := data[offset]
+		offset++
+
+		localMessage := header & 0x0F
+		compressedTimestamp := header&0x80 != 0
+		if compressedTimestamp {
+			localMessage = (header >> 5) & 0x03
+			timeOffset := uint32(header & 0x1F)
+			timestamp := (lastTimestamp &^ 0x1F) + timeOffset
+			if timeOffset < lastTimestamp&0x1F {
+				timestamp += 0x20
+			}
+			lastTimestamp = timestamp
+		}
+
+		if !compressedTimestamp && header&0x40 != 0 {
+			definition, size, err := parseFITDefinition(data[offset:end], header&0x20 != 0)
+			if err != nil {
+				return nil, err
+			}
+			definitions[localMessage] = definition
+			offset += size
+			continue
+		}
+
+		definition, ok := definitions[localMessage]
+		if !ok {
+			return nil, fmt.Errorf("invalid FIT file: data message without definition")
+		}
+
+		clear(values)
+		clear(signed)
+		for _, field := range definition.Fields {
+			if offset+field.Size > end {
+				return nil, errFITTruncated
+			}
+			value, valid := readFITValue(data[offset:offset+field.Size], field.BaseType, definition.ByteOrder)
+			if valid {
+				values[field.Number] = value
+				signed[field.Number] = signExtend(value, field.Size)
+			}
+			offset += field.Size
+		}
+		offset += definition.DeveloperLength
+		if offset > end {
+			return nil, errFITTruncated
+		}
+
+		if timestamp, ok := values[253]; ok {
+			lastTimestamp = uint32(timestamp)
+		} else if compressedTimestamp {
+			values[253] = uint64(lastTimestamp)
+		}
+
+		switch definition.GlobalMessage {
+		case fitMessageRecord:
+			if len(activity.Points) == maxPoints {
+				return nil, ErrTooManyPoints
+			}
+			activity.Points = append(activity.Points, fitRecordPoint(values, signed))
+		case fitMessageSession:
+			applyFITSession(activity, values)
+		}
+	}
+
+	if len(activity.Points) == 0 && activity.StartTime.IsZero() {
+		return nil, fmt.Errorf("FIT file has no activity data")
+	}
+
+	return activity, nil
+}
+
+// parseFITDefinition reads a definition message and returns it with its length in bytes.
+func parseFITDefinition(data []byte, hasDeveloperFields bool) (*fitDefinition, int, error) {
+	if len(data) < 5 {
+		return nil, 0, errFITTruncated
+	}
+
+	definition := &fitDefinition{ByteOrder: binary.LittleEndian}
+	if data[1] == 1 {
+		definition.ByteOrder = binary.BigEndian
+	}
+	definition.GlobalMessage = definition.ByteOrder.Uint16(data[2:4])
+
+	fieldCount := int(data[4])
+	size := 5 + fieldCount*3
+	if len(data) < size {
+		return nil, 0, errFITTruncated
+	}
+
+	for i := 0; i < fieldCount; i++ {
+		field := data[5+i*3 : 8+i*3]
+		definition.Fields = append(definition.Fields, fitFieldDefinition{
+			Number:   field[0],
+			Size:     int(field[1]),
+			BaseType: field[2],
+		})
+	}
+
+	if hasDeveloperFields {
+		if len(data) < size+1 {
+			return nil, 0, errFITTruncated
+		}
+		developerCount := int(data[size])
+		size++
+		if len(data) < size+developerCount*3 {
+			return nil, 0, errFITTruncated
+		}
+		for i := 0; i < developerCount; i++ {
+			definition.DeveloperLength += int(data[size+i*3+1])
+		}
+		size += developerCount * 3
+	}
+
+	return definition, size, nil
+}
+
+// readFITValue reads a single integer field. Arrays, strings and floats are not needed and are
+// reported as invalid, as are the "no value" markers of each base type.
+func readFITValue(data []byte, baseType byte, byteOrder binary.ByteOrder) (uint64, bool) {
+	var value, invalid uint64
+	switch baseType & 0x1F {
+	case 0x00, 0x02, 0x0A, 0x0D: // enum, uint8, uint8z, byte
+		if len(data) != 1 {
+			return 0, false
+		}
+		value, invalid = uint64(data[0]), 0xFF
+	case 0x01: // sint8
+		if len(data) != 1 {
+			return 0, false
+		}
+		value, invalid = uint64(data[0]), 0x7F
+	case 0x03: // sint16
+		if len(data) != 2 {
+			return 0, false
+		}
+		value, invalid = uint64(byteOrder.Uint16(data)), 0x7FFF
+	case 0x04, 0x0B: // uint16, uint16z
+		if len(data) != 2 {
+			return 0, false
+		}
+		value, invalid = uint64(byteOrder.Uint16(data)), 0xFFFF
+	case 0x05: // sint32
+		if len(data) != 4 {
+			return 0, false
+		}
+		value, invalid = uint64(byteOrder.Uint32(data)), 0x7FFFFFFF
+	case 0x06, 0x0C: // uint32, uint32z
+		if len(data) != 4 {
+			return 0, false
+		}
+		value, invalid = uint64(byteOrder.Uint32(data)), 0xFFFFFFFF
+	default:
+		return 0, false
+	}
+
+	// The "z" types mark a missing value with zero instead
+	if value == invalid || (baseType&0x1F >= 0x0A && baseType&0x1F <= 0x0C && value == 0) {
+		return 0, false
+	}
+
+	return value, true
+}
+
+func signExtend(value uint64, size int) int64 {
+	switch size {
+	case 1:
+		return int64(int8(value))
+	case 2:
+		return int64(int16(value))
+	case 4:
+		return int64(int32(value))
+	}
+
+	return int64(value)
+}
+
+func fitTime(value uint64) time.Time {
+	return time.Unix(int64(value)+fitEpoch, 0).UTC()
+}
+
+func fitRecordPoint(values map[byte]uint64, signed map[byte]int64) Point {
+	point := Point{}
+	if timestamp, ok := values[253]; ok {
+		point.Time = fitTime(timestamp)
+	}
+
+	_, hasLat := values[0]
+	_, hasLon := values[1]
+	if hasLat && hasLon {
+		point.Lat = float64(signed[0]) * fitSemicirclesToDegrees
+		point.Lon = float64(signed[1]) * fitSemicirclesToDegrees
+		point.HasPosition = true
+	}
+
+	// Altitude is stored as (metres + 500) * 5, the enhanced field has more range
+	if altitude, ok := values[78]; ok {
+		point.Elevation, point.HasElevation = float64(altitude)/5-500, true
+	} else if altitude, ok := values[2]; ok {
+		point.Elevation, point.HasElevation = float64(altitude)/5-500, true
+	}
+
+	if heartRate, ok := values[3]; ok {
+		point.HeartRate = int(heartRate)
+	}
+
+	return point
+}
+
+// applyFITSession takes the totals the device computed. Multisport files have several sessions,
+// their totals are added up.
+func applyFITSession(activity *Activity, values map[byte]uint64) {
+	if startTime, ok := values[2]; ok {
+		start := fitTime(startTime)
+		if activity.StartTime.IsZero() || start.Before(activity.StartTime) {
+			activity.StartTime = start
+		}
+	}
+	if timestamp, ok := values[253]; ok {
+		end := fitTime(timestamp)
+		if end.After(activity.EndTime) {
+			activity.EndTime = end
+		}
+	}
+
+	// Timer time excludes pauses, elapsed time is the fallback
+	if timerTime, ok := values[8]; ok {
+		activity.DurationSeconds += int(math.Round(float64(timerTime) / 1000))
+	} else if elapsedTime, ok := values[7]; ok {
+		activity.DurationSeconds += int(math.Round(float64(elapsedTime) / 1000))
+	}
+
+	if distance, ok := values[9]; ok {
+		activity.DistanceM += float64(distance) / 100
+	}
+
+	ascent, hasAscent := values[22]
+	descent, hasDescent := values[23]
+	if hasAscent || hasDescent {
+		activity.ElevationGainM += float64(ascent)
+		activity.ElevationLossM += float64(descent)
+		activity.HasElevation = true
+	}
+
+	if heartRate, ok := values[16]; ok && activity.AvgHeartRate == 0 {
+		activity.AvgHeartRate = int(heartRate)
+	}
+	if heartRate, ok := values[17]; ok {
+		activity.MaxHeartRate = max(activity.MaxHeartRate, int(heartRate))
+	}
+
+	if sport, ok := values[5]; ok && activity.ActivityType == "" {
+		activity.ActivityType = fitSports[sport]
+	}
+}
@@ -0,0 +1,72 @@
+package cardio
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"strings"
+	"time"
+)
+
+type gpxFile struct {
+	Name   string `xml:"metadata>name"`
+	Tracks []struct {
+		Name     string `xml:"name"`
+		Type     string `xml:"type"`
+		Segments []struct {
+			Points []struct {
+				Lat       float64   `xml:"lat,attr"`
+				Lon       float64   `xml:"lon,attr"`
+				Elevation *float64  `xml:"ele"`
+				Time      time.Time `xml:"time"`
+				// Garmin's track point extension, other apps reuse it
+				HeartRate int `xml:"extensions>TrackPointExtension>hr"`
+			} `xml:"trkpt"`
+		} `xml:"trkseg"`
+	} `xml:"trk"`
+}
+
+func parseGPX(data []byte) (*Activity, error) {
+	var file gpxFile
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	err := decoder.Decode(&file)
+	if err != nil {
+		return nil, fmt.Errorf("invalid GPX file: %w", err)
+	}
+
+	activity := &Activity{Format: FormatGPX, Name: file.Name}
+	for _, track := range file.Tracks {
+		if activity.Name == "" {
+			activity.Name = track.Name
+		}
+		if activity.ActivityType == "" {
+			activity.ActivityType = activityTypeFromName(strings.ToLower(track.Type))
+		}
+
+		for _, segment := range track.Segments {
+			if len(activity.Points)+len(segment.Points) > maxPoints {
+				return nil, ErrTooManyPoints
+			}
+
+			for _, trackPoint := range segment.Points {
+				point := Point{
+					Time:        trackPoint.Time.UTC(),
+					Lat:         trackPoint.Lat,
+					Lon:         trackPoint.Lon,
+					HasPosition: true,
+					HeartRate:   trackPoint.HeartRate,
+				}
+				if trackPoint.Elevation != nil {
+					point.Elevation, point.HasElevation = *trackPoint.Elevation, true
+				}
+				activity.Points = append(activity.Points, point)
+			}
+		}
+	}
+
+	if len(activity.Points) == 0 {
+		return nil, fmt.Errorf("GPX file has no track points")
+	}
+
+	return activity, nil
+}
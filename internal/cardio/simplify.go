@@ -0,0 +1,100 @@
+package cardio
+
+import "math"
+
+const (
+	// simplifyToleranceM is the furthest a dropped point may lie from the simplified track
+	simplifyToleranceM = 2.0
+	metresPerDegreeLat = 111320.0
+)
+
+// SimplifyTrack returns the positioned points of a track reduced with the Douglas-Peucker algorithm.
+// The tolerance starts at a couple of metres, which is below GPS accuracy, and is doubled until the
+// track has at most maxPoints points.
+func SimplifyTrack(points []Point, maxPoints int) []Point {
+	track := make([]Point, 0, len(points))
+	for _, point := range points {
+		if point.HasPosition {
+			track = append(track, point)
+		}
+	}
+
+	if len(track) <= 2 {
+		return track
+	}
+
+	// Project onto a flat plane in metres around the start, accurate enough for a single activity
+	cosLat := math.Cos(track[0].Lat * math.Pi / 180)
+	xs := make([]float64, len(track))
+	ys := make([]float64, len(track))
+	for i, point := range track {
+		xs[i] = (point.Lon - track[0].Lon) * metresPerDegreeLat * cosLat
+		ys[i] = (point.Lat - track[0].Lat) * metresPerDegreeLat
+	}
+
+	tolerance := simplifyToleranceM
+	for {
+		keep := douglasPeucker(xs, ys, tolerance)
+
+		count := 0
+		for _, kept := range keep {
+			if kept {
+				count++
+			}
+		}
+
+		if count <= maxPoints || tolerance > 1e6 {
+			simplified := make([]Point, 0, count)
+			for i, kept := range keep {
+				if kept {
+					simplified = append(simplified, track[i])
+				}
+			}
+			return simplified
+		}
+
+		tolerance *= 2
+	}
+}
+
+// douglasPeucker marks the points to keep. It uses an explicit stack, as tracks of long activities
+// have tens of thousands of points.
+func douglasPeucker(xs []float64, ys []float64, tolerance float64) []bool {
+	keep := make([]bool, len(xs))
+	keep[0], keep[len(xs)-1] = true, true
+
+	type segment struct{ first, last int }
+	stack := []segment{{0, len(xs) - 1}}
+	for len(stack) > 0 {
+		current := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		furthest, furthestDistance := -1, tolerance
+		for i := current.first + 1; i < current.last; i++ {
+			distance := segmentDistance(xs[i], ys[i], xs[current.first], ys[current.first], xs[current.last], ys[current.last])
+			if distance > furthestDistance {
+				furthest, furthestDistance = i, distance
+			}
+		}
+
+		if furthest >= 0 {
+			keep[furthest] = true
+			stack = append(stack, segment{current.first, furthest}, segment{furthest, current.last})
+		}
+	}
+
+	return keep
+}
+
+// segmentDistance returns the distance from point p to the segment between a and b.
+func segmentDistance(px float64, py float64, ax float64, ay float64, bx float64, by float64) float64 {
+	dx, dy := bx-ax, by-ay
+	lengthSquared := dx*dx + dy*dy
+	if lengthSquared == 0 {
+		return math.Hypot(px-ax, py-ay)
+	}
+
+	t := math.Max(0, math.Min(1, ((px-ax)*dx+(py-ay)*dy)/lengthSquared))
+
+	return math.Hypot(px-(ax+t*dx), py-(ay+t*dy))
+}
@@ -0,0 +1,86 @@
+package cardio
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+type tcxFile struct {
+	Activities []struct {
+		Sport string    `xml:"Sport,attr"`
+		ID    time.Time `xml:"Id"`
+		Notes string    `xml:"Notes"`
+		Laps  []struct {
+			StartTime        time.Time `xml:"StartTime,attr"`
+			TotalTimeSeconds float64   `xml:"TotalTimeSeconds"`
+			DistanceMeters   float64   `xml:"DistanceMeters"`
+			AverageHeartRate int       `xml:"AverageHeartRateBpm>Value"`
+			MaximumHeartRate int       `xml:"MaximumHeartRateBpm>Value"`
+			TrackPoints      []struct {
+				Time      time.Time `xml:"Time"`
+				Latitude  *float64  `xml:"Position>LatitudeDegrees"`
+				Longitude *float64  `xml:"Position>LongitudeDegrees"`
+				Altitude  *float64  `xml:"AltitudeMeters"`
+				HeartRate int       `xml:"HeartRateBpm>Value"`
+			} `xml:"Track>Trackpoint"`
+		} `xml:"Lap"`
+	} `xml:"Activities>Activity"`
+}
+
+// parseTCX reads the first activity of a Training Center file. Lap totals are preferred over values
+// derived from the track points, as the device measured them more precisely.
+func parseTCX(data []byte) (*Activity, error) {
+	var file tcxFile
+	err := xml.NewDecoder(bytes.NewReader(data)).Decode(&file)
+	if err != nil {
+		return nil, fmt.Errorf("invalid TCX file: %w", err)
+	}
+
+	if len(file.Activities) == 0 {
+		return nil, fmt.Errorf("TCX file has no activity")
+	}
+	tcxActivity := file.Activities[0]
+
+	activity := &Activity{
+		Format:       FormatTCX,
+		ActivityType: activityTypeFromName(strings.ToLower(tcxActivity.Sport)),
+		Name:         strings.TrimSpace(tcxActivity.Notes),
+		StartTime:    tcxActivity.ID.UTC(),
+	}
+
+	durationSeconds := 0.0
+	heartRateSum := 0.0
+	for _, lap := range tcxActivity.Laps {
+		durationSeconds += lap.TotalTimeSeconds
+		activity.DistanceM += lap.DistanceMeters
+		heartRateSum += float64(lap.AverageHeartRate) * lap.TotalTimeSeconds
+		activity.MaxHeartRate = max(activity.MaxHeartRate, lap.MaximumHeartRate)
+
+		if len(activity.Points)+len(lap.TrackPoints) > maxPoints {
+			return nil, ErrTooManyPoints
+		}
+
+		for _, trackPoint := range lap.TrackPoints {
+			point := Point{Time: trackPoint.Time.UTC(), HeartRate: trackPoint.HeartRate}
+			if trackPoint.Latitude != nil && trackPoint.Longitude != nil {
+				point.Lat, point.Lon, point.HasPosition = *trackPoint.Latitude, *trackPoint.Longitude, true
+			}
+			if trackPoint.Altitude != nil {
+				point.Elevation, point.HasElevation = *trackPoint.Altitude, true
+			}
+			activity.Points = append(activity.Points, point)
+		}
+	}
+
+	activity.DurationSeconds = int(math.Round(durationSeconds))
+	if durationSeconds > 0 && heartRateSum > 0 {
+		// Weighted by lap length so short laps do not skew the average
+		activity.AvgHeartRate = int(math.Round(heartRateSum / durationSeconds))
+	}
+
+	return activity, nil
+}
@@ -0,0 +1,56 @@
+package handler
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/brightside-dev/ronin-fitness-be/internal/handler/response"
+	"github.com/brightside-dev/ronin-fitness-be/internal/service"
+)
+
+type AchievementHandler interface {
+	GetAchievements() http.HandlerFunc
+	GetProfileAchievements() http.HandlerFunc
+}
+
+type achievementHandler struct {
+	APIResponse        response.APIResponseManager
+	DBLogger           *slog.Logger
+	AchievementService service.AchievementService
+}
+
+func NewAchievementHandler(
+	apiResponse response.APIResponseManager,
+	dbLogger *slog.Logger,
+	achievementService service.AchievementService,
+) AchievementHandler {
+	return &achievementHandler{
+		APIResponse:        apiResponse,
+		DBLogger:           dbLogger,
+		AchievementService: achievementService,
+	}
+}
+
+func (h *achievementHandler) GetAchievements() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		achievementsResponseDTO, err := h.AchievementService.GetAchievements(w, r)
+		if err != nil {
+			h.APIResponse.ErrorResponse(w, r, err, errorStatusCode(err, http.StatusInternalServerError))
+			return
+		}
+
+		h.APIResponse.SuccessResponse(w, r, achievementsResponseDTO)
+	}
+}
+
+func (h *achievementHandler) GetProfileAchievements() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		profileAchievementsResponseDTO, err := h.AchievementService.GetProfileAchievements(w, r)
+		if err != nil {
+			h.APIResponse.ErrorResponse(w, r, err, errorStatusCode(err, http.StatusBadRequest))
+			return
+		}
+
+		h.APIResponse.SuccessResponse(w, r, profileAchievementsResponseDTO)
+	}
+}
@@ -0,0 +1,108 @@
+package handler
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/brightside-dev/ronin-fitness-be/internal/handler/response"
+	"github.com/brightside-dev/ronin-fitness-be/internal/service"
+)
+
+type BodyMeasurementHandler interface {
+	CreateMeasurement() http.HandlerFunc
+	GetMeasurements() http.HandlerFunc
+	GetMeasurement() http.HandlerFunc
+	UpdateMeasurement() http.HandlerFunc
+	DeleteMeasurement() http.HandlerFunc
+	GetMetricSeries() http.HandlerFunc
+}
+
+type bodyMeasurementHandler struct {
+	APIResponse            response.APIResponseManager
+	DBLogger               *slog.Logger
+	BodyMeasurementService service.BodyMeasurementService
+}
+
+func NewBodyMeasurementHandler(
+	apiResponse response.APIResponseManager,
+	dbLogger *slog.Logger,
+	bodyMeasurementService service.BodyMeasurementService,
+) BodyMeasurementHandler {
+	return &bodyMeasurementHandler{
+		APIResponse:            apiResponse,
+		DBLogger:               dbLogger,
+		BodyMeasurementService: bodyMeasurementService,
+	}
+}
+
+func (h *bodyMeasurementHandler) CreateMeasurement() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		bodyMeasurementResponseDTO, err := h.BodyMeasurementService.CreateMeasurement(w, r)
+		if err != nil {
+			h.APIResponse.ErrorResponse(w, r, err, errorStatusCode(err, http.StatusBadRequest))
+			return
+		}
+
+		h.APIResponse.SuccessResponse(w, r, bodyMeasurementResponseDTO, http.StatusCreated)
+	}
+}
+
+func (h *bodyMeasurementHandler) GetMeasurements() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		bodyMeasurementsListResponseDTO, err := h.BodyMeasurementService.GetMeasurements(w, r)
+		if err != nil {
+			h.APIResponse.ErrorResponse(w, r, err, errorStatusCode(err, http.StatusInternalServerError))
+			return
+		}
+
+		h.APIResponse.SuccessResponse(w, r, bodyMeasurementsListResponseDTO)
+	}
+}
+
+func (h *bodyMeasurementHandler) GetMeasurement() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		bodyMeasurementResponseDTO, err := h.BodyMeasurementService.GetMeasurement(w, r)
+		if err != nil {
+			h.APIResponse.ErrorResponse(w, r, err, errorStatusCode(err, http.StatusInternalServerError))
+			return
+		}
+
+		h.APIResponse.SuccessResponse(w, r, bodyMeasurementResponseDTO)
+	}
+}
+
+func (h *bodyMeasurementHandler) UpdateMeasurement() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		bodyMeasurementResponseDTO, err := h.BodyMeasurementService.UpdateMeasurement(w, r)
+		if err != nil {
+			h.APIResponse.ErrorResponse(w, r, err, errorStatusCode(err, http.StatusBadRequest))
+			return
+		}
+
+		h.APIResponse.SuccessResponse(w, r, bodyMeasurementResponseDTO)
+	}
+}
+
+func (h *bodyMeasurementHandler) DeleteMeasurement() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		err := h.BodyMeasurementService.DeleteMeasurement(w, r)
+		if err != nil {
+			h.APIResponse.ErrorResponse(w, r, err, errorStatusCode(err, http.StatusInternalServerError))
+			return
+		}
+
+		h.APIResponse.SuccessResponse(w, r, nil)
+	}
+}
+
+func (h *bodyMeasurementHandler) GetMetricSeries() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		bodyMetricSeriesResponseDTO, err := h.BodyMeasurementService.GetMetricSeries(w, r)
+		if err != nil {
+			h.APIResponse.ErrorResponse(w, r, err, errorStatusCode(err, http.StatusBadRequest))
+			return
+		}
+
+		h.APIResponse.SuccessResponse(w, r, bodyMetricSeriesResponseDTO)
+	}
+}
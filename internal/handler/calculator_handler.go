@@ -0,0 +1,82 @@
+package handler
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/brightside-dev/ronin-fitness-be/internal/handler/response"
+	"github.com/brightside-dev/ronin-fitness-be/internal/service"
+)
+
+type CalculatorHandler interface {
+	GetPlateInventory() http.HandlerFunc
+	UpdatePlateInventory() http.HandlerFunc
+	CalculatePlates() http.HandlerFunc
+	GenerateWarmup() http.HandlerFunc
+}
+
+type calculatorHandler struct {
+	APIResponse       response.APIResponseManager
+	DBLogger          *slog.Logger
+	CalculatorService service.CalculatorService
+}
+
+func NewCalculatorHandler(
+	apiResponse response.APIResponseManager,
+	dbLogger *slog.Logger,
+	calculatorService service.CalculatorService,
+) CalculatorHandler {
+	return &calculatorHandler{
+		APIResponse:       apiResponse,
+		DBLogger:          dbLogger,
+		CalculatorService: calculatorService,
+	}
+}
+
+func (h *calculatorHandler) GetPlateInventory() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		plateInventoryResponseDTO, err := h.CalculatorService.GetPlateInventory(w, r)
+		if err != nil {
+			h.APIResponse.ErrorResponse(w, r, err, errorStatusCode(err, http.StatusBadRequest))
+			return
+		}
+
+		h.APIResponse.SuccessResponse(w, r, plateInventoryResponseDTO)
+	}
+}
+
+func (h *calculatorHandler) UpdatePlateInventory() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		plateInventoryResponseDTO, err := h.CalculatorService.UpdatePlateInventory(w, r)
+		if err != nil {
+			h.APIResponse.ErrorResponse(w, r, err, errorStatusCode(err, http.StatusBadRequest))
+			return
+		}
+
+		h.APIResponse.SuccessResponse(w, r, plateInventoryResponseDTO)
+	}
+}
+
+func (h *calculatorHandler) CalculatePlates() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		plateLoadingResponseDTO, err := h.CalculatorService.CalculatePlates(w, r)
+		if err != nil {
+			h.APIResponse.ErrorResponse(w, r, err, errorStatusCode(err, http.StatusBadRequest))
+			return
+		}
+
+		h.APIResponse.SuccessResponse(w, r, plateLoadingResponseDTO)
+	}
+}
+
+func (h *calculatorHandler) GenerateWarmup() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		warmupResponseDTO, err := h.CalculatorService.GenerateWarmup(w, r)
+		if err != nil {
+			h.APIResponse.ErrorResponse(w, r, err, errorStatusCode(err, http.StatusBadRequest))
+			return
+		}
+
+		h.APIResponse.SuccessResponse(w, r, warmupResponseDTO)
+	}
+}
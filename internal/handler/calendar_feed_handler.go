@@ -0,0 +1,80 @@
+package handler
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/brightside-dev/ronin-fitness-be/internal/handler/response"
+	"github.com/brightside-dev/ronin-fitness-be/internal/service"
+)
+
+type CalendarFeedHandler interface {
+	GetFeed() http.HandlerFunc
+	CreateFeed() http.HandlerFunc
+	DeleteFeed() http.HandlerFunc
+	ServeFeed() http.HandlerFunc
+}
+
+type calendarFeedHandler struct {
+	APIResponse         response.APIResponseManager
+	DBLogger            *slog.Logger
+	CalendarFeedService service.CalendarFeedService
+}
+
+func NewCalendarFeedHandler(
+	apiResponse response.APIResponseManager,
+	dbLogger *slog.Logger,
+	calendarFeedService service.CalendarFeedService,
+) CalendarFeedHandler {
+	return &calendarFeedHandler{
+		APIResponse:         apiResponse,
+		DBLogger:            dbLogger,
+		CalendarFeedService: calendarFeedService,
+	}
+}
+
+func (h *calendarFeedHandler) GetFeed() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		calendarFeedResponseDTO, err := h.CalendarFeedService.GetFeed(w, r)
+		if err != nil {
+			h.APIResponse.ErrorResponse(w, r, err, errorStatusCode(err, http.StatusInternalServerError))
+			return
+		}
+
+		h.APIResponse.SuccessResponse(w, r, calendarFeedResponseDTO)
+	}
+}
+
+func (h *calendarFeedHandler) CreateFeed() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		calendarFeedResponseDTO, err := h.CalendarFeedService.CreateFeed(w, r)
+		if err != nil {
+			h.APIResponse.ErrorResponse(w, r, err, errorStatusCode(err, http.StatusInternalServerError))
+			return
+		}
+
+		h.APIResponse.SuccessResponse(w, r, calendarFeedResponseDTO, http.StatusCreated)
+	}
+}
+
+func (h *calendarFeedHandler) DeleteFeed() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		err := h.CalendarFeedService.DeleteFeed(w, r)
+		if err != nil {
+			h.APIResponse.ErrorResponse(w, r, err, errorStatusCode(err, http.StatusInternalServerError))
+			return
+		}
+
+		h.APIResponse.SuccessResponse(w, r, nil)
+	}
+}
+
+// ServeFeed writes the calendar itself, so only errors get a JSON response.
+func (h *calendarFeedHandler) ServeFeed() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		err := h.CalendarFeedService.ServeFeed(w, r)
+		if err != nil {
+			h.APIResponse.ErrorResponse(w, r, err, errorStatusCode(err, http.StatusInternalServerError))
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package handler
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+
+	"github.com/brightside-dev/ronin-fitness-be/internal/handler/response"
+	"github.com/brightside-dev/ronin-fitness-be/internal/service"
+	"github.com/brightside-dev/ronin-fitness-be/internal/util"
+)
+
+type CardioHandler interface {
+	UploadCardio() http.HandlerFunc
+	GetCardioTrack() http.HandlerFunc
+}
+
+type cardioHandler struct {
+	APIResponse   response.APIResponseManager
+	DBLogger      *slog.Logger
+	CardioService service.CardioService
+}
+
+func NewCardioHandler(
+	apiResponse response.APIResponseManager,
+	dbLogger *slog.Logger,
+	cardioService service.CardioService,
+) CardioHandler {
+	return &cardioHandler{
+		APIResponse:   apiResponse,
+		DBLogger:      dbLogger,
+		CardioService: cardioService,
+	}
+}
+
+func (h *cardioHandler) UploadCardio() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		cardioUploadResponseDTO, err := h.CardioService.UploadCardio(w, r)
+		if err != nil {
+			h.APIResponse.ErrorResponse(w, r, err, errorStatusCode(err, http.StatusBadRequest))
+			return
+		}
+
+		h.APIResponse.SuccessResponse(w, r, cardioUploadResponseDTO, http.StatusCreated)
+	}
+}
+
+// GetCardioTrack responds with a bare GeoJSON Feature so map libraries can load the URL directly.
+func (h *cardioHandler) GetCardioTrack() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		cardioTrackResponseDTO, err := h.CardioService.GetCardioTrack(w, r)
+		if err != nil {
+			h.APIResponse.ErrorResponse(w, r, err, errorStatusCode(err, http.StatusBadRequest))
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/geo+json")
+		w.WriteHeader(http.StatusOK)
+		err = json.NewEncoder(w).Encode(cardioTrackResponseDTO)
+		if err != nil {
+			util.LogWithContext(h.DBLogger, slog.LevelError, err.Error(), nil, r)
+		}
+	}
+}
@@ -0,0 +1,24 @@
+package dto
+
+import "time"
+
+type AchievementResponse struct {
+	ID           int        `json:"id"`
+	Code         string     `json:"code"`
+	Name         string     `json:"name"`
+	Description  string     `json:"description"`
+	Metric       string     `json:"metric"`
+	Threshold    float64    `json:"threshold"` // in kg for lift_weight_kg
+	ExerciseName *string    `json:"exercise_name,omitempty"`
+	UnlockedAt   *time.Time `json:"unlocked_at"` // nil while locked
+}
+
+type AchievementsListResponse struct {
+	Achievements  []AchievementResponse `json:"achievements"`
+	UnlockedCount int                   `json:"unlocked_count"`
+}
+
+type ProfileAchievementsResponse struct {
+	ProfileID    int                   `json:"profile_id"`
+	Achievements []AchievementResponse `json:"achievements"` // only the unlocked ones, most recent first
+}
@@ -0,0 +1,76 @@
+package dto
+
+import "time"
+
+// BodyMeasurementRequest creates a measurement or replaces all values of an existing one. Weights
+// and lengths are in the given units, defaulting to the profile's weight unit and its matching
+// length unit.
+type BodyMeasurementRequest struct {
+	MeasuredAt     *time.Time `json:"measured_at"` // defaults to now
+	Weight         *float64   `json:"weight" validate:"omitempty,gt=0,lte=1000"`
+	WeightUnit     string     `json:"weight_unit" validate:"omitempty,oneof=kg lb"`
+	BodyFatPercent *float64   `json:"body_fat_percent" validate:"omitempty,gt=0,lt=100"`
+	LengthUnit     string     `json:"length_unit" validate:"omitempty,oneof=cm in"`
+	Neck           *float64   `json:"neck" validate:"omitempty,gt=0,lte=500"`
+	Shoulders      *float64   `json:"shoulders" validate:"omitempty,gt=0,lte=500"`
+	Chest          *float64   `json:"chest" validate:"omitempty,gt=0,lte=500"`
+	Waist          *float64   `json:"waist" validate:"omitempty,gt=0,lte=500"`
+	Hips           *float64   `json:"hips" validate:"omitempty,gt=0,lte=500"`
+	LeftArm        *float64   `json:"left_arm" validate:"omitempty,gt=0,lte=500"`
+	RightArm       *float64   `json:"right_arm" validate:"omitempty,gt=0,lte=500"`
+	LeftThigh      *float64   `json:"left_thigh" validate:"omitempty,gt=0,lte=500"`
+	RightThigh     *float64   `json:"right_thigh" validate:"omitempty,gt=0,lte=500"`
+	LeftCalf       *float64   `json:"left_calf" validate:"omitempty,gt=0,lte=500"`
+	RightCalf      *float64   `json:"right_calf" validate:"omitempty,gt=0,lte=500"`
+}
+
+type BodyMeasurementResponse struct {
+	ID             int       `json:"id"`
+	MeasuredAt     time.Time `json:"measured_at"`
+	Weight         *float64  `json:"weight"`
+	WeightUnit     string    `json:"weight_unit"`
+	BodyFatPercent *float64  `json:"body_fat_percent"`
+	LengthUnit     string    `json:"length_unit"`
+	Neck           *float64  `json:"neck"`
+	Shoulders      *float64  `json:"shoulders"`
+	Chest          *float64  `json:"chest"`
+	Waist          *float64  `json:"waist"`
+	Hips           *float64  `json:"hips"`
+	LeftArm        *float64  `json:"left_arm"`
+	RightArm       *float64  `json:"right_arm"`
+	LeftThigh      *float64  `json:"left_thigh"`
+	RightThigh     *float64  `json:"right_thigh"`
+	LeftCalf       *float64  `json:"left_calf"`
+	RightCalf      *float64  `json:"right_calf"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+type BodyMeasurementsListResponse struct {
+	Measurements []BodyMeasurementResponse `json:"measurements"`
+	NextCursor   string                    `json:"next_cursor,omitempty"`
+}
+
+type BodyMetricSeriesRequest struct {
+	Metric     string `validate:"required,oneof=weight body_fat neck shoulders chest waist hips left_arm right_arm left_thigh right_thigh left_calf right_calf"`
+	From       string `validate:"omitempty,datetime=2006-01-02"`
+	To         string `validate:"omitempty,datetime=2006-01-02"`
+	WindowDays int    `validate:"min=1,max=90"`
+}
+
+// BodyMetricSeriesResponse has one point per day with a recorded value. The moving average covers
+// the values recorded within the window of days ending on that day.
+type BodyMetricSeriesResponse struct {
+	Metric     string                    `json:"metric"`
+	Unit       string                    `json:"unit"`
+	From       string                    `json:"from"`
+	To         string                    `json:"to"`
+	WindowDays int                       `json:"window_days"`
+	Points     []BodyMetricPointResponse `json:"points"`
+}
+
+type BodyMetricPointResponse struct {
+	Date          string  `json:"date"`
+	Value         float64 `json:"value"` // average of the day when measured more than once
+	MovingAverage float64 `json:"moving_average"`
+}
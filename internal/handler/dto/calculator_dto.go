@@ -0,0 +1,59 @@
+package dto
+
+type PlateInventoryRequest struct {
+	Unit      string         `json:"unit" validate:"omitempty,oneof=kg lb"` // defaults to the profile's weight unit
+	BarWeight float64        `json:"bar_weight" validate:"required,gt=0,lte=100"`
+	Plates    []PlateRequest `json:"plates" validate:"required,min=1,max=20,dive"`
+}
+
+type PlateRequest struct {
+	Weight    float64 `json:"weight" validate:"required,gt=0,lte=100"`
+	PairCount int     `json:"pair_count" validate:"required,min=1,max=20"`
+}
+
+type PlateInventoryResponse struct {
+	Unit      string          `json:"unit"`
+	BarWeight float64         `json:"bar_weight"`
+	Plates    []PlateResponse `json:"plates"`
+	IsDefault bool            `json:"is_default"` // true until the profile saves its own plates
+}
+
+type PlateResponse struct {
+	Weight    float64 `json:"weight"`
+	PairCount int     `json:"pair_count"`
+}
+
+type PlateCalculatorRequest struct {
+	Weight    float64 `validate:"required,gt=0,lte=1000"`
+	BarWeight float64 `validate:"omitempty,gt=0,lte=100"`
+	Unit      string  `validate:"omitempty,oneof=kg lb"`
+}
+
+type PlateLoadingResponse struct {
+	Unit         string               `json:"unit"`
+	TargetWeight float64              `json:"target_weight"`
+	BarWeight    float64              `json:"bar_weight"`
+	LoadedWeight float64              `json:"loaded_weight"` // closest weight not above the target the plates can make
+	IsExact      bool                 `json:"is_exact"`
+	PerSide      []PlateCountResponse `json:"per_side"`
+}
+
+type PlateCountResponse struct {
+	Weight float64 `json:"weight"`
+	Count  int     `json:"count"`
+}
+
+type WarmupResponse struct {
+	Unit          string              `json:"unit"`
+	WorkingWeight float64             `json:"working_weight"`
+	BarWeight     float64             `json:"bar_weight"`
+	Sets          []WarmupSetResponse `json:"sets"`
+}
+
+type WarmupSetResponse struct {
+	SetNumber int                  `json:"set_number"`
+	Weight    float64              `json:"weight"`
+	Reps      int                  `json:"reps"`
+	Percent   int                  `json:"percent"` // of the working weight
+	PerSide   []PlateCountResponse `json:"per_side"`
+}
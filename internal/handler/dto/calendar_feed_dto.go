@@ -0,0 +1,9 @@
+package dto
+
+import "time"
+
+type CalendarFeedResponse struct {
+	Token     string    `json:"token"`
+	URL       string    `json:"url"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,51 @@
+package dto
+
+import "time"
+
+type CardioUploadRequest struct {
+	Name                string `validate:"max=255"`                                           // defaults to the name in the file
+	ActivityType        string `validate:"omitempty,oneof=run ride walk hike swim row other"` // defaults to the sport in the file
+	Visibility          string `validate:"omitempty,oneof=public followers private"`          // defaults to the profile's privacy
+	MentalEnergyLevel   int    `validate:"omitempty,min=1,max=10"`
+	PhysicalEnergyLevel int    `validate:"omitempty,min=1,max=10"`
+}
+
+type CardioSessionResponse struct {
+	ActivityType     string   `json:"activity_type"`
+	SourceFormat     string   `json:"source_format"`
+	DistanceM        float64  `json:"distance_m"`
+	DurationSeconds  int      `json:"duration_seconds"`
+	PaceSecondsPerKm *float64 `json:"pace_seconds_per_km"`
+	ElevationGainM   *float64 `json:"elevation_gain_m"`
+	ElevationLossM   *float64 `json:"elevation_loss_m"`
+	AvgHeartRate     *int     `json:"avg_heart_rate"`
+	MaxHeartRate     *int     `json:"max_heart_rate"`
+	HasTrack         bool     `json:"has_track"`
+}
+
+type CardioUploadResponse struct {
+	WorkoutID int                   `json:"workout_id"`
+	Name      string                `json:"name"`
+	StartDate time.Time             `json:"start_date"`
+	EndDate   time.Time             `json:"end_date"`
+	Cardio    CardioSessionResponse `json:"cardio"`
+}
+
+// CardioTrackResponse is a GeoJSON Feature holding the track as a LineString.
+type CardioTrackResponse struct {
+	Type       string                        `json:"type"`
+	Geometry   CardioTrackGeometryResponse   `json:"geometry"`
+	Properties CardioTrackPropertiesResponse `json:"properties"`
+}
+
+type CardioTrackGeometryResponse struct {
+	Type        string      `json:"type"`
+	Coordinates [][]float64 `json:"coordinates"`
+}
+
+type CardioTrackPropertiesResponse struct {
+	WorkoutID          int    `json:"workout_id"`
+	ActivityType       string `json:"activity_type"`
+	PointCount         int    `json:"point_count"`
+	OriginalPointCount int    `json:"original_point_count"`
+}
@@ -0,0 +1,21 @@
+package dto
+
+import "time"
+
+type ExperiencePointEntryResponse struct {
+	ID        int       `json:"id"`
+	EventType string    `json:"event_type"`
+	Source    string    `json:"source"`
+	Reason    string    `json:"reason"`
+	Points    int       `json:"points"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type ExperiencePointsResponse struct {
+	ExperiencePoints          int                            `json:"experience_points"`
+	Level                     int                            `json:"level"`
+	LevelExperiencePoints     int                            `json:"level_experience_points"`      // the total the current level starts at
+	NextLevelExperiencePoints int                            `json:"next_level_experience_points"` // the total the next level starts at
+	Entries                   []ExperiencePointEntryResponse `json:"entries"`
+	NextCursor                string                         `json:"next_cursor,omitempty"`
+}
@@ -0,0 +1,32 @@
+package dto
+
+import "time"
+
+type WorkoutExportRequest struct {
+	Format string `validate:"required,oneof=csv json ics"`
+	From   string `validate:"omitempty,datetime=2006-01-02"`
+	To     string `validate:"omitempty,datetime=2006-01-02"`
+}
+
+// WorkoutExportResponse is one workout of a JSON export.
+type WorkoutExportResponse struct {
+	ID                  int                        `json:"id"`
+	Name                string                     `json:"name"`
+	Description         string                     `json:"description"`
+	MentalEnergyLevel   int                        `json:"mental_energy_level"`
+	PhysicalEnergyLevel int                        `json:"physical_energy_level"`
+	StartDate           time.Time                  `json:"start_date"`
+	EndDate             time.Time                  `json:"end_date"`
+	Sets                []WorkoutExportSetResponse `json:"sets"`
+}
+
+type WorkoutExportSetResponse struct {
+	ExerciseID         int     `json:"exercise_id"`
+	ExerciseName       string  `json:"exercise_name"`
+	MuscleGroup        string  `json:"muscle_group"`
+	SetNumber          int     `json:"set_number"`
+	Reps               int     `json:"reps"`
+	Weight             float64 `json:"weight"`
+	Duration           int     `json:"duration"`
+	EstimatedOneRepMax float64 `json:"estimated_one_rep_max"`
+}
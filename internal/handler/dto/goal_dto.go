@@ -0,0 +1,37 @@
+package dto
+
+import "time"
+
+// GoalCreateRequest sets a goal. Target is a weight for lift and bodyweight goals, in Unit or the
+// profile's weight unit, and a number of workouts for workouts per week goals.
+type GoalCreateRequest struct {
+	Type        string  `json:"type" validate:"required,oneof=lift_weight lift_e1rm workouts_per_week bodyweight"`
+	ExerciseID  *int    `json:"exercise_id"` // required for lift goals
+	Target      float64 `json:"target" validate:"required,gt=0,lte=1000"`
+	Unit        string  `json:"unit" validate:"omitempty,oneof=kg lb"`
+	TargetWeeks int     `json:"target_weeks" validate:"omitempty,min=1,max=52"` // consecutive weeks, defaults to 1
+	Deadline    string  `json:"deadline" validate:"omitempty,datetime=2006-01-02"`
+}
+
+type GoalResponse struct {
+	ID           int        `json:"id"`
+	Type         string     `json:"type"`
+	Title        string     `json:"title"`
+	ExerciseID   *int       `json:"exercise_id"`
+	ExerciseName string     `json:"exercise_name,omitempty"`
+	Target       float64    `json:"target"`
+	Unit         string     `json:"unit"` // the weight unit, or "workouts"
+	TargetWeeks  int        `json:"target_weeks"`
+	Start        *float64   `json:"start"`
+	Current      float64    `json:"current"`
+	Progress     float64    `json:"progress"` // percentage from 0 to 100
+	Deadline     *string    `json:"deadline"`
+	Status       string     `json:"status"`
+	CompletedAt  *time.Time `json:"completed_at"`
+	CreatedAt    time.Time  `json:"created_at"`
+}
+
+type GoalsListResponse struct {
+	Goals      []GoalResponse `json:"goals"`
+	NextCursor string         `json:"next_cursor,omitempty"`
+}
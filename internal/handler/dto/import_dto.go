@@ -0,0 +1,60 @@
+package dto
+
+import "time"
+
+type WorkoutImportRequest struct {
+	Source string `validate:"omitempty,oneof=strong hevy"` // detected from the header row when empty
+	Unit   string `validate:"omitempty,oneof=kg lb"`       // unit of weights the file does not label, defaults to the profile's
+	DryRun bool
+}
+
+// WorkoutImportResponse describes an import job. For dry runs nothing is saved and the counts are
+// what the import would do; the exercise mapping and a preview of the workouts are included.
+type WorkoutImportResponse struct {
+	ImportID         int                             `json:"import_id,omitempty"`
+	DryRun           bool                            `json:"dry_run"`
+	Status           string                          `json:"status,omitempty"`
+	Source           string                          `json:"source"`
+	FileName         string                          `json:"file_name"`
+	WeightUnit       string                          `json:"weight_unit"`
+	TotalRows        int                             `json:"total_rows"`
+	ImportedWorkouts int                             `json:"imported_workouts"`
+	ImportedSets     int                             `json:"imported_sets"`
+	SkippedWorkouts  int                             `json:"skipped_workouts"` // already imported before
+	CreatedExercises int                             `json:"created_exercises"`
+	ErrorCount       int                             `json:"error_count"`
+	FailureReason    string                          `json:"failure_reason,omitempty"`
+	CreatedAt        *time.Time                      `json:"created_at,omitempty"`
+	CompletedAt      *time.Time                      `json:"completed_at,omitempty"`
+	Exercises        []WorkoutImportExerciseResponse `json:"exercises,omitempty"`
+	Workouts         []WorkoutImportPreviewResponse  `json:"workouts,omitempty"`
+	Errors           []WorkoutImportErrorResponse    `json:"errors,omitempty"`
+}
+
+// WorkoutImportExerciseResponse shows which exercise a name in the file maps onto.
+type WorkoutImportExerciseResponse struct {
+	Name       string `json:"name"`
+	ExerciseID int    `json:"exercise_id,omitempty"` // not set when the exercise would be created
+	IsNew      bool   `json:"is_new"`
+	SetCount   int    `json:"set_count"`
+}
+
+type WorkoutImportPreviewResponse struct {
+	Name          string    `json:"name"`
+	StartDate     time.Time `json:"start_date"`
+	EndDate       time.Time `json:"end_date"`
+	ExerciseCount int       `json:"exercise_count"`
+	SetCount      int       `json:"set_count"`
+	IsDuplicate   bool      `json:"is_duplicate"`
+}
+
+type WorkoutImportErrorResponse struct {
+	LineNumber int    `json:"line_number"`
+	Message    string `json:"message"`
+	RawRow     string `json:"raw_row"`
+}
+
+type WorkoutImportsListResponse struct {
+	Imports    []WorkoutImportResponse `json:"imports"`
+	NextCursor string                  `json:"next_cursor,omitempty"`
+}
@@ -0,0 +1,24 @@
+package dto
+
+import "time"
+
+type LeaderboardRequest struct {
+	Scope  string `validate:"omitempty,oneof=global country following"`
+	Period string `validate:"omitempty,oneof=all_time week month"`
+}
+
+type LeaderboardEntryResponse struct {
+	Rank    int                    `json:"rank"` // tied profiles share a rank
+	Profile ProfileSummaryResponse `json:"profile"`
+	Handle  string                 `json:"handle"`
+	Points  int                    `json:"points"`
+}
+
+type LeaderboardResponse struct {
+	Scope      string                     `json:"scope"`
+	Period     string                     `json:"period"`
+	Since      *time.Time                 `json:"since,omitempty"` // start of the week or month, points earned since count
+	Entries    []LeaderboardEntryResponse `json:"entries"`
+	Me         LeaderboardEntryResponse   `json:"me"` // the caller's standing, even when private and so not listed
+	NextCursor string                     `json:"next_cursor,omitempty"`
+}
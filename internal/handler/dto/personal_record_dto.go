@@ -0,0 +1,27 @@
+package dto
+
+import "time"
+
+type PersonalRecordResponse struct {
+	ID            int       `json:"id"`
+	ExerciseID    int       `json:"exercise_id"`
+	WorkoutID     int       `json:"workout_id"`
+	SetID         int       `json:"set_id"`
+	RecordType    string    `json:"record_type"`
+	Weight        float64   `json:"weight"`
+	Reps          int       `json:"reps"`
+	Value         float64   `json:"value"`
+	PreviousValue *float64  `json:"previous_value"`
+	WeightUnit    string    `json:"weight_unit"` // also the unit of value unless the record counts reps
+	AchievedAt    time.Time `json:"achieved_at"`
+}
+
+type ExerciseRecordsResponse struct {
+	ExerciseID       int                      `json:"exercise_id"`
+	OneRepMaxFormula string                   `json:"one_rep_max_formula"`
+	HeaviestWeight   *PersonalRecordResponse  `json:"heaviest_weight"`
+	BestE1RM         *PersonalRecordResponse  `json:"best_e1rm"`
+	BestVolume       *PersonalRecordResponse  `json:"best_volume"`
+	MostReps         []PersonalRecordResponse `json:"most_reps"`
+	History          []PersonalRecordResponse `json:"history"`
+}
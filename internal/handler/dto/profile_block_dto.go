@@ -0,0 +1,18 @@
+package dto
+
+import "time"
+
+// ProfileRestrictionRequest blocks or mutes a profile as the caller.
+type ProfileRestrictionRequest struct {
+	ProfileID int `json:"profile_id" validate:"required"`
+}
+
+type RestrictedProfileResponse struct {
+	Profile   ProfileSummaryResponse `json:"profile"`
+	CreatedAt time.Time              `json:"created_at"`
+}
+
+type RestrictedProfilesListResponse struct {
+	Profiles   []RestrictedProfileResponse `json:"profiles"`
+	NextCursor string                      `json:"next_cursor,omitempty"`
+}
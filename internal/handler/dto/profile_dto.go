@@ -1,43 +1,120 @@
 package dto
 
+import "time"
+
 type ProfileRequest struct {
 	UserID int `json:"user_id" validate:"required"`
 }
 
 type MyProfileResponse struct {
-	ProfileID         int    `json:"id"`
-	DisplayName       string `json:"display_name"`
-	AvatarVersion     int    `json:"avatar_version"`
-	Privacy           string `json:"privacy"`
-	FitnessExperience string `json:"fitness_experience"`
-	ExperiencePoints  int    `json:"experience_points"`
+	ProfileID         int        `json:"id"`
+	DisplayName       string     `json:"display_name"`
+	Handle            string     `json:"handle"`
+	HandleChangeAfter *time.Time `json:"handle_change_after,omitempty"` // set while a recent change stops the handle being changed again
+	AvatarVersion     int        `json:"avatar_version"`
+	Privacy           string     `json:"privacy"`
+	Role              string     `json:"role"`
+	FitnessExperience string     `json:"fitness_experience"`
+	OneRepMaxFormula  string     `json:"one_rep_max_formula"`
+	WeightUnit        string     `json:"weight_unit"`
+	WeightIncrement   float64    `json:"weight_increment"`
+	ExperiencePoints  int        `json:"experience_points"`
+	Level             int        `json:"level"`
 }
 
 type ProfileResponse struct {
-	ProfileID         int          `json:"id"`
-	DisplayName       string       `json:"display_name"`
-	AvatarVersion     int          `json:"avatar_version"`
-	Privacy           string       `json:"privacy"`
-	FitnessExperience string       `json:"fitness_experience"`
-	ExperiencePoints  int          `json:"experience_points"`
-	User              UserResponse `json:"user"`
+	ProfileID         int                         `json:"id"`
+	DisplayName       string                      `json:"display_name"`
+	Handle            string                      `json:"handle"`
+	RedirectedFrom    string                      `json:"redirected_from,omitempty"` // the old handle the profile was looked up by
+	AvatarVersion     int                         `json:"avatar_version"`
+	Privacy           string                      `json:"privacy"`
+	Role              string                      `json:"role"`
+	FitnessExperience string                      `json:"fitness_experience"`
+	ExperiencePoints  int                         `json:"experience_points"`
+	Level             int                         `json:"level"`
+	User              UserResponse                `json:"user"`
+	FollowersCount    int                         `json:"followers_count"`
+	FollowingCount    int                         `json:"following_count"`
+	Relationship      ProfileRelationshipResponse `json:"relationship"`
+}
+
+// ProfileRelationshipResponse describes how the caller and a profile are connected.
+type ProfileRelationshipResponse struct {
+	IsSelf     bool `json:"is_self"`
+	Following  bool `json:"following"`   // the caller follows the profile
+	FollowedBy bool `json:"followed_by"` // the profile follows the caller
+	Requested  bool `json:"requested"`   // the caller's request to follow the private profile is pending
+}
+
+// ProfileHandleRequest changes the caller's handle. It is matched case-insensitively and a leading @
+// is ignored.
+type ProfileHandleRequest struct {
+	Handle string `json:"handle" validate:"required"`
 }
 
 type ProfileUpdateRequest struct {
-	UserID                 int    `json:"user_id" validate:"required"`
-	ProfileID              int    `json:"profile_id" validate:"required"`
-	DisplayName            string `json:"display_name" `
-	AvatarVersion          int    `json:"avatar_version"`
-	IsNotificationsEnabled bool   `json:"is_notifications_enabled"`
-	Privacy                string `json:"privacy"`
-	FitnessExperience      string `json:"fitness_experience"`
+	DisplayName            string   `json:"display_name" `
+	AvatarVersion          int      `json:"avatar_version"`
+	IsNotificationsEnabled *bool    `json:"is_notifications_enabled"`
+	Privacy                string   `json:"privacy" validate:"omitempty,oneof=public private"`
+	FitnessExperience      string   `json:"fitness_experience"`
+	OneRepMaxFormula       string   `json:"one_rep_max_formula" validate:"omitempty,oneof=epley brzycki"`
+	WeightUnit             string   `json:"weight_unit" validate:"omitempty,oneof=kg lb"`
+	WeightIncrement        *float64 `json:"weight_increment" validate:"omitempty,gt=0,lte=50"` // in the weight unit
 }
 
+// FollowProfilesRequest follows or unfollows a profile as the caller. FollowerProfileID may be
+// left out, if sent it must be the caller's own profile.
 type FollowProfilesRequest struct {
 	FollowingProfileID int `json:"following_profile_id" validate:"required"`
-	FollowerProfileID  int `json:"follower_profile_id" validate:"required"`
+	FollowerProfileID  int `json:"follower_profile_id"`
 }
 
 type FollowProfilesResponse struct {
-	FollowingProfileID int `json:"following_profile_id"`
+	FollowingProfileID int    `json:"following_profile_id"`
+	Status             string `json:"status,omitempty"`            // following, or requested for private profiles
+	FollowRequestID    *int   `json:"follow_request_id,omitempty"` // set while the request is pending
+}
+
+type FollowRequestResponse struct {
+	ID        int                    `json:"id"`
+	Profile   ProfileSummaryResponse `json:"profile"` // the requester, or the requested profile for sent requests
+	CreatedAt time.Time              `json:"created_at"`
+}
+
+type FollowRequestsListResponse struct {
+	FollowRequests []FollowRequestResponse `json:"follow_requests"`
+	NextCursor     string                  `json:"next_cursor,omitempty"`
+}
+
+type ProfileSummaryResponse struct {
+	ProfileID     int    `json:"id"`
+	DisplayName   string `json:"display_name"`
+	AvatarVersion int    `json:"avatar_version"`
+}
+
+type ProfileFollowResponse struct {
+	Profile     ProfileSummaryResponse `json:"profile"`
+	FollowedAt  time.Time              `json:"followed_at"`
+	IsFollowing bool                   `json:"is_following"` // the caller follows this profile
+	IsMutual    bool                   `json:"is_mutual"`    // this profile and the caller follow each other
+}
+
+type ProfileSearchResultResponse struct {
+	Profile              ProfileSummaryResponse `json:"profile"`
+	Handle               string                 `json:"handle"`
+	Privacy              string                 `json:"privacy"`
+	IsFollowing          bool                   `json:"is_following"`           // the caller follows this profile
+	MutualFollowersCount int                    `json:"mutual_followers_count"` // profiles the caller follows that follow this profile
+}
+
+type ProfileSearchResponse struct {
+	Profiles   []ProfileSearchResultResponse `json:"profiles"`
+	NextCursor string                        `json:"next_cursor,omitempty"`
+}
+
+type ProfileFollowsListResponse struct {
+	Profiles   []ProfileFollowResponse `json:"profiles"`
+	NextCursor string                  `json:"next_cursor,omitempty"`
 }
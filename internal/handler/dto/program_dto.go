@@ -0,0 +1,89 @@
+package dto
+
+import "time"
+
+type ProgramCreateRequest struct {
+	Name          string                   `json:"name" validate:"required,max=255"`
+	Description   string                   `json:"description"`
+	DurationWeeks int                      `json:"duration_weeks" validate:"required,min=1,max=52"`
+	Unit          string                   `json:"unit" validate:"omitempty,oneof=kg lb"` // unit of the rule increments, defaults to the profile's
+	Days          []ProgramDayRequest      `json:"days" validate:"required,min=1,dive"`
+	Rules         []ProgressionRuleRequest `json:"rules" validate:"dive"`
+}
+
+type ProgramDayRequest struct {
+	WeekNumber int `json:"week_number" validate:"required,min=1"`
+	DayNumber  int `json:"day_number" validate:"required,min=1,max=7"`
+	TemplateID int `json:"template_id" validate:"required"`
+}
+
+type ProgressionRuleRequest struct {
+	ExerciseID         int     `json:"exercise_id" validate:"required"`
+	RuleType           string  `json:"rule_type" validate:"required,oneof=linear percent_of_training_max"`
+	Increment          float64 `json:"increment" validate:"min=0"`
+	TrainingMaxPercent float64 `json:"training_max_percent" validate:"omitempty,gt=0,lte=100"`
+	// WeekPercentages holds, for each week of the cycle, the percent of training max for each set
+	WeekPercentages [][]float64 `json:"week_percentages" validate:"required_if=RuleType percent_of_training_max,dive,min=1,dive,gt=0,lte=150"`
+}
+
+type ProgramResponse struct {
+	ID            int                       `json:"id"`
+	ProfileID     int                       `json:"profile_id"`
+	Name          string                    `json:"name"`
+	Description   string                    `json:"description"`
+	DurationWeeks int                       `json:"duration_weeks"`
+	Status        string                    `json:"status"`
+	PublishedAt   *time.Time                `json:"published_at"`
+	Days          []ProgramDayResponse      `json:"days,omitempty"`
+	Rules         []ProgressionRuleResponse `json:"rules,omitempty"`
+	WeightUnit    string                    `json:"weight_unit,omitempty"`
+	CreatedAt     time.Time                 `json:"created_at"`
+	UpdatedAt     time.Time                 `json:"updated_at"`
+}
+
+type ProgramDayResponse struct {
+	ID           int    `json:"id"`
+	WeekNumber   int    `json:"week_number"`
+	DayNumber    int    `json:"day_number"`
+	TemplateID   int    `json:"template_id"`
+	TemplateName string `json:"template_name"`
+}
+
+type ProgressionRuleResponse struct {
+	ExerciseID         int         `json:"exercise_id"`
+	RuleType           string      `json:"rule_type"`
+	Increment          float64     `json:"increment"`
+	TrainingMaxPercent float64     `json:"training_max_percent"`
+	WeekPercentages    [][]float64 `json:"week_percentages,omitempty"`
+}
+
+type ProgramsListResponse struct {
+	Programs   []ProgramResponse `json:"programs"`
+	NextCursor string            `json:"next_cursor,omitempty"`
+}
+
+type ProgramEnrollmentResponse struct {
+	ID             int        `json:"id"`
+	ProgramID      int        `json:"program_id"`
+	ProgramName    string     `json:"program_name"`
+	Status         string     `json:"status"`
+	CurrentSession int        `json:"current_session"`
+	TotalSessions  int        `json:"total_sessions"`
+	StartedAt      time.Time  `json:"started_at"`
+	PausedAt       *time.Time `json:"paused_at"`
+	CompletedAt    *time.Time `json:"completed_at"`
+}
+
+type ProgramEnrollmentsListResponse struct {
+	Enrollments []ProgramEnrollmentResponse `json:"enrollments"`
+}
+
+// ProgramSessionResponse is the next workout of an enrollment with target weights computed by the
+// program's progression rules. Posting it back with program_enrollment_id completes the session.
+type ProgramSessionResponse struct {
+	WorkoutDraftResponse
+	ProgramEnrollmentID int `json:"program_enrollment_id"`
+	ProgramDayID        int `json:"program_day_id"`
+	WeekNumber          int `json:"week_number"`
+	DayNumber           int `json:"day_number"`
+}
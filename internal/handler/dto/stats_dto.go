@@ -0,0 +1,46 @@
+package dto
+
+type StatsRequest struct {
+	From   string `validate:"omitempty,datetime=2006-01-02"`
+	To     string `validate:"omitempty,datetime=2006-01-02"`
+	Bucket string `validate:"omitempty,oneof=day week month"`
+}
+
+type StatsResponse struct {
+	From    string                `json:"from"`
+	To      string                `json:"to"`
+	Bucket  string                `json:"bucket"`
+	Unit    string                `json:"weight_unit"`
+	Totals  StatsBucketResponse   `json:"totals"`
+	Buckets []StatsBucketResponse `json:"buckets"`
+	Streaks StatsStreaksResponse  `json:"streaks"`
+	// Bodyweight is the last one recorded by the end of the range, nil if none was
+	Bodyweight       *float64                        `json:"bodyweight"`
+	RelativeStrength []StatsRelativeStrengthResponse `json:"relative_strength"`
+}
+
+type StatsBucketResponse struct {
+	Start                string         `json:"start,omitempty"`
+	WorkoutCount         int            `json:"workout_count"`
+	TotalDurationSeconds int            `json:"total_duration_seconds"`
+	TotalVolume          float64        `json:"total_volume"`
+	TotalSets            int            `json:"total_sets"`
+	SetsPerMuscleGroup   map[string]int `json:"sets_per_muscle_group"`
+	AvgMentalEnergy      float64        `json:"avg_mental_energy"`
+	AvgPhysicalEnergy    float64        `json:"avg_physical_energy"`
+}
+
+type StatsStreaksResponse struct {
+	CurrentDays  int `json:"current_days"`
+	LongestDays  int `json:"longest_days"`
+	CurrentWeeks int `json:"current_weeks"`
+}
+
+// StatsRelativeStrengthResponse compares the best estimated one rep max of one of the heaviest lifts
+// within the range to the bodyweight.
+type StatsRelativeStrengthResponse struct {
+	ExerciseID      int     `json:"exercise_id"`
+	Name            string  `json:"name"`
+	BestE1RM        float64 `json:"best_e1rm"`
+	BodyweightRatio float64 `json:"bodyweight_ratio"`
+}
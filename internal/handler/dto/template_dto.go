@@ -0,0 +1,67 @@
+package dto
+
+import "time"
+
+type TemplateCreateRequest struct {
+	Name        string               `json:"name" validate:"required,max=255"`
+	Description string               `json:"description"`
+	Unit        string               `json:"unit" validate:"omitempty,oneof=kg lb"` // defaults to the profile's weight unit
+	Sets        []TemplateSetRequest `json:"sets" validate:"required,min=1,dive"`
+}
+
+type TemplateSetRequest struct {
+	ExerciseID     int     `json:"exercise_id" validate:"required"`
+	SetNumber      int     `json:"set_number" validate:"required,min=1"`
+	TargetReps     int     `json:"target_reps" validate:"min=0"`
+	TargetWeight   float64 `json:"target_weight" validate:"min=0"`
+	TargetDuration int     `json:"target_duration" validate:"min=0"`
+}
+
+type TemplateFromWorkoutRequest struct {
+	Name string `json:"name" validate:"max=255"` // defaults to the workout name
+}
+
+type TemplateResponse struct {
+	ID               int                        `json:"id"`
+	ProfileID        int                        `json:"profile_id"`
+	Name             string                     `json:"name"`
+	Description      string                     `json:"description"`
+	ShareToken       *string                    `json:"share_token,omitempty"` // only returned to the owner
+	SourceTemplateID *int                       `json:"source_template_id"`
+	Exercises        []TemplateExerciseResponse `json:"exercises"`
+	WeightUnit       string                     `json:"weight_unit"`
+	CreatedAt        time.Time                  `json:"created_at"`
+	UpdatedAt        time.Time                  `json:"updated_at"`
+}
+
+type TemplateExerciseResponse struct {
+	ExerciseID int                   `json:"exercise_id"`
+	Name       string                `json:"name"`
+	IconName   string                `json:"icon_name"`
+	Sets       []TemplateSetResponse `json:"sets"`
+}
+
+type TemplateSetResponse struct {
+	SetNumber      int     `json:"set_number"`
+	TargetReps     int     `json:"target_reps"`
+	TargetWeight   float64 `json:"target_weight"`
+	TargetDuration int     `json:"target_duration"`
+}
+
+type TemplatesListResponse struct {
+	Templates []TemplateResponse `json:"templates"`
+}
+
+type TemplateShareResponse struct {
+	ShareToken string `json:"share_token"`
+	ShareURL   string `json:"share_url"`
+}
+
+// WorkoutDraftResponse is a workout pre-filled from a template. Its sets can be posted back
+// unchanged as part of a WorkoutCreateRequest.
+type WorkoutDraftResponse struct {
+	TemplateID  int          `json:"template_id"`
+	Name        string       `json:"name"`
+	Description string       `json:"description"`
+	Sets        []SetRequest `json:"sets"`
+}
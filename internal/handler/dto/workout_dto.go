@@ -0,0 +1,92 @@
+package dto
+
+import "time"
+
+type WorkoutResponse struct {
+	ID                  int                              `json:"id"`
+	ProfileID           int                              `json:"profile_id"`
+	Name                string                           `json:"name"`
+	Description         string                           `json:"description"`
+	Visibility          string                           `json:"visibility"`
+	MentalEnergyLevel   int                              `json:"mental_energy_level"`
+	PhysicalEnergyLevel int                              `json:"physical_energy_level"`
+	StartDate           time.Time                        `json:"start_date"`
+	EndDate             time.Time                        `json:"end_date"`
+	Exercises           []WorkoutExerciseSummaryResponse `json:"exercises"`
+	Reactions           WorkoutReactionsSummary          `json:"reactions"`
+	CommentCount        int                              `json:"comment_count"`
+	PhotoCount          int                              `json:"photo_count"`
+	WeightUnit          string                           `json:"weight_unit"`
+	Cardio              *CardioSessionResponse           `json:"cardio,omitempty"`
+}
+
+type WorkoutExerciseSummaryResponse struct {
+	ExerciseID int     `json:"exercise_id"`
+	Name       string  `json:"name"`
+	IconName   string  `json:"icon_name"`
+	SetCount   int     `json:"set_count"`
+	TotalReps  int     `json:"total_reps"`
+	MaxWeight  float64 `json:"max_weight"`
+}
+
+type FeedWorkoutResponse struct {
+	WorkoutResponse
+	Profile ProfileSummaryResponse `json:"profile"`
+}
+
+type FeedResponse struct {
+	Workouts   []FeedWorkoutResponse `json:"workouts"`
+	NextCursor string                `json:"next_cursor,omitempty"`
+}
+
+type WorkoutReactionsSummary struct {
+	Counts      map[string]int `json:"counts"`
+	MyReactions []string       `json:"my_reactions"`
+}
+
+type WorkoutVisibilityRequest struct {
+	Visibility string `json:"visibility" validate:"required,oneof=public followers private"`
+}
+
+type WorkoutReactionRequest struct {
+	Reaction string `validate:"required,oneof=like bicep_flex fire cold star"`
+}
+
+type WorkoutReactionProfileResponse struct {
+	ProfileID     int       `json:"profile_id"`
+	DisplayName   string    `json:"display_name"`
+	AvatarVersion int       `json:"avatar_version"`
+	Reaction      string    `json:"reaction"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+type WorkoutReactionsListResponse struct {
+	Reactions  []WorkoutReactionProfileResponse `json:"reactions"`
+	NextCursor string                           `json:"next_cursor,omitempty"`
+}
+
+type WorkoutCreateRequest struct {
+	Name                string       `json:"name" validate:"required,max=255"`
+	Description         string       `json:"description"`
+	Visibility          string       `json:"visibility" validate:"omitempty,oneof=public followers private"` // defaults to the profile's privacy
+	MentalEnergyLevel   int          `json:"mental_energy_level" validate:"required,min=1,max=10"`
+	PhysicalEnergyLevel int          `json:"physical_energy_level" validate:"required,min=1,max=10"`
+	StartDate           time.Time    `json:"start_date" validate:"required"`
+	EndDate             time.Time    `json:"end_date" validate:"required,gtfield=StartDate"`
+	Sets                []SetRequest `json:"sets" validate:"dive"`
+	ProgramEnrollmentID *int         `json:"program_enrollment_id"` // completes the enrollment's current session
+}
+
+type SetRequest struct {
+	ExerciseID int     `json:"exercise_id" validate:"required"`
+	SetNumber  int     `json:"set_number" validate:"required,min=1"`
+	Duration   int     `json:"duration" validate:"min=0"`
+	Weight     float64 `json:"weight" validate:"min=0"`
+	Unit       string  `json:"unit" validate:"omitempty,oneof=kg lb"` // defaults to the profile's weight unit
+	Reps       int     `json:"reps" validate:"min=0"`
+}
+
+type WorkoutCreateResponse struct {
+	WorkoutResponse
+	PersonalRecords []PersonalRecordResponse `json:"personal_records"`
+}
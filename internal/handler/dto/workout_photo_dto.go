@@ -0,0 +1,19 @@
+package dto
+
+import "time"
+
+type WorkoutPhotoResponse struct {
+	ID           int       `json:"id"`
+	WorkoutID    int       `json:"workout_id"`
+	URL          string    `json:"url"`
+	ThumbnailURL string    `json:"thumbnail_url"`
+	ContentType  string    `json:"content_type"`
+	Width        int       `json:"width"`
+	Height       int       `json:"height"`
+	ExpiresAt    time.Time `json:"expires_at"` // when the URLs stop working
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+type WorkoutPhotosResponse struct {
+	Photos []WorkoutPhotoResponse `json:"photos"`
+}
@@ -0,0 +1,52 @@
+package dto
+
+import "time"
+
+type WorkoutSessionStartRequest struct {
+	Name                string     `json:"name" validate:"required,max=255"`
+	Description         string     `json:"description"`
+	StartedAt           *time.Time `json:"started_at"`            // defaults to now
+	ProgramEnrollmentID *int       `json:"program_enrollment_id"` // completes the enrollment's current session on finish
+}
+
+type WorkoutSessionSetRequest struct {
+	ExerciseID int     `json:"exercise_id" validate:"required"`
+	SetNumber  int     `json:"set_number" validate:"omitempty,min=1"` // defaults to the next set of the exercise
+	Duration   int     `json:"duration" validate:"min=0"`
+	Weight     float64 `json:"weight" validate:"min=0"`
+	Unit       string  `json:"unit" validate:"omitempty,oneof=kg lb"` // defaults to the profile's weight unit
+	Reps       int     `json:"reps" validate:"min=0"`
+}
+
+type WorkoutSessionFinishRequest struct {
+	Name                string  `json:"name" validate:"max=255"` // defaults to the name the session was started with
+	Description         *string `json:"description"`
+	Visibility          string  `json:"visibility" validate:"omitempty,oneof=public followers private"` // defaults to the profile's privacy
+	MentalEnergyLevel   int     `json:"mental_energy_level" validate:"required,min=1,max=10"`
+	PhysicalEnergyLevel int     `json:"physical_energy_level" validate:"required,min=1,max=10"`
+}
+
+type WorkoutSessionResponse struct {
+	ID                  int                         `json:"id"`
+	Name                string                      `json:"name"`
+	Description         string                      `json:"description"`
+	Status              string                      `json:"status"`
+	ProgramEnrollmentID *int                        `json:"program_enrollment_id"`
+	StartedAt           time.Time                   `json:"started_at"`
+	PausedAt            *time.Time                  `json:"paused_at"`
+	ActiveSeconds       int                         `json:"active_seconds"` // time trained so far, leaving out pauses
+	ExpiresAt           time.Time                   `json:"expires_at"`     // when the session is closed unless there is new activity
+	WorkoutID           *int                        `json:"workout_id"`
+	WeightUnit          string                      `json:"weight_unit"`
+	Sets                []WorkoutSessionSetResponse `json:"sets"`
+}
+
+type WorkoutSessionSetResponse struct {
+	ID         int       `json:"id"`
+	ExerciseID int       `json:"exercise_id"`
+	SetNumber  int       `json:"set_number"`
+	Duration   int       `json:"duration"`
+	Weight     float64   `json:"weight"`
+	Reps       int       `json:"reps"`
+	CreatedAt  time.Time `json:"created_at"`
+}
@@ -15,6 +15,12 @@ var (
 	ErrMissingParam     = fmt.Errorf("missing required parameter")
 	ErrInvalidParamType = fmt.Errorf("invalid parameter type")
 	ErrNothingToUpdate  = fmt.Errorf("nothing to update")
+	ErrInvalidCursor    = fmt.Errorf("invalid cursor")
+
+	// Resource errors
+	ErrNotFound  = fmt.Errorf("resource not found")
+	ErrForbidden = fmt.Errorf("you do not have access to this resource")
+	ErrConflict  = fmt.Errorf("resource is in a conflicting state")
 )
 
 // Use this function for system errors that will be logged
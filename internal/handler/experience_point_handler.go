@@ -0,0 +1,43 @@
+package handler
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/brightside-dev/ronin-fitness-be/internal/handler/response"
+	"github.com/brightside-dev/ronin-fitness-be/internal/service"
+)
+
+type ExperiencePointHandler interface {
+	GetMyExperiencePoints() http.HandlerFunc
+}
+
+type experiencePointHandler struct {
+	APIResponse            response.APIResponseManager
+	DBLogger               *slog.Logger
+	ExperiencePointService service.ExperiencePointService
+}
+
+func NewExperiencePointHandler(
+	apiResponse response.APIResponseManager,
+	dbLogger *slog.Logger,
+	experiencePointService service.ExperiencePointService,
+) ExperiencePointHandler {
+	return &experiencePointHandler{
+		APIResponse:            apiResponse,
+		DBLogger:               dbLogger,
+		ExperiencePointService: experiencePointService,
+	}
+}
+
+func (h *experiencePointHandler) GetMyExperiencePoints() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		experiencePointsResponseDTO, err := h.ExperiencePointService.GetMyExperiencePoints(w, r)
+		if err != nil {
+			h.APIResponse.ErrorResponse(w, r, err, errorStatusCode(err, http.StatusInternalServerError))
+			return
+		}
+
+		h.APIResponse.SuccessResponse(w, r, experiencePointsResponseDTO)
+	}
+}
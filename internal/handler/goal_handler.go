@@ -0,0 +1,82 @@
+package handler
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/brightside-dev/ronin-fitness-be/internal/handler/response"
+	"github.com/brightside-dev/ronin-fitness-be/internal/service"
+)
+
+type GoalHandler interface {
+	CreateGoal() http.HandlerFunc
+	GetGoals() http.HandlerFunc
+	GetGoal() http.HandlerFunc
+	DeleteGoal() http.HandlerFunc
+}
+
+type goalHandler struct {
+	APIResponse response.APIResponseManager
+	DBLogger    *slog.Logger
+	GoalService service.GoalService
+}
+
+func NewGoalHandler(
+	apiResponse response.APIResponseManager,
+	dbLogger *slog.Logger,
+	goalService service.GoalService,
+) GoalHandler {
+	return &goalHandler{
+		APIResponse: apiResponse,
+		DBLogger:    dbLogger,
+		GoalService: goalService,
+	}
+}
+
+func (h *goalHandler) CreateGoal() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		goalResponseDTO, err := h.GoalService.CreateGoal(w, r)
+		if err != nil {
+			h.APIResponse.ErrorResponse(w, r, err, errorStatusCode(err, http.StatusBadRequest))
+			return
+		}
+
+		h.APIResponse.SuccessResponse(w, r, goalResponseDTO, http.StatusCreated)
+	}
+}
+
+func (h *goalHandler) GetGoals() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		goalsListResponseDTO, err := h.GoalService.GetGoals(w, r)
+		if err != nil {
+			h.APIResponse.ErrorResponse(w, r, err, errorStatusCode(err, http.StatusBadRequest))
+			return
+		}
+
+		h.APIResponse.SuccessResponse(w, r, goalsListResponseDTO)
+	}
+}
+
+func (h *goalHandler) GetGoal() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		goalResponseDTO, err := h.GoalService.GetGoal(w, r)
+		if err != nil {
+			h.APIResponse.ErrorResponse(w, r, err, errorStatusCode(err, http.StatusInternalServerError))
+			return
+		}
+
+		h.APIResponse.SuccessResponse(w, r, goalResponseDTO)
+	}
+}
+
+func (h *goalHandler) DeleteGoal() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		err := h.GoalService.DeleteGoal(w, r)
+		if err != nil {
+			h.APIResponse.ErrorResponse(w, r, err, errorStatusCode(err, http.StatusInternalServerError))
+			return
+		}
+
+		h.APIResponse.SuccessResponse(w, r, nil)
+	}
+}
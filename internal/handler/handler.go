@@ -0,0 +1,25 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	customError "github.com/brightside-dev/ronin-fitness-be/internal/handler/error"
+)
+
+// errorStatusCode maps the resource errors returned by services to their HTTP status,
+// falling back to the handler's default status for everything else.
+func errorStatusCode(err error, fallback int) int {
+	switch {
+	case errors.Is(err, customError.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, customError.ErrForbidden):
+		return http.StatusForbidden
+	case errors.Is(err, customError.ErrConflict):
+		return http.StatusConflict
+	case errors.Is(err, customError.ErrUnAuthorized):
+		return http.StatusUnauthorized
+	default:
+		return fallback
+	}
+}
@@ -0,0 +1,43 @@
+package handler
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/brightside-dev/ronin-fitness-be/internal/handler/response"
+	"github.com/brightside-dev/ronin-fitness-be/internal/service"
+)
+
+type LeaderboardHandler interface {
+	GetLeaderboard() http.HandlerFunc
+}
+
+type leaderboardHandler struct {
+	APIResponse        response.APIResponseManager
+	DBLogger           *slog.Logger
+	LeaderboardService service.LeaderboardService
+}
+
+func NewLeaderboardHandler(
+	apiResponse response.APIResponseManager,
+	dbLogger *slog.Logger,
+	leaderboardService service.LeaderboardService,
+) LeaderboardHandler {
+	return &leaderboardHandler{
+		APIResponse:        apiResponse,
+		DBLogger:           dbLogger,
+		LeaderboardService: leaderboardService,
+	}
+}
+
+func (h *leaderboardHandler) GetLeaderboard() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		leaderboardResponseDTO, err := h.LeaderboardService.GetLeaderboard(w, r)
+		if err != nil {
+			h.APIResponse.ErrorResponse(w, r, err, errorStatusCode(err, http.StatusBadRequest))
+			return
+		}
+
+		h.APIResponse.SuccessResponse(w, r, leaderboardResponseDTO)
+	}
+}
@@ -0,0 +1,69 @@
+package handler
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/brightside-dev/ronin-fitness-be/internal/handler/response"
+	"github.com/brightside-dev/ronin-fitness-be/internal/service"
+)
+
+type NotificationHandler interface {
+	GetNotifications() http.HandlerFunc
+	MarkRead() http.HandlerFunc
+	MarkAllRead() http.HandlerFunc
+}
+
+type notificationHandler struct {
+	APIResponse         response.APIResponseManager
+	DBLogger            *slog.Logger
+	NotificationService service.NotificationService
+}
+
+func NewNotificationHandler(
+	apiResponse response.APIResponseManager,
+	dbLogger *slog.Logger,
+	notificationService service.NotificationService,
+) NotificationHandler {
+	return &notificationHandler{
+		APIResponse:         apiResponse,
+		DBLogger:            dbLogger,
+		NotificationService: notificationService,
+	}
+}
+
+func (h *notificationHandler) GetNotifications() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		notificationsListResponseDTO, err := h.NotificationService.GetNotifications(w, r)
+		if err != nil {
+			h.APIResponse.ErrorResponse(w, r, err, errorStatusCode(err, http.StatusInternalServerError))
+			return
+		}
+
+		h.APIResponse.SuccessResponse(w, r, notificationsListResponseDTO)
+	}
+}
+
+func (h *notificationHandler) MarkRead() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		err := h.NotificationService.MarkRead(w, r)
+		if err != nil {
+			h.APIResponse.ErrorResponse(w, r, err, errorStatusCode(err, http.StatusInternalServerError))
+			return
+		}
+
+		h.APIResponse.SuccessResponse(w, r, nil)
+	}
+}
+
+func (h *notificationHandler) MarkAllRead() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		err := h.NotificationService.MarkAllRead(w, r)
+		if err != nil {
+			h.APIResponse.ErrorResponse(w, r, err, errorStatusCode(err, http.StatusInternalServerError))
+			return
+		}
+
+		h.APIResponse.SuccessResponse(w, r, nil)
+	}
+}
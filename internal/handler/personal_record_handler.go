@@ -0,0 +1,43 @@
+package handler
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/brightside-dev/ronin-fitness-be/internal/handler/response"
+	"github.com/brightside-dev/ronin-fitness-be/internal/service"
+)
+
+type PersonalRecordHandler interface {
+	GetExerciseRecords() http.HandlerFunc
+}
+
+type personalRecordHandler struct {
+	APIResponse           response.APIResponseManager
+	DBLogger              *slog.Logger
+	PersonalRecordService service.PersonalRecordService
+}
+
+func NewPersonalRecordHandler(
+	apiResponse response.APIResponseManager,
+	dbLogger *slog.Logger,
+	personalRecordService service.PersonalRecordService,
+) PersonalRecordHandler {
+	return &personalRecordHandler{
+		APIResponse:           apiResponse,
+		DBLogger:              dbLogger,
+		PersonalRecordService: personalRecordService,
+	}
+}
+
+func (h *personalRecordHandler) GetExerciseRecords() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		exerciseRecordsDTO, err := h.PersonalRecordService.GetExerciseRecords(w, r)
+		if err != nil {
+			h.APIResponse.ErrorResponse(w, r, err, errorStatusCode(err, http.StatusBadRequest))
+			return
+		}
+
+		h.APIResponse.SuccessResponse(w, r, exerciseRecordsDTO)
+	}
+}
@@ -0,0 +1,108 @@
+package handler
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/brightside-dev/ronin-fitness-be/internal/handler/response"
+	"github.com/brightside-dev/ronin-fitness-be/internal/service"
+)
+
+type ProfileBlockHandler interface {
+	BlockProfile() http.HandlerFunc
+	UnblockProfile() http.HandlerFunc
+	GetBlockedProfiles() http.HandlerFunc
+	MuteProfile() http.HandlerFunc
+	UnmuteProfile() http.HandlerFunc
+	GetMutedProfiles() http.HandlerFunc
+}
+
+type profileBlockHandler struct {
+	APIResponse         response.APIResponseManager
+	DBLogger            *slog.Logger
+	ProfileBlockService service.ProfileBlockService
+}
+
+func NewProfileBlockHandler(
+	apiResponse response.APIResponseManager,
+	dbLogger *slog.Logger,
+	profileBlockService service.ProfileBlockService,
+) ProfileBlockHandler {
+	return &profileBlockHandler{
+		APIResponse:         apiResponse,
+		DBLogger:            dbLogger,
+		ProfileBlockService: profileBlockService,
+	}
+}
+
+func (h *profileBlockHandler) BlockProfile() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		err := h.ProfileBlockService.BlockProfile(w, r)
+		if err != nil {
+			h.APIResponse.ErrorResponse(w, r, err, errorStatusCode(err, http.StatusBadRequest))
+			return
+		}
+
+		h.APIResponse.SuccessResponse(w, r, nil)
+	}
+}
+
+func (h *profileBlockHandler) UnblockProfile() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		err := h.ProfileBlockService.UnblockProfile(w, r)
+		if err != nil {
+			h.APIResponse.ErrorResponse(w, r, err, errorStatusCode(err, http.StatusBadRequest))
+			return
+		}
+
+		h.APIResponse.SuccessResponse(w, r, nil)
+	}
+}
+
+func (h *profileBlockHandler) GetBlockedProfiles() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		restrictedProfilesResponseDTO, err := h.ProfileBlockService.GetBlockedProfiles(w, r)
+		if err != nil {
+			h.APIResponse.ErrorResponse(w, r, err, errorStatusCode(err, http.StatusInternalServerError))
+			return
+		}
+
+		h.APIResponse.SuccessResponse(w, r, restrictedProfilesResponseDTO)
+	}
+}
+
+func (h *profileBlockHandler) MuteProfile() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		err := h.ProfileBlockService.MuteProfile(w, r)
+		if err != nil {
+			h.APIResponse.ErrorResponse(w, r, err, errorStatusCode(err, http.StatusBadRequest))
+			return
+		}
+
+		h.APIResponse.SuccessResponse(w, r, nil)
+	}
+}
+
+func (h *profileBlockHandler) UnmuteProfile() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		err := h.ProfileBlockService.UnmuteProfile(w, r)
+		if err != nil {
+			h.APIResponse.ErrorResponse(w, r, err, errorStatusCode(err, http.StatusBadRequest))
+			return
+		}
+
+		h.APIResponse.SuccessResponse(w, r, nil)
+	}
+}
+
+func (h *profileBlockHandler) GetMutedProfiles() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		restrictedProfilesResponseDTO, err := h.ProfileBlockService.GetMutedProfiles(w, r)
+		if err != nil {
+			h.APIResponse.ErrorResponse(w, r, err, errorStatusCode(err, http.StatusInternalServerError))
+			return
+		}
+
+		h.APIResponse.SuccessResponse(w, r, restrictedProfilesResponseDTO)
+	}
+}
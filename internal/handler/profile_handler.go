@@ -6,6 +6,7 @@ import (
 
 	"github.com/brightside-dev/ronin-fitness-be/internal/handler/response"
 	"github.com/brightside-dev/ronin-fitness-be/internal/service"
+	"github.com/brightside-dev/ronin-fitness-be/internal/util"
 	"github.com/go-chi/jwtauth/v5"
 )
 
@@ -18,6 +19,14 @@ type ProfileHandler interface {
 	GetFollowers() http.HandlerFunc
 	GetFollowing() http.HandlerFunc
 	RemoveFollower() http.HandlerFunc
+	GetFollowRequests() http.HandlerFunc
+	GetSentFollowRequests() http.HandlerFunc
+	ApproveFollowRequest() http.HandlerFunc
+	RejectFollowRequest() http.HandlerFunc
+	CancelFollowRequest() http.HandlerFunc
+	SearchProfiles() http.HandlerFunc
+	GetProfileByHandle() http.HandlerFunc
+	ChangeHandle() http.HandlerFunc
 }
 
 type profileHandler struct {
@@ -33,6 +42,7 @@ func NewProfileHandler(
 	profileService service.ProfileService,
 ) ProfileHandler {
 	return &profileHandler{
+		APIResponse:    apiResponse,
 		DBLogger:       dbLogger,
 		ProfileService: profileService,
 	}
@@ -40,13 +50,13 @@ func NewProfileHandler(
 
 func (h *profileHandler) GetMyProfile() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		_, claims, err := jwtauth.FromContext(r.Context())
+		userID, err := util.GetAuthUserID(r)
 		if err != nil {
 			h.APIResponse.ErrorResponse(w, r, err, http.StatusUnauthorized)
 			return
 		}
 
-		myProfileResponseDTO, err := h.ProfileService.GetMyProfileByUserID(w, r, claims["id"].(int))
+		myProfileResponseDTO, err := h.ProfileService.GetMyProfileByUserID(w, r, userID)
 		if err != nil {
 			h.APIResponse.ErrorResponse(w, r, err, http.StatusInternalServerError)
 			return
@@ -60,7 +70,7 @@ func (h *profileHandler) GetProfile() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		profileResponseDTO, err := h.ProfileService.GetProfileByUserID(w, r)
 		if err != nil {
-			h.APIResponse.ErrorResponse(w, r, err, http.StatusInternalServerError)
+			h.APIResponse.ErrorResponse(w, r, err, errorStatusCode(err, http.StatusBadRequest))
 			return
 		}
 
@@ -72,7 +82,7 @@ func (h *profileHandler) UpdateProfile() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		profileResponseDTO, err := h.ProfileService.UpdateProfile(w, r)
 		if err != nil {
-			h.APIResponse.ErrorResponse(w, r, err, http.StatusInternalServerError)
+			h.APIResponse.ErrorResponse(w, r, err, http.StatusBadRequest)
 			return
 		}
 
@@ -84,7 +94,7 @@ func (h *profileHandler) FollowProfile() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		followProfileResponseDTO, err := h.ProfileService.FollowProfile(w, r)
 		if err != nil {
-			h.APIResponse.ErrorResponse(w, r, err, http.StatusInternalServerError)
+			h.APIResponse.ErrorResponse(w, r, err, errorStatusCode(err, http.StatusBadRequest))
 			return
 		}
 
@@ -96,7 +106,7 @@ func (h *profileHandler) UnfollowProfile() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		unfollowProfileResponseDTO, err := h.ProfileService.UnfollowProfile(w, r)
 		if err != nil {
-			h.APIResponse.ErrorResponse(w, r, err, http.StatusInternalServerError)
+			h.APIResponse.ErrorResponse(w, r, err, errorStatusCode(err, http.StatusBadRequest))
 			return
 		}
 
@@ -106,15 +116,132 @@ func (h *profileHandler) UnfollowProfile() http.HandlerFunc {
 
 func (h *profileHandler) GetFollowers() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
+		profileFollowsListResponseDTO, err := h.ProfileService.GetFollowers(w, r)
+		if err != nil {
+			h.APIResponse.ErrorResponse(w, r, err, errorStatusCode(err, http.StatusBadRequest))
+			return
+		}
+
+		h.APIResponse.SuccessResponse(w, r, profileFollowsListResponseDTO)
 	}
 }
 
 func (h *profileHandler) GetFollowing() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
+		profileFollowsListResponseDTO, err := h.ProfileService.GetFollowing(w, r)
+		if err != nil {
+			h.APIResponse.ErrorResponse(w, r, err, errorStatusCode(err, http.StatusBadRequest))
+			return
+		}
+
+		h.APIResponse.SuccessResponse(w, r, profileFollowsListResponseDTO)
 	}
 }
 
 func (h *profileHandler) RemoveFollower() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
+		err := h.ProfileService.RemoveFollower(w, r)
+		if err != nil {
+			h.APIResponse.ErrorResponse(w, r, err, errorStatusCode(err, http.StatusBadRequest))
+			return
+		}
+
+		h.APIResponse.SuccessResponse(w, r, nil)
+	}
+}
+
+func (h *profileHandler) GetFollowRequests() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		followRequestsListResponseDTO, err := h.ProfileService.GetFollowRequests(w, r)
+		if err != nil {
+			h.APIResponse.ErrorResponse(w, r, err, errorStatusCode(err, http.StatusInternalServerError))
+			return
+		}
+
+		h.APIResponse.SuccessResponse(w, r, followRequestsListResponseDTO)
+	}
+}
+
+func (h *profileHandler) GetSentFollowRequests() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		followRequestsListResponseDTO, err := h.ProfileService.GetSentFollowRequests(w, r)
+		if err != nil {
+			h.APIResponse.ErrorResponse(w, r, err, errorStatusCode(err, http.StatusInternalServerError))
+			return
+		}
+
+		h.APIResponse.SuccessResponse(w, r, followRequestsListResponseDTO)
+	}
+}
+
+func (h *profileHandler) ApproveFollowRequest() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		err := h.ProfileService.ApproveFollowRequest(w, r)
+		if err != nil {
+			h.APIResponse.ErrorResponse(w, r, err, errorStatusCode(err, http.StatusBadRequest))
+			return
+		}
+
+		h.APIResponse.SuccessResponse(w, r, nil)
+	}
+}
+
+func (h *profileHandler) RejectFollowRequest() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		err := h.ProfileService.RejectFollowRequest(w, r)
+		if err != nil {
+			h.APIResponse.ErrorResponse(w, r, err, errorStatusCode(err, http.StatusBadRequest))
+			return
+		}
+
+		h.APIResponse.SuccessResponse(w, r, nil)
+	}
+}
+
+func (h *profileHandler) CancelFollowRequest() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		err := h.ProfileService.CancelFollowRequest(w, r)
+		if err != nil {
+			h.APIResponse.ErrorResponse(w, r, err, errorStatusCode(err, http.StatusBadRequest))
+			return
+		}
+
+		h.APIResponse.SuccessResponse(w, r, nil)
+	}
+}
+
+func (h *profileHandler) SearchProfiles() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		profileSearchResponseDTO, err := h.ProfileService.SearchProfiles(w, r)
+		if err != nil {
+			h.APIResponse.ErrorResponse(w, r, err, errorStatusCode(err, http.StatusBadRequest))
+			return
+		}
+
+		h.APIResponse.SuccessResponse(w, r, profileSearchResponseDTO)
+	}
+}
+
+func (h *profileHandler) GetProfileByHandle() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		profileResponseDTO, err := h.ProfileService.GetProfileByHandle(w, r)
+		if err != nil {
+			h.APIResponse.ErrorResponse(w, r, err, errorStatusCode(err, http.StatusBadRequest))
+			return
+		}
+
+		h.APIResponse.SuccessResponse(w, r, profileResponseDTO)
+	}
+}
+
+func (h *profileHandler) ChangeHandle() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		myProfileResponseDTO, err := h.ProfileService.ChangeHandle(w, r)
+		if err != nil {
+			h.APIResponse.ErrorResponse(w, r, err, errorStatusCode(err, http.StatusBadRequest))
+			return
+		}
+
+		h.APIResponse.SuccessResponse(w, r, myProfileResponseDTO)
 	}
 }
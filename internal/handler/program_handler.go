@@ -0,0 +1,173 @@
+package handler
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/brightside-dev/ronin-fitness-be/internal/handler/response"
+	"github.com/brightside-dev/ronin-fitness-be/internal/service"
+)
+
+type ProgramHandler interface {
+	CreateProgram() http.HandlerFunc
+	GetPrograms() http.HandlerFunc
+	GetProgram() http.HandlerFunc
+	UpdateProgram() http.HandlerFunc
+	PublishProgram() http.HandlerFunc
+	Enroll() http.HandlerFunc
+	GetEnrollments() http.HandlerFunc
+	PauseEnrollment() http.HandlerFunc
+	ResumeEnrollment() http.HandlerFunc
+	RestartEnrollment() http.HandlerFunc
+	GetNextSession() http.HandlerFunc
+}
+
+type programHandler struct {
+	APIResponse    response.APIResponseManager
+	DBLogger       *slog.Logger
+	ProgramService service.ProgramService
+}
+
+func NewProgramHandler(
+	apiResponse response.APIResponseManager,
+	dbLogger *slog.Logger,
+	programService service.ProgramService,
+) ProgramHandler {
+	return &programHandler{
+		APIResponse:    apiResponse,
+		DBLogger:       dbLogger,
+		ProgramService: programService,
+	}
+}
+
+func (h *programHandler) CreateProgram() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		programResponseDTO, err := h.ProgramService.CreateProgram(w, r)
+		if err != nil {
+			h.APIResponse.ErrorResponse(w, r, err, errorStatusCode(err, http.StatusBadRequest))
+			return
+		}
+
+		h.APIResponse.SuccessResponse(w, r, programResponseDTO, http.StatusCreated)
+	}
+}
+
+func (h *programHandler) GetPrograms() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		programsListResponseDTO, err := h.ProgramService.GetPrograms(w, r)
+		if err != nil {
+			h.APIResponse.ErrorResponse(w, r, err, errorStatusCode(err, http.StatusBadRequest))
+			return
+		}
+
+		h.APIResponse.SuccessResponse(w, r, programsListResponseDTO)
+	}
+}
+
+func (h *programHandler) GetProgram() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		programResponseDTO, err := h.ProgramService.GetProgram(w, r)
+		if err != nil {
+			h.APIResponse.ErrorResponse(w, r, err, errorStatusCode(err, http.StatusInternalServerError))
+			return
+		}
+
+		h.APIResponse.SuccessResponse(w, r, programResponseDTO)
+	}
+}
+
+func (h *programHandler) UpdateProgram() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		programResponseDTO, err := h.ProgramService.UpdateProgram(w, r)
+		if err != nil {
+			h.APIResponse.ErrorResponse(w, r, err, errorStatusCode(err, http.StatusBadRequest))
+			return
+		}
+
+		h.APIResponse.SuccessResponse(w, r, programResponseDTO)
+	}
+}
+
+func (h *programHandler) PublishProgram() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		programResponseDTO, err := h.ProgramService.PublishProgram(w, r)
+		if err != nil {
+			h.APIResponse.ErrorResponse(w, r, err, errorStatusCode(err, http.StatusBadRequest))
+			return
+		}
+
+		h.APIResponse.SuccessResponse(w, r, programResponseDTO)
+	}
+}
+
+func (h *programHandler) Enroll() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		enrollmentResponseDTO, err := h.ProgramService.Enroll(w, r)
+		if err != nil {
+			h.APIResponse.ErrorResponse(w, r, err, errorStatusCode(err, http.StatusInternalServerError))
+			return
+		}
+
+		h.APIResponse.SuccessResponse(w, r, enrollmentResponseDTO)
+	}
+}
+
+func (h *programHandler) GetEnrollments() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		enrollmentsListResponseDTO, err := h.ProgramService.GetEnrollments(w, r)
+		if err != nil {
+			h.APIResponse.ErrorResponse(w, r, err, errorStatusCode(err, http.StatusInternalServerError))
+			return
+		}
+
+		h.APIResponse.SuccessResponse(w, r, enrollmentsListResponseDTO)
+	}
+}
+
+func (h *programHandler) PauseEnrollment() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		enrollmentResponseDTO, err := h.ProgramService.PauseEnrollment(w, r)
+		if err != nil {
+			h.APIResponse.ErrorResponse(w, r, err, errorStatusCode(err, http.StatusInternalServerError))
+			return
+		}
+
+		h.APIResponse.SuccessResponse(w, r, enrollmentResponseDTO)
+	}
+}
+
+func (h *programHandler) ResumeEnrollment() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		enrollmentResponseDTO, err := h.ProgramService.ResumeEnrollment(w, r)
+		if err != nil {
+			h.APIResponse.ErrorResponse(w, r, err, errorStatusCode(err, http.StatusInternalServerError))
+			return
+		}
+
+		h.APIResponse.SuccessResponse(w, r, enrollmentResponseDTO)
+	}
+}
+
+func (h *programHandler) RestartEnrollment() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		enrollmentResponseDTO, err := h.ProgramService.RestartEnrollment(w, r)
+		if err != nil {
+			h.APIResponse.ErrorResponse(w, r, err, errorStatusCode(err, http.StatusInternalServerError))
+			return
+		}
+
+		h.APIResponse.SuccessResponse(w, r, enrollmentResponseDTO)
+	}
+}
+
+func (h *programHandler) GetNextSession() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		sessionResponseDTO, err := h.ProgramService.GetNextSession(w, r)
+		if err != nil {
+			h.APIResponse.ErrorResponse(w, r, err, errorStatusCode(err, http.StatusInternalServerError))
+			return
+		}
+
+		h.APIResponse.SuccessResponse(w, r, sessionResponseDTO)
+	}
+}
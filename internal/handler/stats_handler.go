@@ -0,0 +1,43 @@
+package handler
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/brightside-dev/ronin-fitness-be/internal/handler/response"
+	"github.com/brightside-dev/ronin-fitness-be/internal/service"
+)
+
+type StatsHandler interface {
+	GetStats() http.HandlerFunc
+}
+
+type statsHandler struct {
+	APIResponse  response.APIResponseManager
+	DBLogger     *slog.Logger
+	StatsService service.StatsService
+}
+
+func NewStatsHandler(
+	apiResponse response.APIResponseManager,
+	dbLogger *slog.Logger,
+	statsService service.StatsService,
+) StatsHandler {
+	return &statsHandler{
+		APIResponse:  apiResponse,
+		DBLogger:     dbLogger,
+		StatsService: statsService,
+	}
+}
+
+func (h *statsHandler) GetStats() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		statsResponseDTO, err := h.StatsService.GetStats(w, r)
+		if err != nil {
+			h.APIResponse.ErrorResponse(w, r, err, errorStatusCode(err, http.StatusBadRequest))
+			return
+		}
+
+		h.APIResponse.SuccessResponse(w, r, statsResponseDTO)
+	}
+}
@@ -0,0 +1,173 @@
+package handler
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/brightside-dev/ronin-fitness-be/internal/handler/response"
+	"github.com/brightside-dev/ronin-fitness-be/internal/service"
+)
+
+type TemplateHandler interface {
+	CreateTemplate() http.HandlerFunc
+	CreateTemplateFromWorkout() http.HandlerFunc
+	GetTemplates() http.HandlerFunc
+	GetTemplate() http.HandlerFunc
+	UpdateTemplate() http.HandlerFunc
+	DeleteTemplate() http.HandlerFunc
+	ShareTemplate() http.HandlerFunc
+	UnshareTemplate() http.HandlerFunc
+	GetSharedTemplate() http.HandlerFunc
+	CopySharedTemplate() http.HandlerFunc
+	StartWorkout() http.HandlerFunc
+}
+
+type templateHandler struct {
+	APIResponse     response.APIResponseManager
+	DBLogger        *slog.Logger
+	TemplateService service.TemplateService
+}
+
+func NewTemplateHandler(
+	apiResponse response.APIResponseManager,
+	dbLogger *slog.Logger,
+	templateService service.TemplateService,
+) TemplateHandler {
+	return &templateHandler{
+		APIResponse:     apiResponse,
+		DBLogger:        dbLogger,
+		TemplateService: templateService,
+	}
+}
+
+func (h *templateHandler) CreateTemplate() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		templateResponseDTO, err := h.TemplateService.CreateTemplate(w, r)
+		if err != nil {
+			h.APIResponse.ErrorResponse(w, r, err, errorStatusCode(err, http.StatusBadRequest))
+			return
+		}
+
+		h.APIResponse.SuccessResponse(w, r, templateResponseDTO, http.StatusCreated)
+	}
+}
+
+func (h *templateHandler) CreateTemplateFromWorkout() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		templateResponseDTO, err := h.TemplateService.CreateTemplateFromWorkout(w, r)
+		if err != nil {
+			h.APIResponse.ErrorResponse(w, r, err, errorStatusCode(err, http.StatusBadRequest))
+			return
+		}
+
+		h.APIResponse.SuccessResponse(w, r, templateResponseDTO, http.StatusCreated)
+	}
+}
+
+func (h *templateHandler) GetTemplates() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		templatesListResponseDTO, err := h.TemplateService.GetTemplates(w, r)
+		if err != nil {
+			h.APIResponse.ErrorResponse(w, r, err, errorStatusCode(err, http.StatusInternalServerError))
+			return
+		}
+
+		h.APIResponse.SuccessResponse(w, r, templatesListResponseDTO)
+	}
+}
+
+func (h *templateHandler) GetTemplate() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		templateResponseDTO, err := h.TemplateService.GetTemplate(w, r)
+		if err != nil {
+			h.APIResponse.ErrorResponse(w, r, err, errorStatusCode(err, http.StatusInternalServerError))
+			return
+		}
+
+		h.APIResponse.SuccessResponse(w, r, templateResponseDTO)
+	}
+}
+
+func (h *templateHandler) UpdateTemplate() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		templateResponseDTO, err := h.TemplateService.UpdateTemplate(w, r)
+		if err != nil {
+			h.APIResponse.ErrorResponse(w, r, err, errorStatusCode(err, http.StatusBadRequest))
+			return
+		}
+
+		h.APIResponse.SuccessResponse(w, r, templateResponseDTO)
+	}
+}
+
+func (h *templateHandler) DeleteTemplate() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		err := h.TemplateService.DeleteTemplate(w, r)
+		if err != nil {
+			h.APIResponse.ErrorResponse(w, r, err, errorStatusCode(err, http.StatusInternalServerError))
+			return
+		}
+
+		h.APIResponse.SuccessResponse(w, r, nil)
+	}
+}
+
+func (h *templateHandler) ShareTemplate() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		templateShareResponseDTO, err := h.TemplateService.ShareTemplate(w, r)
+		if err != nil {
+			h.APIResponse.ErrorResponse(w, r, err, errorStatusCode(err, http.StatusInternalServerError))
+			return
+		}
+
+		h.APIResponse.SuccessResponse(w, r, templateShareResponseDTO)
+	}
+}
+
+func (h *templateHandler) UnshareTemplate() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		err := h.TemplateService.UnshareTemplate(w, r)
+		if err != nil {
+			h.APIResponse.ErrorResponse(w, r, err, errorStatusCode(err, http.StatusInternalServerError))
+			return
+		}
+
+		h.APIResponse.SuccessResponse(w, r, nil)
+	}
+}
+
+func (h *templateHandler) GetSharedTemplate() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		templateResponseDTO, err := h.TemplateService.GetSharedTemplate(w, r)
+		if err != nil {
+			h.APIResponse.ErrorResponse(w, r, err, errorStatusCode(err, http.StatusInternalServerError))
+			return
+		}
+
+		h.APIResponse.SuccessResponse(w, r, templateResponseDTO)
+	}
+}
+
+func (h *templateHandler) CopySharedTemplate() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		templateResponseDTO, err := h.TemplateService.CopySharedTemplate(w, r)
+		if err != nil {
+			h.APIResponse.ErrorResponse(w, r, err, errorStatusCode(err, http.StatusInternalServerError))
+			return
+		}
+
+		h.APIResponse.SuccessResponse(w, r, templateResponseDTO, http.StatusCreated)
+	}
+}
+
+func (h *templateHandler) StartWorkout() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		workoutDraftResponseDTO, err := h.TemplateService.StartWorkout(w, r)
+		if err != nil {
+			h.APIResponse.ErrorResponse(w, r, err, errorStatusCode(err, http.StatusInternalServerError))
+			return
+		}
+
+		h.APIResponse.SuccessResponse(w, r, workoutDraftResponseDTO)
+	}
+}
@@ -0,0 +1,41 @@
+package handler
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/brightside-dev/ronin-fitness-be/internal/handler/response"
+	"github.com/brightside-dev/ronin-fitness-be/internal/service"
+)
+
+type WorkoutExportHandler interface {
+	ExportWorkouts() http.HandlerFunc
+}
+
+type workoutExportHandler struct {
+	APIResponse          response.APIResponseManager
+	DBLogger             *slog.Logger
+	WorkoutExportService service.WorkoutExportService
+}
+
+func NewWorkoutExportHandler(
+	apiResponse response.APIResponseManager,
+	dbLogger *slog.Logger,
+	workoutExportService service.WorkoutExportService,
+) WorkoutExportHandler {
+	return &workoutExportHandler{
+		APIResponse:          apiResponse,
+		DBLogger:             dbLogger,
+		WorkoutExportService: workoutExportService,
+	}
+}
+
+// ExportWorkouts streams the export itself, so only errors raised before the download starts get a JSON response.
+func (h *workoutExportHandler) ExportWorkouts() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		err := h.WorkoutExportService.ExportWorkouts(w, r)
+		if err != nil {
+			h.APIResponse.ErrorResponse(w, r, err, errorStatusCode(err, http.StatusBadRequest))
+		}
+	}
+}
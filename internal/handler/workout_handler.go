@@ -0,0 +1,134 @@
+package handler
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/brightside-dev/ronin-fitness-be/internal/handler/response"
+	"github.com/brightside-dev/ronin-fitness-be/internal/service"
+)
+
+type WorkoutHandler interface {
+	GetWorkout() http.HandlerFunc
+	CreateWorkout() http.HandlerFunc
+	UpdateVisibility() http.HandlerFunc
+	DeleteWorkout() http.HandlerFunc
+	PutReaction() http.HandlerFunc
+	RemoveReaction() http.HandlerFunc
+	GetReactions() http.HandlerFunc
+	GetFeed() http.HandlerFunc
+}
+
+type workoutHandler struct {
+	APIResponse    response.APIResponseManager
+	DBLogger       *slog.Logger
+	WorkoutService service.WorkoutService
+}
+
+func NewWorkoutHandler(
+	apiResponse response.APIResponseManager,
+	dbLogger *slog.Logger,
+	workoutService service.WorkoutService,
+) WorkoutHandler {
+	return &workoutHandler{
+		APIResponse:    apiResponse,
+		DBLogger:       dbLogger,
+		WorkoutService: workoutService,
+	}
+}
+
+func (h *workoutHandler) GetWorkout() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		workoutResponseDTO, err := h.WorkoutService.GetWorkout(w, r)
+		if err != nil {
+			h.APIResponse.ErrorResponse(w, r, err, errorStatusCode(err, http.StatusInternalServerError))
+			return
+		}
+
+		h.APIResponse.SuccessResponse(w, r, workoutResponseDTO)
+	}
+}
+
+func (h *workoutHandler) CreateWorkout() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		workoutCreateResponseDTO, err := h.WorkoutService.CreateWorkout(w, r)
+		if err != nil {
+			h.APIResponse.ErrorResponse(w, r, err, errorStatusCode(err, http.StatusBadRequest))
+			return
+		}
+
+		h.APIResponse.SuccessResponse(w, r, workoutCreateResponseDTO, http.StatusCreated)
+	}
+}
+
+func (h *workoutHandler) UpdateVisibility() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		workoutResponseDTO, err := h.WorkoutService.UpdateVisibility(w, r)
+		if err != nil {
+			h.APIResponse.ErrorResponse(w, r, err, errorStatusCode(err, http.StatusBadRequest))
+			return
+		}
+
+		h.APIResponse.SuccessResponse(w, r, workoutResponseDTO)
+	}
+}
+
+func (h *workoutHandler) DeleteWorkout() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		err := h.WorkoutService.DeleteWorkout(w, r)
+		if err != nil {
+			h.APIResponse.ErrorResponse(w, r, err, errorStatusCode(err, http.StatusInternalServerError))
+			return
+		}
+
+		h.APIResponse.SuccessResponse(w, r, nil)
+	}
+}
+
+func (h *workoutHandler) PutReaction() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		reactionsSummaryDTO, err := h.WorkoutService.PutReaction(w, r)
+		if err != nil {
+			h.APIResponse.ErrorResponse(w, r, err, errorStatusCode(err, http.StatusBadRequest))
+			return
+		}
+
+		h.APIResponse.SuccessResponse(w, r, reactionsSummaryDTO)
+	}
+}
+
+func (h *workoutHandler) RemoveReaction() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		reactionsSummaryDTO, err := h.WorkoutService.RemoveReaction(w, r)
+		if err != nil {
+			h.APIResponse.ErrorResponse(w, r, err, errorStatusCode(err, http.StatusBadRequest))
+			return
+		}
+
+		h.APIResponse.SuccessResponse(w, r, reactionsSummaryDTO)
+	}
+}
+
+func (h *workoutHandler) GetReactions() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		reactionsListDTO, err := h.WorkoutService.GetReactions(w, r)
+		if err != nil {
+			h.APIResponse.ErrorResponse(w, r, err, errorStatusCode(err, http.StatusBadRequest))
+			return
+		}
+
+		h.APIResponse.SuccessResponse(w, r, reactionsListDTO)
+	}
+}
+
+func (h *workoutHandler) GetFeed() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		feedResponseDTO, err := h.WorkoutService.GetFeed(w, r)
+		if err != nil {
+			h.APIResponse.ErrorResponse(w, r, err, errorStatusCode(err, http.StatusBadRequest))
+			return
+		}
+
+		h.APIResponse.SuccessResponse(w, r, feedResponseDTO)
+	}
+}
@@ -0,0 +1,74 @@
+package handler
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/brightside-dev/ronin-fitness-be/internal/handler/response"
+	"github.com/brightside-dev/ronin-fitness-be/internal/service"
+)
+
+type WorkoutImportHandler interface {
+	ImportWorkouts() http.HandlerFunc
+	GetImports() http.HandlerFunc
+	GetImport() http.HandlerFunc
+}
+
+type workoutImportHandler struct {
+	APIResponse          response.APIResponseManager
+	DBLogger             *slog.Logger
+	WorkoutImportService service.WorkoutImportService
+}
+
+func NewWorkoutImportHandler(
+	apiResponse response.APIResponseManager,
+	dbLogger *slog.Logger,
+	workoutImportService service.WorkoutImportService,
+) WorkoutImportHandler {
+	return &workoutImportHandler{
+		APIResponse:          apiResponse,
+		DBLogger:             dbLogger,
+		WorkoutImportService: workoutImportService,
+	}
+}
+
+func (h *workoutImportHandler) ImportWorkouts() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		workoutImportResponseDTO, err := h.WorkoutImportService.ImportWorkouts(w, r)
+		if err != nil {
+			h.APIResponse.ErrorResponse(w, r, err, errorStatusCode(err, http.StatusBadRequest))
+			return
+		}
+
+		if workoutImportResponseDTO.DryRun {
+			h.APIResponse.SuccessResponse(w, r, workoutImportResponseDTO)
+			return
+		}
+
+		h.APIResponse.SuccessResponse(w, r, workoutImportResponseDTO, http.StatusCreated)
+	}
+}
+
+func (h *workoutImportHandler) GetImports() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		workoutImportsListResponseDTO, err := h.WorkoutImportService.GetImports(w, r)
+		if err != nil {
+			h.APIResponse.ErrorResponse(w, r, err, errorStatusCode(err, http.StatusBadRequest))
+			return
+		}
+
+		h.APIResponse.SuccessResponse(w, r, workoutImportsListResponseDTO)
+	}
+}
+
+func (h *workoutImportHandler) GetImport() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		workoutImportResponseDTO, err := h.WorkoutImportService.GetImport(w, r)
+		if err != nil {
+			h.APIResponse.ErrorResponse(w, r, err, errorStatusCode(err, http.StatusBadRequest))
+			return
+		}
+
+		h.APIResponse.SuccessResponse(w, r, workoutImportResponseDTO)
+	}
+}
@@ -0,0 +1,69 @@
+package handler
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/brightside-dev/ronin-fitness-be/internal/handler/response"
+	"github.com/brightside-dev/ronin-fitness-be/internal/service"
+)
+
+type WorkoutPhotoHandler interface {
+	UploadPhoto() http.HandlerFunc
+	GetPhotos() http.HandlerFunc
+	DeletePhoto() http.HandlerFunc
+}
+
+type workoutPhotoHandler struct {
+	APIResponse         response.APIResponseManager
+	DBLogger            *slog.Logger
+	WorkoutPhotoService service.WorkoutPhotoService
+}
+
+func NewWorkoutPhotoHandler(
+	apiResponse response.APIResponseManager,
+	dbLogger *slog.Logger,
+	workoutPhotoService service.WorkoutPhotoService,
+) WorkoutPhotoHandler {
+	return &workoutPhotoHandler{
+		APIResponse:         apiResponse,
+		DBLogger:            dbLogger,
+		WorkoutPhotoService: workoutPhotoService,
+	}
+}
+
+func (h *workoutPhotoHandler) UploadPhoto() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		workoutPhotoResponseDTO, err := h.WorkoutPhotoService.UploadPhoto(w, r)
+		if err != nil {
+			h.APIResponse.ErrorResponse(w, r, err, errorStatusCode(err, http.StatusBadRequest))
+			return
+		}
+
+		h.APIResponse.SuccessResponse(w, r, workoutPhotoResponseDTO, http.StatusCreated)
+	}
+}
+
+func (h *workoutPhotoHandler) GetPhotos() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		workoutPhotosResponseDTO, err := h.WorkoutPhotoService.GetPhotos(w, r)
+		if err != nil {
+			h.APIResponse.ErrorResponse(w, r, err, errorStatusCode(err, http.StatusInternalServerError))
+			return
+		}
+
+		h.APIResponse.SuccessResponse(w, r, workoutPhotosResponseDTO)
+	}
+}
+
+func (h *workoutPhotoHandler) DeletePhoto() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		err := h.WorkoutPhotoService.DeletePhoto(w, r)
+		if err != nil {
+			h.APIResponse.ErrorResponse(w, r, err, errorStatusCode(err, http.StatusInternalServerError))
+			return
+		}
+
+		h.APIResponse.SuccessResponse(w, r, nil)
+	}
+}
@@ -0,0 +1,147 @@
+package handler
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/brightside-dev/ronin-fitness-be/internal/handler/response"
+	"github.com/brightside-dev/ronin-fitness-be/internal/service"
+)
+
+type WorkoutSessionHandler interface {
+	StartSession() http.HandlerFunc
+	GetActiveSession() http.HandlerFunc
+	AddSet() http.HandlerFunc
+	UpdateSet() http.HandlerFunc
+	DeleteSet() http.HandlerFunc
+	PauseSession() http.HandlerFunc
+	ResumeSession() http.HandlerFunc
+	DiscardSession() http.HandlerFunc
+	FinishSession() http.HandlerFunc
+}
+
+type workoutSessionHandler struct {
+	APIResponse           response.APIResponseManager
+	DBLogger              *slog.Logger
+	WorkoutSessionService service.WorkoutSessionService
+}
+
+func NewWorkoutSessionHandler(
+	apiResponse response.APIResponseManager,
+	dbLogger *slog.Logger,
+	workoutSessionService service.WorkoutSessionService,
+) WorkoutSessionHandler {
+	return &workoutSessionHandler{
+		APIResponse:           apiResponse,
+		DBLogger:              dbLogger,
+		WorkoutSessionService: workoutSessionService,
+	}
+}
+
+func (h *workoutSessionHandler) StartSession() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		workoutSessionResponseDTO, err := h.WorkoutSessionService.StartSession(w, r)
+		if err != nil {
+			h.APIResponse.ErrorResponse(w, r, err, errorStatusCode(err, http.StatusBadRequest))
+			return
+		}
+
+		h.APIResponse.SuccessResponse(w, r, workoutSessionResponseDTO, http.StatusCreated)
+	}
+}
+
+func (h *workoutSessionHandler) GetActiveSession() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		workoutSessionResponseDTO, err := h.WorkoutSessionService.GetActiveSession(w, r)
+		if err != nil {
+			h.APIResponse.ErrorResponse(w, r, err, errorStatusCode(err, http.StatusBadRequest))
+			return
+		}
+
+		h.APIResponse.SuccessResponse(w, r, workoutSessionResponseDTO)
+	}
+}
+
+func (h *workoutSessionHandler) AddSet() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		workoutSessionResponseDTO, err := h.WorkoutSessionService.AddSet(w, r)
+		if err != nil {
+			h.APIResponse.ErrorResponse(w, r, err, errorStatusCode(err, http.StatusBadRequest))
+			return
+		}
+
+		h.APIResponse.SuccessResponse(w, r, workoutSessionResponseDTO)
+	}
+}
+
+func (h *workoutSessionHandler) UpdateSet() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		workoutSessionResponseDTO, err := h.WorkoutSessionService.UpdateSet(w, r)
+		if err != nil {
+			h.APIResponse.ErrorResponse(w, r, err, errorStatusCode(err, http.StatusBadRequest))
+			return
+		}
+
+		h.APIResponse.SuccessResponse(w, r, workoutSessionResponseDTO)
+	}
+}
+
+func (h *workoutSessionHandler) DeleteSet() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		workoutSessionResponseDTO, err := h.WorkoutSessionService.DeleteSet(w, r)
+		if err != nil {
+			h.APIResponse.ErrorResponse(w, r, err, errorStatusCode(err, http.StatusBadRequest))
+			return
+		}
+
+		h.APIResponse.SuccessResponse(w, r, workoutSessionResponseDTO)
+	}
+}
+
+func (h *workoutSessionHandler) PauseSession() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		workoutSessionResponseDTO, err := h.WorkoutSessionService.PauseSession(w, r)
+		if err != nil {
+			h.APIResponse.ErrorResponse(w, r, err, errorStatusCode(err, http.StatusBadRequest))
+			return
+		}
+
+		h.APIResponse.SuccessResponse(w, r, workoutSessionResponseDTO)
+	}
+}
+
+func (h *workoutSessionHandler) ResumeSession() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		workoutSessionResponseDTO, err := h.WorkoutSessionService.ResumeSession(w, r)
+		if err != nil {
+			h.APIResponse.ErrorResponse(w, r, err, errorStatusCode(err, http.StatusBadRequest))
+			return
+		}
+
+		h.APIResponse.SuccessResponse(w, r, workoutSessionResponseDTO)
+	}
+}
+
+func (h *workoutSessionHandler) DiscardSession() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		workoutSessionResponseDTO, err := h.WorkoutSessionService.DiscardSession(w, r)
+		if err != nil {
+			h.APIResponse.ErrorResponse(w, r, err, errorStatusCode(err, http.StatusBadRequest))
+			return
+		}
+
+		h.APIResponse.SuccessResponse(w, r, workoutSessionResponseDTO)
+	}
+}
+
+func (h *workoutSessionHandler) FinishSession() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		workoutCreateResponseDTO, err := h.WorkoutSessionService.FinishSession(w, r)
+		if err != nil {
+			h.APIResponse.ErrorResponse(w, r, err, errorStatusCode(err, http.StatusBadRequest))
+			return
+		}
+
+		h.APIResponse.SuccessResponse(w, r, workoutCreateResponseDTO, http.StatusCreated)
+	}
+}
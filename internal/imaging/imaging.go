@@ -0,0 +1,135 @@
+// Package imaging validates uploaded photos and re-encodes them, which drops EXIF and any other
+// metadata, and produces resized copies such as thumbnails.
+package imaging
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/jpeg"
+	"image/png"
+	"net/http"
+)
+
+const (
+	ContentTypeJPEG = "image/jpeg"
+	ContentTypePNG  = "image/png"
+
+	// maxPixels guards against small files that decode to huge images
+	maxPixels   = 50_000_000
+	jpegQuality = 85
+)
+
+var ErrUnsupportedImage = errors.New("photo must be a JPEG or PNG image")
+
+// Image is a decoded photo, upright and ready to be encoded.
+type Image struct {
+	image.Image
+	ContentType string
+}
+
+// Decode sniffs the type of data from its content, ignoring what the client claimed, and decodes
+// it. JPEGs are rotated according to their EXIF orientation, as it is lost once re-encoded.
+func Decode(data []byte) (*Image, error) {
+	contentType := http.DetectContentType(data)
+	if contentType != ContentTypeJPEG && contentType != ContentTypePNG {
+		return nil, ErrUnsupportedImage
+	}
+
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrUnsupportedImage
+	}
+
+	if config.Width*config.Height > maxPixels {
+		return nil, fmt.Errorf("photo must not have more than %d megapixels", maxPixels/1_000_000)
+	}
+
+	var img image.Image
+	if contentType == ContentTypeJPEG {
+		img, err = jpeg.Decode(bytes.NewReader(data))
+	} else {
+		img, err = png.Decode(bytes.NewReader(data))
+	}
+	if err != nil {
+		return nil, ErrUnsupportedImage
+	}
+
+	if contentType == ContentTypeJPEG {
+		img = orient(img, jpegOrientation(data))
+	}
+
+	return &Image{Image: img, ContentType: contentType}, nil
+}
+
+// Encode writes the image in its original format, without any metadata.
+func (img *Image) Encode() ([]byte, error) {
+	var buf bytes.Buffer
+	var err error
+	if img.ContentType == ContentTypePNG {
+		err = png.Encode(&buf, img.Image)
+	} else {
+		err = jpeg.Encode(&buf, img.Image, &jpeg.Options{Quality: jpegQuality})
+	}
+
+	return buf.Bytes(), err
+}
+
+// Fit returns the image scaled down so neither side exceeds maxSize. Smaller images are returned as is.
+func (img *Image) Fit(maxSize int) *Image {
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if width <= maxSize && height <= maxSize {
+		return img
+	}
+
+	if width >= height {
+		width, height = maxSize, max(height*maxSize/width, 1)
+	} else {
+		width, height = max(width*maxSize/height, 1), maxSize
+	}
+
+	return &Image{Image: resize(img.Image, width, height), ContentType: img.ContentType}
+}
+
+// resize scales src down to width x height by averaging the source pixels behind each target pixel.
+func resize(src image.Image, width int, height int) *image.RGBA {
+	bounds := src.Bounds()
+	rgba, ok := src.(*image.RGBA)
+	if !ok || bounds.Min != (image.Point{}) {
+		rgba = image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+		draw.Draw(rgba, rgba.Bounds(), src, bounds.Min, draw.Src)
+	}
+
+	srcWidth, srcHeight := bounds.Dx(), bounds.Dy()
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		y0, y1 := y*srcHeight/height, max((y+1)*srcHeight/height, y*srcHeight/height+1)
+		for x := 0; x < width; x++ {
+			x0, x1 := x*srcWidth/width, max((x+1)*srcWidth/width, x*srcWidth/width+1)
+
+			var r, g, b, a, count uint64
+			for sy := y0; sy < y1; sy++ {
+				offset := sy*rgba.Stride + x0*4
+				for sx := x0; sx < x1; sx++ {
+					r += uint64(rgba.Pix[offset])
+					g += uint64(rgba.Pix[offset+1])
+					b += uint64(rgba.Pix[offset+2])
+					a += uint64(rgba.Pix[offset+3])
+					offset += 4
+					count++
+				}
+			}
+
+			i := y*dst.Stride + x*4
+			dst.Pix[i] = uint8(r / count)
+			dst.Pix[i+1] = uint8(g / count)
+			dst.Pix[i+2] = uint8(b / count)
+			dst.Pix[i+3] = uint8(a / count)
+		}
+	}
+
+	return dst
+}
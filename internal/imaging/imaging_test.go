@@ -0,0 +1,95 @@
+package imaging
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/png"
+	"testing"
+)
+
+func TestEncodeStripsMetadata(t *testing.T) {
+	comment := []byte{0xFF, 0xFE, 0x00, 0x0E, 'g', 'p', 's', ' ', '5', '1', '.', '5', ',', '-', '0', '.'}
+	data := withSegments(encodeJPEG(t, quadrants()), exifSegment(binary.LittleEndian, 6), comment)
+
+	img, err := Decode(data)
+	if err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+
+	encoded, err := img.Encode()
+	if err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+
+	if bytes.Contains(encoded, []byte("Exif")) || bytes.Contains(encoded, []byte("gps 51.5")) {
+		t.Errorf("Encode() output still holds the metadata")
+	}
+
+	// The rotation is applied once: decoding the output again keeps it upright
+	again, err := Decode(encoded)
+	if err != nil {
+		t.Fatalf("Decode() of the output error = %v", err)
+	}
+	if again.Bounds().Dx() != 16 || again.Bounds().Dy() != 32 {
+		t.Errorf("size after re-encoding = %dx%d, want 16x32", again.Bounds().Dx(), again.Bounds().Dy())
+	}
+}
+
+func TestDecode(t *testing.T) {
+	var pngData bytes.Buffer
+	if err := png.Encode(&pngData, quadrants()); err != nil {
+		t.Fatalf("png.Encode() error = %v", err)
+	}
+	jpg := encodeJPEG(t, quadrants())
+
+	tests := []struct {
+		name        string
+		data        []byte
+		contentType string
+		wantErr     bool
+	}{
+		{name: "jpeg", data: jpg, contentType: ContentTypeJPEG},
+		{name: "png", data: pngData.Bytes(), contentType: ContentTypePNG},
+		{name: "malformed segment before the frame", data: withSegments(jpg, []byte{0xFF, 0xE1, 0x00, 0x00}), wantErr: true},
+		{name: "truncated jpeg", data: jpg[:len(jpg)/2], wantErr: true},
+		{name: "gif", data: []byte("GIF89a\x01\x00\x01\x00\x00\x00\x00;"), wantErr: true},
+		{name: "text", data: []byte("not an image"), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			img, err := Decode(tt.data)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Decode() error = %v, want error %v", err, tt.wantErr)
+			}
+			if err == nil && img.ContentType != tt.contentType {
+				t.Errorf("ContentType = %q, want %q", img.ContentType, tt.contentType)
+			}
+		})
+	}
+}
+
+func TestFit(t *testing.T) {
+	tests := []struct {
+		name          string
+		width, height int
+		maxSize       int
+		wantW, wantH  int
+	}{
+		{name: "smaller image is kept", width: 32, height: 16, maxSize: 64, wantW: 32, wantH: 16},
+		{name: "landscape", width: 32, height: 16, maxSize: 8, wantW: 8, wantH: 4},
+		{name: "portrait", width: 16, height: 32, maxSize: 8, wantW: 4, wantH: 8},
+		{name: "thin side keeps a pixel", width: 100, height: 1, maxSize: 10, wantW: 10, wantH: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			img := &Image{Image: image.NewRGBA(image.Rect(0, 0, tt.width, tt.height)), ContentType: ContentTypePNG}
+			bounds := img.Fit(tt.maxSize).Bounds()
+			if bounds.Dx() != tt.wantW || bounds.Dy() != tt.wantH {
+				t.Errorf("Fit() size = %dx%d, want %dx%d", bounds.Dx(), bounds.Dy(), tt.wantW, tt.wantH)
+			}
+		})
+	}
+}
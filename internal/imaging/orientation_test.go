@@ -0,0 +1,200 @@
+package imaging
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"testing"
+)
+
+var (
+	red   = color.RGBA{R: 255, A: 255}
+	green = color.RGBA{G: 255, A: 255}
+	blue  = color.RGBA{B: 255, A: 255}
+	white = color.RGBA{R: 255, G: 255, B: 255, A: 255}
+)
+
+// quadrants returns a 32x16 image with a red, green, blue and white quadrant, from top-left to
+// bottom-right, which tells every orientation apart.
+func quadrants() *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, 32, 16))
+	for y := 0; y < 16; y++ {
+		for x := 0; x < 32; x++ {
+			switch {
+			case x < 16 && y < 8:
+				img.Set(x, y, red)
+			case y < 8:
+				img.Set(x, y, green)
+			case x < 16:
+				img.Set(x, y, blue)
+			default:
+				img.Set(x, y, white)
+			}
+		}
+	}
+
+	return img
+}
+
+func encodeJPEG(t *testing.T, img image.Image) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: 100}); err != nil {
+		t.Fatalf("jpeg.Encode() error = %v", err)
+	}
+
+	return buf.Bytes()
+}
+
+// exifSegment returns an APP1 segment holding a TIFF structure whose first IFD has the orientation.
+func exifSegment(order binary.ByteOrder, orientation uint16) []byte {
+	tiff := make([]byte, 8+2+12+4)
+	if order == binary.LittleEndian {
+		copy(tiff, "II")
+	} else {
+		copy(tiff, "MM")
+	}
+	order.PutUint16(tiff[2:], 42)
+	order.PutUint32(tiff[4:], 8)
+	order.PutUint16(tiff[8:], 1)
+	order.PutUint16(tiff[10:], exifOrientationTag)
+	order.PutUint16(tiff[12:], 3) // SHORT
+	order.PutUint32(tiff[14:], 1)
+	order.PutUint16(tiff[18:], orientation)
+
+	payload := append([]byte("Exif\x00\x00"), tiff...)
+	segment := []byte{0xFF, 0xE1, 0, 0}
+	binary.BigEndian.PutUint16(segment[2:], uint16(2+len(payload)))
+
+	return append(segment, payload...)
+}
+
+// withSegments inserts the bytes right after the start of image marker of a JPEG.
+func withSegments(jpg []byte, segments ...[]byte) []byte {
+	data := append([]byte{}, jpg[:2]...)
+	for _, segment := range segments {
+		data = append(data, segment...)
+	}
+
+	return append(data, jpg[2:]...)
+}
+
+func TestJPEGOrientation(t *testing.T) {
+	jpg := encodeJPEG(t, quadrants())
+	exif := exifSegment(binary.BigEndian, 6)
+
+	tooFarIFD := exifSegment(binary.BigEndian, 6)
+	binary.BigEndian.PutUint32(tooFarIFD[14:], 0xFFFFFFF0)
+	tooManyEntries := exifSegment(binary.LittleEndian, 6)
+	binary.LittleEndian.PutUint16(tooManyEntries[18:], 500)
+	binary.LittleEndian.PutUint16(tooManyEntries[20:], 0x0100) // image width, not the orientation
+	badByteOrder := exifSegment(binary.BigEndian, 6)
+	copy(badByteOrder[10:], "XX")
+
+	tests := []struct {
+		name string
+		data []byte
+		want int
+	}{
+		{name: "empty", data: nil, want: 1},
+		{name: "not a jpeg", data: []byte("\x89PNG\r\n\x1a\n"), want: 1},
+		{name: "without exif", data: jpg, want: 1},
+		{name: "big endian exif", data: withSegments(jpg, exif), want: 6},
+		{name: "little endian exif", data: withSegments(jpg, exifSegment(binary.LittleEndian, 8)), want: 8},
+		{name: "out of range orientation", data: withSegments(jpg, exifSegment(binary.BigEndian, 9)), want: 1},
+		{name: "fill bytes before the segment", data: withSegments(jpg, []byte{0xFF, 0xFF, 0xFF}, exif[1:]), want: 6},
+		{name: "standalone markers before the segment", data: withSegments(jpg, []byte{0xFF, 0x01, 0xFF, 0xD0, 0xFF, 0xD7}, exif), want: 6},
+		{name: "zero byte after a marker prefix", data: []byte{0xFF, 0xD8, 0xFF, 0x00, 0x00, 0x00}, want: 1},
+		{name: "segment length of zero", data: []byte{0xFF, 0xD8, 0xFF, 0xE1, 0x00, 0x00, 0xFF, 0xD9}, want: 1},
+		{name: "segment length of one", data: []byte{0xFF, 0xD8, 0xFF, 0xE1, 0x00, 0x01, 0xFF, 0xD9}, want: 1},
+		{name: "segment longer than the file", data: []byte{0xFF, 0xD8, 0xFF, 0xE1, 0xFF, 0xFF, 'E', 'x', 'i', 'f', 0, 0, 'M', 'M'}, want: 1},
+		{name: "marker without length", data: []byte{0xFF, 0xD8, 0xFF, 0xE1, 0x00}, want: 1},
+		{name: "ifd past the end", data: withSegments(jpg, tooFarIFD), want: 1},
+		{name: "more entries than bytes", data: withSegments(jpg, tooManyEntries), want: 1},
+		{name: "unknown byte order", data: withSegments(jpg, badByteOrder), want: 1},
+		{name: "exif after the start of scan", data: append(append([]byte{}, jpg[:len(jpg)-2]...), exif...), want: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := jpegOrientation(tt.data); got != tt.want {
+				t.Errorf("jpegOrientation() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestJPEGOrientationTruncated(t *testing.T) {
+	data := withSegments(encodeJPEG(t, quadrants()), []byte{0xFF, 0xFF, 0xFF, 0x01}, exifSegment(binary.LittleEndian, 3))
+
+	// Every prefix of the file must be read without panicking
+	for i := range data {
+		jpegOrientation(data[:i])
+	}
+}
+
+func TestDecodeOrientation(t *testing.T) {
+	jpg := encodeJPEG(t, quadrants())
+
+	// The colour of the top-left, top-right, bottom-left and bottom-right corners once upright
+	tests := []struct {
+		orientation uint16
+		corners     [4]color.RGBA
+	}{
+		{orientation: 1, corners: [4]color.RGBA{red, green, blue, white}},
+		{orientation: 2, corners: [4]color.RGBA{green, red, white, blue}},
+		{orientation: 3, corners: [4]color.RGBA{white, blue, green, red}},
+		{orientation: 4, corners: [4]color.RGBA{blue, white, red, green}},
+		{orientation: 5, corners: [4]color.RGBA{red, blue, green, white}},
+		{orientation: 6, corners: [4]color.RGBA{blue, red, white, green}},
+		{orientation: 7, corners: [4]color.RGBA{white, green, blue, red}},
+		{orientation: 8, corners: [4]color.RGBA{green, white, red, blue}},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("orientation %d", tt.orientation), func(t *testing.T) {
+			img, err := Decode(withSegments(jpg, exifSegment(binary.BigEndian, tt.orientation)))
+			if err != nil {
+				t.Fatalf("Decode() error = %v", err)
+			}
+
+			width, height := 32, 16
+			if tt.orientation >= 5 {
+				width, height = height, width
+			}
+
+			bounds := img.Bounds()
+			if bounds.Dx() != width || bounds.Dy() != height {
+				t.Fatalf("size = %dx%d, want %dx%d", bounds.Dx(), bounds.Dy(), width, height)
+			}
+
+			// Sample away from the edges between quadrants, which JPEG blurs
+			corners := [4]image.Point{
+				{bounds.Min.X + 2, bounds.Min.Y + 2},
+				{bounds.Max.X - 3, bounds.Min.Y + 2},
+				{bounds.Min.X + 2, bounds.Max.Y - 3},
+				{bounds.Max.X - 3, bounds.Max.Y - 3},
+			}
+			for i, corner := range corners {
+				if got := img.At(corner.X, corner.Y); !closeTo(got, tt.corners[i]) {
+					t.Errorf("corner %d = %v, want %v", i, got, tt.corners[i])
+				}
+			}
+		})
+	}
+}
+
+// closeTo reports whether c is within the error JPEG compression adds to want.
+func closeTo(c color.Color, want color.RGBA) bool {
+	r, g, b, _ := c.RGBA()
+	near := func(got uint32, want uint8) bool {
+		diff := int(got>>8) - int(want)
+		return diff > -48 && diff < 48
+	}
+
+	return near(r, want.R) && near(g, want.G) && near(b, want.B)
+}
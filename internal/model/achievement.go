@@ -0,0 +1,40 @@
+package model
+
+import "time"
+
+// The metrics achievements can be unlocked by, each measured over everything the profile has logged
+const (
+	AchievementMetricWorkoutCount        = "workout_count"
+	AchievementMetricPersonalRecordCount = "personal_record_count"
+	AchievementMetricStreakDays          = "streak_days" // the longest run of consecutive training days in UTC
+	AchievementMetricLiftWeightKg        = "lift_weight_kg"
+	AchievementMetricFollowerCount       = "follower_count"
+	AchievementMetricFollowingCount      = "following_count"
+)
+
+// The events achievements are evaluated on
+const (
+	AchievementEventWorkout        = "workout"
+	AchievementEventPersonalRecord = "personal_record"
+	AchievementEventFollow         = "follow"
+)
+
+// Achievement is a badge of the catalogue, unlocked once the profile's metric reaches the threshold.
+type Achievement struct {
+	ID           int       `json:"id"`
+	Code         string    `json:"code"`
+	Name         string    `json:"name"`
+	Description  string    `json:"description"`
+	Metric       string    `json:"metric"`
+	Threshold    float64   `json:"threshold"`
+	ExerciseName *string   `json:"exercise_name"` // the exercise a lift_weight_kg threshold applies to
+	SortOrder    int       `json:"sort_order"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+type ProfileAchievement struct {
+	ID            int       `json:"id"`
+	ProfileID     int       `json:"profile_id"`
+	AchievementID int       `json:"achievement_id"`
+	UnlockedAt    time.Time `json:"unlocked_at"`
+}
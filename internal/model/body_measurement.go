@@ -0,0 +1,49 @@
+package model
+
+import "time"
+
+// Metrics of a body measurement that can be charted over time
+const (
+	BodyMetricWeight     = "weight"
+	BodyMetricBodyFat    = "body_fat"
+	BodyMetricNeck       = "neck"
+	BodyMetricShoulders  = "shoulders"
+	BodyMetricChest      = "chest"
+	BodyMetricWaist      = "waist"
+	BodyMetricHips       = "hips"
+	BodyMetricLeftArm    = "left_arm"
+	BodyMetricRightArm   = "right_arm"
+	BodyMetricLeftThigh  = "left_thigh"
+	BodyMetricRightThigh = "right_thigh"
+	BodyMetricLeftCalf   = "left_calf"
+	BodyMetricRightCalf  = "right_calf"
+)
+
+// BodyMeasurement is one check-in of a profile. Every value is optional, but at least one is set.
+type BodyMeasurement struct {
+	ID             int       `json:"id"`
+	ProfileID      int       `json:"profile_id"`
+	MeasuredAt     time.Time `json:"measured_at"`
+	WeightKg       *float64  `json:"weight_kg"`
+	WeightLb       *float64  `json:"weight_lb"`
+	BodyFatPercent *float64  `json:"body_fat_percent"`
+	NeckCm         *float64  `json:"neck_cm"`
+	ShouldersCm    *float64  `json:"shoulders_cm"`
+	ChestCm        *float64  `json:"chest_cm"`
+	WaistCm        *float64  `json:"waist_cm"`
+	HipsCm         *float64  `json:"hips_cm"`
+	LeftArmCm      *float64  `json:"left_arm_cm"`
+	RightArmCm     *float64  `json:"right_arm_cm"`
+	LeftThighCm    *float64  `json:"left_thigh_cm"`
+	RightThighCm   *float64  `json:"right_thigh_cm"`
+	LeftCalfCm     *float64  `json:"left_calf_cm"`
+	RightCalfCm    *float64  `json:"right_calf_cm"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// BodyMetricPoint is the value of one metric at one point in time, in canonical units.
+type BodyMetricPoint struct {
+	MeasuredAt time.Time `json:"measured_at"`
+	Value      float64   `json:"value"`
+}
@@ -0,0 +1,23 @@
+package model
+
+import "time"
+
+// CalendarFeed is a profile's secret iCalendar subscription URL. Version fingerprints the
+// feed's content as of ChangedAt, the last time it was seen to change.
+type CalendarFeed struct {
+	ProfileID int       `json:"profile_id"`
+	Token     string    `json:"token"`
+	Version   *string   `json:"version"`
+	ChangedAt time.Time `json:"changed_at"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// CalendarFeedState holds what a profile's calendar feed is built from, cheap enough to read on
+// every poll to tell whether the feed changed.
+type CalendarFeedState struct {
+	WorkoutCount         int
+	WorkoutsUpdatedAt    *time.Time
+	EnrollmentCount      int
+	EnrollmentsUpdatedAt *time.Time
+	TemplatesUpdatedAt   *time.Time
+}
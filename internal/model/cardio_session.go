@@ -0,0 +1,28 @@
+package model
+
+import "time"
+
+type CardioSession struct {
+	ID               int       `json:"id"`
+	WorkoutID        int       `json:"workout_id"`
+	ActivityType     string    `json:"activity_type"`
+	SourceFormat     string    `json:"source_format"`
+	DistanceM        float64   `json:"distance_m"`
+	DurationSeconds  int       `json:"duration_seconds"`
+	PaceSecondsPerKm *float64  `json:"pace_seconds_per_km"`
+	ElevationGainM   *float64  `json:"elevation_gain_m"`
+	ElevationLossM   *float64  `json:"elevation_loss_m"`
+	AvgHeartRate     *int      `json:"avg_heart_rate"`
+	MaxHeartRate     *int      `json:"max_heart_rate"`
+	HasTrack         bool      `json:"has_track"`
+	CreatedAt        time.Time `json:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at"`
+}
+
+// CardioTrack is the simplified GPS track of a cardio session.
+type CardioTrack struct {
+	CardioSessionID    int         `json:"cardio_session_id"`
+	Coordinates        [][]float64 `json:"coordinates"` // [lon, lat] or [lon, lat, elevation], as in GeoJSON
+	OriginalPointCount int         `json:"original_point_count"`
+	CreatedAt          time.Time   `json:"created_at"`
+}
@@ -0,0 +1,14 @@
+package model
+
+import "time"
+
+type Exercise struct {
+	ID          int       `json:"id"`
+	ProfileID   *int      `json:"profile_id"` // nil for the default exercises available to everyone
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	IconName    string    `json:"icon_name"`
+	MuscleGroup string    `json:"muscle_group"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
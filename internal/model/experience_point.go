@@ -0,0 +1,23 @@
+package model
+
+import "time"
+
+const (
+	ExperiencePointEventWorkoutCompleted  = "workout_completed"
+	ExperiencePointEventPersonalRecordSet = "personal_record_set"
+	ExperiencePointEventStreakKept        = "streak_kept"
+	ExperiencePointEventGoalReached       = "goal_reached"
+	ExperiencePointEventLegacy            = "legacy" // earned before awards recorded their source
+)
+
+// ExperiencePointEntry is one award in the append-only ledger. Source identifies the event that
+// earned it, e.g. "workout:42", and is unique per profile so no event is rewarded twice.
+type ExperiencePointEntry struct {
+	ID        int       `json:"id"`
+	ProfileID int       `json:"profile_id"`
+	EventType string    `json:"event_type"`
+	Source    string    `json:"source"`
+	Reason    string    `json:"reason"`
+	Points    int       `json:"points"`
+	CreatedAt time.Time `json:"created_at"`
+}
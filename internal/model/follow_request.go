@@ -0,0 +1,24 @@
+package model
+
+import "time"
+
+const (
+	FollowStatusFollowing = "following"
+	FollowStatusRequested = "requested"
+)
+
+// FollowRequest is a pending request by RequesterProfileID to follow the private profile ProfileID.
+type FollowRequest struct {
+	ID                 int       `json:"id"`
+	ProfileID          int       `json:"profile_id"`
+	RequesterProfileID int       `json:"requester_profile_id"`
+	CreatedAt          time.Time `json:"created_at"`
+}
+
+// FollowRequestWithProfile is a follow request with the profile on the other side of it, the
+// requester in incoming requests and the requested profile in sent ones.
+type FollowRequestWithProfile struct {
+	FollowRequest
+	DisplayName   string `json:"display_name"`
+	AvatarVersion int    `json:"avatar_version"`
+}
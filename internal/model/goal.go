@@ -0,0 +1,36 @@
+package model
+
+import "time"
+
+const (
+	GoalTypeLiftWeight      = "lift_weight"
+	GoalTypeLiftE1RM        = "lift_e1rm"
+	GoalTypeWorkoutsPerWeek = "workouts_per_week"
+	GoalTypeBodyweight      = "bodyweight"
+)
+
+const (
+	GoalStatusActive    = "active"
+	GoalStatusCompleted = "completed"
+	GoalStatusExpired   = "expired"
+)
+
+// Goal is a target a profile works towards. Weights are in kg; a workouts per week goal counts
+// workouts in TargetValue and needs that many in TargetWeeks consecutive weeks.
+type Goal struct {
+	ID           int        `json:"id"`
+	ProfileID    int        `json:"profile_id"`
+	Type         string     `json:"type"`
+	ExerciseID   *int       `json:"exercise_id"`   // set for lift goals
+	ExerciseName string     `json:"exercise_name"` // joined from exercises
+	TargetValue  float64    `json:"target_value"`
+	TargetWeeks  int        `json:"target_weeks"`
+	StartValue   *float64   `json:"start_value"` // the bodyweight when a bodyweight goal was set
+	CurrentValue float64    `json:"current_value"`
+	Progress     float64    `json:"progress"` // percentage, as of the last evaluation
+	Deadline     *time.Time `json:"deadline"`
+	Status       string     `json:"status"`
+	CompletedAt  *time.Time `json:"completed_at"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
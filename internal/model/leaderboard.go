@@ -0,0 +1,32 @@
+package model
+
+import "time"
+
+const (
+	LeaderboardScopeGlobal    = "global"
+	LeaderboardScopeCountry   = "country"
+	LeaderboardScopeFollowing = "following" // the profiles the viewer follows and the viewer
+)
+
+const (
+	LeaderboardPeriodAllTime = "all_time"
+	LeaderboardPeriodWeek    = "week"
+	LeaderboardPeriodMonth   = "month"
+)
+
+// LeaderboardFilter picks the profiles on a leaderboard and the points they are ranked by.
+type LeaderboardFilter struct {
+	ViewerProfileID int
+	Scope           string
+	Country         string    // for the country scope
+	Since           time.Time // only points earned since count, zero for all time
+}
+
+type LeaderboardEntry struct {
+	ProfileID     int    `json:"profile_id"`
+	DisplayName   string `json:"display_name"`
+	Handle        string `json:"handle"`
+	AvatarVersion int    `json:"avatar_version"`
+	Points        int    `json:"points"`
+	Rank          int    `json:"rank"`
+}
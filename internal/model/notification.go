@@ -0,0 +1,25 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	NotificationTypeGoalCompleted         = "goal_completed"
+	NotificationTypeFollowRequestApproved = "follow_request_approved"
+	NotificationTypeAchievementUnlocked   = "achievement_unlocked"
+)
+
+type Notification struct {
+	ID        int `json:"id"`
+	ProfileID int `json:"profile_id"`
+	// ActorProfileID is the profile whose action caused the notification, nil for system notifications
+	ActorProfileID *int            `json:"actor_profile_id"`
+	Type           string          `json:"type"`
+	Title          string          `json:"title"`
+	Body           string          `json:"body"`
+	Data           json.RawMessage `json:"data"` // details for the client to link to, e.g. the goal ID
+	ReadAt         *time.Time      `json:"read_at"`
+	CreatedAt      time.Time       `json:"created_at"`
+}
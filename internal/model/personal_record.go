@@ -0,0 +1,38 @@
+package model
+
+import "time"
+
+const (
+	PersonalRecordHeaviestWeight = "heaviest_weight"
+	PersonalRecordBestE1RM       = "best_e1rm"
+	PersonalRecordMostReps       = "most_reps"
+	PersonalRecordBestVolume     = "best_volume"
+)
+
+const (
+	OneRepMaxFormulaEpley   = "epley"
+	OneRepMaxFormulaBrzycki = "brzycki"
+)
+
+type PersonalRecord struct {
+	ID            int       `json:"id"`
+	ProfileID     int       `json:"profile_id"`
+	ExerciseID    int       `json:"exercise_id"`
+	WorkoutID     int       `json:"workout_id"`
+	SetID         int       `json:"set_id"`
+	RecordType    string    `json:"record_type"`
+	WeightKg      float64   `json:"weight_kg"`
+	Reps          int       `json:"reps"`
+	Value         float64   `json:"value"`
+	PreviousValue *float64  `json:"previous_value"` // nil when this is the first record of its type
+	AchievedAt    time.Time `json:"achieved_at"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// ExerciseBests holds a profile's best values for one exercise before a given point in time.
+type ExerciseBests struct {
+	HeaviestWeightKg float64
+	BestE1RMKg       float64
+	BestVolumeKg     float64
+	MostRepsByWeight map[float64]int
+}
@@ -3,21 +3,61 @@ package model
 import "time"
 
 type Profile struct {
-	ID                     int       `json:"id"`
-	UserID                 int       `json:"user_id"`
-	DisplayName            string    `json:"display_name"`
-	Privacy                string    `json:"privacy"`
-	AvatarVersion          int       `json:"avatar_version"`
-	IsNotificationsEnabled bool      `json:"is_notifications_enabled"`
-	FitnessExperience      string    `json:"fitness_experience"`
-	ExperiencePoints       int       `json:"experience_points"`
-	CreatedAt              time.Time `json:"created_at"`
-	UpdatedAt              time.Time `json:"updated_at"`
+	ID                     int        `json:"id"`
+	UserID                 int        `json:"user_id"`
+	DisplayName            string     `json:"display_name"`
+	Handle                 string     `json:"handle"`
+	HandleChangedAt        *time.Time `json:"handle_changed_at"`
+	Privacy                string     `json:"privacy"`
+	Role                   string     `json:"role"`
+	AvatarVersion          int        `json:"avatar_version"`
+	IsNotificationsEnabled bool       `json:"is_notifications_enabled"`
+	FitnessExperience      string     `json:"fitness_experience"`
+	OneRepMaxFormula       string     `json:"one_rep_max_formula"`
+	WeightUnit             string     `json:"weight_unit"`
+	WeightIncrement        float64    `json:"weight_increment"` // in WeightUnit
+	BarWeightKg            float64    `json:"bar_weight_kg"`
+	ExperiencePoints       int        `json:"experience_points"`
+	Level                  int        `json:"level"` // derived from ExperiencePoints by the level curve
+	CreatedAt              time.Time  `json:"created_at"`
+	UpdatedAt              time.Time  `json:"updated_at"`
 }
 
+// DefaultHandlePrefix followed by the profile ID is the handle every profile starts with.
+const DefaultHandlePrefix = "athlete"
+
+const (
+	ProfileRoleMember = "member"
+	ProfileRoleCoach  = "coach"
+	ProfileRoleAdmin  = "admin"
+)
+
 type ProfileWithUser struct {
 	Profile
 	FirstName string `json:"first_name"`
 	LastName  string `json:"last_name"`
 	Country   string `json:"country"`
 }
+
+// ProfileSearchResult is a profile matching a search, with what made it rank where it did.
+type ProfileSearchResult struct {
+	ID                   int    `json:"id"`
+	DisplayName          string `json:"display_name"`
+	Handle               string `json:"handle"`
+	AvatarVersion        int    `json:"avatar_version"`
+	Privacy              string `json:"privacy"`
+	Following            bool   `json:"following"`              // the searcher follows the profile
+	MutualFollowersCount int    `json:"mutual_followers_count"` // profiles the searcher follows that follow the profile
+	SameCountry          bool   `json:"same_country"`
+	Score                int64  `json:"score"`
+}
+
+// ProfileHandle is a handle the profile changed away from. Until ExpiresAt it still leads to the
+// profile and nobody else can take it.
+type ProfileHandle struct {
+	ID        int       `json:"id"`
+	ProfileID int       `json:"profile_id"`
+	Handle    string    `json:"handle"`
+	ExpiresAt time.Time `json:"expires_at"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,28 @@
+package model
+
+import "time"
+
+// ProfileBlock hides ProfileID and BlockedProfileID from each other entirely.
+type ProfileBlock struct {
+	ID               int       `json:"id"`
+	ProfileID        int       `json:"profile_id"`
+	BlockedProfileID int       `json:"blocked_profile_id"`
+	CreatedAt        time.Time `json:"created_at"`
+}
+
+// ProfileMute hides MutedProfileID's content from ProfileID's feed and notifications only.
+type ProfileMute struct {
+	ID             int       `json:"id"`
+	ProfileID      int       `json:"profile_id"`
+	MutedProfileID int       `json:"muted_profile_id"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// RestrictedProfile is a blocked or muted profile as listed to the profile that restricted it.
+type RestrictedProfile struct {
+	ID            int       `json:"id"`
+	ProfileID     int       `json:"profile_id"`
+	DisplayName   string    `json:"display_name"`
+	AvatarVersion int       `json:"avatar_version"`
+	CreatedAt     time.Time `json:"created_at"`
+}
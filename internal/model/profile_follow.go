@@ -8,3 +8,11 @@ type ProfileFollow struct {
 	FollowerProfileID int       `json:"follower_profile_id"`
 	CreatedAt         time.Time `json:"created_at"`
 }
+
+// ProfileFollowWithProfile is a follow with the profile on the listed side of it, the follower in
+// follower lists and the followed profile in following lists.
+type ProfileFollowWithProfile struct {
+	ProfileFollow
+	DisplayName   string `json:"display_name"`
+	AvatarVersion int    `json:"avatar_version"`
+}
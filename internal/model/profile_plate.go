@@ -0,0 +1,11 @@
+package model
+
+import "time"
+
+type ProfilePlate struct {
+	ID        int       `json:"id"`
+	ProfileID int       `json:"profile_id"`
+	WeightKg  float64   `json:"weight_kg"`
+	PairCount int       `json:"pair_count"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,23 @@
+package model
+
+import "time"
+
+// ProfileDailyStats is the per-day training aggregate of a profile. Rows are adjusted incrementally
+// whenever a workout is saved or removed, so stats never have to be computed from raw sets.
+type ProfileDailyStats struct {
+	ProfileID            int       `json:"profile_id"`
+	StatDate             time.Time `json:"stat_date"`
+	WorkoutCount         int       `json:"workout_count"`
+	TotalDurationSeconds int       `json:"total_duration_seconds"`
+	TotalVolumeKg        float64   `json:"total_volume_kg"`
+	TotalSets            int       `json:"total_sets"`
+	MentalEnergySum      int       `json:"mental_energy_sum"`
+	PhysicalEnergySum    int       `json:"physical_energy_sum"`
+}
+
+type ProfileDailyMuscleGroupStats struct {
+	ProfileID   int       `json:"profile_id"`
+	StatDate    time.Time `json:"stat_date"`
+	MuscleGroup string    `json:"muscle_group"`
+	SetCount    int       `json:"set_count"`
+}
@@ -0,0 +1,59 @@
+package model
+
+import "time"
+
+const (
+	ProgramStatusDraft     = "draft"
+	ProgramStatusPublished = "published"
+
+	ProgressionRuleLinear               = "linear"
+	ProgressionRulePercentOfTrainingMax = "percent_of_training_max"
+
+	ProgramEnrollmentStatusActive    = "active"
+	ProgramEnrollmentStatusPaused    = "paused"
+	ProgramEnrollmentStatusCompleted = "completed"
+)
+
+type Program struct {
+	ID            int        `json:"id"`
+	ProfileID     int        `json:"profile_id"`
+	Name          string     `json:"name"`
+	Description   string     `json:"description"`
+	DurationWeeks int        `json:"duration_weeks"`
+	Status        string     `json:"status"`
+	PublishedAt   *time.Time `json:"published_at"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+type ProgramDay struct {
+	ID         int `json:"id"`
+	ProgramID  int `json:"program_id"`
+	WeekNumber int `json:"week_number"`
+	DayNumber  int `json:"day_number"`
+	TemplateID int `json:"template_id"`
+}
+
+type ProgressionRule struct {
+	ID                 int     `json:"id"`
+	ProgramID          int     `json:"program_id"`
+	ExerciseID         int     `json:"exercise_id"`
+	RuleType           string  `json:"rule_type"`
+	IncrementKg        float64 `json:"increment_kg"`
+	TrainingMaxPercent float64 `json:"training_max_percent"`
+	// WeekPercentages holds, for each week of the cycle, the percent of training max for each set.
+	WeekPercentages [][]float64 `json:"week_percentages"`
+}
+
+type ProgramEnrollment struct {
+	ID             int        `json:"id"`
+	ProfileID      int        `json:"profile_id"`
+	ProgramID      int        `json:"program_id"`
+	Status         string     `json:"status"`
+	CurrentSession int        `json:"current_session"`
+	StartedAt      time.Time  `json:"started_at"`
+	PausedAt       *time.Time `json:"paused_at"`
+	CompletedAt    *time.Time `json:"completed_at"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
@@ -1 +1,35 @@
 package model
+
+import "time"
+
+type Set struct {
+	ID         int       `json:"id"`
+	WorkoutID  int       `json:"workout_id"`
+	ExerciseID int       `json:"exercise_id"`
+	SetNumber  int       `json:"set_number"`
+	Duration   int       `json:"duration"`
+	WeightKg   float64   `json:"weight_kg"`
+	WeightLb   float64   `json:"weight_lb"`
+	Reps       int       `json:"reps"`
+	E1RMKg     float64   `json:"estimated_one_rep_max_kg"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// WorkoutExerciseSummary condenses the sets of one exercise within a workout.
+type WorkoutExerciseSummary struct {
+	WorkoutID   int     `json:"workout_id"`
+	ExerciseID  int     `json:"exercise_id"`
+	Name        string  `json:"name"`
+	IconName    string  `json:"icon_name"`
+	SetCount    int     `json:"set_count"`
+	TotalReps   int     `json:"total_reps"`
+	MaxWeightKg float64 `json:"max_weight_kg"`
+}
+
+// ExerciseE1RM is the best estimated one rep max of a profile for an exercise.
+type ExerciseE1RM struct {
+	ExerciseID int     `json:"exercise_id"`
+	Name       string  `json:"name"`
+	E1RMKg     float64 `json:"e1rm_kg"`
+}
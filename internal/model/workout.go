@@ -2,15 +2,38 @@ package model
 
 import "time"
 
+const (
+	WorkoutVisibilityPublic    = "public"
+	WorkoutVisibilityFollowers = "followers"
+	WorkoutVisibilityPrivate   = "private"
+)
+
 type Workout struct {
 	ID                  int       `json:"id"`
 	ProfileID           int       `json:"profile_id"`
 	Name                string    `json:"name"`
 	Description         string    `json:"description"`
+	Visibility          string    `json:"visibility"`
 	MentalEnergyLevel   int       `json:"mental_energy_level"`
 	PhysicalEnergyLevel int       `json:"physical_energy_level"`
 	StartDate           time.Time `json:"start_date"`
 	EndDate             time.Time `json:"end_date"`
+	ImportKey           *string   `json:"import_key"` // identifies workouts brought in from another app
 	CreatedAt           time.Time `json:"created_at"`
 	UpdatedAt           time.Time `json:"updated_at"`
 }
+
+type WorkoutWithProfile struct {
+	Workout
+	DisplayName   string `json:"display_name"`
+	AvatarVersion int    `json:"avatar_version"`
+}
+
+// WorkoutExportRow is a workout joined with one of its sets. Workouts without sets have a single
+// row with a nil Set.
+type WorkoutExportRow struct {
+	Workout
+	Set          *Set
+	ExerciseName string
+	MuscleGroup  string
+}
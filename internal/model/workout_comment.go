@@ -1 +1,12 @@
 package model
+
+import "time"
+
+type WorkoutComment struct {
+	ID        int       `json:"id"`
+	WorkoutID int       `json:"workout_id"`
+	ProfileID int       `json:"profile_id"`
+	Comment   string    `json:"comment"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
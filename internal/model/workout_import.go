@@ -0,0 +1,40 @@
+package model
+
+import "time"
+
+const (
+	WorkoutImportSourceStrong = "strong"
+	WorkoutImportSourceHevy   = "hevy"
+
+	WorkoutImportStatusProcessing = "processing"
+	WorkoutImportStatusCompleted  = "completed"
+	WorkoutImportStatusFailed     = "failed"
+)
+
+type WorkoutImport struct {
+	ID               int        `json:"id"`
+	ProfileID        int        `json:"profile_id"`
+	Source           string     `json:"source"`
+	Status           string     `json:"status"`
+	FileName         string     `json:"file_name"`
+	FileHash         string     `json:"file_hash"`
+	WeightUnit       string     `json:"weight_unit"`
+	TotalRows        int        `json:"total_rows"`
+	ImportedWorkouts int        `json:"imported_workouts"`
+	ImportedSets     int        `json:"imported_sets"`
+	SkippedWorkouts  int        `json:"skipped_workouts"` // already imported by an earlier job
+	CreatedExercises int        `json:"created_exercises"`
+	ErrorCount       int        `json:"error_count"`
+	FailureReason    string     `json:"failure_reason"`
+	CreatedAt        time.Time  `json:"created_at"`
+	CompletedAt      *time.Time `json:"completed_at"`
+}
+
+// WorkoutImportError is a row of an import file that could not be imported.
+type WorkoutImportError struct {
+	ID         int    `json:"id"`
+	ImportID   int    `json:"import_id"`
+	LineNumber int    `json:"line_number"`
+	Message    string `json:"message"`
+	RawRow     string `json:"raw_row"`
+}
@@ -0,0 +1,16 @@
+package model
+
+import "time"
+
+// WorkoutPhoto is a photo attached to a workout. The files themselves live in the blob storage.
+type WorkoutPhoto struct {
+	ID           int       `json:"id"`
+	WorkoutID    int       `json:"workout_id"`
+	StorageKey   string    `json:"storage_key"`
+	ThumbnailKey string    `json:"thumbnail_key"`
+	ContentType  string    `json:"content_type"`
+	Width        int       `json:"width"`
+	Height       int       `json:"height"`
+	SizeBytes    int       `json:"size_bytes"`
+	CreatedAt    time.Time `json:"created_at"`
+}
@@ -2,6 +2,23 @@ package model
 
 import "time"
 
+const (
+	WorkoutReactionLike      = "like"
+	WorkoutReactionBicepFlex = "bicep_flex"
+	WorkoutReactionFire      = "fire"
+	WorkoutReactionCold      = "cold"
+	WorkoutReactionStar      = "star"
+)
+
+// WorkoutReactionTypes lists every value allowed by the workout_reactions.reaction enum.
+var WorkoutReactionTypes = []string{
+	WorkoutReactionLike,
+	WorkoutReactionBicepFlex,
+	WorkoutReactionFire,
+	WorkoutReactionCold,
+	WorkoutReactionStar,
+}
+
 type WorkoutReaction struct {
 	ID        int       `json:"id"`
 	WorkoutID int       `json:"workout_id"`
@@ -10,3 +27,9 @@ type WorkoutReaction struct {
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
 }
+
+type WorkoutReactionWithProfile struct {
+	WorkoutReaction
+	DisplayName   string `json:"display_name"`
+	AvatarVersion int    `json:"avatar_version"`
+}
@@ -0,0 +1,40 @@
+package model
+
+import "time"
+
+const (
+	WorkoutSessionStatusActive    = "active"
+	WorkoutSessionStatusPaused    = "paused"
+	WorkoutSessionStatusFinished  = "finished"
+	WorkoutSessionStatusAbandoned = "abandoned"
+)
+
+// WorkoutSession is a workout being logged live. Finishing it saves a regular workout.
+type WorkoutSession struct {
+	ID                  int        `json:"id"`
+	ProfileID           int        `json:"profile_id"`
+	Name                string     `json:"name"`
+	Description         string     `json:"description"`
+	ProgramEnrollmentID *int       `json:"program_enrollment_id"`
+	Status              string     `json:"status"`
+	StartedAt           time.Time  `json:"started_at"`
+	PausedAt            *time.Time `json:"paused_at"`
+	PausedSeconds       int        `json:"paused_seconds"` // total of the completed pauses
+	LastActivityAt      time.Time  `json:"last_activity_at"`
+	FinishedAt          *time.Time `json:"finished_at"`
+	WorkoutID           *int       `json:"workout_id"`
+	CreatedAt           time.Time  `json:"created_at"`
+	UpdatedAt           time.Time  `json:"updated_at"`
+}
+
+type WorkoutSessionSet struct {
+	ID         int       `json:"id"`
+	SessionID  int       `json:"session_id"`
+	ExerciseID int       `json:"exercise_id"`
+	SetNumber  int       `json:"set_number"`
+	Duration   int       `json:"duration"`
+	WeightKg   float64   `json:"weight_kg"`
+	Reps       int       `json:"reps"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
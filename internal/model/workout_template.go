@@ -0,0 +1,25 @@
+package model
+
+import "time"
+
+type WorkoutTemplate struct {
+	ID               int       `json:"id"`
+	ProfileID        int       `json:"profile_id"`
+	Name             string    `json:"name"`
+	Description      string    `json:"description"`
+	ShareToken       *string   `json:"share_token"` // nil while the template is not shared
+	SourceTemplateID *int      `json:"source_template_id"`
+	CreatedAt        time.Time `json:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at"`
+}
+
+type WorkoutTemplateSet struct {
+	ID             int     `json:"id"`
+	TemplateID     int     `json:"template_id"`
+	ExerciseID     int     `json:"exercise_id"`
+	ExerciseOrder  int     `json:"exercise_order"`
+	SetNumber      int     `json:"set_number"`
+	TargetReps     int     `json:"target_reps"`
+	TargetWeightKg float64 `json:"target_weight_kg"`
+	TargetDuration int     `json:"target_duration"`
+}
@@ -0,0 +1,163 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/brightside-dev/ronin-fitness-be/database/client"
+	"github.com/brightside-dev/ronin-fitness-be/internal/model"
+)
+
+type AchievementRepository interface {
+	GetAll(ctx context.Context) ([]model.Achievement, error)
+	GetUnlocked(ctx context.Context, profileID int) ([]model.ProfileAchievement, error)
+	Unlock(ctx context.Context, tx *sql.Tx, profileID int, achievementID int, unlockedAt time.Time) (bool, error)
+	GetMetric(ctx context.Context, profileID int, metric string, exerciseName string) (float64, error)
+}
+
+type achievementRepository struct {
+	db client.DatabaseService
+}
+
+func NewAchievementRepository(db client.DatabaseService) AchievementRepository {
+	return &achievementRepository{db: db}
+}
+
+// achievementMetricQueries measure each metric of a profile, taking the profile ID and, for lifts,
+// the lowercased exercise name.
+var achievementMetricQueries = map[string]string{
+	model.AchievementMetricWorkoutCount:        `SELECT COUNT(*) FROM workouts WHERE profile_id = ?`,
+	model.AchievementMetricPersonalRecordCount: `SELECT COUNT(*) FROM personal_records WHERE profile_id = ?`,
+	// Consecutive days share the difference between the date and its row number
+	model.AchievementMetricStreakDays: `
+		SELECT COALESCE(MAX(days), 0)
+		FROM (
+			SELECT COUNT(*) AS days
+			FROM (
+				SELECT DATE_SUB(stat_date, INTERVAL ROW_NUMBER() OVER (ORDER BY stat_date) DAY) AS streak_start
+				FROM profile_daily_stats
+				WHERE profile_id = ? AND workout_count > 0
+			) training_days
+			GROUP BY streak_start
+		) streaks
+	`,
+	model.AchievementMetricLiftWeightKg: `
+		SELECT COALESCE(MAX(pr.weight_kg), 0)
+		FROM personal_records pr
+		INNER JOIN exercises e ON e.id = pr.exercise_id
+		WHERE pr.profile_id = ? AND pr.record_type = 'heaviest_weight' AND LOWER(e.name) = ?
+	`,
+	model.AchievementMetricFollowerCount:  `SELECT COUNT(*) FROM profile_follows WHERE profile_id = ?`,
+	model.AchievementMetricFollowingCount: `SELECT COUNT(*) FROM profile_follows WHERE follower_profile_id = ?`,
+}
+
+// GetAll returns the catalogue in display order.
+func (r *achievementRepository) GetAll(ctx context.Context) ([]model.Achievement, error) {
+	query := `
+		SELECT id, code, name, description, metric, threshold, exercise_name, sort_order, created_at
+		FROM achievements
+		ORDER BY sort_order, id
+	`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	achievements := []model.Achievement{}
+	for rows.Next() {
+		var achievement model.Achievement
+		if err := rows.Scan(
+			&achievement.ID,
+			&achievement.Code,
+			&achievement.Name,
+			&achievement.Description,
+			&achievement.Metric,
+			&achievement.Threshold,
+			&achievement.ExerciseName,
+			&achievement.SortOrder,
+			&achievement.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		achievements = append(achievements, achievement)
+	}
+
+	return achievements, rows.Err()
+}
+
+// GetUnlocked returns the achievements the profile unlocked, most recent first.
+func (r *achievementRepository) GetUnlocked(ctx context.Context, profileID int) ([]model.ProfileAchievement, error) {
+	query := `
+		SELECT id, profile_id, achievement_id, unlocked_at
+		FROM profile_achievements
+		WHERE profile_id = ?
+		ORDER BY unlocked_at DESC, id DESC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, profileID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	profileAchievements := []model.ProfileAchievement{}
+	for rows.Next() {
+		var profileAchievement model.ProfileAchievement
+		if err := rows.Scan(
+			&profileAchievement.ID,
+			&profileAchievement.ProfileID,
+			&profileAchievement.AchievementID,
+			&profileAchievement.UnlockedAt,
+		); err != nil {
+			return nil, err
+		}
+		profileAchievements = append(profileAchievements, profileAchievement)
+	}
+
+	return profileAchievements, rows.Err()
+}
+
+// Unlock awards the achievement to the profile, reporting false if it was already unlocked, e.g. by a
+// concurrent evaluation.
+func (r *achievementRepository) Unlock(ctx context.Context, tx *sql.Tx, profileID int, achievementID int, unlockedAt time.Time) (bool, error) {
+	query := `
+		INSERT INTO profile_achievements (profile_id, achievement_id, unlocked_at)
+		VALUES (?, ?, ?)
+		ON DUPLICATE KEY UPDATE id = id
+	`
+
+	res, err := tx.ExecContext(ctx, query, profileID, achievementID, unlockedAt)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affected > 0, nil
+}
+
+// GetMetric measures the metric for the profile. The exercise name is only used by lift_weight_kg.
+func (r *achievementRepository) GetMetric(ctx context.Context, profileID int, metric string, exerciseName string) (float64, error) {
+	query, ok := achievementMetricQueries[metric]
+	if !ok {
+		return 0, fmt.Errorf("unknown achievement metric %q", metric)
+	}
+
+	args := []interface{}{profileID}
+	if metric == model.AchievementMetricLiftWeightKg {
+		args = append(args, strings.ToLower(exerciseName))
+	}
+
+	var value float64
+	err := r.db.QueryRowContext(ctx, query, args...).Scan(&value)
+
+	return value, err
+}
@@ -0,0 +1,259 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/brightside-dev/ronin-fitness-be/database/client"
+	"github.com/brightside-dev/ronin-fitness-be/internal/model"
+)
+
+type BodyMeasurementRepository interface {
+	GetByID(ctx context.Context, id int) (*model.BodyMeasurement, error)
+	GetByProfileID(ctx context.Context, profileID int, beforeTime time.Time, beforeID int, limit int) ([]model.BodyMeasurement, error)
+	GetMetricPoints(ctx context.Context, profileID int, metric string, from time.Time, to time.Time) ([]model.BodyMetricPoint, error)
+	GetLatestWeightKg(ctx context.Context, profileID int, before time.Time) (*float64, error)
+	Create(ctx context.Context, measurement *model.BodyMeasurement) (*model.BodyMeasurement, error)
+	Update(ctx context.Context, measurement *model.BodyMeasurement) error
+	Delete(ctx context.Context, id int) error
+}
+
+type bodyMeasurementRepository struct {
+	db client.DatabaseService
+}
+
+func NewBodyMeasurementRepository(db client.DatabaseService) BodyMeasurementRepository {
+	return &bodyMeasurementRepository{db: db}
+}
+
+// bodyMeasurementValueColumns lists the nullable value columns in the order of bodyMeasurementValues.
+const bodyMeasurementValueColumns = `
+	weight_kg, weight_lb, body_fat_percent, neck_cm, shoulders_cm, chest_cm, waist_cm, hips_cm,
+	left_arm_cm, right_arm_cm, left_thigh_cm, right_thigh_cm, left_calf_cm, right_calf_cm
+`
+
+const bodyMeasurementColumns = `id, profile_id, measured_at, ` + bodyMeasurementValueColumns + `, created_at, updated_at`
+
+// bodyMetricColumns maps the chartable metrics to their canonical unit column.
+var bodyMetricColumns = map[string]string{
+	model.BodyMetricWeight:     "weight_kg",
+	model.BodyMetricBodyFat:    "body_fat_percent",
+	model.BodyMetricNeck:       "neck_cm",
+	model.BodyMetricShoulders:  "shoulders_cm",
+	model.BodyMetricChest:      "chest_cm",
+	model.BodyMetricWaist:      "waist_cm",
+	model.BodyMetricHips:       "hips_cm",
+	model.BodyMetricLeftArm:    "left_arm_cm",
+	model.BodyMetricRightArm:   "right_arm_cm",
+	model.BodyMetricLeftThigh:  "left_thigh_cm",
+	model.BodyMetricRightThigh: "right_thigh_cm",
+	model.BodyMetricLeftCalf:   "left_calf_cm",
+	model.BodyMetricRightCalf:  "right_calf_cm",
+}
+
+func bodyMeasurementValues(measurement *model.BodyMeasurement) []**float64 {
+	return []**float64{
+		&measurement.WeightKg,
+		&measurement.WeightLb,
+		&measurement.BodyFatPercent,
+		&measurement.NeckCm,
+		&measurement.ShouldersCm,
+		&measurement.ChestCm,
+		&measurement.WaistCm,
+		&measurement.HipsCm,
+		&measurement.LeftArmCm,
+		&measurement.RightArmCm,
+		&measurement.LeftThighCm,
+		&measurement.RightThighCm,
+		&measurement.LeftCalfCm,
+		&measurement.RightCalfCm,
+	}
+}
+
+func bodyMeasurementArgs(measurement *model.BodyMeasurement) []interface{} {
+	args := []interface{}{}
+	for _, value := range bodyMeasurementValues(measurement) {
+		args = append(args, *value)
+	}
+
+	return args
+}
+
+func scanBodyMeasurement(scanner interface{ Scan(...interface{}) error }) (*model.BodyMeasurement, error) {
+	var measurement model.BodyMeasurement
+	values := bodyMeasurementValues(&measurement)
+	nullValues := make([]sql.NullFloat64, len(values))
+
+	dest := []interface{}{&measurement.ID, &measurement.ProfileID, &measurement.MeasuredAt}
+	for i := range nullValues {
+		dest = append(dest, &nullValues[i])
+	}
+	dest = append(dest, &measurement.CreatedAt, &measurement.UpdatedAt)
+
+	err := scanner.Scan(dest...)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, nullValue := range nullValues {
+		if nullValue.Valid {
+			value := nullValue.Float64
+			*values[i] = &value
+		}
+	}
+
+	return &measurement, nil
+}
+
+func (r *bodyMeasurementRepository) GetByID(ctx context.Context, id int) (*model.BodyMeasurement, error) {
+	row := r.db.QueryRowContext(ctx, `SELECT `+bodyMeasurementColumns+` FROM body_measurements WHERE id = ?`, id)
+
+	measurement, err := scanBodyMeasurement(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+
+	return measurement, err
+}
+
+// GetByProfileID returns the profile's measurements, most recently measured first. Pass a zero
+// beforeTime for the first page.
+func (r *bodyMeasurementRepository) GetByProfileID(
+	ctx context.Context,
+	profileID int,
+	beforeTime time.Time,
+	beforeID int,
+	limit int,
+) ([]model.BodyMeasurement, error) {
+	query := `
+		SELECT ` + bodyMeasurementColumns + `
+		FROM body_measurements
+		WHERE profile_id = ?
+		AND (? OR (measured_at, id) < (?, ?))
+		ORDER BY measured_at DESC, id DESC
+		LIMIT ?
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, profileID, beforeTime.IsZero(), beforeTime, beforeID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	measurements := []model.BodyMeasurement{}
+	for rows.Next() {
+		measurement, err := scanBodyMeasurement(rows)
+		if err != nil {
+			return nil, err
+		}
+		measurements = append(measurements, *measurement)
+	}
+
+	return measurements, rows.Err()
+}
+
+// GetMetricPoints returns the recorded values of one metric measured within [from, to), oldest first.
+func (r *bodyMeasurementRepository) GetMetricPoints(
+	ctx context.Context,
+	profileID int,
+	metric string,
+	from time.Time,
+	to time.Time,
+) ([]model.BodyMetricPoint, error) {
+	column, ok := bodyMetricColumns[metric]
+	if !ok {
+		return nil, fmt.Errorf("unknown body metric %q", metric)
+	}
+
+	query := `
+		SELECT measured_at, ` + column + `
+		FROM body_measurements
+		WHERE profile_id = ? AND measured_at >= ? AND measured_at < ? AND ` + column + ` IS NOT NULL
+		ORDER BY measured_at, id
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, profileID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	points := []model.BodyMetricPoint{}
+	for rows.Next() {
+		var point model.BodyMetricPoint
+		if err := rows.Scan(&point.MeasuredAt, &point.Value); err != nil {
+			return nil, err
+		}
+		points = append(points, point)
+	}
+
+	return points, rows.Err()
+}
+
+// GetLatestWeightKg returns the last bodyweight the profile recorded before the given time, or nil.
+func (r *bodyMeasurementRepository) GetLatestWeightKg(ctx context.Context, profileID int, before time.Time) (*float64, error) {
+	query := `
+		SELECT weight_kg
+		FROM body_measurements
+		WHERE profile_id = ? AND measured_at < ? AND weight_kg IS NOT NULL
+		ORDER BY measured_at DESC, id DESC
+		LIMIT 1
+	`
+
+	var weightKg float64
+	err := r.db.QueryRowContext(ctx, query, profileID, before).Scan(&weightKg)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &weightKg, nil
+}
+
+func (r *bodyMeasurementRepository) Create(ctx context.Context, measurement *model.BodyMeasurement) (*model.BodyMeasurement, error) {
+	query := `
+		INSERT INTO body_measurements (profile_id, measured_at, ` + bodyMeasurementValueColumns + `)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	args := append([]interface{}{measurement.ProfileID, measurement.MeasuredAt}, bodyMeasurementArgs(measurement)...)
+	res, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return nil, err
+	}
+
+	return r.GetByID(ctx, int(id))
+}
+
+// Update replaces the measured time and every value of a measurement.
+func (r *bodyMeasurementRepository) Update(ctx context.Context, measurement *model.BodyMeasurement) error {
+	query := `
+		UPDATE body_measurements
+		SET measured_at = ?,
+			weight_kg = ?, weight_lb = ?, body_fat_percent = ?, neck_cm = ?, shoulders_cm = ?,
+			chest_cm = ?, waist_cm = ?, hips_cm = ?, left_arm_cm = ?, right_arm_cm = ?,
+			left_thigh_cm = ?, right_thigh_cm = ?, left_calf_cm = ?, right_calf_cm = ?
+		WHERE id = ?
+	`
+
+	args := append([]interface{}{measurement.MeasuredAt}, bodyMeasurementArgs(measurement)...)
+	args = append(args, measurement.ID)
+	_, err := r.db.ExecContext(ctx, query, args...)
+
+	return err
+}
+
+func (r *bodyMeasurementRepository) Delete(ctx context.Context, id int) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM body_measurements WHERE id = ?`, id)
+
+	return err
+}
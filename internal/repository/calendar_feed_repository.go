@@ -0,0 +1,157 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/brightside-dev/ronin-fitness-be/database/client"
+	"github.com/brightside-dev/ronin-fitness-be/internal/model"
+)
+
+type CalendarFeedRepository interface {
+	GetByProfileID(ctx context.Context, profileID int) (*model.CalendarFeed, error)
+	GetByToken(ctx context.Context, token string) (*model.CalendarFeed, error)
+	Upsert(ctx context.Context, profileID int, token string) (*model.CalendarFeed, error)
+	UpdateVersion(ctx context.Context, profileID int, version string, changedAt time.Time) error
+	Delete(ctx context.Context, profileID int) error
+	GetState(ctx context.Context, profileID int, workoutsFrom time.Time) (*model.CalendarFeedState, error)
+}
+
+type calendarFeedRepository struct {
+	db client.DatabaseService
+}
+
+func NewCalendarFeedRepository(db client.DatabaseService) CalendarFeedRepository {
+	return &calendarFeedRepository{db: db}
+}
+
+const calendarFeedColumns = `profile_id, token, version, changed_at, created_at`
+
+func scanCalendarFeed(scanner interface{ Scan(...interface{}) error }) (*model.CalendarFeed, error) {
+	var feed model.CalendarFeed
+	var version sql.NullString
+	err := scanner.Scan(
+		&feed.ProfileID,
+		&feed.Token,
+		&version,
+		&feed.ChangedAt,
+		&feed.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if version.Valid {
+		feed.Version = &version.String
+	}
+
+	return &feed, nil
+}
+
+func (r *calendarFeedRepository) GetByProfileID(ctx context.Context, profileID int) (*model.CalendarFeed, error) {
+	row := r.db.QueryRowContext(ctx, `SELECT `+calendarFeedColumns+` FROM calendar_feeds WHERE profile_id = ?`, profileID)
+
+	feed, err := scanCalendarFeed(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+
+	return feed, err
+}
+
+func (r *calendarFeedRepository) GetByToken(ctx context.Context, token string) (*model.CalendarFeed, error) {
+	row := r.db.QueryRowContext(ctx, `SELECT `+calendarFeedColumns+` FROM calendar_feeds WHERE token = ?`, token)
+
+	feed, err := scanCalendarFeed(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+
+	return feed, err
+}
+
+// Upsert gives the profile a feed with token, replacing the token of an existing feed so its old URL stops working.
+func (r *calendarFeedRepository) Upsert(ctx context.Context, profileID int, token string) (*model.CalendarFeed, error) {
+	query := `
+		INSERT INTO calendar_feeds (profile_id, token) VALUES (?, ?)
+		ON DUPLICATE KEY UPDATE token = VALUES(token), version = NULL, changed_at = NOW(), created_at = NOW()
+	`
+
+	_, err := r.db.ExecContext(ctx, query, profileID, token)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.GetByProfileID(ctx, profileID)
+}
+
+// UpdateVersion records the feed's current content version, moving changed_at only if the version differs.
+func (r *calendarFeedRepository) UpdateVersion(ctx context.Context, profileID int, version string, changedAt time.Time) error {
+	query := `
+		UPDATE calendar_feeds
+		SET version = ?, changed_at = ?
+		WHERE profile_id = ? AND (version IS NULL OR version <> ?)
+	`
+
+	_, err := r.db.ExecContext(ctx, query, version, changedAt, profileID, version)
+
+	return err
+}
+
+func (r *calendarFeedRepository) Delete(ctx context.Context, profileID int) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM calendar_feeds WHERE profile_id = ?`, profileID)
+
+	return err
+}
+
+// GetState reads the counts and last update times of everything the profile's feed shows: the workouts
+// that started from workoutsFrom, the program enrollments and the templates of active programs.
+func (r *calendarFeedRepository) GetState(ctx context.Context, profileID int, workoutsFrom time.Time) (*model.CalendarFeedState, error) {
+	query := `
+		SELECT
+			(SELECT COUNT(*) FROM workouts WHERE profile_id = ? AND start_date >= ?),
+			(SELECT MAX(updated_at) FROM workouts WHERE profile_id = ? AND start_date >= ?),
+			(SELECT COUNT(*) FROM program_enrollments WHERE profile_id = ?),
+			(
+				SELECT MAX(GREATEST(e.updated_at, p.updated_at))
+				FROM program_enrollments e
+				INNER JOIN programs p ON p.id = e.program_id
+				WHERE e.profile_id = ?
+			),
+			(
+				SELECT MAX(t.updated_at)
+				FROM program_enrollments e
+				INNER JOIN program_days d ON d.program_id = e.program_id
+				INNER JOIN workout_templates t ON t.id = d.template_id
+				WHERE e.profile_id = ? AND e.status = 'active'
+			)
+	`
+
+	var state model.CalendarFeedState
+	var workoutsUpdatedAt, enrollmentsUpdatedAt, templatesUpdatedAt sql.NullTime
+	err := r.db.QueryRowContext(ctx, query,
+		profileID, workoutsFrom, profileID, workoutsFrom, profileID, profileID, profileID,
+	).Scan(
+		&state.WorkoutCount,
+		&workoutsUpdatedAt,
+		&state.EnrollmentCount,
+		&enrollmentsUpdatedAt,
+		&templatesUpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if workoutsUpdatedAt.Valid {
+		state.WorkoutsUpdatedAt = &workoutsUpdatedAt.Time
+	}
+	if enrollmentsUpdatedAt.Valid {
+		state.EnrollmentsUpdatedAt = &enrollmentsUpdatedAt.Time
+	}
+	if templatesUpdatedAt.Valid {
+		state.TemplatesUpdatedAt = &templatesUpdatedAt.Time
+	}
+
+	return &state, nil
+}
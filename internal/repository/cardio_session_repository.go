@@ -0,0 +1,176 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+
+	"github.com/brightside-dev/ronin-fitness-be/database/client"
+	"github.com/brightside-dev/ronin-fitness-be/internal/model"
+)
+
+type CardioSessionRepository interface {
+	GetByWorkoutIDs(ctx context.Context, workoutIDs []int) (map[int]model.CardioSession, error)
+	Create(ctx context.Context, tx *sql.Tx, session *model.CardioSession) (*model.CardioSession, error)
+	CreateTrack(ctx context.Context, tx *sql.Tx, track *model.CardioTrack) error
+	GetTrackByWorkoutID(ctx context.Context, workoutID int) (*model.CardioTrack, error)
+}
+
+type cardioSessionRepository struct {
+	db client.DatabaseService
+}
+
+func NewCardioSessionRepository(db client.DatabaseService) CardioSessionRepository {
+	return &cardioSessionRepository{db: db}
+}
+
+// GetByWorkoutIDs returns the cardio sessions of the workouts that have one, keyed by workout ID.
+func (r *cardioSessionRepository) GetByWorkoutIDs(ctx context.Context, workoutIDs []int) (map[int]model.CardioSession, error) {
+	sessions := map[int]model.CardioSession{}
+	if len(workoutIDs) == 0 {
+		return sessions, nil
+	}
+
+	placeholders, args := inClause(workoutIDs)
+	query := `
+		SELECT
+			cs.id, cs.workout_id, cs.activity_type, cs.source_format, cs.distance_m, cs.duration_seconds,
+			cs.pace_seconds_per_km, cs.elevation_gain_m, cs.elevation_loss_m, cs.avg_heart_rate, cs.max_heart_rate,
+			ct.cardio_session_id IS NOT NULL, cs.created_at, cs.updated_at
+		FROM cardio_sessions cs
+		LEFT JOIN cardio_tracks ct ON ct.cardio_session_id = cs.id
+		WHERE cs.workout_id IN (` + placeholders + `)
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var session model.CardioSession
+		var pace, elevationGain, elevationLoss sql.NullFloat64
+		var avgHeartRate, maxHeartRate sql.NullInt64
+		if err := rows.Scan(
+			&session.ID,
+			&session.WorkoutID,
+			&session.ActivityType,
+			&session.SourceFormat,
+			&session.DistanceM,
+			&session.DurationSeconds,
+			&pace,
+			&elevationGain,
+			&elevationLoss,
+			&avgHeartRate,
+			&maxHeartRate,
+			&session.HasTrack,
+			&session.CreatedAt,
+			&session.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+
+		if pace.Valid {
+			session.PaceSecondsPerKm = &pace.Float64
+		}
+		if elevationGain.Valid {
+			session.ElevationGainM = &elevationGain.Float64
+		}
+		if elevationLoss.Valid {
+			session.ElevationLossM = &elevationLoss.Float64
+		}
+		if avgHeartRate.Valid {
+			heartRate := int(avgHeartRate.Int64)
+			session.AvgHeartRate = &heartRate
+		}
+		if maxHeartRate.Valid {
+			heartRate := int(maxHeartRate.Int64)
+			session.MaxHeartRate = &heartRate
+		}
+
+		sessions[session.WorkoutID] = session
+	}
+
+	return sessions, rows.Err()
+}
+
+func (r *cardioSessionRepository) Create(ctx context.Context, tx *sql.Tx, session *model.CardioSession) (*model.CardioSession, error) {
+	query := `
+		INSERT INTO cardio_sessions
+			(workout_id, activity_type, source_format, distance_m, duration_seconds, pace_seconds_per_km,
+			elevation_gain_m, elevation_loss_m, avg_heart_rate, max_heart_rate)
+		VALUES
+			(?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	res, err := tx.ExecContext(
+		ctx, query,
+		session.WorkoutID,
+		session.ActivityType,
+		session.SourceFormat,
+		session.DistanceM,
+		session.DurationSeconds,
+		session.PaceSecondsPerKm,
+		session.ElevationGainM,
+		session.ElevationLossM,
+		session.AvgHeartRate,
+		session.MaxHeartRate,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return nil, err
+	}
+
+	session.ID = int(id)
+
+	return session, nil
+}
+
+func (r *cardioSessionRepository) CreateTrack(ctx context.Context, tx *sql.Tx, track *model.CardioTrack) error {
+	coordinates, err := json.Marshal(track.Coordinates)
+	if err != nil {
+		return err
+	}
+
+	query := `INSERT INTO cardio_tracks (cardio_session_id, coordinates, original_point_count) VALUES (?, ?, ?)`
+
+	_, err = tx.ExecContext(ctx, query, track.CardioSessionID, coordinates, track.OriginalPointCount)
+
+	return err
+}
+
+func (r *cardioSessionRepository) GetTrackByWorkoutID(ctx context.Context, workoutID int) (*model.CardioTrack, error) {
+	query := `
+		SELECT ct.cardio_session_id, ct.coordinates, ct.original_point_count, ct.created_at
+		FROM cardio_tracks ct
+		INNER JOIN cardio_sessions cs ON cs.id = ct.cardio_session_id
+		WHERE cs.workout_id = ?
+	`
+
+	var track model.CardioTrack
+	var coordinates []byte
+	err := r.db.QueryRowContext(ctx, query, workoutID).Scan(
+		&track.CardioSessionID,
+		&coordinates,
+		&track.OriginalPointCount,
+		&track.CreatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	err = json.Unmarshal(coordinates, &track.Coordinates)
+	if err != nil {
+		return nil, err
+	}
+
+	return &track, nil
+}
@@ -0,0 +1,155 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/brightside-dev/ronin-fitness-be/database/client"
+	"github.com/brightside-dev/ronin-fitness-be/internal/model"
+)
+
+type ExerciseRepository interface {
+	GetByID(ctx context.Context, id int) (*model.Exercise, error)
+	GetByIDs(ctx context.Context, ids []int) (map[int]model.Exercise, error)
+	GetAvailableByProfileID(ctx context.Context, profileID int) ([]model.Exercise, error)
+	Create(ctx context.Context, tx *sql.Tx, exercise *model.Exercise) (*model.Exercise, error)
+}
+
+type exerciseRepository struct {
+	db client.DatabaseService
+}
+
+func NewExerciseRepository(db client.DatabaseService) ExerciseRepository {
+	return &exerciseRepository{db: db}
+}
+
+func (r *exerciseRepository) GetByID(ctx context.Context, id int) (*model.Exercise, error) {
+	exercises, err := r.GetByIDs(ctx, []int{id})
+	if err != nil {
+		return nil, err
+	}
+
+	exercise, ok := exercises[id]
+	if !ok {
+		return nil, nil
+	}
+
+	return &exercise, nil
+}
+
+func (r *exerciseRepository) GetByIDs(ctx context.Context, ids []int) (map[int]model.Exercise, error) {
+	exercises := make(map[int]model.Exercise, len(ids))
+	if len(ids) == 0 {
+		return exercises, nil
+	}
+
+	placeholders, args := inClause(ids)
+	query := `
+		SELECT id, profile_id, name, description, icon_name, muscle_group, created_at, updated_at
+		FROM exercises
+		WHERE id IN (` + placeholders + `)
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var exercise model.Exercise
+		var profileID sql.NullInt64
+		if err := rows.Scan(
+			&exercise.ID,
+			&profileID,
+			&exercise.Name,
+			&exercise.Description,
+			&exercise.IconName,
+			&exercise.MuscleGroup,
+			&exercise.CreatedAt,
+			&exercise.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+
+		if profileID.Valid {
+			id := int(profileID.Int64)
+			exercise.ProfileID = &id
+		}
+
+		exercises[exercise.ID] = exercise
+	}
+
+	return exercises, rows.Err()
+}
+
+// GetAvailableByProfileID returns the default exercises and the profile's own, defaults first.
+func (r *exerciseRepository) GetAvailableByProfileID(ctx context.Context, profileID int) ([]model.Exercise, error) {
+	query := `
+		SELECT id, profile_id, name, description, icon_name, muscle_group, created_at, updated_at
+		FROM exercises
+		WHERE profile_id IS NULL OR profile_id = ?
+		ORDER BY profile_id IS NOT NULL, id
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, profileID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	exercises := []model.Exercise{}
+	for rows.Next() {
+		var exercise model.Exercise
+		var exerciseProfileID sql.NullInt64
+		if err := rows.Scan(
+			&exercise.ID,
+			&exerciseProfileID,
+			&exercise.Name,
+			&exercise.Description,
+			&exercise.IconName,
+			&exercise.MuscleGroup,
+			&exercise.CreatedAt,
+			&exercise.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+
+		if exerciseProfileID.Valid {
+			id := int(exerciseProfileID.Int64)
+			exercise.ProfileID = &id
+		}
+
+		exercises = append(exercises, exercise)
+	}
+
+	return exercises, rows.Err()
+}
+
+func (r *exerciseRepository) Create(ctx context.Context, tx *sql.Tx, exercise *model.Exercise) (*model.Exercise, error) {
+	query := `
+		INSERT INTO exercises (profile_id, name, description, icon_name, muscle_group)
+		VALUES (?, ?, ?, ?, COALESCE(NULLIF(?, ''), 'other'))
+	`
+
+	res, err := tx.ExecContext(
+		ctx, query,
+		exercise.ProfileID,
+		exercise.Name,
+		exercise.Description,
+		exercise.IconName,
+		exercise.MuscleGroup,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return nil, err
+	}
+
+	exercise.ID = int(id)
+
+	return exercise, nil
+}
@@ -0,0 +1,164 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/brightside-dev/ronin-fitness-be/database/client"
+	"github.com/brightside-dev/ronin-fitness-be/internal/model"
+)
+
+// ExperiencePointRepository reads and appends to the experience point ledger. Entries are never
+// updated or deleted, a correction is a new entry.
+type ExperiencePointRepository interface {
+	Create(ctx context.Context, tx *sql.Tx, entry *model.ExperiencePointEntry) (bool, error)
+	GetTotal(ctx context.Context, tx *sql.Tx, profileID int) (int, error)
+	GetBySources(ctx context.Context, tx *sql.Tx, profileID int, sources []string) ([]model.ExperiencePointEntry, error)
+	GetWorkoutEntries(ctx context.Context, tx *sql.Tx, profileID int, workoutID int) ([]model.ExperiencePointEntry, error)
+	GetByProfileID(ctx context.Context, profileID int, beforeTime time.Time, beforeID int, limit int) ([]model.ExperiencePointEntry, error)
+}
+
+type experiencePointRepository struct {
+	db client.DatabaseService
+}
+
+func NewExperiencePointRepository(db client.DatabaseService) ExperiencePointRepository {
+	return &experiencePointRepository{db: db}
+}
+
+// Create appends the entry, reporting false without adding anything when the profile already has an
+// entry for the same source.
+func (r *experiencePointRepository) Create(ctx context.Context, tx *sql.Tx, entry *model.ExperiencePointEntry) (bool, error) {
+	query := `
+		INSERT INTO experience_point_ledger (profile_id, event_type, source, reason, points)
+		VALUES (?, ?, ?, ?, ?)
+		ON DUPLICATE KEY UPDATE id = id
+	`
+
+	res, err := tx.ExecContext(ctx, query, entry.ProfileID, entry.EventType, entry.Source, entry.Reason, entry.Points)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	if affected == 0 {
+		return false, nil
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return false, err
+	}
+
+	entry.ID = int(id)
+
+	return true, nil
+}
+
+// GetTotal sums every entry of the profile.
+func (r *experiencePointRepository) GetTotal(ctx context.Context, tx *sql.Tx, profileID int) (int, error) {
+	var total int
+	err := tx.QueryRowContext(ctx, `SELECT COALESCE(SUM(points), 0) FROM experience_point_ledger WHERE profile_id = ?`, profileID).Scan(&total)
+
+	return total, err
+}
+
+const experiencePointEntryColumns = `id, profile_id, event_type, source, reason, points, created_at`
+
+// GetBySources returns the profile's entries for the given sources.
+func (r *experiencePointRepository) GetBySources(ctx context.Context, tx *sql.Tx, profileID int, sources []string) ([]model.ExperiencePointEntry, error) {
+	if len(sources) == 0 {
+		return []model.ExperiencePointEntry{}, nil
+	}
+
+	args := []interface{}{profileID}
+	for _, source := range sources {
+		args = append(args, source)
+	}
+
+	query := `
+		SELECT ` + experiencePointEntryColumns + `
+		FROM experience_point_ledger
+		WHERE profile_id = ? AND source IN (?` + strings.Repeat(", ?", len(sources)-1) + `)
+	`
+
+	return r.query(ctx, tx, query, args...)
+}
+
+// GetWorkoutEntries returns the entries awarded for the workout and the personal records set in it.
+func (r *experiencePointRepository) GetWorkoutEntries(ctx context.Context, tx *sql.Tx, profileID int, workoutID int) ([]model.ExperiencePointEntry, error) {
+	query := `
+		SELECT ` + experiencePointEntryColumns + `
+		FROM experience_point_ledger
+		WHERE profile_id = ?
+		AND (
+			source = CONCAT('workout:', ?)
+			OR source IN (SELECT CONCAT('personal_record:', pr.id) FROM personal_records pr WHERE pr.workout_id = ?)
+		)
+	`
+
+	return r.query(ctx, tx, query, profileID, workoutID, workoutID)
+}
+
+// GetByProfileID returns the profile's entries, newest first. Pass a zero beforeTime for the first page.
+func (r *experiencePointRepository) GetByProfileID(
+	ctx context.Context,
+	profileID int,
+	beforeTime time.Time,
+	beforeID int,
+	limit int,
+) ([]model.ExperiencePointEntry, error) {
+	query := `
+		SELECT ` + experiencePointEntryColumns + `
+		FROM experience_point_ledger
+		WHERE profile_id = ?
+		AND (? OR (created_at, id) < (?, ?))
+		ORDER BY created_at DESC, id DESC
+		LIMIT ?
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, profileID, beforeTime.IsZero(), beforeTime, beforeID, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return scanExperiencePointEntries(rows)
+}
+
+func (r *experiencePointRepository) query(ctx context.Context, tx *sql.Tx, query string, args ...interface{}) ([]model.ExperiencePointEntry, error) {
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	return scanExperiencePointEntries(rows)
+}
+
+func scanExperiencePointEntries(rows *sql.Rows) ([]model.ExperiencePointEntry, error) {
+	defer rows.Close()
+
+	entries := []model.ExperiencePointEntry{}
+	for rows.Next() {
+		var entry model.ExperiencePointEntry
+		if err := rows.Scan(
+			&entry.ID,
+			&entry.ProfileID,
+			&entry.EventType,
+			&entry.Source,
+			&entry.Reason,
+			&entry.Points,
+			&entry.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, rows.Err()
+}
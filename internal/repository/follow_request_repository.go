@@ -0,0 +1,182 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/brightside-dev/ronin-fitness-be/database/client"
+	"github.com/brightside-dev/ronin-fitness-be/internal/model"
+)
+
+type FollowRequestRepository interface {
+	GetByID(ctx context.Context, id int) (*model.FollowRequest, error)
+	GetByProfiles(ctx context.Context, profileID int, requesterProfileID int) (*model.FollowRequest, error)
+	GetIncoming(ctx context.Context, profileID int, beforeTime time.Time, beforeID int, limit int) ([]model.FollowRequestWithProfile, error)
+	GetSent(ctx context.Context, requesterProfileID int, beforeTime time.Time, beforeID int, limit int) ([]model.FollowRequestWithProfile, error)
+	Create(ctx context.Context, followRequest *model.FollowRequest) (*model.FollowRequest, error)
+	Delete(ctx context.Context, tx *sql.Tx, id int) (bool, error)
+	DeleteBetween(ctx context.Context, tx *sql.Tx, profileID int, otherProfileID int) error
+}
+
+type followRequestRepository struct {
+	db client.DatabaseService
+}
+
+func NewFollowRequestRepository(db client.DatabaseService) FollowRequestRepository {
+	return &followRequestRepository{db: db}
+}
+
+const followRequestColumns = `fr.id, fr.profile_id, fr.requester_profile_id, fr.created_at`
+
+func scanFollowRequest(scanner interface{ Scan(...interface{}) error }) (*model.FollowRequest, error) {
+	var followRequest model.FollowRequest
+	err := scanner.Scan(
+		&followRequest.ID,
+		&followRequest.ProfileID,
+		&followRequest.RequesterProfileID,
+		&followRequest.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &followRequest, nil
+}
+
+func (r *followRequestRepository) GetByID(ctx context.Context, id int) (*model.FollowRequest, error) {
+	row := r.db.QueryRowContext(ctx, `SELECT `+followRequestColumns+` FROM follow_requests fr WHERE fr.id = ?`, id)
+
+	followRequest, err := scanFollowRequest(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+
+	return followRequest, err
+}
+
+func (r *followRequestRepository) GetByProfiles(ctx context.Context, profileID int, requesterProfileID int) (*model.FollowRequest, error) {
+	query := `SELECT ` + followRequestColumns + ` FROM follow_requests fr WHERE fr.profile_id = ? AND fr.requester_profile_id = ?`
+	row := r.db.QueryRowContext(ctx, query, profileID, requesterProfileID)
+
+	followRequest, err := scanFollowRequest(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+
+	return followRequest, err
+}
+
+// GetIncoming returns the requests to follow the profile with their requesters, newest first.
+// Pass a zero beforeTime for the first page.
+func (r *followRequestRepository) GetIncoming(
+	ctx context.Context,
+	profileID int,
+	beforeTime time.Time,
+	beforeID int,
+	limit int,
+) ([]model.FollowRequestWithProfile, error) {
+	query := `
+		SELECT ` + followRequestColumns + `, p.display_name, p.avatar_version
+		FROM follow_requests fr
+		INNER JOIN profiles p ON p.id = fr.requester_profile_id
+		WHERE fr.profile_id = ?
+		AND (? OR (fr.created_at, fr.id) < (?, ?))
+		ORDER BY fr.created_at DESC, fr.id DESC
+		LIMIT ?
+	`
+
+	return r.queryWithProfile(ctx, query, profileID, beforeTime.IsZero(), beforeTime, beforeID, limit)
+}
+
+// GetSent returns the profile's own pending requests with the profiles they were sent to, newest first.
+// Pass a zero beforeTime for the first page.
+func (r *followRequestRepository) GetSent(
+	ctx context.Context,
+	requesterProfileID int,
+	beforeTime time.Time,
+	beforeID int,
+	limit int,
+) ([]model.FollowRequestWithProfile, error) {
+	query := `
+		SELECT ` + followRequestColumns + `, p.display_name, p.avatar_version
+		FROM follow_requests fr
+		INNER JOIN profiles p ON p.id = fr.profile_id
+		WHERE fr.requester_profile_id = ?
+		AND (? OR (fr.created_at, fr.id) < (?, ?))
+		ORDER BY fr.created_at DESC, fr.id DESC
+		LIMIT ?
+	`
+
+	return r.queryWithProfile(ctx, query, requesterProfileID, beforeTime.IsZero(), beforeTime, beforeID, limit)
+}
+
+func (r *followRequestRepository) queryWithProfile(ctx context.Context, query string, args ...interface{}) ([]model.FollowRequestWithProfile, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	followRequests := []model.FollowRequestWithProfile{}
+	for rows.Next() {
+		var fr model.FollowRequestWithProfile
+		if err := rows.Scan(
+			&fr.ID,
+			&fr.ProfileID,
+			&fr.RequesterProfileID,
+			&fr.CreatedAt,
+			&fr.DisplayName,
+			&fr.AvatarVersion,
+		); err != nil {
+			return nil, err
+		}
+		followRequests = append(followRequests, fr)
+	}
+
+	return followRequests, rows.Err()
+}
+
+// Create adds the request. Requesting twice is a no-op thanks to the unique
+// (profile_id, requester_profile_id) index and returns the existing request.
+func (r *followRequestRepository) Create(ctx context.Context, followRequest *model.FollowRequest) (*model.FollowRequest, error) {
+	query := `
+		INSERT INTO follow_requests (profile_id, requester_profile_id)
+		VALUES (?, ?)
+		ON DUPLICATE KEY UPDATE id = id
+	`
+
+	_, err := r.db.ExecContext(ctx, query, followRequest.ProfileID, followRequest.RequesterProfileID)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.GetByProfiles(ctx, followRequest.ProfileID, followRequest.RequesterProfileID)
+}
+
+// Delete removes the request, reporting false if it was already gone, e.g. approved concurrently.
+func (r *followRequestRepository) Delete(ctx context.Context, tx *sql.Tx, id int) (bool, error) {
+	res, err := tx.ExecContext(ctx, `DELETE FROM follow_requests WHERE id = ?`, id)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affected > 0, nil
+}
+
+// DeleteBetween removes the pending requests between the two profiles in both directions.
+func (r *followRequestRepository) DeleteBetween(ctx context.Context, tx *sql.Tx, profileID int, otherProfileID int) error {
+	query := `
+		DELETE FROM follow_requests
+		WHERE (profile_id = ? AND requester_profile_id = ?) OR (profile_id = ? AND requester_profile_id = ?)
+	`
+
+	_, err := tx.ExecContext(ctx, query, profileID, otherProfileID, otherProfileID, profileID)
+
+	return err
+}
@@ -0,0 +1,218 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/brightside-dev/ronin-fitness-be/database/client"
+	"github.com/brightside-dev/ronin-fitness-be/internal/model"
+)
+
+type GoalRepository interface {
+	GetByID(ctx context.Context, id int) (*model.Goal, error)
+	GetByProfileID(ctx context.Context, profileID int, status string, beforeTime time.Time, beforeID int, limit int) ([]model.Goal, error)
+	GetActiveByProfileID(ctx context.Context, profileID int) ([]model.Goal, error)
+	Create(ctx context.Context, goal *model.Goal) (*model.Goal, error)
+	UpdateProgress(ctx context.Context, id int, currentValue float64, progress float64) error
+	Complete(ctx context.Context, tx *sql.Tx, id int, currentValue float64, completedAt time.Time) (bool, error)
+	Expire(ctx context.Context, id int, currentValue float64, progress float64) error
+	Delete(ctx context.Context, id int) error
+}
+
+type goalRepository struct {
+	db client.DatabaseService
+}
+
+func NewGoalRepository(db client.DatabaseService) GoalRepository {
+	return &goalRepository{db: db}
+}
+
+const goalColumns = `
+	g.id, g.profile_id, g.type, g.exercise_id, COALESCE(e.name, ''), g.target_value, g.target_weeks,
+	g.start_value, g.current_value, g.progress, g.deadline, g.status, g.completed_at, g.created_at, g.updated_at
+`
+
+const goalFrom = `
+	FROM goals g
+	LEFT JOIN exercises e ON e.id = g.exercise_id
+`
+
+func scanGoal(scanner interface{ Scan(...interface{}) error }) (*model.Goal, error) {
+	var goal model.Goal
+	var exerciseID sql.NullInt64
+	var startValue sql.NullFloat64
+	var deadline, completedAt sql.NullTime
+	err := scanner.Scan(
+		&goal.ID,
+		&goal.ProfileID,
+		&goal.Type,
+		&exerciseID,
+		&goal.ExerciseName,
+		&goal.TargetValue,
+		&goal.TargetWeeks,
+		&startValue,
+		&goal.CurrentValue,
+		&goal.Progress,
+		&deadline,
+		&goal.Status,
+		&completedAt,
+		&goal.CreatedAt,
+		&goal.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if exerciseID.Valid {
+		id := int(exerciseID.Int64)
+		goal.ExerciseID = &id
+	}
+	if startValue.Valid {
+		goal.StartValue = &startValue.Float64
+	}
+	if deadline.Valid {
+		goal.Deadline = &deadline.Time
+	}
+	if completedAt.Valid {
+		goal.CompletedAt = &completedAt.Time
+	}
+
+	return &goal, nil
+}
+
+func (r *goalRepository) GetByID(ctx context.Context, id int) (*model.Goal, error) {
+	row := r.db.QueryRowContext(ctx, `SELECT `+goalColumns+goalFrom+` WHERE g.id = ?`, id)
+
+	goal, err := scanGoal(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+
+	return goal, err
+}
+
+// GetByProfileID returns the profile's goals with the given status, or any status when empty,
+// newest first. Pass a zero beforeTime for the first page.
+func (r *goalRepository) GetByProfileID(
+	ctx context.Context,
+	profileID int,
+	status string,
+	beforeTime time.Time,
+	beforeID int,
+	limit int,
+) ([]model.Goal, error) {
+	query := `
+		SELECT ` + goalColumns + goalFrom + `
+		WHERE g.profile_id = ?
+		AND (? = '' OR g.status = ?)
+		AND (? OR (g.created_at, g.id) < (?, ?))
+		ORDER BY g.created_at DESC, g.id DESC
+		LIMIT ?
+	`
+
+	return r.query(ctx, query, profileID, status, status, beforeTime.IsZero(), beforeTime, beforeID, limit)
+}
+
+func (r *goalRepository) GetActiveByProfileID(ctx context.Context, profileID int) ([]model.Goal, error) {
+	query := `
+		SELECT ` + goalColumns + goalFrom + `
+		WHERE g.profile_id = ? AND g.status = ?
+		ORDER BY g.id
+	`
+
+	return r.query(ctx, query, profileID, model.GoalStatusActive)
+}
+
+func (r *goalRepository) query(ctx context.Context, query string, args ...interface{}) ([]model.Goal, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	goals := []model.Goal{}
+	for rows.Next() {
+		goal, err := scanGoal(rows)
+		if err != nil {
+			return nil, err
+		}
+		goals = append(goals, *goal)
+	}
+
+	return goals, rows.Err()
+}
+
+func (r *goalRepository) Create(ctx context.Context, goal *model.Goal) (*model.Goal, error) {
+	query := `
+		INSERT INTO goals
+			(profile_id, type, exercise_id, target_value, target_weeks, start_value, current_value, progress, deadline)
+		VALUES
+			(?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	res, err := r.db.ExecContext(
+		ctx, query,
+		goal.ProfileID,
+		goal.Type,
+		goal.ExerciseID,
+		goal.TargetValue,
+		goal.TargetWeeks,
+		goal.StartValue,
+		goal.CurrentValue,
+		goal.Progress,
+		goal.Deadline,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return nil, err
+	}
+
+	return r.GetByID(ctx, int(id))
+}
+
+func (r *goalRepository) UpdateProgress(ctx context.Context, id int, currentValue float64, progress float64) error {
+	query := `UPDATE goals SET current_value = ?, progress = ? WHERE id = ? AND status = ?`
+	_, err := r.db.ExecContext(ctx, query, currentValue, progress, id, model.GoalStatusActive)
+
+	return err
+}
+
+// Complete marks an active goal as reached. It returns false if the goal was no longer active, so
+// that a completion is only ever rewarded once.
+func (r *goalRepository) Complete(ctx context.Context, tx *sql.Tx, id int, currentValue float64, completedAt time.Time) (bool, error) {
+	query := `
+		UPDATE goals
+		SET status = ?, current_value = ?, progress = 100, completed_at = ?
+		WHERE id = ? AND status = ?
+	`
+
+	res, err := tx.ExecContext(ctx, query, model.GoalStatusCompleted, currentValue, completedAt, id, model.GoalStatusActive)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affected > 0, nil
+}
+
+func (r *goalRepository) Expire(ctx context.Context, id int, currentValue float64, progress float64) error {
+	query := `UPDATE goals SET status = ?, current_value = ?, progress = ? WHERE id = ? AND status = ?`
+	_, err := r.db.ExecContext(ctx, query, model.GoalStatusExpired, currentValue, progress, id, model.GoalStatusActive)
+
+	return err
+}
+
+func (r *goalRepository) Delete(ctx context.Context, id int) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM goals WHERE id = ?`, id)
+
+	return err
+}
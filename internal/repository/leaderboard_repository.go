@@ -0,0 +1,141 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/brightside-dev/ronin-fitness-be/database/client"
+	"github.com/brightside-dev/ronin-fitness-be/internal/model"
+)
+
+type LeaderboardRepository interface {
+	GetEntries(ctx context.Context, filter model.LeaderboardFilter, beforePoints int, beforeID int, limit int) ([]model.LeaderboardEntry, error)
+	CountAhead(ctx context.Context, filter model.LeaderboardFilter, points int) (int, error)
+	GetPoints(ctx context.Context, filter model.LeaderboardFilter, profileID int) (int, error)
+}
+
+type leaderboardRepository struct {
+	db client.DatabaseService
+}
+
+func NewLeaderboardRepository(db client.DatabaseService) LeaderboardRepository {
+	return &leaderboardRepository{db: db}
+}
+
+// GetEntries returns a page of the leaderboard, most points first. Entries are left unranked, see
+// CountAhead. Pass a zero beforeID for the first page.
+func (r *leaderboardRepository) GetEntries(
+	ctx context.Context,
+	filter model.LeaderboardFilter,
+	beforePoints int,
+	beforeID int,
+	limit int,
+) ([]model.LeaderboardEntry, error) {
+	board, args := leaderboardQuery(filter)
+
+	// The first page has no cursor condition, later pages spell the row comparison out so the
+	// all-time board can seek on idx_profiles_experience_points
+	cursorCondition := ""
+	if beforeID != 0 {
+		cursorCondition = `AND (board.points < ? OR (board.points = ? AND p.id < ?))`
+		args = append(args, beforePoints, beforePoints, beforeID)
+	}
+	args = append(args, limit)
+
+	query := `
+		SELECT p.id, p.display_name, p.handle, p.avatar_version, board.points
+		` + board + `
+		` + cursorCondition + `
+		ORDER BY board.points DESC, p.id DESC
+		LIMIT ?
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := []model.LeaderboardEntry{}
+	for rows.Next() {
+		var entry model.LeaderboardEntry
+		if err := rows.Scan(
+			&entry.ProfileID,
+			&entry.DisplayName,
+			&entry.Handle,
+			&entry.AvatarVersion,
+			&entry.Points,
+		); err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, rows.Err()
+}
+
+// CountAhead counts the profiles on the leaderboard with more than points. One more is the rank
+// of anyone with points, tied profiles share a rank.
+func (r *leaderboardRepository) CountAhead(ctx context.Context, filter model.LeaderboardFilter, points int) (int, error) {
+	board, args := leaderboardQuery(filter)
+	args = append(args, points)
+
+	var count int
+	err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) `+board+` AND board.points > ?`, args...).Scan(&count)
+
+	return count, err
+}
+
+// GetPoints returns the points the profile is ranked by on the leaderboard, whether or not it is
+// shown on it.
+func (r *leaderboardRepository) GetPoints(ctx context.Context, filter model.LeaderboardFilter, profileID int) (int, error) {
+	query := `SELECT experience_points FROM profiles WHERE id = ?`
+	args := []interface{}{profileID}
+	if !filter.Since.IsZero() {
+		query = `SELECT COALESCE(SUM(points), 0) FROM experience_point_ledger WHERE profile_id = ? AND created_at >= ?`
+		args = append(args, filter.Since)
+	}
+
+	var points int
+	err := r.db.QueryRowContext(ctx, query, args...).Scan(&points)
+
+	return points, err
+}
+
+// leaderboardQuery builds the FROM and WHERE clauses selecting the profiles on the leaderboard with
+// their points as board.points. Only public profiles with points that the viewer has not blocked
+// or been blocked by are on it.
+func leaderboardQuery(filter model.LeaderboardFilter) (string, []interface{}) {
+	board := `SELECT id AS profile_id, experience_points AS points FROM profiles WHERE experience_points > 0`
+	args := []interface{}{}
+	if !filter.Since.IsZero() {
+		board = `
+			SELECT profile_id, SUM(points) AS points
+			FROM experience_point_ledger
+			WHERE created_at >= ?
+			GROUP BY profile_id
+			HAVING SUM(points) > 0
+		`
+		args = append(args, filter.Since)
+	}
+
+	query := `
+		FROM (` + board + `) board
+		INNER JOIN profiles p ON p.id = board.profile_id
+		INNER JOIN users u ON u.id = p.user_id
+		WHERE p.privacy = 'public'
+		AND ` + notBlockedCondition("p.id")
+	args = append(args, filter.ViewerProfileID, filter.ViewerProfileID)
+
+	switch filter.Scope {
+	case model.LeaderboardScopeCountry:
+		query += ` AND u.country = ?`
+		args = append(args, filter.Country)
+	case model.LeaderboardScopeFollowing:
+		query += ` AND (p.id = ? OR EXISTS (
+			SELECT 1 FROM profile_follows f WHERE f.profile_id = p.id AND f.follower_profile_id = ?
+		))`
+		args = append(args, filter.ViewerProfileID, filter.ViewerProfileID)
+	}
+
+	return query, args
+}
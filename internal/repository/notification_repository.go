@@ -0,0 +1,177 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/brightside-dev/ronin-fitness-be/database/client"
+	"github.com/brightside-dev/ronin-fitness-be/internal/model"
+)
+
+type NotificationRepository interface {
+	Create(ctx context.Context, tx *sql.Tx, notification *model.Notification) (*model.Notification, error)
+	GetByProfileID(ctx context.Context, profileID int, beforeTime time.Time, beforeID int, limit int) ([]model.Notification, error)
+	CountUnread(ctx context.Context, profileID int) (int, error)
+	MarkRead(ctx context.Context, profileID int, id int, readAt time.Time) (bool, error)
+	MarkAllRead(ctx context.Context, profileID int, readAt time.Time) error
+}
+
+type notificationRepository struct {
+	db client.DatabaseService
+}
+
+func NewNotificationRepository(db client.DatabaseService) NotificationRepository {
+	return &notificationRepository{db: db}
+}
+
+// notificationActorCondition hides notifications caused by profiles the recipient muted or that are
+// blocked either way. It takes the recipient's profile ID three times as arguments.
+var notificationActorCondition = notBlockedCondition("actor_profile_id") + ` AND ` + notMutedCondition("actor_profile_id")
+
+func (r *notificationRepository) Create(ctx context.Context, tx *sql.Tx, notification *model.Notification) (*model.Notification, error) {
+	query := `
+		INSERT INTO notifications (profile_id, actor_profile_id, type, title, body, data)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`
+
+	var data interface{}
+	if len(notification.Data) > 0 {
+		data = []byte(notification.Data)
+	}
+
+	res, err := tx.ExecContext(
+		ctx, query,
+		notification.ProfileID,
+		notification.ActorProfileID,
+		notification.Type,
+		notification.Title,
+		notification.Body,
+		data,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return nil, err
+	}
+
+	notification.ID = int(id)
+
+	return notification, nil
+}
+
+// GetByProfileID returns the profile's notifications, newest first, leaving out those caused by profiles
+// it muted or that are blocked either way. Pass a zero beforeTime for the first page.
+func (r *notificationRepository) GetByProfileID(
+	ctx context.Context,
+	profileID int,
+	beforeTime time.Time,
+	beforeID int,
+	limit int,
+) ([]model.Notification, error) {
+	query := `
+		SELECT id, profile_id, actor_profile_id, type, title, body, data, read_at, created_at
+		FROM notifications
+		WHERE profile_id = ?
+		AND ` + notificationActorCondition + `
+		AND (? OR (created_at, id) < (?, ?))
+		ORDER BY created_at DESC, id DESC
+		LIMIT ?
+	`
+
+	rows, err := r.db.QueryContext(
+		ctx, query,
+		profileID,
+		profileID, profileID, profileID,
+		beforeTime.IsZero(), beforeTime, beforeID,
+		limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	notifications := []model.Notification{}
+	for rows.Next() {
+		var notification model.Notification
+		var data []byte
+		var actorProfileID sql.NullInt64
+		var readAt sql.NullTime
+		if err := rows.Scan(
+			&notification.ID,
+			&notification.ProfileID,
+			&actorProfileID,
+			&notification.Type,
+			&notification.Title,
+			&notification.Body,
+			&data,
+			&readAt,
+			&notification.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+
+		if actorProfileID.Valid {
+			id := int(actorProfileID.Int64)
+			notification.ActorProfileID = &id
+		}
+		if len(data) > 0 {
+			notification.Data = data
+		}
+		if readAt.Valid {
+			notification.ReadAt = &readAt.Time
+		}
+
+		notifications = append(notifications, notification)
+	}
+
+	return notifications, rows.Err()
+}
+
+// CountUnread counts the unread notifications GetByProfileID would return.
+func (r *notificationRepository) CountUnread(ctx context.Context, profileID int) (int, error) {
+	query := `SELECT COUNT(*) FROM notifications WHERE profile_id = ? AND read_at IS NULL AND ` + notificationActorCondition
+
+	var count int
+	err := r.db.QueryRowContext(ctx, query, profileID, profileID, profileID, profileID).Scan(&count)
+
+	return count, err
+}
+
+// MarkRead marks one of the profile's notifications as read. It returns false if there is no such notification.
+func (r *notificationRepository) MarkRead(ctx context.Context, profileID int, id int, readAt time.Time) (bool, error) {
+	query := `
+		UPDATE notifications
+		SET read_at = COALESCE(read_at, ?)
+		WHERE id = ? AND profile_id = ?
+	`
+
+	res, err := r.db.ExecContext(ctx, query, readAt, id, profileID)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	if affected > 0 {
+		return true, nil
+	}
+
+	// MySQL reports no affected rows for a notification that was already read
+	var exists bool
+	err = r.db.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM notifications WHERE id = ? AND profile_id = ?)`, id, profileID).Scan(&exists)
+
+	return exists, err
+}
+
+func (r *notificationRepository) MarkAllRead(ctx context.Context, profileID int, readAt time.Time) error {
+	_, err := r.db.ExecContext(ctx, `UPDATE notifications SET read_at = ? WHERE profile_id = ? AND read_at IS NULL`, readAt, profileID)
+
+	return err
+}
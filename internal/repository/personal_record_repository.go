@@ -0,0 +1,136 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/brightside-dev/ronin-fitness-be/database/client"
+	"github.com/brightside-dev/ronin-fitness-be/internal/model"
+)
+
+type PersonalRecordRepository interface {
+	Create(ctx context.Context, tx *sql.Tx, personalRecord *model.PersonalRecord) (*model.PersonalRecord, error)
+	GetByExerciseID(ctx context.Context, profileID int, exerciseID int) ([]model.PersonalRecord, error)
+	RecalculateOneRepMax(ctx context.Context, tx *sql.Tx, profileID int, formula string) error
+}
+
+type personalRecordRepository struct {
+	db client.DatabaseService
+}
+
+func NewPersonalRecordRepository(db client.DatabaseService) PersonalRecordRepository {
+	return &personalRecordRepository{db: db}
+}
+
+func (r *personalRecordRepository) Create(ctx context.Context, tx *sql.Tx, personalRecord *model.PersonalRecord) (*model.PersonalRecord, error) {
+	query := `
+		INSERT INTO personal_records
+			(profile_id, exercise_id, workout_id, set_id, record_type, weight_kg, reps, value, previous_value, achieved_at)
+		VALUES
+			(?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	res, err := tx.ExecContext(
+		ctx, query,
+		personalRecord.ProfileID,
+		personalRecord.ExerciseID,
+		personalRecord.WorkoutID,
+		personalRecord.SetID,
+		personalRecord.RecordType,
+		personalRecord.WeightKg,
+		personalRecord.Reps,
+		personalRecord.Value,
+		personalRecord.PreviousValue,
+		personalRecord.AchievedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return nil, err
+	}
+
+	personalRecord.ID = int(id)
+
+	return personalRecord, nil
+}
+
+// GetByExerciseID returns the full PR history of a profile for one exercise, newest first.
+func (r *personalRecordRepository) GetByExerciseID(ctx context.Context, profileID int, exerciseID int) ([]model.PersonalRecord, error) {
+	query := `
+		SELECT
+			id, profile_id, exercise_id, workout_id, set_id, record_type,
+			weight_kg, reps, value, previous_value, achieved_at, created_at
+		FROM personal_records
+		WHERE profile_id = ? AND exercise_id = ?
+		ORDER BY achieved_at DESC, id DESC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, profileID, exerciseID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	personalRecords := []model.PersonalRecord{}
+	for rows.Next() {
+		var personalRecord model.PersonalRecord
+		var previousValue sql.NullFloat64
+		if err := rows.Scan(
+			&personalRecord.ID,
+			&personalRecord.ProfileID,
+			&personalRecord.ExerciseID,
+			&personalRecord.WorkoutID,
+			&personalRecord.SetID,
+			&personalRecord.RecordType,
+			&personalRecord.WeightKg,
+			&personalRecord.Reps,
+			&personalRecord.Value,
+			&previousValue,
+			&personalRecord.AchievedAt,
+			&personalRecord.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+
+		if previousValue.Valid {
+			personalRecord.PreviousValue = &previousValue.Float64
+		}
+
+		personalRecords = append(personalRecords, personalRecord)
+	}
+
+	return personalRecords, rows.Err()
+}
+
+// RecalculateOneRepMax recomputes the profile's best e1RM records with the formula from the set each
+// was set with, and each previous value as the value of the record before it. The history keeps
+// the sets that were records when they were logged.
+func (r *personalRecordRepository) RecalculateOneRepMax(ctx context.Context, tx *sql.Tx, profileID int, formula string) error {
+	query := `
+		UPDATE personal_records
+		SET value = ` + oneRepMaxExpression("weight_kg", "reps") + `
+		WHERE profile_id = ? AND record_type = 'best_e1rm'
+	`
+
+	_, err := tx.ExecContext(ctx, query, formula, profileID)
+	if err != nil {
+		return err
+	}
+
+	query = `
+		UPDATE personal_records pr
+		INNER JOIN (
+			SELECT id, LAG(value) OVER (PARTITION BY exercise_id ORDER BY achieved_at, id) AS previous_value
+			FROM personal_records
+			WHERE profile_id = ? AND record_type = 'best_e1rm'
+		) ordered ON ordered.id = pr.id
+		SET pr.previous_value = ordered.previous_value
+	`
+
+	_, err = tx.ExecContext(ctx, query, profileID)
+
+	return err
+}
@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/brightside-dev/ronin-fitness-be/database/client"
+	"github.com/brightside-dev/ronin-fitness-be/internal/model"
+)
+
+type ProfileBlockRepository interface {
+	Create(ctx context.Context, tx *sql.Tx, profileBlock *model.ProfileBlock) error
+	Delete(ctx context.Context, profileID int, blockedProfileID int) error
+	IsBlocked(ctx context.Context, profileID int, otherProfileID int) (bool, error)
+	GetByProfileID(ctx context.Context, profileID int, beforeTime time.Time, beforeID int, limit int) ([]model.RestrictedProfile, error)
+}
+
+type profileBlockRepository struct {
+	db client.DatabaseService
+}
+
+func NewProfileBlockRepository(db client.DatabaseService) ProfileBlockRepository {
+	return &profileBlockRepository{db: db}
+}
+
+// Create adds the block. Blocking twice is a no-op thanks to the unique (profile_id, blocked_profile_id) index.
+func (r *profileBlockRepository) Create(ctx context.Context, tx *sql.Tx, profileBlock *model.ProfileBlock) error {
+	query := `
+		INSERT INTO profile_blocks (profile_id, blocked_profile_id)
+		VALUES (?, ?)
+		ON DUPLICATE KEY UPDATE id = id
+	`
+
+	_, err := tx.ExecContext(ctx, query, profileBlock.ProfileID, profileBlock.BlockedProfileID)
+
+	return err
+}
+
+func (r *profileBlockRepository) Delete(ctx context.Context, profileID int, blockedProfileID int) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM profile_blocks WHERE profile_id = ? AND blocked_profile_id = ?`, profileID, blockedProfileID)
+
+	return err
+}
+
+// IsBlocked reports whether either profile blocked the other.
+func (r *profileBlockRepository) IsBlocked(ctx context.Context, profileID int, otherProfileID int) (bool, error) {
+	query := `
+		SELECT EXISTS(
+			SELECT 1 FROM profile_blocks
+			WHERE (profile_id = ? AND blocked_profile_id = ?) OR (profile_id = ? AND blocked_profile_id = ?)
+		)
+	`
+
+	var exists bool
+	err := r.db.QueryRowContext(ctx, query, profileID, otherProfileID, otherProfileID, profileID).Scan(&exists)
+	if err != nil {
+		return false, err
+	}
+
+	return exists, nil
+}
+
+// GetByProfileID returns the profiles the profile blocked, most recently blocked first. Pass a zero
+// beforeTime for the first page.
+func (r *profileBlockRepository) GetByProfileID(
+	ctx context.Context,
+	profileID int,
+	beforeTime time.Time,
+	beforeID int,
+	limit int,
+) ([]model.RestrictedProfile, error) {
+	query := `
+		SELECT pb.id, pb.blocked_profile_id, p.display_name, p.avatar_version, pb.created_at
+		FROM profile_blocks pb
+		INNER JOIN profiles p ON p.id = pb.blocked_profile_id
+		WHERE pb.profile_id = ?
+		AND (? OR (pb.created_at, pb.id) < (?, ?))
+		ORDER BY pb.created_at DESC, pb.id DESC
+		LIMIT ?
+	`
+
+	return queryRestrictedProfiles(ctx, r.db, query, profileID, beforeTime.IsZero(), beforeTime, beforeID, limit)
+}
+
+func queryRestrictedProfiles(ctx context.Context, db client.DatabaseService, query string, args ...interface{}) ([]model.RestrictedProfile, error) {
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	profiles := []model.RestrictedProfile{}
+	for rows.Next() {
+		var profile model.RestrictedProfile
+		if err := rows.Scan(
+			&profile.ID,
+			&profile.ProfileID,
+			&profile.DisplayName,
+			&profile.AvatarVersion,
+			&profile.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		profiles = append(profiles, profile)
+	}
+
+	return profiles, rows.Err()
+}
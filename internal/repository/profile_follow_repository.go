@@ -2,14 +2,23 @@ package repository
 
 import (
 	"context"
+	"database/sql"
+	"time"
 
 	"github.com/brightside-dev/ronin-fitness-be/database/client"
 	"github.com/brightside-dev/ronin-fitness-be/internal/model"
 )
 
 type ProfileFollowRepository interface {
-	Create(ctx context.Context, profileFollow *model.ProfileFollow) (*model.ProfileFollow, error)
+	Create(ctx context.Context, tx *sql.Tx, profileFollow *model.ProfileFollow) (*model.ProfileFollow, error)
 	Delete(ctx context.Context, profileFollow *model.ProfileFollow) error
+	DeleteBetween(ctx context.Context, tx *sql.Tx, profileID int, otherProfileID int) error
+	IsFollowing(ctx context.Context, profileID int, followerProfileID int) (bool, error)
+	GetCounts(ctx context.Context, profileID int) (int, int, error)
+	GetFollowers(ctx context.Context, profileID int, viewerProfileID int, beforeTime time.Time, beforeID int, limit int) ([]model.ProfileFollowWithProfile, error)
+	GetFollowing(ctx context.Context, followerProfileID int, viewerProfileID int, beforeTime time.Time, beforeID int, limit int) ([]model.ProfileFollowWithProfile, error)
+	GetFollowedProfileIDs(ctx context.Context, followerProfileID int, profileIDs []int) (map[int]bool, error)
+	GetFollowerProfileIDs(ctx context.Context, profileID int, followerProfileIDs []int) (map[int]bool, error)
 }
 
 type profileFollowRepository struct {
@@ -20,13 +29,16 @@ func NewProfileFollowRepository(db client.DatabaseService) ProfileFollowReposito
 	return &profileFollowRepository{db: db}
 }
 
-func (r *profileFollowRepository) Create(ctx context.Context, profileFollow *model.ProfileFollow) (*model.ProfileFollow, error) {
+// Create adds the follow. Following twice is a no-op thanks to the unique (profile_id, follower_profile_id)
+// index and returns the existing follow's ID.
+func (r *profileFollowRepository) Create(ctx context.Context, tx *sql.Tx, profileFollow *model.ProfileFollow) (*model.ProfileFollow, error) {
 	query := `
 		INSERT INTO profile_follows (profile_id, follower_profile_id)
 		VALUES (?, ?)
+		ON DUPLICATE KEY UPDATE id = LAST_INSERT_ID(id)
 	`
 
-	res, err := r.db.ExecContext(ctx, query, profileFollow.ProfileID, profileFollow.FollowerProfileID)
+	res, err := tx.ExecContext(ctx, query, profileFollow.ProfileID, profileFollow.FollowerProfileID)
 	if err != nil {
 		return nil, err
 	}
@@ -51,3 +63,165 @@ func (r *profileFollowRepository) Delete(ctx context.Context, profileFollow *mod
 
 	return nil
 }
+
+// DeleteBetween removes the follows between the two profiles in both directions.
+func (r *profileFollowRepository) DeleteBetween(ctx context.Context, tx *sql.Tx, profileID int, otherProfileID int) error {
+	query := `
+		DELETE FROM profile_follows
+		WHERE (profile_id = ? AND follower_profile_id = ?) OR (profile_id = ? AND follower_profile_id = ?)
+	`
+
+	_, err := tx.ExecContext(ctx, query, profileID, otherProfileID, otherProfileID, profileID)
+
+	return err
+}
+
+func (r *profileFollowRepository) IsFollowing(ctx context.Context, profileID int, followerProfileID int) (bool, error) {
+	query := `
+		SELECT EXISTS(
+			SELECT 1 FROM profile_follows WHERE profile_id = ? AND follower_profile_id = ?
+		)
+	`
+
+	var exists bool
+	err := r.db.QueryRowContext(ctx, query, profileID, followerProfileID).Scan(&exists)
+	if err != nil {
+		return false, err
+	}
+
+	return exists, nil
+}
+
+// GetCounts returns how many profiles follow the profile and how many it follows.
+func (r *profileFollowRepository) GetCounts(ctx context.Context, profileID int) (int, int, error) {
+	query := `
+		SELECT
+			(SELECT COUNT(*) FROM profile_follows WHERE profile_id = ?),
+			(SELECT COUNT(*) FROM profile_follows WHERE follower_profile_id = ?)
+	`
+
+	var followers, following int
+	err := r.db.QueryRowContext(ctx, query, profileID, profileID).Scan(&followers, &following)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return followers, following, nil
+}
+
+// GetFollowers returns the profile's followers, most recently followed first, leaving out profiles
+// blocked by or blocking the viewer. Pass a zero beforeTime for the first page.
+func (r *profileFollowRepository) GetFollowers(
+	ctx context.Context,
+	profileID int,
+	viewerProfileID int,
+	beforeTime time.Time,
+	beforeID int,
+	limit int,
+) ([]model.ProfileFollowWithProfile, error) {
+	query := `
+		SELECT pf.id, pf.profile_id, pf.follower_profile_id, pf.created_at, p.display_name, p.avatar_version
+		FROM profile_follows pf
+		INNER JOIN profiles p ON p.id = pf.follower_profile_id
+		WHERE pf.profile_id = ?
+		AND ` + notBlockedCondition("pf.follower_profile_id") + `
+		AND (? OR (pf.created_at, pf.id) < (?, ?))
+		ORDER BY pf.created_at DESC, pf.id DESC
+		LIMIT ?
+	`
+
+	return r.queryWithProfile(ctx, query, profileID, viewerProfileID, viewerProfileID, beforeTime.IsZero(), beforeTime, beforeID, limit)
+}
+
+// GetFollowing returns the profiles the profile follows, most recently followed first, leaving out
+// profiles blocked by or blocking the viewer. Pass a zero beforeTime for the first page.
+func (r *profileFollowRepository) GetFollowing(
+	ctx context.Context,
+	followerProfileID int,
+	viewerProfileID int,
+	beforeTime time.Time,
+	beforeID int,
+	limit int,
+) ([]model.ProfileFollowWithProfile, error) {
+	query := `
+		SELECT pf.id, pf.profile_id, pf.follower_profile_id, pf.created_at, p.display_name, p.avatar_version
+		FROM profile_follows pf
+		INNER JOIN profiles p ON p.id = pf.profile_id
+		WHERE pf.follower_profile_id = ?
+		AND ` + notBlockedCondition("pf.profile_id") + `
+		AND (? OR (pf.created_at, pf.id) < (?, ?))
+		ORDER BY pf.created_at DESC, pf.id DESC
+		LIMIT ?
+	`
+
+	return r.queryWithProfile(ctx, query, followerProfileID, viewerProfileID, viewerProfileID, beforeTime.IsZero(), beforeTime, beforeID, limit)
+}
+
+func (r *profileFollowRepository) queryWithProfile(ctx context.Context, query string, args ...interface{}) ([]model.ProfileFollowWithProfile, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	follows := []model.ProfileFollowWithProfile{}
+	for rows.Next() {
+		var pf model.ProfileFollowWithProfile
+		if err := rows.Scan(
+			&pf.ID,
+			&pf.ProfileID,
+			&pf.FollowerProfileID,
+			&pf.CreatedAt,
+			&pf.DisplayName,
+			&pf.AvatarVersion,
+		); err != nil {
+			return nil, err
+		}
+		follows = append(follows, pf)
+	}
+
+	return follows, rows.Err()
+}
+
+// GetFollowedProfileIDs returns which of the given profiles the follower follows.
+func (r *profileFollowRepository) GetFollowedProfileIDs(ctx context.Context, followerProfileID int, profileIDs []int) (map[int]bool, error) {
+	if len(profileIDs) == 0 {
+		return map[int]bool{}, nil
+	}
+
+	placeholders, args := inClause(profileIDs)
+	query := `SELECT profile_id FROM profile_follows WHERE follower_profile_id = ? AND profile_id IN (` + placeholders + `)`
+
+	return r.queryIDSet(ctx, query, append([]interface{}{followerProfileID}, args...)...)
+}
+
+// GetFollowerProfileIDs returns which of the given profiles follow the profile.
+func (r *profileFollowRepository) GetFollowerProfileIDs(ctx context.Context, profileID int, followerProfileIDs []int) (map[int]bool, error) {
+	if len(followerProfileIDs) == 0 {
+		return map[int]bool{}, nil
+	}
+
+	placeholders, args := inClause(followerProfileIDs)
+	query := `SELECT follower_profile_id FROM profile_follows WHERE profile_id = ? AND follower_profile_id IN (` + placeholders + `)`
+
+	return r.queryIDSet(ctx, query, append([]interface{}{profileID}, args...)...)
+}
+
+func (r *profileFollowRepository) queryIDSet(ctx context.Context, query string, args ...interface{}) (map[int]bool, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ids := map[int]bool{}
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids[id] = true
+	}
+
+	return ids, rows.Err()
+}
@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/brightside-dev/ronin-fitness-be/database/client"
+	"github.com/brightside-dev/ronin-fitness-be/internal/model"
+)
+
+type ProfileHandleRepository interface {
+	GetActiveByHandle(ctx context.Context, handle string, now time.Time) (*model.ProfileHandle, error)
+	Create(ctx context.Context, tx *sql.Tx, profileHandle *model.ProfileHandle) error
+	Delete(ctx context.Context, tx *sql.Tx, handle string) error
+}
+
+type profileHandleRepository struct {
+	db client.DatabaseService
+}
+
+func NewProfileHandleRepository(db client.DatabaseService) ProfileHandleRepository {
+	return &profileHandleRepository{db: db}
+}
+
+// GetActiveByHandle returns the old handle if it has not expired by now, or nil.
+func (r *profileHandleRepository) GetActiveByHandle(ctx context.Context, handle string, now time.Time) (*model.ProfileHandle, error) {
+	query := `
+		SELECT id, profile_id, handle, expires_at, created_at
+		FROM profile_handle_history
+		WHERE handle = ? AND expires_at > ?
+	`
+
+	var profileHandle model.ProfileHandle
+	err := r.db.QueryRowContext(ctx, query, handle, now).Scan(
+		&profileHandle.ID,
+		&profileHandle.ProfileID,
+		&profileHandle.Handle,
+		&profileHandle.ExpiresAt,
+		&profileHandle.CreatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &profileHandle, nil
+}
+
+// Create records an old handle. An expired record of the same handle, left by whoever held it
+// before, is taken over.
+func (r *profileHandleRepository) Create(ctx context.Context, tx *sql.Tx, profileHandle *model.ProfileHandle) error {
+	query := `
+		INSERT INTO profile_handle_history (profile_id, handle, expires_at)
+		VALUES (?, ?, ?)
+		ON DUPLICATE KEY UPDATE profile_id = VALUES(profile_id), expires_at = VALUES(expires_at), created_at = CURRENT_TIMESTAMP
+	`
+
+	_, err := tx.ExecContext(ctx, query, profileHandle.ProfileID, profileHandle.Handle, profileHandle.ExpiresAt)
+
+	return err
+}
+
+// Delete forgets the old handle, when it is taken again.
+func (r *profileHandleRepository) Delete(ctx context.Context, tx *sql.Tx, handle string) error {
+	_, err := tx.ExecContext(ctx, `DELETE FROM profile_handle_history WHERE handle = ?`, handle)
+
+	return err
+}
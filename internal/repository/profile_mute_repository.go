@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/brightside-dev/ronin-fitness-be/database/client"
+	"github.com/brightside-dev/ronin-fitness-be/internal/model"
+)
+
+type ProfileMuteRepository interface {
+	Create(ctx context.Context, profileMute *model.ProfileMute) error
+	Delete(ctx context.Context, profileID int, mutedProfileID int) error
+	GetByProfileID(ctx context.Context, profileID int, beforeTime time.Time, beforeID int, limit int) ([]model.RestrictedProfile, error)
+}
+
+type profileMuteRepository struct {
+	db client.DatabaseService
+}
+
+func NewProfileMuteRepository(db client.DatabaseService) ProfileMuteRepository {
+	return &profileMuteRepository{db: db}
+}
+
+// Create adds the mute. Muting twice is a no-op thanks to the unique (profile_id, muted_profile_id) index.
+func (r *profileMuteRepository) Create(ctx context.Context, profileMute *model.ProfileMute) error {
+	query := `
+		INSERT INTO profile_mutes (profile_id, muted_profile_id)
+		VALUES (?, ?)
+		ON DUPLICATE KEY UPDATE id = id
+	`
+
+	_, err := r.db.ExecContext(ctx, query, profileMute.ProfileID, profileMute.MutedProfileID)
+
+	return err
+}
+
+func (r *profileMuteRepository) Delete(ctx context.Context, profileID int, mutedProfileID int) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM profile_mutes WHERE profile_id = ? AND muted_profile_id = ?`, profileID, mutedProfileID)
+
+	return err
+}
+
+// GetByProfileID returns the profiles the profile muted, most recently muted first. Pass a zero
+// beforeTime for the first page.
+func (r *profileMuteRepository) GetByProfileID(
+	ctx context.Context,
+	profileID int,
+	beforeTime time.Time,
+	beforeID int,
+	limit int,
+) ([]model.RestrictedProfile, error) {
+	query := `
+		SELECT pm.id, pm.muted_profile_id, p.display_name, p.avatar_version, pm.created_at
+		FROM profile_mutes pm
+		INNER JOIN profiles p ON p.id = pm.muted_profile_id
+		WHERE pm.profile_id = ?
+		AND (? OR (pm.created_at, pm.id) < (?, ?))
+		ORDER BY pm.created_at DESC, pm.id DESC
+		LIMIT ?
+	`
+
+	return queryRestrictedProfiles(ctx, r.db, query, profileID, beforeTime.IsZero(), beforeTime, beforeID, limit)
+}
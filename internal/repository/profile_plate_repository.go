@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/brightside-dev/ronin-fitness-be/database/client"
+	"github.com/brightside-dev/ronin-fitness-be/internal/model"
+)
+
+type ProfilePlateRepository interface {
+	GetByProfileID(ctx context.Context, profileID int) ([]model.ProfilePlate, error)
+	Replace(ctx context.Context, tx *sql.Tx, profileID int, plates []model.ProfilePlate) error
+}
+
+type profilePlateRepository struct {
+	db client.DatabaseService
+}
+
+func NewProfilePlateRepository(db client.DatabaseService) ProfilePlateRepository {
+	return &profilePlateRepository{db: db}
+}
+
+// GetByProfileID returns the profile's plates, heaviest first.
+func (r *profilePlateRepository) GetByProfileID(ctx context.Context, profileID int) ([]model.ProfilePlate, error) {
+	query := `
+		SELECT id, profile_id, weight_kg, pair_count, created_at
+		FROM profile_plates
+		WHERE profile_id = ?
+		ORDER BY weight_kg DESC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, profileID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	plates := []model.ProfilePlate{}
+	for rows.Next() {
+		var plate model.ProfilePlate
+		if err := rows.Scan(&plate.ID, &plate.ProfileID, &plate.WeightKg, &plate.PairCount, &plate.CreatedAt); err != nil {
+			return nil, err
+		}
+		plates = append(plates, plate)
+	}
+
+	return plates, rows.Err()
+}
+
+func (r *profilePlateRepository) Replace(ctx context.Context, tx *sql.Tx, profileID int, plates []model.ProfilePlate) error {
+	_, err := tx.ExecContext(ctx, `DELETE FROM profile_plates WHERE profile_id = ?`, profileID)
+	if err != nil {
+		return err
+	}
+
+	query := `INSERT INTO profile_plates (profile_id, weight_kg, pair_count) VALUES (?, ?, ?)`
+	for _, plate := range plates {
+		_, err := tx.ExecContext(ctx, query, profileID, plate.WeightKg, plate.PairCount)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
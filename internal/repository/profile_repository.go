@@ -4,6 +4,8 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/brightside-dev/ronin-fitness-be/database/client"
 	"github.com/brightside-dev/ronin-fitness-be/internal/model"
@@ -11,8 +13,15 @@ import (
 
 type ProfileRepository interface {
 	GetByUserID(ctx context.Context, id int) (*model.ProfileWithUser, error)
+	GetByID(ctx context.Context, id int) (*model.ProfileWithUser, error)
+	GetByHandle(ctx context.Context, handle string) (*model.ProfileWithUser, error)
 	Create(ctx context.Context, tx *sql.Tx, profile *model.Profile) (*model.Profile, error)
 	Update(ctx context.Context, tx *sql.Tx, profile *model.Profile) (*model.Profile, error)
+	GetIDs(ctx context.Context, afterID int, limit int) ([]int, error)
+	GetExperiencePointsForUpdate(ctx context.Context, tx *sql.Tx, profileID int) (int, error)
+	SetExperiencePoints(ctx context.Context, tx *sql.Tx, profileID int, points int, level int) error
+	UpdateHandle(ctx context.Context, tx *sql.Tx, profileID int, handle string, changedAt time.Time) error
+	Search(ctx context.Context, query string, viewerProfileID int, viewerCountry string, beforeScore int64, beforeID int, limit int) ([]model.ProfileSearchResult, error)
 }
 
 type profileRepository struct {
@@ -23,30 +32,35 @@ func NewProfileRepository(db client.DatabaseService) ProfileRepository {
 	return &profileRepository{db: db}
 }
 
-func (r *profileRepository) GetByUserID(ctx context.Context, userID int) (*model.ProfileWithUser, error) {
-	fmt.Println(userID)
-	query := `
+const profileWithUserQuery = `
         SELECT 
-            p.id, p.user_id, p.display_name, p.privacy, p.avatar_version, 
-            p.is_notifications_enabled, p.fitness_experience, p.experience_points, 
+            p.id, p.user_id, p.display_name, p.handle, p.handle_changed_at, p.privacy, p.role, p.avatar_version, 
+            p.is_notifications_enabled, p.fitness_experience, p.one_rep_max_formula, p.weight_unit, p.weight_increment, p.bar_weight_kg, p.experience_points, p.level, 
             p.created_at, p.updated_at, 
             u.first_name, u.last_name, u.country 
         FROM profiles p 
         INNER JOIN users u ON p.user_id = u.id 
-        WHERE p.user_id = ?
     `
-	row := r.db.QueryRowContext(ctx, query, userID)
 
+func scanProfileWithUser(scanner interface{ Scan(...interface{}) error }) (*model.ProfileWithUser, error) {
 	var profile model.ProfileWithUser
-	err := row.Scan(
+	err := scanner.Scan(
 		&profile.ID,
 		&profile.UserID,
 		&profile.DisplayName,
+		&profile.Handle,
+		&profile.HandleChangedAt,
 		&profile.Privacy,
+		&profile.Role,
 		&profile.AvatarVersion,
 		&profile.IsNotificationsEnabled,
 		&profile.FitnessExperience,
+		&profile.OneRepMaxFormula,
+		&profile.WeightUnit,
+		&profile.WeightIncrement,
+		&profile.BarWeightKg,
 		&profile.ExperiencePoints,
+		&profile.Level,
 		&profile.CreatedAt,
 		&profile.UpdatedAt,
 		&profile.FirstName,
@@ -60,12 +74,42 @@ func (r *profileRepository) GetByUserID(ctx context.Context, userID int) (*model
 	return &profile, nil
 }
 
+func (r *profileRepository) GetByUserID(ctx context.Context, userID int) (*model.ProfileWithUser, error) {
+	fmt.Println(userID)
+	row := r.db.QueryRowContext(ctx, profileWithUserQuery+`WHERE p.user_id = ?`, userID)
+
+	return scanProfileWithUser(row)
+}
+
+func (r *profileRepository) GetByID(ctx context.Context, id int) (*model.ProfileWithUser, error) {
+	row := r.db.QueryRowContext(ctx, profileWithUserQuery+`WHERE p.id = ?`, id)
+
+	profile, err := scanProfileWithUser(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+
+	return profile, err
+}
+
+// GetByHandle looks up a profile by its current handle, which must already be lowercased.
+func (r *profileRepository) GetByHandle(ctx context.Context, handle string) (*model.ProfileWithUser, error) {
+	row := r.db.QueryRowContext(ctx, profileWithUserQuery+`WHERE p.handle = ?`, handle)
+
+	profile, err := scanProfileWithUser(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+
+	return profile, err
+}
+
 func (r *profileRepository) Create(ctx context.Context, tx *sql.Tx, profile *model.Profile) (*model.Profile, error) {
 	query := `
 		INSERT INTO profiles 
-			(user_id, display_name, privacy, avatar_version, is_notifications_enabled, fitness_experience, experience_points) 
+			(user_id, display_name, handle, privacy, avatar_version, is_notifications_enabled, fitness_experience, one_rep_max_formula, experience_points) 
 		VALUES 
-			(?, ?, ?, ?, ?, ?, ?)
+			(?, ?, CONCAT('new', UUID_SHORT()), ?, ?, ?, ?, COALESCE(NULLIF(?, ''), 'epley'), ?)
 	`
 	res, err := tx.ExecContext(
 		ctx, query,
@@ -75,6 +119,7 @@ func (r *profileRepository) Create(ctx context.Context, tx *sql.Tx, profile *mod
 		profile.AvatarVersion,
 		profile.IsNotificationsEnabled,
 		profile.FitnessExperience,
+		profile.OneRepMaxFormula,
 		profile.ExperiencePoints,
 	)
 	if err != nil {
@@ -86,14 +131,28 @@ func (r *profileRepository) Create(ctx context.Context, tx *sql.Tx, profile *mod
 		return nil, err
 	}
 
+	// The handle needs the ID, until then it holds a unique placeholder
+	handle := fmt.Sprintf("%s%d", model.DefaultHandlePrefix, id)
+	_, err = tx.ExecContext(ctx, `UPDATE profiles SET handle = ? WHERE id = ?`, handle, id)
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.indexDisplayName(ctx, tx, int(id), profile.DisplayName)
+	if err != nil {
+		return nil, err
+	}
+
 	newProfile := &model.Profile{
 		ID:                     int(id),
 		UserID:                 profile.UserID,
 		DisplayName:            profile.DisplayName,
+		Handle:                 handle,
 		Privacy:                profile.Privacy,
 		AvatarVersion:          profile.AvatarVersion,
 		IsNotificationsEnabled: profile.IsNotificationsEnabled,
 		FitnessExperience:      profile.FitnessExperience,
+		OneRepMaxFormula:       profile.OneRepMaxFormula,
 		ExperiencePoints:       profile.ExperiencePoints,
 	}
 
@@ -102,49 +161,259 @@ func (r *profileRepository) Create(ctx context.Context, tx *sql.Tx, profile *mod
 
 func (r *profileRepository) Update(ctx context.Context, tx *sql.Tx, profile *model.Profile) (*model.Profile, error) {
 	query := `
-		UPDATE profiles p
-		JOIN users u ON p.user_id = u.id
-		SET 
-			p.display_name = COALESCE(NULLIF(?, ''), p.display_name),
-			p.avatar_version = COALESCE(NULLIF(?, 0), p.avatar_version),
-			p.privacy = COALESCE(NULLIF(?, ''), p.privacy),
-			p.fitness_experience = COALESCE(NULLIF(?, ''), p.fitness_experience)
-		WHERE p.user_id = ?
-		AND (
-			p.display_name != COALESCE(NULLIF(?, ''), p.display_name) OR
-			p.avatar_version != COALESCE(NULLIF(?, 0), p.avatar_version) OR
-			p.privacy != COALESCE(NULLIF(?, ''), p.privacy) OR
-			p.fitness_experience != COALESCE(NULLIF(?, ''), p.fitness_experience)
-		);
-
-		SELECT 
-			p.id AS profile_id,
-			p.display_name,
-			p.avatar_version,
-			p.privacy,
-			p.fitness_experience,
-			p.experience_points,
-			u.id AS user_id,
-			u.first_name,
-			u.last_name,
-			u.country
-		FROM profiles p
-		JOIN users u ON p.user_id = u.id
-		WHERE p.user_id = ?;
+		UPDATE profiles
+		SET
+			display_name = ?,
+			avatar_version = ?,
+			privacy = ?,
+			is_notifications_enabled = ?,
+			fitness_experience = ?,
+			one_rep_max_formula = ?,
+			weight_unit = ?,
+			weight_increment = ?,
+			bar_weight_kg = ?
+		WHERE id = ?
 	`
-	_, err := r.db.ExecContext(
+	_, err := tx.ExecContext(
 		ctx, query,
 		profile.DisplayName,
 		profile.AvatarVersion,
 		profile.Privacy,
-		profile.FitnessExperience,
 		profile.IsNotificationsEnabled,
-		profile.ExperiencePoints,
+		profile.FitnessExperience,
+		profile.OneRepMaxFormula,
+		profile.WeightUnit,
+		profile.WeightIncrement,
+		profile.BarWeightKg,
 		profile.ID,
 	)
 	if err != nil {
 		return nil, err
 	}
 
+	err = r.indexDisplayName(ctx, tx, profile.ID, profile.DisplayName)
+	if err != nil {
+		return nil, err
+	}
+
 	return profile, nil
 }
+
+// GetIDs returns the IDs of profiles after afterID in ascending order, for walking every profile in batches.
+func (r *profileRepository) GetIDs(ctx context.Context, afterID int, limit int) ([]int, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT id FROM profiles WHERE id > ? ORDER BY id LIMIT ?`, afterID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ids := []int{}
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, rows.Err()
+}
+
+// GetExperiencePointsForUpdate returns the profile's total, locking the profile until the
+// transaction ends so concurrent awards are applied one after the other.
+func (r *profileRepository) GetExperiencePointsForUpdate(ctx context.Context, tx *sql.Tx, profileID int) (int, error) {
+	var points int
+	err := tx.QueryRowContext(ctx, `SELECT experience_points FROM profiles WHERE id = ? FOR UPDATE`, profileID).Scan(&points)
+
+	return points, err
+}
+
+// SetExperiencePoints stores the profile's total and the level it corresponds to.
+func (r *profileRepository) SetExperiencePoints(ctx context.Context, tx *sql.Tx, profileID int, points int, level int) error {
+	_, err := tx.ExecContext(ctx, `UPDATE profiles SET experience_points = ?, level = ? WHERE id = ?`, points, level, profileID)
+
+	return err
+}
+
+// UpdateHandle changes the profile's handle, which must already be lowercased and free.
+func (r *profileRepository) UpdateHandle(ctx context.Context, tx *sql.Tx, profileID int, handle string, changedAt time.Time) error {
+	_, err := tx.ExecContext(ctx, `UPDATE profiles SET handle = ?, handle_changed_at = ? WHERE id = ?`, handle, changedAt, profileID)
+
+	return err
+}
+
+const (
+	// minFuzzySearchLength is the shortest query matched by trigrams as well as by prefix, shorter
+	// ones share too few trigrams with any name to tell a typo from a different name.
+	minFuzzySearchLength = 3
+	// fuzzySearchCandidates caps the profiles sharing trigrams with the query that get ranked, so
+	// searches stay fast however many names contain a common trigram.
+	fuzzySearchCandidates = 500
+)
+
+// Search finds profiles whose display name starts with the query, has a word starting with it or
+// is close to it allowing for typos, for typeahead. The score ranks exact and prefix matches over
+// fuzzy ones, then boosts profiles the viewer follows, profiles followed by people the viewer
+// follows and profiles in the viewer's country. The viewer and profiles blocked either way are left
+// out. Pass a zero beforeID for the first page.
+func (r *profileRepository) Search(
+	ctx context.Context,
+	query string,
+	viewerProfileID int,
+	viewerCountry string,
+	beforeScore int64,
+	beforeID int,
+	limit int,
+) ([]model.ProfileSearchResult, error) {
+	normalized := strings.ToLower(strings.Trim(query, " "))
+	pattern := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(normalized)
+
+	// The query is usually still being typed, so it is not padded at the end like indexed names
+	trigrams := []string{}
+	if len([]rune(normalized)) >= minFuzzySearchLength {
+		trigrams = searchTrigrams("  " + normalized)
+	}
+
+	// A query that can't match trigrams looks them up with an empty list that finds nothing
+	trigramList, trigramArgs := `NULL`, []interface{}{}
+	if len(trigrams) > 0 {
+		trigramList = strings.TrimSuffix(strings.Repeat("?, ", len(trigrams)), ", ")
+		for _, trigram := range trigrams {
+			trigramArgs = append(trigramArgs, trigram)
+		}
+	}
+
+	// At least 40% of the query's trigrams must be shared, roughly one typo per five letters
+	minSharedTrigrams := (len(trigrams)*2 + 4) / 5
+
+	sqlQuery := `
+		WITH fuzzy AS (
+			SELECT t.profile_id, COUNT(*) AS shared
+			FROM profile_search_trigrams t
+			WHERE t.trigram IN (` + trigramList + `)
+			GROUP BY t.profile_id
+			HAVING COUNT(*) >= ?
+			ORDER BY shared DESC
+			LIMIT ?
+		), prefixed AS (
+			SELECT p.id AS profile_id
+			FROM profiles p
+			WHERE p.display_name LIKE CONCAT(?, '%')
+			ORDER BY p.display_name
+			LIMIT ?
+		), ranked AS (
+			SELECT
+				p.id, p.display_name, p.handle, p.avatar_version, p.privacy,
+				EXISTS (
+					SELECT 1 FROM profile_follows f
+					WHERE f.profile_id = p.id AND f.follower_profile_id = ?
+				) AS following,
+				(
+					SELECT COUNT(*)
+					FROM profile_follows vf
+					INNER JOIN profile_follows mf ON mf.follower_profile_id = vf.profile_id
+					WHERE vf.follower_profile_id = ? AND mf.profile_id = p.id
+				) AS mutual_followers,
+				u.country = ? AS same_country,
+				COALESCE(fuzzy.shared, 0) AS shared
+			FROM profiles p
+			INNER JOIN users u ON u.id = p.user_id
+			LEFT JOIN fuzzy ON fuzzy.profile_id = p.id
+			WHERE p.id IN (SELECT profile_id FROM fuzzy UNION SELECT profile_id FROM prefixed)
+			AND p.id != ?
+			AND ` + notBlockedCondition("p.id") + `
+		), scored AS (
+			SELECT
+				ranked.*,
+				CASE
+					WHEN display_name = ? THEN 1000
+					WHEN display_name LIKE CONCAT(?, '%') THEN 800
+					WHEN display_name LIKE CONCAT('% ', ?, '%') THEN 600
+					ELSE 0
+				END
+				+ ROUND(shared * 500 / GREATEST(?, 1))
+				+ IF(following, 200, 0)
+				+ LEAST(mutual_followers, 10) * 20
+				+ IF(same_country, 100, 0) AS score
+			FROM ranked
+		)
+		SELECT id, display_name, handle, avatar_version, privacy, following, mutual_followers, same_country, score
+		FROM scored
+		WHERE (? OR (score, id) < (?, ?))
+		ORDER BY score DESC, id DESC
+		LIMIT ?
+	`
+
+	args := append(trigramArgs, minSharedTrigrams, fuzzySearchCandidates, pattern, fuzzySearchCandidates)
+	args = append(args, viewerProfileID, viewerProfileID, viewerCountry, viewerProfileID, viewerProfileID, viewerProfileID)
+	args = append(args, normalized, pattern, pattern, len(trigrams))
+	args = append(args, beforeID == 0, beforeScore, beforeID, limit)
+
+	rows, err := r.db.QueryContext(ctx, sqlQuery, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	results := []model.ProfileSearchResult{}
+	for rows.Next() {
+		var result model.ProfileSearchResult
+		if err := rows.Scan(
+			&result.ID,
+			&result.DisplayName,
+			&result.Handle,
+			&result.AvatarVersion,
+			&result.Privacy,
+			&result.Following,
+			&result.MutualFollowersCount,
+			&result.SameCountry,
+			&result.Score,
+		); err != nil {
+			return nil, err
+		}
+		results = append(results, result)
+	}
+
+	return results, rows.Err()
+}
+
+// indexDisplayName replaces the search trigrams of the profile with those of its display name.
+func (r *profileRepository) indexDisplayName(ctx context.Context, tx *sql.Tx, profileID int, displayName string) error {
+	_, err := tx.ExecContext(ctx, `DELETE FROM profile_search_trigrams WHERE profile_id = ?`, profileID)
+	if err != nil {
+		return err
+	}
+
+	normalized := strings.ToLower(strings.Trim(displayName, " "))
+	if normalized == "" {
+		return nil
+	}
+
+	// Padded the same way as the backfill in the migration creating the table
+	trigrams := searchTrigrams("  " + normalized + " ")
+	placeholders := strings.TrimSuffix(strings.Repeat("(?, ?), ", len(trigrams)), ", ")
+	args := make([]interface{}, 0, len(trigrams)*2)
+	for _, trigram := range trigrams {
+		args = append(args, trigram, profileID)
+	}
+
+	_, err = tx.ExecContext(ctx, `INSERT IGNORE INTO profile_search_trigrams (trigram, profile_id) VALUES `+placeholders, args...)
+
+	return err
+}
+
+// searchTrigrams returns the distinct runs of three characters in text.
+func searchTrigrams(text string) []string {
+	runes := []rune(text)
+	seen := map[string]bool{}
+	trigrams := []string{}
+	for i := 0; i+3 <= len(runes); i++ {
+		trigram := string(runes[i : i+3])
+		if !seen[trigram] {
+			seen[trigram] = true
+			trigrams = append(trigrams, trigram)
+		}
+	}
+
+	return trigrams
+}
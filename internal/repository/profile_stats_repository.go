@@ -0,0 +1,174 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/brightside-dev/ronin-fitness-be/database/client"
+	"github.com/brightside-dev/ronin-fitness-be/internal/model"
+)
+
+type ProfileStatsRepository interface {
+	AddDailyStats(ctx context.Context, tx *sql.Tx, stats *model.ProfileDailyStats) error
+	AddDailyMuscleGroupStats(ctx context.Context, tx *sql.Tx, stats *model.ProfileDailyMuscleGroupStats) error
+	GetDailyStats(ctx context.Context, profileID int, from time.Time, to time.Time) ([]model.ProfileDailyStats, error)
+	GetDailyMuscleGroupStats(ctx context.Context, profileID int, from time.Time, to time.Time) ([]model.ProfileDailyMuscleGroupStats, error)
+	GetWorkoutDates(ctx context.Context, profileID int, to time.Time, limit int) ([]time.Time, error)
+	GetWorkoutCount(ctx context.Context, tx *sql.Tx, profileID int, date time.Time) (int, error)
+}
+
+type profileStatsRepository struct {
+	db client.DatabaseService
+}
+
+func NewProfileStatsRepository(db client.DatabaseService) ProfileStatsRepository {
+	return &profileStatsRepository{db: db}
+}
+
+// AddDailyStats adds the given values to the day's totals. Pass negative values to remove a workout.
+func (r *profileStatsRepository) AddDailyStats(ctx context.Context, tx *sql.Tx, stats *model.ProfileDailyStats) error {
+	query := `
+		INSERT INTO profile_daily_stats
+			(profile_id, stat_date, workout_count, total_duration_seconds, total_volume_kg, total_sets, mental_energy_sum, physical_energy_sum)
+		VALUES
+			(?, ?, ?, ?, ?, ?, ?, ?)
+		ON DUPLICATE KEY UPDATE
+			workout_count = workout_count + VALUES(workout_count),
+			total_duration_seconds = total_duration_seconds + VALUES(total_duration_seconds),
+			total_volume_kg = total_volume_kg + VALUES(total_volume_kg),
+			total_sets = total_sets + VALUES(total_sets),
+			mental_energy_sum = mental_energy_sum + VALUES(mental_energy_sum),
+			physical_energy_sum = physical_energy_sum + VALUES(physical_energy_sum)
+	`
+
+	_, err := tx.ExecContext(
+		ctx, query,
+		stats.ProfileID,
+		stats.StatDate.Format("2006-01-02"),
+		stats.WorkoutCount,
+		stats.TotalDurationSeconds,
+		stats.TotalVolumeKg,
+		stats.TotalSets,
+		stats.MentalEnergySum,
+		stats.PhysicalEnergySum,
+	)
+
+	return err
+}
+
+// AddDailyMuscleGroupStats adds the given set count to the day's muscle group total.
+func (r *profileStatsRepository) AddDailyMuscleGroupStats(ctx context.Context, tx *sql.Tx, stats *model.ProfileDailyMuscleGroupStats) error {
+	query := `
+		INSERT INTO profile_daily_muscle_group_stats (profile_id, stat_date, muscle_group, set_count)
+		VALUES (?, ?, ?, ?)
+		ON DUPLICATE KEY UPDATE set_count = set_count + VALUES(set_count)
+	`
+
+	_, err := tx.ExecContext(ctx, query, stats.ProfileID, stats.StatDate.Format("2006-01-02"), stats.MuscleGroup, stats.SetCount)
+
+	return err
+}
+
+func (r *profileStatsRepository) GetDailyStats(ctx context.Context, profileID int, from time.Time, to time.Time) ([]model.ProfileDailyStats, error) {
+	query := `
+		SELECT
+			profile_id, stat_date, workout_count, total_duration_seconds, total_volume_kg,
+			total_sets, mental_energy_sum, physical_energy_sum
+		FROM profile_daily_stats
+		WHERE profile_id = ? AND stat_date BETWEEN ? AND ?
+		AND workout_count > 0
+		ORDER BY stat_date
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, profileID, from.Format("2006-01-02"), to.Format("2006-01-02"))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	dailyStats := []model.ProfileDailyStats{}
+	for rows.Next() {
+		var stats model.ProfileDailyStats
+		if err := rows.Scan(
+			&stats.ProfileID,
+			&stats.StatDate,
+			&stats.WorkoutCount,
+			&stats.TotalDurationSeconds,
+			&stats.TotalVolumeKg,
+			&stats.TotalSets,
+			&stats.MentalEnergySum,
+			&stats.PhysicalEnergySum,
+		); err != nil {
+			return nil, err
+		}
+		dailyStats = append(dailyStats, stats)
+	}
+
+	return dailyStats, rows.Err()
+}
+
+func (r *profileStatsRepository) GetDailyMuscleGroupStats(ctx context.Context, profileID int, from time.Time, to time.Time) ([]model.ProfileDailyMuscleGroupStats, error) {
+	query := `
+		SELECT profile_id, stat_date, muscle_group, set_count
+		FROM profile_daily_muscle_group_stats
+		WHERE profile_id = ? AND stat_date BETWEEN ? AND ?
+		AND set_count > 0
+		ORDER BY stat_date
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, profileID, from.Format("2006-01-02"), to.Format("2006-01-02"))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	muscleGroupStats := []model.ProfileDailyMuscleGroupStats{}
+	for rows.Next() {
+		var stats model.ProfileDailyMuscleGroupStats
+		if err := rows.Scan(&stats.ProfileID, &stats.StatDate, &stats.MuscleGroup, &stats.SetCount); err != nil {
+			return nil, err
+		}
+		muscleGroupStats = append(muscleGroupStats, stats)
+	}
+
+	return muscleGroupStats, rows.Err()
+}
+
+// GetWorkoutCount returns how many workouts the profile logged on the day.
+func (r *profileStatsRepository) GetWorkoutCount(ctx context.Context, tx *sql.Tx, profileID int, date time.Time) (int, error) {
+	query := `SELECT COALESCE(SUM(workout_count), 0) FROM profile_daily_stats WHERE profile_id = ? AND stat_date = ?`
+
+	var count int
+	err := tx.QueryRowContext(ctx, query, profileID, date.Format("2006-01-02")).Scan(&count)
+
+	return count, err
+}
+
+// GetWorkoutDates returns the most recent days up to and including "to" on which the profile trained.
+func (r *profileStatsRepository) GetWorkoutDates(ctx context.Context, profileID int, to time.Time, limit int) ([]time.Time, error) {
+	query := `
+		SELECT stat_date
+		FROM profile_daily_stats
+		WHERE profile_id = ? AND stat_date <= ? AND workout_count > 0
+		ORDER BY stat_date DESC
+		LIMIT ?
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, profileID, to.Format("2006-01-02"), limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	dates := []time.Time{}
+	for rows.Next() {
+		var date time.Time
+		if err := rows.Scan(&date); err != nil {
+			return nil, err
+		}
+		dates = append(dates, date)
+	}
+
+	return dates, rows.Err()
+}
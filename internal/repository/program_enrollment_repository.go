@@ -0,0 +1,186 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/brightside-dev/ronin-fitness-be/database/client"
+	"github.com/brightside-dev/ronin-fitness-be/internal/model"
+)
+
+type ProgramEnrollmentRepository interface {
+	GetByID(ctx context.Context, id int) (*model.ProgramEnrollment, error)
+	GetByIDForUpdate(ctx context.Context, tx *sql.Tx, id int) (*model.ProgramEnrollment, error)
+	GetByProfileAndProgram(ctx context.Context, profileID int, programID int) (*model.ProgramEnrollment, error)
+	GetByProfileID(ctx context.Context, profileID int) ([]model.ProgramEnrollment, error)
+	Create(ctx context.Context, enrollment *model.ProgramEnrollment) (*model.ProgramEnrollment, error)
+	Pause(ctx context.Context, id int) error
+	Resume(ctx context.Context, id int) error
+	Restart(ctx context.Context, id int) error
+	Advance(ctx context.Context, tx *sql.Tx, id int, sessionCount int) error
+	CreateSession(ctx context.Context, tx *sql.Tx, enrollmentID int, programDayID int, workoutID int) error
+}
+
+type programEnrollmentRepository struct {
+	db client.DatabaseService
+}
+
+func NewProgramEnrollmentRepository(db client.DatabaseService) ProgramEnrollmentRepository {
+	return &programEnrollmentRepository{db: db}
+}
+
+const programEnrollmentColumns = `id, profile_id, program_id, status, current_session, started_at, paused_at, completed_at, created_at, updated_at`
+
+func scanProgramEnrollment(scanner interface{ Scan(...interface{}) error }) (*model.ProgramEnrollment, error) {
+	var enrollment model.ProgramEnrollment
+	var pausedAt, completedAt sql.NullTime
+	err := scanner.Scan(
+		&enrollment.ID,
+		&enrollment.ProfileID,
+		&enrollment.ProgramID,
+		&enrollment.Status,
+		&enrollment.CurrentSession,
+		&enrollment.StartedAt,
+		&pausedAt,
+		&completedAt,
+		&enrollment.CreatedAt,
+		&enrollment.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if pausedAt.Valid {
+		enrollment.PausedAt = &pausedAt.Time
+	}
+	if completedAt.Valid {
+		enrollment.CompletedAt = &completedAt.Time
+	}
+
+	return &enrollment, nil
+}
+
+func (r *programEnrollmentRepository) GetByID(ctx context.Context, id int) (*model.ProgramEnrollment, error) {
+	row := r.db.QueryRowContext(ctx, `SELECT `+programEnrollmentColumns+` FROM program_enrollments WHERE id = ?`, id)
+
+	enrollment, err := scanProgramEnrollment(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+
+	return enrollment, err
+}
+
+// GetByIDForUpdate locks the enrollment row until the transaction ends so concurrent
+// workouts cannot complete the same session twice.
+func (r *programEnrollmentRepository) GetByIDForUpdate(ctx context.Context, tx *sql.Tx, id int) (*model.ProgramEnrollment, error) {
+	row := tx.QueryRowContext(ctx, `SELECT `+programEnrollmentColumns+` FROM program_enrollments WHERE id = ? FOR UPDATE`, id)
+
+	enrollment, err := scanProgramEnrollment(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+
+	return enrollment, err
+}
+
+func (r *programEnrollmentRepository) GetByProfileAndProgram(ctx context.Context, profileID int, programID int) (*model.ProgramEnrollment, error) {
+	query := `SELECT ` + programEnrollmentColumns + ` FROM program_enrollments WHERE profile_id = ? AND program_id = ?`
+	row := r.db.QueryRowContext(ctx, query, profileID, programID)
+
+	enrollment, err := scanProgramEnrollment(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+
+	return enrollment, err
+}
+
+func (r *programEnrollmentRepository) GetByProfileID(ctx context.Context, profileID int) ([]model.ProgramEnrollment, error) {
+	query := `SELECT ` + programEnrollmentColumns + ` FROM program_enrollments WHERE profile_id = ? ORDER BY updated_at DESC, id DESC`
+
+	rows, err := r.db.QueryContext(ctx, query, profileID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	enrollments := []model.ProgramEnrollment{}
+	for rows.Next() {
+		enrollment, err := scanProgramEnrollment(rows)
+		if err != nil {
+			return nil, err
+		}
+		enrollments = append(enrollments, *enrollment)
+	}
+
+	return enrollments, rows.Err()
+}
+
+func (r *programEnrollmentRepository) Create(ctx context.Context, enrollment *model.ProgramEnrollment) (*model.ProgramEnrollment, error) {
+	res, err := r.db.ExecContext(ctx, `INSERT INTO program_enrollments (profile_id, program_id) VALUES (?, ?)`, enrollment.ProfileID, enrollment.ProgramID)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return nil, err
+	}
+
+	return r.GetByID(ctx, int(id))
+}
+
+func (r *programEnrollmentRepository) Pause(ctx context.Context, id int) error {
+	query := `UPDATE program_enrollments SET status = 'paused', paused_at = NOW() WHERE id = ? AND status = 'active'`
+
+	_, err := r.db.ExecContext(ctx, query, id)
+
+	return err
+}
+
+func (r *programEnrollmentRepository) Resume(ctx context.Context, id int) error {
+	query := `UPDATE program_enrollments SET status = 'active', paused_at = NULL WHERE id = ? AND status = 'paused'`
+
+	_, err := r.db.ExecContext(ctx, query, id)
+
+	return err
+}
+
+// Restart puts the enrollment back to the first session of the program.
+func (r *programEnrollmentRepository) Restart(ctx context.Context, id int) error {
+	query := `
+		UPDATE program_enrollments
+		SET status = 'active', current_session = 0, started_at = NOW(), paused_at = NULL, completed_at = NULL
+		WHERE id = ?
+	`
+
+	_, err := r.db.ExecContext(ctx, query, id)
+
+	return err
+}
+
+// Advance moves the enrollment to its next session, completing it once all sessionCount sessions are done.
+func (r *programEnrollmentRepository) Advance(ctx context.Context, tx *sql.Tx, id int, sessionCount int) error {
+	// MySQL applies single-table assignments left to right, so the status checks see the advanced session
+	query := `
+		UPDATE program_enrollments
+		SET
+			current_session = current_session + 1,
+			status = IF(current_session >= ?, 'completed', status),
+			completed_at = IF(current_session >= ?, NOW(), completed_at)
+		WHERE id = ?
+	`
+
+	_, err := tx.ExecContext(ctx, query, sessionCount, sessionCount, id)
+
+	return err
+}
+
+func (r *programEnrollmentRepository) CreateSession(ctx context.Context, tx *sql.Tx, enrollmentID int, programDayID int, workoutID int) error {
+	query := `INSERT INTO program_enrollment_sessions (enrollment_id, program_day_id, workout_id) VALUES (?, ?, ?)`
+
+	_, err := tx.ExecContext(ctx, query, enrollmentID, programDayID, workoutID)
+
+	return err
+}
@@ -0,0 +1,264 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/brightside-dev/ronin-fitness-be/database/client"
+	"github.com/brightside-dev/ronin-fitness-be/internal/model"
+)
+
+type ProgramRepository interface {
+	GetByID(ctx context.Context, id int) (*model.Program, error)
+	GetCatalogue(ctx context.Context, profileID int, beforeTime time.Time, beforeID int, limit int) ([]model.Program, error)
+	Create(ctx context.Context, tx *sql.Tx, program *model.Program) (*model.Program, error)
+	Update(ctx context.Context, tx *sql.Tx, program *model.Program) error
+	Publish(ctx context.Context, id int) error
+	ReplaceDays(ctx context.Context, tx *sql.Tx, programID int, days []model.ProgramDay) error
+	GetDays(ctx context.Context, programID int) ([]model.ProgramDay, error)
+	ReplaceRules(ctx context.Context, tx *sql.Tx, programID int, rules []model.ProgressionRule) error
+	GetRules(ctx context.Context, programID int) ([]model.ProgressionRule, error)
+}
+
+type programRepository struct {
+	db client.DatabaseService
+}
+
+func NewProgramRepository(db client.DatabaseService) ProgramRepository {
+	return &programRepository{db: db}
+}
+
+const programColumns = `id, profile_id, name, description, duration_weeks, status, published_at, created_at, updated_at`
+
+func scanProgram(scanner interface{ Scan(...interface{}) error }) (*model.Program, error) {
+	var program model.Program
+	var description sql.NullString
+	var publishedAt sql.NullTime
+	err := scanner.Scan(
+		&program.ID,
+		&program.ProfileID,
+		&program.Name,
+		&description,
+		&program.DurationWeeks,
+		&program.Status,
+		&publishedAt,
+		&program.CreatedAt,
+		&program.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	program.Description = description.String
+	if publishedAt.Valid {
+		program.PublishedAt = &publishedAt.Time
+	}
+
+	return &program, nil
+}
+
+func (r *programRepository) GetByID(ctx context.Context, id int) (*model.Program, error) {
+	row := r.db.QueryRowContext(ctx, `SELECT `+programColumns+` FROM programs WHERE id = ?`, id)
+
+	program, err := scanProgram(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+
+	return program, err
+}
+
+// GetCatalogue returns published programs along with the profile's own drafts, newest first, leaving
+// out programs of profiles blocked by or blocking the profile.
+func (r *programRepository) GetCatalogue(ctx context.Context, profileID int, beforeTime time.Time, beforeID int, limit int) ([]model.Program, error) {
+	query := `
+		SELECT ` + programColumns + `
+		FROM programs
+		WHERE (status = 'published' OR profile_id = ?)
+			AND ` + notBlockedCondition("profile_id") + `
+			AND (? OR (created_at, id) < (?, ?))
+		ORDER BY created_at DESC, id DESC
+		LIMIT ?
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, profileID, profileID, profileID, beforeTime.IsZero(), beforeTime, beforeID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	programs := []model.Program{}
+	for rows.Next() {
+		program, err := scanProgram(rows)
+		if err != nil {
+			return nil, err
+		}
+		programs = append(programs, *program)
+	}
+
+	return programs, rows.Err()
+}
+
+func (r *programRepository) Create(ctx context.Context, tx *sql.Tx, program *model.Program) (*model.Program, error) {
+	query := `
+		INSERT INTO programs (profile_id, name, description, duration_weeks)
+		VALUES (?, ?, NULLIF(?, ''), ?)
+	`
+
+	res, err := tx.ExecContext(ctx, query, program.ProfileID, program.Name, program.Description, program.DurationWeeks)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return nil, err
+	}
+
+	program.ID = int(id)
+	program.Status = model.ProgramStatusDraft
+
+	return program, nil
+}
+
+func (r *programRepository) Update(ctx context.Context, tx *sql.Tx, program *model.Program) error {
+	query := `UPDATE programs SET name = ?, description = NULLIF(?, ''), duration_weeks = ? WHERE id = ?`
+
+	_, err := tx.ExecContext(ctx, query, program.Name, program.Description, program.DurationWeeks, program.ID)
+
+	return err
+}
+
+func (r *programRepository) Publish(ctx context.Context, id int) error {
+	query := `UPDATE programs SET status = 'published', published_at = COALESCE(published_at, NOW()) WHERE id = ?`
+
+	_, err := r.db.ExecContext(ctx, query, id)
+
+	return err
+}
+
+func (r *programRepository) ReplaceDays(ctx context.Context, tx *sql.Tx, programID int, days []model.ProgramDay) error {
+	_, err := tx.ExecContext(ctx, `DELETE FROM program_days WHERE program_id = ?`, programID)
+	if err != nil {
+		return err
+	}
+
+	query := `INSERT INTO program_days (program_id, week_number, day_number, template_id) VALUES (?, ?, ?, ?)`
+	for _, day := range days {
+		_, err := tx.ExecContext(ctx, query, programID, day.WeekNumber, day.DayNumber, day.TemplateID)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// GetDays returns the program's days in the order they are meant to be trained.
+func (r *programRepository) GetDays(ctx context.Context, programID int) ([]model.ProgramDay, error) {
+	query := `
+		SELECT id, program_id, week_number, day_number, template_id
+		FROM program_days
+		WHERE program_id = ?
+		ORDER BY week_number, day_number
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, programID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	days := []model.ProgramDay{}
+	for rows.Next() {
+		var day model.ProgramDay
+		if err := rows.Scan(&day.ID, &day.ProgramID, &day.WeekNumber, &day.DayNumber, &day.TemplateID); err != nil {
+			return nil, err
+		}
+		days = append(days, day)
+	}
+
+	return days, rows.Err()
+}
+
+func (r *programRepository) ReplaceRules(ctx context.Context, tx *sql.Tx, programID int, rules []model.ProgressionRule) error {
+	_, err := tx.ExecContext(ctx, `DELETE FROM program_progression_rules WHERE program_id = ?`, programID)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO program_progression_rules
+			(program_id, exercise_id, rule_type, increment_kg, training_max_percent, week_percentages)
+		VALUES
+			(?, ?, ?, ?, ?, ?)
+	`
+	for _, rule := range rules {
+		var weekPercentages interface{}
+		if len(rule.WeekPercentages) > 0 {
+			b, err := json.Marshal(rule.WeekPercentages)
+			if err != nil {
+				return err
+			}
+			weekPercentages = string(b)
+		}
+
+		_, err := tx.ExecContext(
+			ctx, query,
+			programID,
+			rule.ExerciseID,
+			rule.RuleType,
+			rule.IncrementKg,
+			rule.TrainingMaxPercent,
+			weekPercentages,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *programRepository) GetRules(ctx context.Context, programID int) ([]model.ProgressionRule, error) {
+	query := `
+		SELECT id, program_id, exercise_id, rule_type, increment_kg, training_max_percent, week_percentages
+		FROM program_progression_rules
+		WHERE program_id = ?
+		ORDER BY id
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, programID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	rules := []model.ProgressionRule{}
+	for rows.Next() {
+		var rule model.ProgressionRule
+		var weekPercentages sql.NullString
+		if err := rows.Scan(
+			&rule.ID,
+			&rule.ProgramID,
+			&rule.ExerciseID,
+			&rule.RuleType,
+			&rule.IncrementKg,
+			&rule.TrainingMaxPercent,
+			&weekPercentages,
+		); err != nil {
+			return nil, err
+		}
+
+		if weekPercentages.Valid {
+			if err := json.Unmarshal([]byte(weekPercentages.String), &rule.WeekPercentages); err != nil {
+				return nil, err
+			}
+		}
+		rules = append(rules, rule)
+	}
+
+	return rules, rows.Err()
+}
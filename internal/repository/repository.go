@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+)
+
+// queryer runs reads on the database or, when a caller passes one, inside its transaction.
+type queryer interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// oneRepMaxExpression is the SQL form of service.EstimateOneRepMax for the weight and reps columns.
+// It takes the formula as its one argument. Dividing last keeps the DECIMAL arithmetic exact enough
+// to round the same way.
+func oneRepMaxExpression(weightColumn string, repsColumn string) string {
+	return `CASE
+		WHEN ` + repsColumn + ` <= 0 OR ` + weightColumn + ` <= 0 THEN 0
+		WHEN ` + repsColumn + ` = 1 THEN ` + weightColumn + `
+		WHEN ? = 'brzycki' AND ` + repsColumn + ` < 37 THEN ROUND(` + weightColumn + ` * 36 / (37 - ` + repsColumn + `), 2)
+		ELSE ROUND(` + weightColumn + ` * (30 + ` + repsColumn + `) / 30, 2)
+	END`
+}
+
+// inClause builds the placeholder list and arguments for an "IN (...)" condition.
+func inClause(ids []int) (string, []interface{}) {
+	args := make([]interface{}, len(ids))
+	for i, id := range ids {
+		args[i] = id
+	}
+
+	return strings.TrimSuffix(strings.Repeat("?, ", len(ids)), ", "), args
+}
+
+// notBlockedCondition is an SQL condition that holds unless the profile in column and the viewer
+// have blocked one another, in either direction. It takes the viewer's profile ID twice as arguments.
+// Every query showing other profiles or their content to a viewer must include it.
+func notBlockedCondition(column string) string {
+	return `NOT EXISTS (
+		SELECT 1 FROM profile_blocks pb
+		WHERE (pb.profile_id = ? AND pb.blocked_profile_id = ` + column + `)
+		OR (pb.profile_id = ` + column + ` AND pb.blocked_profile_id = ?)
+	)`
+}
+
+// notMutedCondition is an SQL condition that holds unless the viewer, passed as its one argument,
+// muted the profile in column.
+func notMutedCondition(column string) string {
+	return `NOT EXISTS (
+		SELECT 1 FROM profile_mutes pm
+		WHERE pm.profile_id = ? AND pm.muted_profile_id = ` + column + `
+	)`
+}
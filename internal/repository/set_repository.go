@@ -0,0 +1,323 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/brightside-dev/ronin-fitness-be/database/client"
+	"github.com/brightside-dev/ronin-fitness-be/internal/model"
+)
+
+type SetRepository interface {
+	Create(ctx context.Context, tx *sql.Tx, set *model.Set) (*model.Set, error)
+	GetByWorkoutID(ctx context.Context, workoutID int) ([]model.Set, error)
+	GetLastSessionSets(ctx context.Context, profileID int, exerciseID int) ([]model.Set, error)
+	GetBestsBefore(ctx context.Context, tx *sql.Tx, profileID int, exerciseID int, before time.Time, weights []float64) (*model.ExerciseBests, error)
+	RecalculateOneRepMax(ctx context.Context, tx *sql.Tx, profileID int, formula string) error
+	GetExerciseSummariesByWorkoutIDs(ctx context.Context, workoutIDs []int) (map[int][]model.WorkoutExerciseSummary, error)
+	GetTopE1RMs(ctx context.Context, profileID int, from time.Time, to time.Time, limit int) ([]model.ExerciseE1RM, error)
+}
+
+type setRepository struct {
+	db client.DatabaseService
+}
+
+func NewSetRepository(db client.DatabaseService) SetRepository {
+	return &setRepository{db: db}
+}
+
+func (r *setRepository) Create(ctx context.Context, tx *sql.Tx, set *model.Set) (*model.Set, error) {
+	query := `
+		INSERT INTO sets
+			(workout_id, exercise_id, set_number, duration, weight_kg, weight_lb, reps, estimated_one_rep_max_kg)
+		VALUES
+			(?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	res, err := tx.ExecContext(
+		ctx, query,
+		set.WorkoutID,
+		set.ExerciseID,
+		set.SetNumber,
+		set.Duration,
+		set.WeightKg,
+		set.WeightLb,
+		set.Reps,
+		set.E1RMKg,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return nil, err
+	}
+
+	set.ID = int(id)
+
+	return set, nil
+}
+
+// GetByWorkoutID returns the sets of a workout in the order they were logged.
+func (r *setRepository) GetByWorkoutID(ctx context.Context, workoutID int) ([]model.Set, error) {
+	query := `
+		SELECT
+			id, workout_id, exercise_id, set_number, duration, weight_kg, weight_lb, reps,
+			estimated_one_rep_max_kg, created_at, updated_at
+		FROM sets
+		WHERE workout_id = ?
+		ORDER BY id
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, workoutID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	sets := []model.Set{}
+	for rows.Next() {
+		var set model.Set
+		if err := rows.Scan(
+			&set.ID,
+			&set.WorkoutID,
+			&set.ExerciseID,
+			&set.SetNumber,
+			&set.Duration,
+			&set.WeightKg,
+			&set.WeightLb,
+			&set.Reps,
+			&set.E1RMKg,
+			&set.CreatedAt,
+			&set.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		sets = append(sets, set)
+	}
+
+	return sets, rows.Err()
+}
+
+// GetLastSessionSets returns the sets of an exercise from the profile's most recent workout that included it.
+func (r *setRepository) GetLastSessionSets(ctx context.Context, profileID int, exerciseID int) ([]model.Set, error) {
+	query := `
+		SELECT
+			s.id, s.workout_id, s.exercise_id, s.set_number, s.duration, s.weight_kg, s.weight_lb, s.reps,
+			s.estimated_one_rep_max_kg, s.created_at, s.updated_at
+		FROM sets s
+		WHERE s.exercise_id = ? AND s.workout_id = (
+			SELECT w.id
+			FROM workouts w
+			INNER JOIN sets ls ON ls.workout_id = w.id AND ls.exercise_id = ?
+			WHERE w.profile_id = ?
+			ORDER BY w.start_date DESC, w.id DESC
+			LIMIT 1
+		)
+		ORDER BY s.set_number, s.id
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, exerciseID, exerciseID, profileID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	sets := []model.Set{}
+	for rows.Next() {
+		var set model.Set
+		if err := rows.Scan(
+			&set.ID,
+			&set.WorkoutID,
+			&set.ExerciseID,
+			&set.SetNumber,
+			&set.Duration,
+			&set.WeightKg,
+			&set.WeightLb,
+			&set.Reps,
+			&set.E1RMKg,
+			&set.CreatedAt,
+			&set.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		sets = append(sets, set)
+	}
+
+	return sets, rows.Err()
+}
+
+// GetBestsBefore returns the profile's best values for an exercise across workouts that started
+// before the given time. MostRepsByWeight is only filled for the requested weights. Pass the
+// transaction saving a workout so its reads see what the transaction wrote, or nil outside one.
+func (r *setRepository) GetBestsBefore(
+	ctx context.Context,
+	tx *sql.Tx,
+	profileID int,
+	exerciseID int,
+	before time.Time,
+	weights []float64,
+) (*model.ExerciseBests, error) {
+	query := `
+		SELECT
+			COALESCE(MAX(s.weight_kg), 0),
+			COALESCE(MAX(s.estimated_one_rep_max_kg), 0),
+			COALESCE(MAX(s.weight_kg * s.reps), 0)
+		FROM sets s
+		INNER JOIN workouts w ON w.id = s.workout_id
+		WHERE w.profile_id = ? AND s.exercise_id = ? AND w.start_date < ?
+	`
+
+	var q queryer = r.db
+	if tx != nil {
+		q = tx
+	}
+
+	bests := &model.ExerciseBests{MostRepsByWeight: map[float64]int{}}
+	err := q.QueryRowContext(ctx, query, profileID, exerciseID, before).Scan(
+		&bests.HeaviestWeightKg,
+		&bests.BestE1RMKg,
+		&bests.BestVolumeKg,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(weights) == 0 {
+		return bests, nil
+	}
+
+	placeholders := ""
+	args := []interface{}{profileID, exerciseID, before}
+	for i, weight := range weights {
+		if i > 0 {
+			placeholders += ", "
+		}
+		placeholders += "?"
+		args = append(args, weight)
+	}
+
+	query = `
+		SELECT s.weight_kg, MAX(s.reps)
+		FROM sets s
+		INNER JOIN workouts w ON w.id = s.workout_id
+		WHERE w.profile_id = ? AND s.exercise_id = ? AND w.start_date < ?
+		AND s.weight_kg IN (` + placeholders + `)
+		GROUP BY s.weight_kg
+	`
+
+	rows, err := q.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var weight float64
+		var reps int
+		if err := rows.Scan(&weight, &reps); err != nil {
+			return nil, err
+		}
+		bests.MostRepsByWeight[weight] = reps
+	}
+
+	return bests, rows.Err()
+}
+
+// RecalculateOneRepMax recomputes the estimated one-rep max of every set the profile logged with
+// the formula, which changes when the profile picks another one.
+func (r *setRepository) RecalculateOneRepMax(ctx context.Context, tx *sql.Tx, profileID int, formula string) error {
+	query := `
+		UPDATE sets s
+		INNER JOIN workouts w ON w.id = s.workout_id
+		SET s.estimated_one_rep_max_kg = ` + oneRepMaxExpression("s.weight_kg", "s.reps") + `
+		WHERE w.profile_id = ?
+	`
+
+	_, err := tx.ExecContext(ctx, query, formula, profileID)
+
+	return err
+}
+
+// GetExerciseSummariesByWorkoutIDs groups the sets of each workout by exercise, in the order
+// the exercises were first performed.
+func (r *setRepository) GetExerciseSummariesByWorkoutIDs(ctx context.Context, workoutIDs []int) (map[int][]model.WorkoutExerciseSummary, error) {
+	summaries := make(map[int][]model.WorkoutExerciseSummary, len(workoutIDs))
+	for _, workoutID := range workoutIDs {
+		summaries[workoutID] = []model.WorkoutExerciseSummary{}
+	}
+
+	if len(workoutIDs) == 0 {
+		return summaries, nil
+	}
+
+	placeholders, args := inClause(workoutIDs)
+	query := `
+		SELECT
+			s.workout_id, e.id, e.name, e.icon_name,
+			COUNT(*), SUM(s.reps), MAX(s.weight_kg)
+		FROM sets s
+		INNER JOIN exercises e ON e.id = s.exercise_id
+		WHERE s.workout_id IN (` + placeholders + `)
+		GROUP BY s.workout_id, e.id, e.name, e.icon_name
+		ORDER BY s.workout_id, MIN(s.id)
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var summary model.WorkoutExerciseSummary
+		if err := rows.Scan(
+			&summary.WorkoutID,
+			&summary.ExerciseID,
+			&summary.Name,
+			&summary.IconName,
+			&summary.SetCount,
+			&summary.TotalReps,
+			&summary.MaxWeightKg,
+		); err != nil {
+			return nil, err
+		}
+		summaries[summary.WorkoutID] = append(summaries[summary.WorkoutID], summary)
+	}
+
+	return summaries, rows.Err()
+}
+
+// GetTopE1RMs returns the exercises with the heaviest estimated one rep max in workouts started
+// within [from, to), along with that best, heaviest first.
+func (r *setRepository) GetTopE1RMs(ctx context.Context, profileID int, from time.Time, to time.Time, limit int) ([]model.ExerciseE1RM, error) {
+	query := `
+		SELECT e.id, e.name, MAX(s.estimated_one_rep_max_kg) AS best_e1rm_kg
+		FROM sets s
+		INNER JOIN workouts w ON w.id = s.workout_id
+		INNER JOIN exercises e ON e.id = s.exercise_id
+		WHERE w.profile_id = ? AND w.start_date >= ? AND w.start_date < ? AND s.estimated_one_rep_max_kg > 0
+		GROUP BY e.id, e.name
+		ORDER BY best_e1rm_kg DESC, e.id
+		LIMIT ?
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, profileID, from, to, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	bests := []model.ExerciseE1RM{}
+	for rows.Next() {
+		var best model.ExerciseE1RM
+		if err := rows.Scan(&best.ExerciseID, &best.Name, &best.E1RMKg); err != nil {
+			return nil, err
+		}
+		bests = append(bests, best)
+	}
+
+	return bests, rows.Err()
+}
@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/brightside-dev/ronin-fitness-be/database/client"
+)
+
+type WorkoutCommentRepository interface {
+	GetCountsByWorkoutIDs(ctx context.Context, workoutIDs []int, viewerProfileID int) (map[int]int, error)
+}
+
+type workoutCommentRepository struct {
+	db client.DatabaseService
+}
+
+func NewWorkoutCommentRepository(db client.DatabaseService) WorkoutCommentRepository {
+	return &workoutCommentRepository{db: db}
+}
+
+// GetCountsByWorkoutIDs returns the number of comments on each workout, leaving out comments of
+// profiles blocked by or blocking the viewer.
+func (r *workoutCommentRepository) GetCountsByWorkoutIDs(ctx context.Context, workoutIDs []int, viewerProfileID int) (map[int]int, error) {
+	counts := make(map[int]int, len(workoutIDs))
+	if len(workoutIDs) == 0 {
+		return counts, nil
+	}
+
+	placeholders, args := inClause(workoutIDs)
+	query := `
+		SELECT workout_id, COUNT(*)
+		FROM workout_comments
+		WHERE workout_id IN (` + placeholders + `)
+		AND ` + notBlockedCondition("profile_id") + `
+		GROUP BY workout_id
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, append(args, viewerProfileID, viewerProfileID)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var workoutID, count int
+		if err := rows.Scan(&workoutID, &count); err != nil {
+			return nil, err
+		}
+		counts[workoutID] = count
+	}
+
+	return counts, rows.Err()
+}
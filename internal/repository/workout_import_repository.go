@@ -0,0 +1,239 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/brightside-dev/ronin-fitness-be/database/client"
+	"github.com/brightside-dev/ronin-fitness-be/internal/model"
+)
+
+type WorkoutImportRepository interface {
+	GetByID(ctx context.Context, id int) (*model.WorkoutImport, error)
+	GetByProfileID(ctx context.Context, profileID int, beforeTime time.Time, beforeID int, limit int) ([]model.WorkoutImport, error)
+	Create(ctx context.Context, workoutImport *model.WorkoutImport) (*model.WorkoutImport, error)
+	UpdateProgress(ctx context.Context, tx *sql.Tx, workoutImport *model.WorkoutImport) error
+	Finish(ctx context.Context, workoutImport *model.WorkoutImport) error
+	CreateErrors(ctx context.Context, importID int, importErrors []model.WorkoutImportError) error
+	GetErrors(ctx context.Context, importID int) ([]model.WorkoutImportError, error)
+}
+
+type workoutImportRepository struct {
+	db client.DatabaseService
+}
+
+func NewWorkoutImportRepository(db client.DatabaseService) WorkoutImportRepository {
+	return &workoutImportRepository{db: db}
+}
+
+const workoutImportColumns = `
+	id, profile_id, source, status, file_name, file_hash, weight_unit, total_rows, imported_workouts,
+	imported_sets, skipped_workouts, created_exercises, error_count, failure_reason, created_at, completed_at
+`
+
+func scanWorkoutImport(scanner interface{ Scan(...interface{}) error }) (*model.WorkoutImport, error) {
+	var workoutImport model.WorkoutImport
+	var failureReason sql.NullString
+	var completedAt sql.NullTime
+	err := scanner.Scan(
+		&workoutImport.ID,
+		&workoutImport.ProfileID,
+		&workoutImport.Source,
+		&workoutImport.Status,
+		&workoutImport.FileName,
+		&workoutImport.FileHash,
+		&workoutImport.WeightUnit,
+		&workoutImport.TotalRows,
+		&workoutImport.ImportedWorkouts,
+		&workoutImport.ImportedSets,
+		&workoutImport.SkippedWorkouts,
+		&workoutImport.CreatedExercises,
+		&workoutImport.ErrorCount,
+		&failureReason,
+		&workoutImport.CreatedAt,
+		&completedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	workoutImport.FailureReason = failureReason.String
+	if completedAt.Valid {
+		workoutImport.CompletedAt = &completedAt.Time
+	}
+
+	return &workoutImport, nil
+}
+
+func (r *workoutImportRepository) GetByID(ctx context.Context, id int) (*model.WorkoutImport, error) {
+	row := r.db.QueryRowContext(ctx, `SELECT `+workoutImportColumns+` FROM workout_imports WHERE id = ?`, id)
+
+	workoutImport, err := scanWorkoutImport(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+
+	return workoutImport, err
+}
+
+// GetByProfileID returns the profile's imports, newest first. Pass a zero beforeTime for the first page.
+func (r *workoutImportRepository) GetByProfileID(
+	ctx context.Context,
+	profileID int,
+	beforeTime time.Time,
+	beforeID int,
+	limit int,
+) ([]model.WorkoutImport, error) {
+	query := `
+		SELECT ` + workoutImportColumns + `
+		FROM workout_imports
+		WHERE profile_id = ?
+		AND (? OR (created_at, id) < (?, ?))
+		ORDER BY created_at DESC, id DESC
+		LIMIT ?
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, profileID, beforeTime.IsZero(), beforeTime, beforeID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	workoutImports := []model.WorkoutImport{}
+	for rows.Next() {
+		workoutImport, err := scanWorkoutImport(rows)
+		if err != nil {
+			return nil, err
+		}
+		workoutImports = append(workoutImports, *workoutImport)
+	}
+
+	return workoutImports, rows.Err()
+}
+
+func (r *workoutImportRepository) Create(ctx context.Context, workoutImport *model.WorkoutImport) (*model.WorkoutImport, error) {
+	query := `
+		INSERT INTO workout_imports (profile_id, source, file_name, file_hash, weight_unit, total_rows)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`
+
+	res, err := r.db.ExecContext(
+		ctx, query,
+		workoutImport.ProfileID,
+		workoutImport.Source,
+		workoutImport.FileName,
+		workoutImport.FileHash,
+		workoutImport.WeightUnit,
+		workoutImport.TotalRows,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return nil, err
+	}
+
+	return r.GetByID(ctx, int(id))
+}
+
+// UpdateProgress saves the counters of the import in the transaction of the batch they describe,
+// so a failed import reports exactly what was committed.
+func (r *workoutImportRepository) UpdateProgress(ctx context.Context, tx *sql.Tx, workoutImport *model.WorkoutImport) error {
+	query := `
+		UPDATE workout_imports
+		SET imported_workouts = ?, imported_sets = ?, skipped_workouts = ?, created_exercises = ?
+		WHERE id = ?
+	`
+
+	_, err := tx.ExecContext(
+		ctx, query,
+		workoutImport.ImportedWorkouts,
+		workoutImport.ImportedSets,
+		workoutImport.SkippedWorkouts,
+		workoutImport.CreatedExercises,
+		workoutImport.ID,
+	)
+
+	return err
+}
+
+// Finish records the final status and counters of the import.
+func (r *workoutImportRepository) Finish(ctx context.Context, workoutImport *model.WorkoutImport) error {
+	query := `
+		UPDATE workout_imports
+		SET
+			status = ?, imported_workouts = ?, imported_sets = ?, skipped_workouts = ?, created_exercises = ?,
+			error_count = ?, failure_reason = NULLIF(?, ''), completed_at = NOW()
+		WHERE id = ?
+	`
+
+	_, err := r.db.ExecContext(
+		ctx, query,
+		workoutImport.Status,
+		workoutImport.ImportedWorkouts,
+		workoutImport.ImportedSets,
+		workoutImport.SkippedWorkouts,
+		workoutImport.CreatedExercises,
+		workoutImport.ErrorCount,
+		workoutImport.FailureReason,
+		workoutImport.ID,
+	)
+
+	return err
+}
+
+func (r *workoutImportRepository) CreateErrors(ctx context.Context, importID int, importErrors []model.WorkoutImportError) error {
+	if len(importErrors) == 0 {
+		return nil
+	}
+
+	args := make([]interface{}, 0, len(importErrors)*4)
+	for _, importError := range importErrors {
+		args = append(args, importID, importError.LineNumber, importError.Message, importError.RawRow)
+	}
+
+	query := `
+		INSERT INTO workout_import_errors (import_id, line_number, message, raw_row)
+		VALUES ` + strings.TrimSuffix(strings.Repeat("(?, ?, ?, ?), ", len(importErrors)), ", ")
+
+	_, err := r.db.ExecContext(ctx, query, args...)
+
+	return err
+}
+
+// GetErrors returns the rows of the import that failed, in file order.
+func (r *workoutImportRepository) GetErrors(ctx context.Context, importID int) ([]model.WorkoutImportError, error) {
+	query := `
+		SELECT id, import_id, line_number, message, raw_row
+		FROM workout_import_errors
+		WHERE import_id = ?
+		ORDER BY line_number, id
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, importID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	importErrors := []model.WorkoutImportError{}
+	for rows.Next() {
+		var importError model.WorkoutImportError
+		if err := rows.Scan(
+			&importError.ID,
+			&importError.ImportID,
+			&importError.LineNumber,
+			&importError.Message,
+			&importError.RawRow,
+		); err != nil {
+			return nil, err
+		}
+		importErrors = append(importErrors, importError)
+	}
+
+	return importErrors, rows.Err()
+}
@@ -0,0 +1,165 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/brightside-dev/ronin-fitness-be/database/client"
+	"github.com/brightside-dev/ronin-fitness-be/internal/model"
+)
+
+type WorkoutPhotoRepository interface {
+	GetByID(ctx context.Context, id int) (*model.WorkoutPhoto, error)
+	GetByWorkoutID(ctx context.Context, workoutID int) ([]model.WorkoutPhoto, error)
+	GetCountsByWorkoutIDs(ctx context.Context, workoutIDs []int) (map[int]int, error)
+	CountByWorkoutIDForUpdate(ctx context.Context, tx *sql.Tx, workoutID int) (int, error)
+	Create(ctx context.Context, tx *sql.Tx, photo *model.WorkoutPhoto) (*model.WorkoutPhoto, error)
+	Delete(ctx context.Context, id int) error
+}
+
+type workoutPhotoRepository struct {
+	db client.DatabaseService
+}
+
+func NewWorkoutPhotoRepository(db client.DatabaseService) WorkoutPhotoRepository {
+	return &workoutPhotoRepository{db: db}
+}
+
+const workoutPhotoColumns = `id, workout_id, storage_key, thumbnail_key, content_type, width, height, size_bytes, created_at`
+
+func scanWorkoutPhoto(scanner interface{ Scan(...interface{}) error }) (*model.WorkoutPhoto, error) {
+	var photo model.WorkoutPhoto
+	err := scanner.Scan(
+		&photo.ID,
+		&photo.WorkoutID,
+		&photo.StorageKey,
+		&photo.ThumbnailKey,
+		&photo.ContentType,
+		&photo.Width,
+		&photo.Height,
+		&photo.SizeBytes,
+		&photo.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &photo, nil
+}
+
+func (r *workoutPhotoRepository) GetByID(ctx context.Context, id int) (*model.WorkoutPhoto, error) {
+	row := r.db.QueryRowContext(ctx, `SELECT `+workoutPhotoColumns+` FROM workout_photos WHERE id = ?`, id)
+
+	photo, err := scanWorkoutPhoto(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+
+	return photo, err
+}
+
+// GetByWorkoutID returns the photos of a workout in the order they were attached.
+func (r *workoutPhotoRepository) GetByWorkoutID(ctx context.Context, workoutID int) ([]model.WorkoutPhoto, error) {
+	query := `SELECT ` + workoutPhotoColumns + ` FROM workout_photos WHERE workout_id = ? ORDER BY id`
+
+	rows, err := r.db.QueryContext(ctx, query, workoutID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	photos := []model.WorkoutPhoto{}
+	for rows.Next() {
+		photo, err := scanWorkoutPhoto(rows)
+		if err != nil {
+			return nil, err
+		}
+		photos = append(photos, *photo)
+	}
+
+	return photos, rows.Err()
+}
+
+func (r *workoutPhotoRepository) GetCountsByWorkoutIDs(ctx context.Context, workoutIDs []int) (map[int]int, error) {
+	counts := make(map[int]int, len(workoutIDs))
+	if len(workoutIDs) == 0 {
+		return counts, nil
+	}
+
+	placeholders, args := inClause(workoutIDs)
+	query := `
+		SELECT workout_id, COUNT(*)
+		FROM workout_photos
+		WHERE workout_id IN (` + placeholders + `)
+		GROUP BY workout_id
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var workoutID, count int
+		if err := rows.Scan(&workoutID, &count); err != nil {
+			return nil, err
+		}
+		counts[workoutID] = count
+	}
+
+	return counts, rows.Err()
+}
+
+// CountByWorkoutIDForUpdate counts the photos of a workout after locking the workout, so uploads to
+// the same workout wait for each other until the transaction ends.
+func (r *workoutPhotoRepository) CountByWorkoutIDForUpdate(ctx context.Context, tx *sql.Tx, workoutID int) (int, error) {
+	var id int
+	err := tx.QueryRowContext(ctx, `SELECT id FROM workouts WHERE id = ? FOR UPDATE`, workoutID).Scan(&id)
+	if err != nil {
+		return 0, err
+	}
+
+	var count int
+	err = tx.QueryRowContext(ctx, `SELECT COUNT(*) FROM workout_photos WHERE workout_id = ?`, workoutID).Scan(&count)
+
+	return count, err
+}
+
+func (r *workoutPhotoRepository) Create(ctx context.Context, tx *sql.Tx, photo *model.WorkoutPhoto) (*model.WorkoutPhoto, error) {
+	query := `
+		INSERT INTO workout_photos
+			(workout_id, storage_key, thumbnail_key, content_type, width, height, size_bytes)
+		VALUES
+			(?, ?, ?, ?, ?, ?, ?)
+	`
+
+	res, err := tx.ExecContext(
+		ctx, query,
+		photo.WorkoutID,
+		photo.StorageKey,
+		photo.ThumbnailKey,
+		photo.ContentType,
+		photo.Width,
+		photo.Height,
+		photo.SizeBytes,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return nil, err
+	}
+
+	row := tx.QueryRowContext(ctx, `SELECT `+workoutPhotoColumns+` FROM workout_photos WHERE id = ?`, id)
+
+	return scanWorkoutPhoto(row)
+}
+
+func (r *workoutPhotoRepository) Delete(ctx context.Context, id int) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM workout_photos WHERE id = ?`, id)
+
+	return err
+}
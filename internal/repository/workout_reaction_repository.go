@@ -0,0 +1,191 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/brightside-dev/ronin-fitness-be/database/client"
+	"github.com/brightside-dev/ronin-fitness-be/internal/model"
+)
+
+type WorkoutReactionRepository interface {
+	Create(ctx context.Context, workoutReaction *model.WorkoutReaction) error
+	Delete(ctx context.Context, workoutReaction *model.WorkoutReaction) error
+	GetCountsByWorkoutIDs(ctx context.Context, workoutIDs []int, viewerProfileID int) (map[int]map[string]int, error)
+	GetReactionsByProfileID(ctx context.Context, workoutIDs []int, profileID int) (map[int][]string, error)
+	GetByWorkoutID(ctx context.Context, workoutID int, viewerProfileID int, reaction string, beforeTime time.Time, beforeID int, limit int) ([]model.WorkoutReactionWithProfile, error)
+}
+
+type workoutReactionRepository struct {
+	db client.DatabaseService
+}
+
+func NewWorkoutReactionRepository(db client.DatabaseService) WorkoutReactionRepository {
+	return &workoutReactionRepository{db: db}
+}
+
+// Create adds the reaction. Reacting twice with the same type is a no-op thanks to the
+// unique (workout_id, profile_id, reaction) index.
+func (r *workoutReactionRepository) Create(ctx context.Context, workoutReaction *model.WorkoutReaction) error {
+	query := `
+		INSERT INTO workout_reactions (workout_id, profile_id, reaction)
+		VALUES (?, ?, ?)
+		ON DUPLICATE KEY UPDATE id = id
+	`
+
+	_, err := r.db.ExecContext(ctx, query, workoutReaction.WorkoutID, workoutReaction.ProfileID, workoutReaction.Reaction)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *workoutReactionRepository) Delete(ctx context.Context, workoutReaction *model.WorkoutReaction) error {
+	query := `DELETE FROM workout_reactions WHERE workout_id = ? AND profile_id = ? AND reaction = ?`
+
+	_, err := r.db.ExecContext(ctx, query, workoutReaction.WorkoutID, workoutReaction.ProfileID, workoutReaction.Reaction)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// GetCountsByWorkoutIDs returns the number of reactions of each type for every given workout, leaving
+// out reactions of profiles blocked by or blocking the viewer. Every reaction type is present in the
+// result, including those with no reactions.
+func (r *workoutReactionRepository) GetCountsByWorkoutIDs(ctx context.Context, workoutIDs []int, viewerProfileID int) (map[int]map[string]int, error) {
+	counts := make(map[int]map[string]int, len(workoutIDs))
+	for _, workoutID := range workoutIDs {
+		counts[workoutID] = make(map[string]int, len(model.WorkoutReactionTypes))
+		for _, reaction := range model.WorkoutReactionTypes {
+			counts[workoutID][reaction] = 0
+		}
+	}
+
+	if len(workoutIDs) == 0 {
+		return counts, nil
+	}
+
+	placeholders, args := inClause(workoutIDs)
+	query := `
+		SELECT workout_id, reaction, COUNT(*)
+		FROM workout_reactions
+		WHERE workout_id IN (` + placeholders + `)
+		AND ` + notBlockedCondition("profile_id") + `
+		GROUP BY workout_id, reaction
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, append(args, viewerProfileID, viewerProfileID)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var workoutID, count int
+		var reaction string
+		if err := rows.Scan(&workoutID, &reaction, &count); err != nil {
+			return nil, err
+		}
+		counts[workoutID][reaction] = count
+	}
+
+	return counts, rows.Err()
+}
+
+// GetReactionsByProfileID returns the reactions the profile has left on each of the given workouts.
+func (r *workoutReactionRepository) GetReactionsByProfileID(ctx context.Context, workoutIDs []int, profileID int) (map[int][]string, error) {
+	reactions := make(map[int][]string, len(workoutIDs))
+	for _, workoutID := range workoutIDs {
+		reactions[workoutID] = []string{}
+	}
+
+	if len(workoutIDs) == 0 {
+		return reactions, nil
+	}
+
+	placeholders, args := inClause(workoutIDs)
+	query := `
+		SELECT workout_id, reaction
+		FROM workout_reactions
+		WHERE profile_id = ? AND workout_id IN (` + placeholders + `)
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, append([]interface{}{profileID}, args...)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var workoutID int
+		var reaction string
+		if err := rows.Scan(&workoutID, &reaction); err != nil {
+			return nil, err
+		}
+		reactions[workoutID] = append(reactions[workoutID], reaction)
+	}
+
+	return reactions, rows.Err()
+}
+
+// GetByWorkoutID returns reactions newest first, leaving out those of profiles blocked by or blocking
+// the viewer. Pass a zero beforeTime for the first page. An empty reaction returns every type.
+func (r *workoutReactionRepository) GetByWorkoutID(
+	ctx context.Context,
+	workoutID int,
+	viewerProfileID int,
+	reaction string,
+	beforeTime time.Time,
+	beforeID int,
+	limit int,
+) ([]model.WorkoutReactionWithProfile, error) {
+	query := `
+		SELECT
+			wr.id, wr.workout_id, wr.profile_id, wr.reaction, wr.created_at, wr.updated_at,
+			p.display_name, p.avatar_version
+		FROM workout_reactions wr
+		INNER JOIN profiles p ON p.id = wr.profile_id
+		WHERE wr.workout_id = ?
+		AND ` + notBlockedCondition("wr.profile_id") + `
+		AND (? = '' OR wr.reaction = ?)
+		AND (? OR (wr.created_at, wr.id) < (?, ?))
+		ORDER BY wr.created_at DESC, wr.id DESC
+		LIMIT ?
+	`
+
+	rows, err := r.db.QueryContext(
+		ctx, query,
+		workoutID,
+		viewerProfileID, viewerProfileID,
+		reaction, reaction,
+		beforeTime.IsZero(), beforeTime, beforeID,
+		limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	reactions := []model.WorkoutReactionWithProfile{}
+	for rows.Next() {
+		var wr model.WorkoutReactionWithProfile
+		if err := rows.Scan(
+			&wr.ID,
+			&wr.WorkoutID,
+			&wr.ProfileID,
+			&wr.Reaction,
+			&wr.CreatedAt,
+			&wr.UpdatedAt,
+			&wr.DisplayName,
+			&wr.AvatarVersion,
+		); err != nil {
+			return nil, err
+		}
+		reactions = append(reactions, wr)
+	}
+
+	return reactions, rows.Err()
+}
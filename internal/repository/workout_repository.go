@@ -0,0 +1,300 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/brightside-dev/ronin-fitness-be/database/client"
+	"github.com/brightside-dev/ronin-fitness-be/internal/model"
+)
+
+type WorkoutRepository interface {
+	GetByID(ctx context.Context, id int) (*model.Workout, error)
+	Create(ctx context.Context, tx *sql.Tx, workout *model.Workout) (*model.Workout, error)
+	GetFeed(ctx context.Context, followerProfileID int, beforeTime time.Time, beforeID int, limit int) ([]model.WorkoutWithProfile, error)
+	ExportByProfileID(ctx context.Context, profileID int, from time.Time, to time.Time, fn func(row *model.WorkoutExportRow) error) error
+	GetExistingImportKeys(ctx context.Context, profileID int, importKeys []string) (map[string]bool, error)
+	UpdateVisibility(ctx context.Context, id int, visibility string) error
+	Delete(ctx context.Context, tx *sql.Tx, id int) error
+}
+
+type workoutRepository struct {
+	db client.DatabaseService
+}
+
+func NewWorkoutRepository(db client.DatabaseService) WorkoutRepository {
+	return &workoutRepository{db: db}
+}
+
+func (r *workoutRepository) GetByID(ctx context.Context, id int) (*model.Workout, error) {
+	query := `
+		SELECT
+			id, profile_id, name, description, visibility, mental_energy_level, physical_energy_level,
+			start_date, end_date, created_at, updated_at
+		FROM workouts
+		WHERE id = ?
+	`
+	row := r.db.QueryRowContext(ctx, query, id)
+
+	var workout model.Workout
+	var description sql.NullString
+	err := row.Scan(
+		&workout.ID,
+		&workout.ProfileID,
+		&workout.Name,
+		&description,
+		&workout.Visibility,
+		&workout.MentalEnergyLevel,
+		&workout.PhysicalEnergyLevel,
+		&workout.StartDate,
+		&workout.EndDate,
+		&workout.CreatedAt,
+		&workout.UpdatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	workout.Description = description.String
+
+	return &workout, nil
+}
+
+func (r *workoutRepository) Create(ctx context.Context, tx *sql.Tx, workout *model.Workout) (*model.Workout, error) {
+	query := `
+		INSERT INTO workouts
+			(profile_id, name, description, visibility, mental_energy_level, physical_energy_level, start_date, end_date, import_key)
+		VALUES
+			(?, ?, NULLIF(?, ''), ?, ?, ?, ?, ?, ?)
+	`
+
+	res, err := tx.ExecContext(
+		ctx, query,
+		workout.ProfileID,
+		workout.Name,
+		workout.Description,
+		workout.Visibility,
+		workout.MentalEnergyLevel,
+		workout.PhysicalEnergyLevel,
+		workout.StartDate,
+		workout.EndDate,
+		workout.ImportKey,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return nil, err
+	}
+
+	workout.ID = int(id)
+
+	return workout, nil
+}
+
+// GetFeed returns workouts of the profiles followed by followerProfileID, newest first, leaving
+// out private ones and those of blocked or muted profiles. Pass a zero beforeTime for the first page.
+func (r *workoutRepository) GetFeed(
+	ctx context.Context,
+	followerProfileID int,
+	beforeTime time.Time,
+	beforeID int,
+	limit int,
+) ([]model.WorkoutWithProfile, error) {
+	args := []interface{}{followerProfileID, followerProfileID, followerProfileID, followerProfileID}
+
+	// The first page has no cursor condition, later pages spell the row comparison out so it can seek
+	// on idx_workouts_profile_start_date
+	cursorCondition := ""
+	if !beforeTime.IsZero() {
+		cursorCondition = `AND (w.start_date < ? OR (w.start_date = ? AND w.id < ?))`
+		args = append(args, beforeTime, beforeTime, beforeID)
+	}
+	args = append(args, limit)
+
+	query := `
+		SELECT
+			w.id, w.profile_id, w.name, w.description, w.visibility, w.mental_energy_level, w.physical_energy_level,
+			w.start_date, w.end_date, w.created_at, w.updated_at,
+			p.display_name, p.avatar_version
+		FROM profile_follows pf
+		INNER JOIN workouts w ON w.profile_id = pf.profile_id
+		INNER JOIN profiles p ON p.id = w.profile_id
+		WHERE pf.follower_profile_id = ? AND w.visibility <> 'private'
+		AND ` + notBlockedCondition("w.profile_id") + `
+		AND ` + notMutedCondition("w.profile_id") + `
+		` + cursorCondition + `
+		ORDER BY w.start_date DESC, w.id DESC
+		LIMIT ?
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	workouts := []model.WorkoutWithProfile{}
+	for rows.Next() {
+		var workout model.WorkoutWithProfile
+		var description sql.NullString
+		if err := rows.Scan(
+			&workout.ID,
+			&workout.ProfileID,
+			&workout.Name,
+			&description,
+			&workout.Visibility,
+			&workout.MentalEnergyLevel,
+			&workout.PhysicalEnergyLevel,
+			&workout.StartDate,
+			&workout.EndDate,
+			&workout.CreatedAt,
+			&workout.UpdatedAt,
+			&workout.DisplayName,
+			&workout.AvatarVersion,
+		); err != nil {
+			return nil, err
+		}
+		workout.Description = description.String
+		workouts = append(workouts, workout)
+	}
+
+	return workouts, rows.Err()
+}
+
+func (r *workoutRepository) UpdateVisibility(ctx context.Context, id int, visibility string) error {
+	_, err := r.db.ExecContext(ctx, `UPDATE workouts SET visibility = ? WHERE id = ?`, visibility, id)
+
+	return err
+}
+
+// Delete removes the workout along with its sets, reactions, comments, records and photo rows.
+func (r *workoutRepository) Delete(ctx context.Context, tx *sql.Tx, id int) error {
+	_, err := tx.ExecContext(ctx, `DELETE FROM workouts WHERE id = ?`, id)
+
+	return err
+}
+
+// GetExistingImportKeys returns which of the import keys the profile already has a workout for.
+func (r *workoutRepository) GetExistingImportKeys(ctx context.Context, profileID int, importKeys []string) (map[string]bool, error) {
+	existing := map[string]bool{}
+	if len(importKeys) == 0 {
+		return existing, nil
+	}
+
+	args := []interface{}{profileID}
+	for _, importKey := range importKeys {
+		args = append(args, importKey)
+	}
+
+	query := `
+		SELECT import_key
+		FROM workouts
+		WHERE profile_id = ? AND import_key IN (` + strings.TrimSuffix(strings.Repeat("?, ", len(importKeys)), ", ") + `)
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var importKey string
+		if err := rows.Scan(&importKey); err != nil {
+			return nil, err
+		}
+		existing[importKey] = true
+	}
+
+	return existing, rows.Err()
+}
+
+// ExportByProfileID passes the profile's workouts that started in [from, to) to fn one set at a time,
+// oldest first with the sets of a workout in the order they were logged. Rows are read as they are
+// streamed from the database so exports of any size use constant memory.
+func (r *workoutRepository) ExportByProfileID(
+	ctx context.Context,
+	profileID int,
+	from time.Time,
+	to time.Time,
+	fn func(row *model.WorkoutExportRow) error,
+) error {
+	query := `
+		SELECT
+			w.id, w.profile_id, w.name, w.description, w.mental_energy_level, w.physical_energy_level,
+			w.start_date, w.end_date, w.created_at, w.updated_at,
+			s.id, s.exercise_id, s.set_number, s.duration, s.weight_kg, s.reps, s.estimated_one_rep_max_kg,
+			e.name, e.muscle_group
+		FROM workouts w
+		LEFT JOIN sets s ON s.workout_id = w.id
+		LEFT JOIN exercises e ON e.id = s.exercise_id
+		WHERE w.profile_id = ? AND w.start_date >= ? AND w.start_date < ?
+		ORDER BY w.start_date, w.id, s.id
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, profileID, from, to)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var row model.WorkoutExportRow
+		var description, exerciseName, muscleGroup sql.NullString
+		var setID, exerciseID, setNumber, duration, reps sql.NullInt64
+		var weightKg, e1rmKg sql.NullFloat64
+		if err := rows.Scan(
+			&row.ID,
+			&row.ProfileID,
+			&row.Name,
+			&description,
+			&row.MentalEnergyLevel,
+			&row.PhysicalEnergyLevel,
+			&row.StartDate,
+			&row.EndDate,
+			&row.CreatedAt,
+			&row.UpdatedAt,
+			&setID,
+			&exerciseID,
+			&setNumber,
+			&duration,
+			&weightKg,
+			&reps,
+			&e1rmKg,
+			&exerciseName,
+			&muscleGroup,
+		); err != nil {
+			return err
+		}
+
+		row.Description = description.String
+		if setID.Valid {
+			row.Set = &model.Set{
+				ID:         int(setID.Int64),
+				WorkoutID:  row.ID,
+				ExerciseID: int(exerciseID.Int64),
+				SetNumber:  int(setNumber.Int64),
+				Duration:   int(duration.Int64),
+				WeightKg:   weightKg.Float64,
+				Reps:       int(reps.Int64),
+				E1RMKg:     e1rmKg.Float64,
+			}
+			row.ExerciseName = exerciseName.String
+			row.MuscleGroup = muscleGroup.String
+		}
+
+		err = fn(&row)
+		if err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
@@ -0,0 +1,319 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/brightside-dev/ronin-fitness-be/database/client"
+	"github.com/brightside-dev/ronin-fitness-be/internal/model"
+)
+
+type WorkoutSessionRepository interface {
+	GetByID(ctx context.Context, id int) (*model.WorkoutSession, error)
+	GetByIDForUpdate(ctx context.Context, tx *sql.Tx, id int) (*model.WorkoutSession, error)
+	GetOpenByProfileID(ctx context.Context, profileID int) (*model.WorkoutSession, error)
+	GetInactiveSince(ctx context.Context, before time.Time, limit int) ([]model.WorkoutSession, error)
+	Create(ctx context.Context, session *model.WorkoutSession) (*model.WorkoutSession, error)
+	Pause(ctx context.Context, tx *sql.Tx, id int, at time.Time) error
+	Resume(ctx context.Context, tx *sql.Tx, id int, pausedSeconds int, at time.Time) error
+	Touch(ctx context.Context, tx *sql.Tx, id int, at time.Time) error
+	Close(ctx context.Context, tx *sql.Tx, id int, status string, at time.Time, workoutID *int) error
+	GetSets(ctx context.Context, sessionID int) ([]model.WorkoutSessionSet, error)
+	GetSetByID(ctx context.Context, tx *sql.Tx, sessionID int, id int) (*model.WorkoutSessionSet, error)
+	GetNextSetNumber(ctx context.Context, tx *sql.Tx, sessionID int, exerciseID int) (int, error)
+	CreateSet(ctx context.Context, tx *sql.Tx, set *model.WorkoutSessionSet) (*model.WorkoutSessionSet, error)
+	UpdateSet(ctx context.Context, tx *sql.Tx, set *model.WorkoutSessionSet) error
+	DeleteSet(ctx context.Context, tx *sql.Tx, sessionID int, id int) error
+}
+
+type workoutSessionRepository struct {
+	db client.DatabaseService
+}
+
+func NewWorkoutSessionRepository(db client.DatabaseService) WorkoutSessionRepository {
+	return &workoutSessionRepository{db: db}
+}
+
+const workoutSessionColumns = `
+	id, profile_id, name, description, program_enrollment_id, status, started_at, paused_at, paused_seconds,
+	last_activity_at, finished_at, workout_id, created_at, updated_at
+`
+
+func scanWorkoutSession(scanner interface{ Scan(...interface{}) error }) (*model.WorkoutSession, error) {
+	var session model.WorkoutSession
+	var description sql.NullString
+	var programEnrollmentID, workoutID sql.NullInt64
+	var pausedAt, finishedAt sql.NullTime
+	err := scanner.Scan(
+		&session.ID,
+		&session.ProfileID,
+		&session.Name,
+		&description,
+		&programEnrollmentID,
+		&session.Status,
+		&session.StartedAt,
+		&pausedAt,
+		&session.PausedSeconds,
+		&session.LastActivityAt,
+		&finishedAt,
+		&workoutID,
+		&session.CreatedAt,
+		&session.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	session.Description = description.String
+	if programEnrollmentID.Valid {
+		id := int(programEnrollmentID.Int64)
+		session.ProgramEnrollmentID = &id
+	}
+	if pausedAt.Valid {
+		session.PausedAt = &pausedAt.Time
+	}
+	if finishedAt.Valid {
+		session.FinishedAt = &finishedAt.Time
+	}
+	if workoutID.Valid {
+		id := int(workoutID.Int64)
+		session.WorkoutID = &id
+	}
+
+	return &session, nil
+}
+
+func (r *workoutSessionRepository) GetByID(ctx context.Context, id int) (*model.WorkoutSession, error) {
+	row := r.db.QueryRowContext(ctx, `SELECT `+workoutSessionColumns+` FROM workout_sessions WHERE id = ?`, id)
+
+	session, err := scanWorkoutSession(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+
+	return session, err
+}
+
+// GetByIDForUpdate locks the session row until the transaction ends so concurrent requests from
+// several devices apply one after the other.
+func (r *workoutSessionRepository) GetByIDForUpdate(ctx context.Context, tx *sql.Tx, id int) (*model.WorkoutSession, error) {
+	row := tx.QueryRowContext(ctx, `SELECT `+workoutSessionColumns+` FROM workout_sessions WHERE id = ? FOR UPDATE`, id)
+
+	session, err := scanWorkoutSession(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+
+	return session, err
+}
+
+// GetOpenByProfileID returns the profile's active or paused session, of which there is at most one.
+func (r *workoutSessionRepository) GetOpenByProfileID(ctx context.Context, profileID int) (*model.WorkoutSession, error) {
+	row := r.db.QueryRowContext(ctx, `SELECT `+workoutSessionColumns+` FROM workout_sessions WHERE open_profile_id = ?`, profileID)
+
+	session, err := scanWorkoutSession(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+
+	return session, err
+}
+
+// GetInactiveSince returns open sessions with no activity since before, oldest first.
+func (r *workoutSessionRepository) GetInactiveSince(ctx context.Context, before time.Time, limit int) ([]model.WorkoutSession, error) {
+	query := `
+		SELECT ` + workoutSessionColumns + `
+		FROM workout_sessions
+		WHERE status IN ('active', 'paused') AND last_activity_at < ?
+		ORDER BY last_activity_at, id
+		LIMIT ?
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, before, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	sessions := []model.WorkoutSession{}
+	for rows.Next() {
+		session, err := scanWorkoutSession(rows)
+		if err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, *session)
+	}
+
+	return sessions, rows.Err()
+}
+
+func (r *workoutSessionRepository) Create(ctx context.Context, session *model.WorkoutSession) (*model.WorkoutSession, error) {
+	query := `
+		INSERT INTO workout_sessions
+			(profile_id, name, description, program_enrollment_id, started_at, last_activity_at)
+		VALUES
+			(?, ?, ?, ?, ?, ?)
+	`
+
+	res, err := r.db.ExecContext(
+		ctx, query,
+		session.ProfileID,
+		session.Name,
+		session.Description,
+		session.ProgramEnrollmentID,
+		session.StartedAt,
+		session.LastActivityAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return nil, err
+	}
+
+	return r.GetByID(ctx, int(id))
+}
+
+func (r *workoutSessionRepository) Pause(ctx context.Context, tx *sql.Tx, id int, at time.Time) error {
+	query := `UPDATE workout_sessions SET status = 'paused', paused_at = ?, last_activity_at = ? WHERE id = ? AND status = 'active'`
+
+	_, err := tx.ExecContext(ctx, query, at, at, id)
+
+	return err
+}
+
+// Resume ends the current pause, pausedSeconds being the new total of all pauses.
+func (r *workoutSessionRepository) Resume(ctx context.Context, tx *sql.Tx, id int, pausedSeconds int, at time.Time) error {
+	query := `
+		UPDATE workout_sessions
+		SET status = 'active', paused_at = NULL, paused_seconds = ?, last_activity_at = ?
+		WHERE id = ? AND status = 'paused'
+	`
+
+	_, err := tx.ExecContext(ctx, query, pausedSeconds, at, id)
+
+	return err
+}
+
+// Touch records activity on the session, which keeps it from being closed as abandoned.
+func (r *workoutSessionRepository) Touch(ctx context.Context, tx *sql.Tx, id int, at time.Time) error {
+	_, err := tx.ExecContext(ctx, `UPDATE workout_sessions SET last_activity_at = ? WHERE id = ?`, at, id)
+
+	return err
+}
+
+// Close finishes or abandons the session; workoutID is the workout it was saved as, if any.
+func (r *workoutSessionRepository) Close(ctx context.Context, tx *sql.Tx, id int, status string, at time.Time, workoutID *int) error {
+	query := `UPDATE workout_sessions SET status = ?, finished_at = ?, workout_id = ? WHERE id = ?`
+
+	_, err := tx.ExecContext(ctx, query, status, at, workoutID, id)
+
+	return err
+}
+
+const workoutSessionSetColumns = `id, session_id, exercise_id, set_number, duration, weight_kg, reps, created_at, updated_at`
+
+func scanWorkoutSessionSet(scanner interface{ Scan(...interface{}) error }) (*model.WorkoutSessionSet, error) {
+	var set model.WorkoutSessionSet
+	err := scanner.Scan(
+		&set.ID,
+		&set.SessionID,
+		&set.ExerciseID,
+		&set.SetNumber,
+		&set.Duration,
+		&set.WeightKg,
+		&set.Reps,
+		&set.CreatedAt,
+		&set.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &set, nil
+}
+
+// GetSets returns the sets of a session in the order they were logged.
+func (r *workoutSessionRepository) GetSets(ctx context.Context, sessionID int) ([]model.WorkoutSessionSet, error) {
+	query := `SELECT ` + workoutSessionSetColumns + ` FROM workout_session_sets WHERE session_id = ? ORDER BY id`
+
+	rows, err := r.db.QueryContext(ctx, query, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	sets := []model.WorkoutSessionSet{}
+	for rows.Next() {
+		set, err := scanWorkoutSessionSet(rows)
+		if err != nil {
+			return nil, err
+		}
+		sets = append(sets, *set)
+	}
+
+	return sets, rows.Err()
+}
+
+func (r *workoutSessionRepository) GetSetByID(ctx context.Context, tx *sql.Tx, sessionID int, id int) (*model.WorkoutSessionSet, error) {
+	query := `SELECT ` + workoutSessionSetColumns + ` FROM workout_session_sets WHERE id = ? AND session_id = ?`
+
+	set, err := scanWorkoutSessionSet(tx.QueryRowContext(ctx, query, id, sessionID))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+
+	return set, err
+}
+
+// GetNextSetNumber returns the number following the last set of the exercise in the session.
+func (r *workoutSessionRepository) GetNextSetNumber(ctx context.Context, tx *sql.Tx, sessionID int, exerciseID int) (int, error) {
+	query := `SELECT COALESCE(MAX(set_number), 0) + 1 FROM workout_session_sets WHERE session_id = ? AND exercise_id = ?`
+
+	var setNumber int
+	err := tx.QueryRowContext(ctx, query, sessionID, exerciseID).Scan(&setNumber)
+
+	return setNumber, err
+}
+
+func (r *workoutSessionRepository) CreateSet(ctx context.Context, tx *sql.Tx, set *model.WorkoutSessionSet) (*model.WorkoutSessionSet, error) {
+	query := `
+		INSERT INTO workout_session_sets
+			(session_id, exercise_id, set_number, duration, weight_kg, reps)
+		VALUES
+			(?, ?, ?, ?, ?, ?)
+	`
+
+	res, err := tx.ExecContext(ctx, query, set.SessionID, set.ExerciseID, set.SetNumber, set.Duration, set.WeightKg, set.Reps)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return nil, err
+	}
+
+	return r.GetSetByID(ctx, tx, set.SessionID, int(id))
+}
+
+func (r *workoutSessionRepository) UpdateSet(ctx context.Context, tx *sql.Tx, set *model.WorkoutSessionSet) error {
+	query := `
+		UPDATE workout_session_sets
+		SET exercise_id = ?, set_number = ?, duration = ?, weight_kg = ?, reps = ?
+		WHERE id = ? AND session_id = ?
+	`
+
+	_, err := tx.ExecContext(ctx, query, set.ExerciseID, set.SetNumber, set.Duration, set.WeightKg, set.Reps, set.ID, set.SessionID)
+
+	return err
+}
+
+func (r *workoutSessionRepository) DeleteSet(ctx context.Context, tx *sql.Tx, sessionID int, id int) error {
+	_, err := tx.ExecContext(ctx, `DELETE FROM workout_session_sets WHERE id = ? AND session_id = ?`, id, sessionID)
+
+	return err
+}
@@ -0,0 +1,230 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/brightside-dev/ronin-fitness-be/database/client"
+	"github.com/brightside-dev/ronin-fitness-be/internal/model"
+)
+
+type WorkoutTemplateRepository interface {
+	GetByID(ctx context.Context, id int) (*model.WorkoutTemplate, error)
+	GetByShareToken(ctx context.Context, shareToken string) (*model.WorkoutTemplate, error)
+	GetByProfileID(ctx context.Context, profileID int) ([]model.WorkoutTemplate, error)
+	Create(ctx context.Context, tx *sql.Tx, template *model.WorkoutTemplate) (*model.WorkoutTemplate, error)
+	Update(ctx context.Context, tx *sql.Tx, template *model.WorkoutTemplate) error
+	UpdateShareToken(ctx context.Context, id int, shareToken *string) error
+	Delete(ctx context.Context, id int) error
+	IsUsedByProgram(ctx context.Context, id int) (bool, error)
+	ReplaceSets(ctx context.Context, tx *sql.Tx, templateID int, sets []model.WorkoutTemplateSet) error
+	GetSetsByTemplateIDs(ctx context.Context, templateIDs []int) (map[int][]model.WorkoutTemplateSet, error)
+}
+
+type workoutTemplateRepository struct {
+	db client.DatabaseService
+}
+
+func NewWorkoutTemplateRepository(db client.DatabaseService) WorkoutTemplateRepository {
+	return &workoutTemplateRepository{db: db}
+}
+
+const workoutTemplateColumns = `id, profile_id, name, description, share_token, source_template_id, created_at, updated_at`
+
+func scanWorkoutTemplate(scanner interface{ Scan(...interface{}) error }) (*model.WorkoutTemplate, error) {
+	var template model.WorkoutTemplate
+	var description, shareToken sql.NullString
+	var sourceTemplateID sql.NullInt64
+	err := scanner.Scan(
+		&template.ID,
+		&template.ProfileID,
+		&template.Name,
+		&description,
+		&shareToken,
+		&sourceTemplateID,
+		&template.CreatedAt,
+		&template.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	template.Description = description.String
+	if shareToken.Valid {
+		template.ShareToken = &shareToken.String
+	}
+	if sourceTemplateID.Valid {
+		id := int(sourceTemplateID.Int64)
+		template.SourceTemplateID = &id
+	}
+
+	return &template, nil
+}
+
+func (r *workoutTemplateRepository) GetByID(ctx context.Context, id int) (*model.WorkoutTemplate, error) {
+	row := r.db.QueryRowContext(ctx, `SELECT `+workoutTemplateColumns+` FROM workout_templates WHERE id = ?`, id)
+
+	template, err := scanWorkoutTemplate(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+
+	return template, err
+}
+
+func (r *workoutTemplateRepository) GetByShareToken(ctx context.Context, shareToken string) (*model.WorkoutTemplate, error) {
+	row := r.db.QueryRowContext(ctx, `SELECT `+workoutTemplateColumns+` FROM workout_templates WHERE share_token = ?`, shareToken)
+
+	template, err := scanWorkoutTemplate(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+
+	return template, err
+}
+
+func (r *workoutTemplateRepository) GetByProfileID(ctx context.Context, profileID int) ([]model.WorkoutTemplate, error) {
+	query := `SELECT ` + workoutTemplateColumns + ` FROM workout_templates WHERE profile_id = ? ORDER BY name, id`
+
+	rows, err := r.db.QueryContext(ctx, query, profileID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	templates := []model.WorkoutTemplate{}
+	for rows.Next() {
+		template, err := scanWorkoutTemplate(rows)
+		if err != nil {
+			return nil, err
+		}
+		templates = append(templates, *template)
+	}
+
+	return templates, rows.Err()
+}
+
+func (r *workoutTemplateRepository) Create(ctx context.Context, tx *sql.Tx, template *model.WorkoutTemplate) (*model.WorkoutTemplate, error) {
+	query := `
+		INSERT INTO workout_templates (profile_id, name, description, source_template_id)
+		VALUES (?, ?, NULLIF(?, ''), ?)
+	`
+
+	res, err := tx.ExecContext(ctx, query, template.ProfileID, template.Name, template.Description, template.SourceTemplateID)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return nil, err
+	}
+
+	template.ID = int(id)
+
+	return template, nil
+}
+
+func (r *workoutTemplateRepository) Update(ctx context.Context, tx *sql.Tx, template *model.WorkoutTemplate) error {
+	query := `UPDATE workout_templates SET name = ?, description = NULLIF(?, '') WHERE id = ?`
+
+	_, err := tx.ExecContext(ctx, query, template.Name, template.Description, template.ID)
+
+	return err
+}
+
+// UpdateShareToken sets the template's share link token. A nil token revokes the link.
+func (r *workoutTemplateRepository) UpdateShareToken(ctx context.Context, id int, shareToken *string) error {
+	_, err := r.db.ExecContext(ctx, `UPDATE workout_templates SET share_token = ? WHERE id = ?`, shareToken, id)
+
+	return err
+}
+
+func (r *workoutTemplateRepository) Delete(ctx context.Context, id int) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM workout_templates WHERE id = ?`, id)
+
+	return err
+}
+
+func (r *workoutTemplateRepository) IsUsedByProgram(ctx context.Context, id int) (bool, error) {
+	var isUsed bool
+	err := r.db.QueryRowContext(ctx, `SELECT EXISTS(SELECT 1 FROM program_days WHERE template_id = ?)`, id).Scan(&isUsed)
+
+	return isUsed, err
+}
+
+// ReplaceSets swaps the template's target sets for the given ones.
+func (r *workoutTemplateRepository) ReplaceSets(ctx context.Context, tx *sql.Tx, templateID int, sets []model.WorkoutTemplateSet) error {
+	_, err := tx.ExecContext(ctx, `DELETE FROM workout_template_sets WHERE template_id = ?`, templateID)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO workout_template_sets
+			(template_id, exercise_id, exercise_order, set_number, target_reps, target_weight_kg, target_duration)
+		VALUES
+			(?, ?, ?, ?, ?, ?, ?)
+	`
+	for _, set := range sets {
+		_, err := tx.ExecContext(
+			ctx, query,
+			templateID,
+			set.ExerciseID,
+			set.ExerciseOrder,
+			set.SetNumber,
+			set.TargetReps,
+			set.TargetWeightKg,
+			set.TargetDuration,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *workoutTemplateRepository) GetSetsByTemplateIDs(ctx context.Context, templateIDs []int) (map[int][]model.WorkoutTemplateSet, error) {
+	sets := make(map[int][]model.WorkoutTemplateSet, len(templateIDs))
+	for _, templateID := range templateIDs {
+		sets[templateID] = []model.WorkoutTemplateSet{}
+	}
+
+	if len(templateIDs) == 0 {
+		return sets, nil
+	}
+
+	placeholders, args := inClause(templateIDs)
+	query := `
+		SELECT id, template_id, exercise_id, exercise_order, set_number, target_reps, target_weight_kg, target_duration
+		FROM workout_template_sets
+		WHERE template_id IN (` + placeholders + `)
+		ORDER BY template_id, exercise_order, set_number
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var set model.WorkoutTemplateSet
+		if err := rows.Scan(
+			&set.ID,
+			&set.TemplateID,
+			&set.ExerciseID,
+			&set.ExerciseOrder,
+			&set.SetNumber,
+			&set.TargetReps,
+			&set.TargetWeightKg,
+			&set.TargetDuration,
+		); err != nil {
+			return nil, err
+		}
+		sets[set.TemplateID] = append(sets[set.TemplateID], set)
+	}
+
+	return sets, rows.Err()
+}
@@ -0,0 +1,290 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/brightside-dev/ronin-fitness-be/database/client"
+	"github.com/brightside-dev/ronin-fitness-be/internal/handler/dto"
+	customError "github.com/brightside-dev/ronin-fitness-be/internal/handler/error"
+	"github.com/brightside-dev/ronin-fitness-be/internal/model"
+	"github.com/brightside-dev/ronin-fitness-be/internal/repository"
+	"github.com/brightside-dev/ronin-fitness-be/internal/util"
+)
+
+// achievementMetricEvents is the event that can move each metric, achievements of other metrics are
+// not measured when it happens.
+var achievementMetricEvents = map[string]string{
+	model.AchievementMetricWorkoutCount:        model.AchievementEventWorkout,
+	model.AchievementMetricStreakDays:          model.AchievementEventWorkout,
+	model.AchievementMetricPersonalRecordCount: model.AchievementEventPersonalRecord,
+	model.AchievementMetricLiftWeightKg:        model.AchievementEventPersonalRecord,
+	model.AchievementMetricFollowerCount:       model.AchievementEventFollow,
+	model.AchievementMetricFollowingCount:      model.AchievementEventFollow,
+}
+
+type AchievementService interface {
+	EvaluateAchievements(ctx context.Context, profileID int, events ...string) error
+	GetAchievements(w http.ResponseWriter, r *http.Request) (*dto.AchievementsListResponse, error)
+	GetProfileAchievements(w http.ResponseWriter, r *http.Request) (*dto.ProfileAchievementsResponse, error)
+}
+
+type achievementService struct {
+	DB                      client.DatabaseService
+	DBLogger                *slog.Logger
+	AchievementRepository   repository.AchievementRepository
+	ProfileRepository       repository.ProfileRepository
+	ProfileFollowRepository repository.ProfileFollowRepository
+	ProfileBlockRepository  repository.ProfileBlockRepository
+	NotificationService     NotificationService
+}
+
+func NewAchievementService(
+	db client.DatabaseService,
+	dbLogger *slog.Logger,
+	achievementRepository repository.AchievementRepository,
+	profileRepository repository.ProfileRepository,
+	profileFollowRepository repository.ProfileFollowRepository,
+	profileBlockRepository repository.ProfileBlockRepository,
+	notificationService NotificationService,
+) AchievementService {
+	return &achievementService{
+		DB:                      db,
+		DBLogger:                dbLogger,
+		AchievementRepository:   achievementRepository,
+		ProfileRepository:       profileRepository,
+		ProfileFollowRepository: profileFollowRepository,
+		ProfileBlockRepository:  profileBlockRepository,
+		NotificationService:     notificationService,
+	}
+}
+
+// EvaluateAchievements unlocks the achievements the profile has reached among those the events can
+// affect, notifying the profile of each. Callers run it once the event is committed and only log a
+// failure, the achievements then unlock on the next event of the same kind.
+func (s *achievementService) EvaluateAchievements(ctx context.Context, profileID int, events ...string) error {
+	achievements, err := s.AchievementRepository.GetAll(ctx)
+	if err != nil {
+		return err
+	}
+
+	unlocked, err := s.AchievementRepository.GetUnlocked(ctx, profileID)
+	if err != nil {
+		return err
+	}
+
+	unlockedIDs := map[int]bool{}
+	for _, profileAchievement := range unlocked {
+		unlockedIDs[profileAchievement.AchievementID] = true
+	}
+
+	evaluated := map[string]bool{}
+	for _, event := range events {
+		evaluated[event] = true
+	}
+
+	// Achievements often share a metric at different thresholds, each is only measured once
+	values := map[string]float64{}
+	reached := []model.Achievement{}
+	for _, achievement := range achievements {
+		if unlockedIDs[achievement.ID] || !evaluated[achievementMetricEvents[achievement.Metric]] {
+			continue
+		}
+
+		exerciseName := ""
+		if achievement.ExerciseName != nil {
+			exerciseName = strings.ToLower(*achievement.ExerciseName)
+		}
+
+		key := achievement.Metric + ":" + exerciseName
+		value, ok := values[key]
+		if !ok {
+			value, err = s.AchievementRepository.GetMetric(ctx, profileID, achievement.Metric, exerciseName)
+			if err != nil {
+				return err
+			}
+			values[key] = value
+		}
+
+		if value >= achievement.Threshold {
+			reached = append(reached, achievement)
+		}
+	}
+
+	if len(reached) == 0 {
+		return nil
+	}
+
+	now := time.Now().UTC().Truncate(time.Second)
+	_, err = util.WithTransaction(ctx, s.DB.GetDB(), func(tx *sql.Tx) (interface{}, error) {
+		for _, achievement := range reached {
+			created, err := s.AchievementRepository.Unlock(ctx, tx, profileID, achievement.ID, now)
+			if err != nil {
+				return nil, err
+			}
+
+			// A concurrent evaluation got there first and sent the notification
+			if !created {
+				continue
+			}
+
+			err = s.NotificationService.Notify(
+				ctx, tx, profileID,
+				model.NotificationTypeAchievementUnlocked,
+				"Achievement unlocked",
+				fmt.Sprintf("You unlocked %s: %s", achievement.Name, achievement.Description),
+				map[string]interface{}{
+					"achievement_id":   achievement.ID,
+					"achievement_code": achievement.Code,
+				},
+			)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		return nil, nil
+	})
+
+	return err
+}
+
+// GetAchievements returns the whole catalogue with when the caller unlocked each achievement.
+func (s *achievementService) GetAchievements(w http.ResponseWriter, r *http.Request) (*dto.AchievementsListResponse, error) {
+	profile, err := getAuthProfile(r, s.ProfileRepository)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrUnAuthorized
+	}
+
+	achievements, err := s.AchievementRepository.GetAll(r.Context())
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrInternalServerError
+	}
+
+	unlocked, err := s.AchievementRepository.GetUnlocked(r.Context(), profile.ID)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrInternalServerError
+	}
+
+	unlockedAt := map[int]time.Time{}
+	for _, profileAchievement := range unlocked {
+		unlockedAt[profileAchievement.AchievementID] = profileAchievement.UnlockedAt
+	}
+
+	res := &dto.AchievementsListResponse{
+		Achievements:  make([]dto.AchievementResponse, 0, len(achievements)),
+		UnlockedCount: len(unlocked),
+	}
+	for _, achievement := range achievements {
+		achievementDTO := toAchievementResponse(achievement)
+		if at, ok := unlockedAt[achievement.ID]; ok {
+			achievementDTO.UnlockedAt = &at
+		}
+		res.Achievements = append(res.Achievements, achievementDTO)
+	}
+
+	return res, nil
+}
+
+// GetProfileAchievements returns the achievements the profile unlocked, visible to whoever can see
+// the profile's content.
+func (s *achievementService) GetProfileAchievements(w http.ResponseWriter, r *http.Request) (*dto.ProfileAchievementsResponse, error) {
+	profileID, err := getIDParam(r, "profileId")
+	if err != nil {
+		return nil, err
+	}
+
+	viewer, err := getAuthProfile(r, s.ProfileRepository)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrUnAuthorized
+	}
+
+	profile, err := s.ProfileRepository.GetByID(r.Context(), profileID)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrInternalServerError
+	}
+
+	if profile == nil {
+		return nil, customError.ErrNotFound
+	}
+
+	blocked, err := s.ProfileBlockRepository.IsBlocked(r.Context(), profile.ID, viewer.ID)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrInternalServerError
+	}
+
+	if blocked {
+		return nil, customError.ErrNotFound
+	}
+
+	canView, err := canViewProfileContent(r, s.ProfileFollowRepository, s.ProfileBlockRepository, viewer.ID, profile)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrInternalServerError
+	}
+
+	if !canView {
+		return nil, customError.ErrForbidden
+	}
+
+	achievements, err := s.AchievementRepository.GetAll(r.Context())
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrInternalServerError
+	}
+
+	unlocked, err := s.AchievementRepository.GetUnlocked(r.Context(), profile.ID)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrInternalServerError
+	}
+
+	achievementsByID := map[int]model.Achievement{}
+	for _, achievement := range achievements {
+		achievementsByID[achievement.ID] = achievement
+	}
+
+	res := &dto.ProfileAchievementsResponse{
+		ProfileID:    profile.ID,
+		Achievements: make([]dto.AchievementResponse, 0, len(unlocked)),
+	}
+	for _, profileAchievement := range unlocked {
+		achievementDTO := toAchievementResponse(achievementsByID[profileAchievement.AchievementID])
+		achievementDTO.UnlockedAt = &profileAchievement.UnlockedAt
+		res.Achievements = append(res.Achievements, achievementDTO)
+	}
+
+	return res, nil
+}
+
+// workoutAchievementEvents are the events of saving a workout in which the personal records were set.
+func workoutAchievementEvents(personalRecords []model.PersonalRecord) []string {
+	if len(personalRecords) == 0 {
+		return []string{model.AchievementEventWorkout}
+	}
+
+	return []string{model.AchievementEventWorkout, model.AchievementEventPersonalRecord}
+}
+
+func toAchievementResponse(achievement model.Achievement) dto.AchievementResponse {
+	return dto.AchievementResponse{
+		ID:           achievement.ID,
+		Code:         achievement.Code,
+		Name:         achievement.Name,
+		Description:  achievement.Description,
+		Metric:       achievement.Metric,
+		Threshold:    achievement.Threshold,
+		ExerciseName: achievement.ExerciseName,
+	}
+}
@@ -0,0 +1,452 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/brightside-dev/ronin-fitness-be/database/client"
+	"github.com/brightside-dev/ronin-fitness-be/internal/handler/dto"
+	customError "github.com/brightside-dev/ronin-fitness-be/internal/handler/error"
+	"github.com/brightside-dev/ronin-fitness-be/internal/model"
+	"github.com/brightside-dev/ronin-fitness-be/internal/repository"
+	"github.com/brightside-dev/ronin-fitness-be/internal/util"
+	"github.com/go-playground/validator/v10"
+)
+
+const (
+	defaultMovingAverageDays = 7
+	// maxBodyMetricSeriesDays bounds the size of a series response, e.g. two years of daily points
+	maxBodyMetricSeriesDays = 731
+)
+
+type BodyMeasurementService interface {
+	CreateMeasurement(w http.ResponseWriter, r *http.Request) (*dto.BodyMeasurementResponse, error)
+	GetMeasurements(w http.ResponseWriter, r *http.Request) (*dto.BodyMeasurementsListResponse, error)
+	GetMeasurement(w http.ResponseWriter, r *http.Request) (*dto.BodyMeasurementResponse, error)
+	UpdateMeasurement(w http.ResponseWriter, r *http.Request) (*dto.BodyMeasurementResponse, error)
+	DeleteMeasurement(w http.ResponseWriter, r *http.Request) error
+	GetMetricSeries(w http.ResponseWriter, r *http.Request) (*dto.BodyMetricSeriesResponse, error)
+}
+
+type bodyMeasurementService struct {
+	DB                        client.DatabaseService
+	DBLogger                  *slog.Logger
+	Validate                  *validator.Validate
+	BodyMeasurementRepository repository.BodyMeasurementRepository
+	ProfileRepository         repository.ProfileRepository
+	GoalService               GoalService
+}
+
+func NewBodyMeasurementService(
+	db client.DatabaseService,
+	dbLogger *slog.Logger,
+	validator *validator.Validate,
+	bodyMeasurementRepository repository.BodyMeasurementRepository,
+	profileRepository repository.ProfileRepository,
+	goalService GoalService,
+) BodyMeasurementService {
+	return &bodyMeasurementService{
+		DB:                        db,
+		DBLogger:                  dbLogger,
+		Validate:                  validator,
+		BodyMeasurementRepository: bodyMeasurementRepository,
+		ProfileRepository:         profileRepository,
+		GoalService:               goalService,
+	}
+}
+
+func (s *bodyMeasurementService) CreateMeasurement(w http.ResponseWriter, r *http.Request) (*dto.BodyMeasurementResponse, error) {
+	profile, err := getAuthProfile(r, s.ProfileRepository)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrUnAuthorized
+	}
+
+	measurement, err := s.decodeMeasurement(r, profile)
+	if err != nil {
+		return nil, err
+	}
+
+	measurement, err = s.BodyMeasurementRepository.Create(r.Context(), measurement)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrInternalServerError
+	}
+
+	err = s.GoalService.EvaluateGoals(r.Context(), profile.ID)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+	}
+
+	res := toBodyMeasurementResponse(*measurement, profile)
+	return &res, nil
+}
+
+func (s *bodyMeasurementService) GetMeasurements(w http.ResponseWriter, r *http.Request) (*dto.BodyMeasurementsListResponse, error) {
+	beforeTime, beforeID, err := util.DecodeCursor(r.URL.Query().Get("cursor"))
+	if err != nil {
+		return nil, customError.ErrInvalidCursor
+	}
+
+	profile, err := getAuthProfile(r, s.ProfileRepository)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrUnAuthorized
+	}
+
+	limit := util.GetPageLimit(r, 30, 100)
+	measurements, err := s.BodyMeasurementRepository.GetByProfileID(r.Context(), profile.ID, beforeTime, beforeID, limit)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrInternalServerError
+	}
+
+	res := &dto.BodyMeasurementsListResponse{
+		Measurements: make([]dto.BodyMeasurementResponse, 0, len(measurements)),
+	}
+	for _, measurement := range measurements {
+		res.Measurements = append(res.Measurements, toBodyMeasurementResponse(measurement, profile))
+	}
+
+	if len(measurements) == limit {
+		last := measurements[len(measurements)-1]
+		res.NextCursor = util.EncodeCursor(last.MeasuredAt, last.ID)
+	}
+
+	return res, nil
+}
+
+func (s *bodyMeasurementService) GetMeasurement(w http.ResponseWriter, r *http.Request) (*dto.BodyMeasurementResponse, error) {
+	profile, measurement, err := s.getOwnMeasurement(r)
+	if err != nil {
+		return nil, err
+	}
+
+	res := toBodyMeasurementResponse(*measurement, profile)
+	return &res, nil
+}
+
+func (s *bodyMeasurementService) UpdateMeasurement(w http.ResponseWriter, r *http.Request) (*dto.BodyMeasurementResponse, error) {
+	profile, existing, err := s.getOwnMeasurement(r)
+	if err != nil {
+		return nil, err
+	}
+
+	measurement, err := s.decodeMeasurement(r, profile)
+	if err != nil {
+		return nil, err
+	}
+
+	measurement.ID = existing.ID
+	err = s.BodyMeasurementRepository.Update(r.Context(), measurement)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrInternalServerError
+	}
+
+	err = s.GoalService.EvaluateGoals(r.Context(), profile.ID)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+	}
+
+	measurement, err = s.BodyMeasurementRepository.GetByID(r.Context(), existing.ID)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrInternalServerError
+	}
+
+	if measurement == nil {
+		return nil, customError.ErrNotFound
+	}
+
+	res := toBodyMeasurementResponse(*measurement, profile)
+	return &res, nil
+}
+
+func (s *bodyMeasurementService) DeleteMeasurement(w http.ResponseWriter, r *http.Request) error {
+	profile, measurement, err := s.getOwnMeasurement(r)
+	if err != nil {
+		return err
+	}
+
+	err = s.BodyMeasurementRepository.Delete(r.Context(), measurement.ID)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return customError.ErrInternalServerError
+	}
+
+	err = s.GoalService.EvaluateGoals(r.Context(), profile.ID)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+	}
+
+	return nil
+}
+
+// GetMetricSeries charts one metric over a date range, 90 days up to today by default, with a
+// trailing moving average that smooths out day to day fluctuations such as water weight.
+func (s *bodyMeasurementService) GetMetricSeries(w http.ResponseWriter, r *http.Request) (*dto.BodyMetricSeriesResponse, error) {
+	req := dto.BodyMetricSeriesRequest{
+		Metric:     r.URL.Query().Get("metric"),
+		From:       r.URL.Query().Get("from"),
+		To:         r.URL.Query().Get("to"),
+		WindowDays: defaultMovingAverageDays,
+	}
+
+	if window := r.URL.Query().Get("window"); window != "" {
+		windowDays, err := strconv.Atoi(window)
+		if err != nil {
+			return nil, fmt.Errorf("window must be a number of days")
+		}
+		req.WindowDays = windowDays
+	}
+
+	err := s.Validate.Struct(req)
+	if err != nil {
+		return nil, util.FormatValidationError(err.(validator.ValidationErrors))
+	}
+
+	to := time.Now().UTC().Truncate(24 * time.Hour)
+	if req.To != "" {
+		to, _ = time.Parse("2006-01-02", req.To)
+	}
+
+	from := to.AddDate(0, 0, -89)
+	if req.From != "" {
+		from, _ = time.Parse("2006-01-02", req.From)
+	}
+
+	if from.After(to) {
+		return nil, fmt.Errorf("from must not be after to")
+	}
+
+	if to.Sub(from) >= maxBodyMetricSeriesDays*24*time.Hour {
+		return nil, fmt.Errorf("date range must not be longer than %d days", maxBodyMetricSeriesDays)
+	}
+
+	profile, err := getAuthProfile(r, s.ProfileRepository)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrUnAuthorized
+	}
+
+	// The days before from are only loaded for the moving averages of the first points
+	points, err := s.BodyMeasurementRepository.GetMetricPoints(r.Context(), profile.ID, req.Metric, from.AddDate(0, 0, 1-req.WindowDays), to.AddDate(0, 0, 1))
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrInternalServerError
+	}
+
+	unit, convert := bodyMetricUnit(req.Metric, profile)
+
+	res := &dto.BodyMetricSeriesResponse{
+		Metric:     req.Metric,
+		Unit:       unit,
+		From:       from.Format("2006-01-02"),
+		To:         to.Format("2006-01-02"),
+		WindowDays: req.WindowDays,
+		Points:     []dto.BodyMetricPointResponse{},
+	}
+	for _, point := range bodyMetricMovingAverages(points, req.WindowDays) {
+		if point.date.Before(from) {
+			continue
+		}
+
+		res.Points = append(res.Points, dto.BodyMetricPointResponse{
+			Date:          point.date.Format("2006-01-02"),
+			Value:         convert(point.value),
+			MovingAverage: convert(point.movingAverage),
+		})
+	}
+
+	return res, nil
+}
+
+// getOwnMeasurement loads the measurement named by the measurementId URL parameter, failing unless
+// it belongs to the caller.
+func (s *bodyMeasurementService) getOwnMeasurement(r *http.Request) (*model.ProfileWithUser, *model.BodyMeasurement, error) {
+	measurementID, err := getIDParam(r, "measurementId")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	profile, err := getAuthProfile(r, s.ProfileRepository)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, nil, customError.ErrUnAuthorized
+	}
+
+	measurement, err := s.BodyMeasurementRepository.GetByID(r.Context(), measurementID)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, nil, customError.ErrInternalServerError
+	}
+
+	if measurement == nil || measurement.ProfileID != profile.ID {
+		return nil, nil, customError.ErrNotFound
+	}
+
+	return profile, measurement, nil
+}
+
+// decodeMeasurement validates the request body and converts it to canonical units.
+func (s *bodyMeasurementService) decodeMeasurement(r *http.Request, profile *model.ProfileWithUser) (*model.BodyMeasurement, error) {
+	req := dto.BodyMeasurementRequest{}
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrInvalidRequestBody
+	}
+
+	err = s.Validate.Struct(req)
+	if err != nil {
+		return nil, util.FormatValidationError(err.(validator.ValidationErrors))
+	}
+
+	now := time.Now().UTC()
+	measuredAt := now
+	if req.MeasuredAt != nil {
+		measuredAt = req.MeasuredAt.UTC()
+	}
+
+	if measuredAt.After(now.Add(time.Minute)) {
+		return nil, fmt.Errorf("measured_at must not be in the future")
+	}
+
+	weightUnit := req.WeightUnit
+	if weightUnit == "" {
+		weightUnit = profile.WeightUnit
+	}
+
+	lengthUnit := req.LengthUnit
+	if lengthUnit == "" {
+		lengthUnit = util.DefaultLengthUnit(profile.WeightUnit)
+	}
+
+	toKg := func(weight float64) float64 { return util.ToKg(weight, weightUnit) }
+	toCm := func(length float64) float64 { return util.ToCm(length, lengthUnit) }
+	round := func(value float64) float64 { return math.Round(value*100) / 100 }
+
+	measurement := &model.BodyMeasurement{
+		ProfileID:      profile.ID,
+		MeasuredAt:     measuredAt.Truncate(time.Second),
+		WeightKg:       convertOptional(req.Weight, toKg),
+		BodyFatPercent: convertOptional(req.BodyFatPercent, round),
+		NeckCm:         convertOptional(req.Neck, toCm),
+		ShouldersCm:    convertOptional(req.Shoulders, toCm),
+		ChestCm:        convertOptional(req.Chest, toCm),
+		WaistCm:        convertOptional(req.Waist, toCm),
+		HipsCm:         convertOptional(req.Hips, toCm),
+		LeftArmCm:      convertOptional(req.LeftArm, toCm),
+		RightArmCm:     convertOptional(req.RightArm, toCm),
+		LeftThighCm:    convertOptional(req.LeftThigh, toCm),
+		RightThighCm:   convertOptional(req.RightThigh, toCm),
+		LeftCalfCm:     convertOptional(req.LeftCalf, toCm),
+		RightCalfCm:    convertOptional(req.RightCalf, toCm),
+	}
+	measurement.WeightLb = convertOptional(measurement.WeightKg, util.KgToLb)
+
+	if measurement.WeightKg == nil && measurement.BodyFatPercent == nil && measurement.NeckCm == nil &&
+		measurement.ShouldersCm == nil && measurement.ChestCm == nil && measurement.WaistCm == nil &&
+		measurement.HipsCm == nil && measurement.LeftArmCm == nil && measurement.RightArmCm == nil &&
+		measurement.LeftThighCm == nil && measurement.RightThighCm == nil && measurement.LeftCalfCm == nil &&
+		measurement.RightCalfCm == nil {
+		return nil, fmt.Errorf("at least one measurement is required")
+	}
+
+	return measurement, nil
+}
+
+func toBodyMeasurementResponse(measurement model.BodyMeasurement, profile *model.ProfileWithUser) dto.BodyMeasurementResponse {
+	lengthUnit := util.DefaultLengthUnit(profile.WeightUnit)
+	fromKg := func(weightKg float64) float64 { return util.FromKg(weightKg, profile.WeightUnit) }
+	fromCm := func(lengthCm float64) float64 { return util.FromCm(lengthCm, lengthUnit) }
+
+	return dto.BodyMeasurementResponse{
+		ID:             measurement.ID,
+		MeasuredAt:     measurement.MeasuredAt,
+		Weight:         convertOptional(measurement.WeightKg, fromKg),
+		WeightUnit:     profile.WeightUnit,
+		BodyFatPercent: measurement.BodyFatPercent,
+		LengthUnit:     lengthUnit,
+		Neck:           convertOptional(measurement.NeckCm, fromCm),
+		Shoulders:      convertOptional(measurement.ShouldersCm, fromCm),
+		Chest:          convertOptional(measurement.ChestCm, fromCm),
+		Waist:          convertOptional(measurement.WaistCm, fromCm),
+		Hips:           convertOptional(measurement.HipsCm, fromCm),
+		LeftArm:        convertOptional(measurement.LeftArmCm, fromCm),
+		RightArm:       convertOptional(measurement.RightArmCm, fromCm),
+		LeftThigh:      convertOptional(measurement.LeftThighCm, fromCm),
+		RightThigh:     convertOptional(measurement.RightThighCm, fromCm),
+		LeftCalf:       convertOptional(measurement.LeftCalfCm, fromCm),
+		RightCalf:      convertOptional(measurement.RightCalfCm, fromCm),
+		CreatedAt:      measurement.CreatedAt,
+		UpdatedAt:      measurement.UpdatedAt,
+	}
+}
+
+func convertOptional(value *float64, convert func(float64) float64) *float64 {
+	if value == nil {
+		return nil
+	}
+
+	converted := convert(*value)
+	return &converted
+}
+
+// bodyMetricUnit returns the unit a metric is shown in to the profile, and the conversion to it
+// from the canonical unit.
+func bodyMetricUnit(metric string, profile *model.ProfileWithUser) (string, func(float64) float64) {
+	switch metric {
+	case model.BodyMetricWeight:
+		return profile.WeightUnit, func(weightKg float64) float64 { return util.FromKg(weightKg, profile.WeightUnit) }
+	case model.BodyMetricBodyFat:
+		return "%", func(percent float64) float64 { return math.Round(percent*100) / 100 }
+	default:
+		lengthUnit := util.DefaultLengthUnit(profile.WeightUnit)
+		return lengthUnit, func(lengthCm float64) float64 { return util.FromCm(lengthCm, lengthUnit) }
+	}
+}
+
+type bodyMetricDay struct {
+	date          time.Time
+	value         float64
+	movingAverage float64
+}
+
+// bodyMetricMovingAverages averages the points of each day, then averages those daily values over
+// the trailing window of days. Days without a measurement neither count nor get a point.
+func bodyMetricMovingAverages(points []model.BodyMetricPoint, windowDays int) []bodyMetricDay {
+	days := []bodyMetricDay{}
+	counts := []int{}
+	for _, point := range points {
+		date := point.MeasuredAt.UTC().Truncate(24 * time.Hour)
+		if len(days) == 0 || !days[len(days)-1].date.Equal(date) {
+			days = append(days, bodyMetricDay{date: date})
+			counts = append(counts, 0)
+		}
+		days[len(days)-1].value += point.Value
+		counts[len(counts)-1]++
+	}
+
+	windowStart := 0
+	windowSum := 0.0
+	for i := range days {
+		days[i].value /= float64(counts[i])
+		windowSum += days[i].value
+
+		for !days[windowStart].date.After(days[i].date.AddDate(0, 0, -windowDays)) {
+			windowSum -= days[windowStart].value
+			windowStart++
+		}
+
+		days[i].movingAverage = windowSum / float64(i-windowStart+1)
+	}
+
+	return days
+}
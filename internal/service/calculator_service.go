@@ -0,0 +1,370 @@
+package service
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+
+	"github.com/brightside-dev/ronin-fitness-be/database/client"
+	"github.com/brightside-dev/ronin-fitness-be/internal/handler/dto"
+	customError "github.com/brightside-dev/ronin-fitness-be/internal/handler/error"
+	"github.com/brightside-dev/ronin-fitness-be/internal/model"
+	"github.com/brightside-dev/ronin-fitness-be/internal/repository"
+	"github.com/brightside-dev/ronin-fitness-be/internal/util"
+	"github.com/go-playground/validator/v10"
+)
+
+// Plate sets assumed for profiles that have not saved their own, as pairs of each weight.
+var defaultPlates = map[string][]dto.PlateResponse{
+	util.WeightUnitKg: {{Weight: 25, PairCount: 10}, {Weight: 20, PairCount: 10}, {Weight: 15, PairCount: 10}, {Weight: 10, PairCount: 10}, {Weight: 5, PairCount: 10}, {Weight: 2.5, PairCount: 10}, {Weight: 1.25, PairCount: 10}},
+	util.WeightUnitLb: {{Weight: 45, PairCount: 10}, {Weight: 35, PairCount: 10}, {Weight: 25, PairCount: 10}, {Weight: 10, PairCount: 10}, {Weight: 5, PairCount: 10}, {Weight: 2.5, PairCount: 10}},
+}
+
+var defaultBarWeights = map[string]float64{
+	util.WeightUnitKg: 20,
+	util.WeightUnitLb: 45,
+}
+
+// warmupScheme is the ramp generated before a working weight. A zero percent means the empty bar.
+var warmupScheme = []struct {
+	Percent int
+	Reps    int
+}{
+	{Percent: 0, Reps: 10},
+	{Percent: 40, Reps: 5},
+	{Percent: 60, Reps: 3},
+	{Percent: 80, Reps: 2},
+}
+
+type CalculatorService interface {
+	GetPlateInventory(w http.ResponseWriter, r *http.Request) (*dto.PlateInventoryResponse, error)
+	UpdatePlateInventory(w http.ResponseWriter, r *http.Request) (*dto.PlateInventoryResponse, error)
+	CalculatePlates(w http.ResponseWriter, r *http.Request) (*dto.PlateLoadingResponse, error)
+	GenerateWarmup(w http.ResponseWriter, r *http.Request) (*dto.WarmupResponse, error)
+}
+
+type calculatorService struct {
+	DB                     client.DatabaseService
+	DBLogger               *slog.Logger
+	Validate               *validator.Validate
+	ProfilePlateRepository repository.ProfilePlateRepository
+	ProfileRepository      repository.ProfileRepository
+}
+
+func NewCalculatorService(
+	db client.DatabaseService,
+	dbLogger *slog.Logger,
+	validator *validator.Validate,
+	profilePlateRepository repository.ProfilePlateRepository,
+	profileRepository repository.ProfileRepository,
+) CalculatorService {
+	return &calculatorService{
+		DB:                     db,
+		DBLogger:               dbLogger,
+		Validate:               validator,
+		ProfilePlateRepository: profilePlateRepository,
+		ProfileRepository:      profileRepository,
+	}
+}
+
+func (s *calculatorService) GetPlateInventory(w http.ResponseWriter, r *http.Request) (*dto.PlateInventoryResponse, error) {
+	unit := r.URL.Query().Get("unit")
+	if unit != "" && unit != util.WeightUnitKg && unit != util.WeightUnitLb {
+		return nil, fmt.Errorf("unit must be one of kg lb")
+	}
+
+	profile, err := getAuthProfile(r, s.ProfileRepository)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrUnAuthorized
+	}
+
+	if unit == "" {
+		unit = profile.WeightUnit
+	}
+
+	return s.getPlateInventory(r, profile, unit)
+}
+
+func (s *calculatorService) UpdatePlateInventory(w http.ResponseWriter, r *http.Request) (*dto.PlateInventoryResponse, error) {
+	req := dto.PlateInventoryRequest{}
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrInvalidRequestBody
+	}
+
+	err = s.Validate.Struct(req)
+	if err != nil {
+		return nil, util.FormatValidationError(err.(validator.ValidationErrors))
+	}
+
+	profile, err := getAuthProfile(r, s.ProfileRepository)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrUnAuthorized
+	}
+
+	unit := req.Unit
+	if unit == "" {
+		unit = profile.WeightUnit
+	}
+
+	pairCounts := map[float64]int{}
+	for _, plate := range req.Plates {
+		pairCounts[util.ToKg(plate.Weight, unit)] += plate.PairCount
+	}
+
+	plates := make([]model.ProfilePlate, 0, len(pairCounts))
+	for weightKg, pairCount := range pairCounts {
+		plates = append(plates, model.ProfilePlate{WeightKg: weightKg, PairCount: pairCount})
+	}
+
+	updatedProfile := profile.Profile
+	updatedProfile.BarWeightKg = util.ToKg(req.BarWeight, unit)
+
+	_, err = util.WithTransaction(r.Context(), s.DB.GetDB(), func(tx *sql.Tx) (interface{}, error) {
+		_, err := s.ProfileRepository.Update(r.Context(), tx, &updatedProfile)
+		if err != nil {
+			return nil, err
+		}
+
+		return nil, s.ProfilePlateRepository.Replace(r.Context(), tx, profile.ID, plates)
+	})
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrInternalServerError
+	}
+
+	profile.Profile = updatedProfile
+
+	return s.getPlateInventory(r, profile, unit)
+}
+
+// CalculatePlates returns the plates to load on each side of the bar for the weight query parameter.
+func (s *calculatorService) CalculatePlates(w http.ResponseWriter, r *http.Request) (*dto.PlateLoadingResponse, error) {
+	req, err := s.getCalculatorRequest(r)
+	if err != nil {
+		return nil, err
+	}
+
+	profile, err := getAuthProfile(r, s.ProfileRepository)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrUnAuthorized
+	}
+
+	if req.Unit == "" {
+		req.Unit = profile.WeightUnit
+	}
+
+	inventory, err := s.getPlateInventory(r, profile, req.Unit)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.BarWeight == 0 {
+		req.BarWeight = inventory.BarWeight
+	}
+
+	loadedWeight, perSide := loadPlates(req.Weight, req.BarWeight, inventory.Plates)
+
+	return &dto.PlateLoadingResponse{
+		Unit:         req.Unit,
+		TargetWeight: req.Weight,
+		BarWeight:    req.BarWeight,
+		LoadedWeight: loadedWeight,
+		IsExact:      math.Abs(loadedWeight-req.Weight) < 0.005,
+		PerSide:      perSide,
+	}, nil
+}
+
+// GenerateWarmup builds a ramp of loadable warm-up sets leading up to the weight query parameter.
+func (s *calculatorService) GenerateWarmup(w http.ResponseWriter, r *http.Request) (*dto.WarmupResponse, error) {
+	req, err := s.getCalculatorRequest(r)
+	if err != nil {
+		return nil, err
+	}
+
+	profile, err := getAuthProfile(r, s.ProfileRepository)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrUnAuthorized
+	}
+
+	if req.Unit == "" {
+		req.Unit = profile.WeightUnit
+	}
+
+	inventory, err := s.getPlateInventory(r, profile, req.Unit)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.BarWeight == 0 {
+		req.BarWeight = inventory.BarWeight
+	}
+
+	return &dto.WarmupResponse{
+		Unit:          req.Unit,
+		WorkingWeight: req.Weight,
+		BarWeight:     req.BarWeight,
+		Sets:          warmupSets(req.Weight, req.BarWeight, inventory.Plates),
+	}, nil
+}
+
+// warmupSets loads each step of the warm-up scheme for the working weight with the plates.
+func warmupSets(workingWeight float64, barWeight float64, plates []dto.PlateResponse) []dto.WarmupSetResponse {
+	sets := []dto.WarmupSetResponse{}
+
+	previousWeight := 0.0
+	for _, step := range warmupScheme {
+		weight, perSide := barWeight, []dto.PlateCountResponse{}
+		if step.Percent > 0 {
+			weight, perSide = loadPlates(workingWeight*float64(step.Percent)/100, barWeight, plates)
+		}
+
+		// Skip steps that round to the same load as the previous one or reach the working weight
+		if weight <= previousWeight || weight >= workingWeight {
+			continue
+		}
+		previousWeight = weight
+
+		sets = append(sets, dto.WarmupSetResponse{
+			SetNumber: len(sets) + 1,
+			Weight:    weight,
+			Reps:      step.Reps,
+			Percent:   int(math.Round(weight / workingWeight * 100)),
+			PerSide:   perSide,
+		})
+	}
+
+	return sets
+}
+
+func (s *calculatorService) getCalculatorRequest(r *http.Request) (*dto.PlateCalculatorRequest, error) {
+	req := &dto.PlateCalculatorRequest{Unit: r.URL.Query().Get("unit")}
+
+	for name, value := range map[string]*float64{"weight": &req.Weight, "bar_weight": &req.BarWeight} {
+		param := r.URL.Query().Get(name)
+		if param == "" {
+			continue
+		}
+
+		parsed, err := strconv.ParseFloat(param, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%s must be a number", name)
+		}
+		*value = parsed
+	}
+
+	err := s.Validate.Struct(req)
+	if err != nil {
+		return nil, util.FormatValidationError(err.(validator.ValidationErrors))
+	}
+
+	return req, nil
+}
+
+// getPlateInventory returns the profile's plates in the given unit, or the default set for that unit
+// when the profile has not saved any.
+func (s *calculatorService) getPlateInventory(r *http.Request, profile *model.ProfileWithUser, unit string) (*dto.PlateInventoryResponse, error) {
+	plates, err := s.ProfilePlateRepository.GetByProfileID(r.Context(), profile.ID)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrInternalServerError
+	}
+
+	if len(plates) == 0 {
+		return &dto.PlateInventoryResponse{
+			Unit:      unit,
+			BarWeight: defaultBarWeights[unit],
+			Plates:    defaultPlates[unit],
+			IsDefault: true,
+		}, nil
+	}
+
+	res := &dto.PlateInventoryResponse{
+		Unit:      unit,
+		BarWeight: util.FromKg(profile.BarWeightKg, unit),
+		Plates:    make([]dto.PlateResponse, 0, len(plates)),
+	}
+	for _, plate := range plates {
+		res.Plates = append(res.Plates, dto.PlateResponse{
+			Weight:    util.FromKg(plate.WeightKg, unit),
+			PairCount: plate.PairCount,
+		})
+	}
+
+	return res, nil
+}
+
+// loadPlates finds the heaviest total not above target that the plates can load symmetrically on the
+// bar, using as few plates as possible. Weights are handled in hundredths of the unit so that the
+// search is exact; each pair gives one plate per side.
+func loadPlates(target float64, barWeight float64, plates []dto.PlateResponse) (float64, []dto.PlateCountResponse) {
+	perSide := []dto.PlateCountResponse{}
+	// The tolerance keeps a target such as 20.06 from flooring to 0.02 per side in floating point
+	perSideTarget := int(math.Floor((target-barWeight)/2*100 + 1e-6))
+	if perSideTarget <= 0 {
+		return barWeight, perSide
+	}
+
+	sorted := append([]dto.PlateResponse{}, plates...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Weight > sorted[j].Weight })
+
+	// minPlates[t][sum] is the fewest plates of the first t weights adding up to sum, -1 if impossible,
+	// and counts[t][sum] how many of plate t-1 that solution uses
+	minPlates := make([][]int, len(sorted)+1)
+	counts := make([][]int, len(sorted)+1)
+	for t := range minPlates {
+		minPlates[t] = make([]int, perSideTarget+1)
+		counts[t] = make([]int, perSideTarget+1)
+		for sum := range minPlates[t] {
+			minPlates[t][sum] = -1
+		}
+	}
+	minPlates[0][0] = 0
+
+	for t := 1; t <= len(sorted); t++ {
+		weight := int(math.Round(sorted[t-1].Weight * 100))
+		for sum := 0; sum <= perSideTarget; sum++ {
+			for k := 0; k <= sorted[t-1].PairCount && k*weight <= sum; k++ {
+				previous := minPlates[t-1][sum-k*weight]
+				if previous < 0 {
+					continue
+				}
+				if minPlates[t][sum] < 0 || previous+k < minPlates[t][sum] {
+					minPlates[t][sum] = previous + k
+					counts[t][sum] = k
+				}
+			}
+		}
+	}
+
+	best := perSideTarget
+	for best > 0 && minPlates[len(sorted)][best] < 0 {
+		best--
+	}
+
+	sum := best
+	for t := len(sorted); t > 0; t-- {
+		k := counts[t][sum]
+		if k > 0 {
+			perSide = append(perSide, dto.PlateCountResponse{Weight: sorted[t-1].Weight, Count: k})
+		}
+		sum -= k * int(math.Round(sorted[t-1].Weight*100))
+	}
+
+	// Plates were collected lightest first, list them in loading order
+	for i, j := 0, len(perSide)-1; i < j; i, j = i+1, j-1 {
+		perSide[i], perSide[j] = perSide[j], perSide[i]
+	}
+
+	return math.Round((barWeight+float64(best)*2/100)*100) / 100, perSide
+}
@@ -0,0 +1,167 @@
+package service
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/brightside-dev/ronin-fitness-be/internal/handler/dto"
+	"github.com/brightside-dev/ronin-fitness-be/internal/util"
+)
+
+func TestLoadPlates(t *testing.T) {
+	kgPlates := defaultPlates[util.WeightUnitKg]
+	lbPlates := defaultPlates[util.WeightUnitLb]
+
+	tests := []struct {
+		name       string
+		target     float64
+		barWeight  float64
+		plates     []dto.PlateResponse
+		wantLoaded float64
+		wantPlates int                      // per side
+		wantSide   []dto.PlateCountResponse // when only one set of plates is the fewest
+	}{
+		{name: "one plate a side", target: 60, barWeight: 20, plates: kgPlates, wantLoaded: 60, wantPlates: 1, wantSide: []dto.PlateCountResponse{{Weight: 20, Count: 1}}},
+		{name: "change plates", target: 62.5, barWeight: 20, plates: kgPlates, wantLoaded: 62.5, wantPlates: 2, wantSide: []dto.PlateCountResponse{{Weight: 20, Count: 1}, {Weight: 1.25, Count: 1}}},
+		{name: "heavy", target: 220, barWeight: 20, plates: kgPlates, wantLoaded: 220, wantPlates: 4, wantSide: []dto.PlateCountResponse{{Weight: 25, Count: 4}}},
+		{name: "target between loadable weights", target: 101, barWeight: 20, plates: kgPlates, wantLoaded: 100, wantPlates: 2},
+		{name: "target just under the next plate", target: 22.49, barWeight: 20, plates: kgPlates, wantLoaded: 20, wantPlates: 0},
+		{name: "target equals the bar", target: 20, barWeight: 20, plates: kgPlates, wantLoaded: 20, wantPlates: 0},
+		{name: "bar heavier than the target", target: 15, barWeight: 20, plates: kgPlates, wantLoaded: 20, wantPlates: 0},
+		{name: "pounds", target: 225, barWeight: 45, plates: lbPlates, wantLoaded: 225, wantPlates: 2, wantSide: []dto.PlateCountResponse{{Weight: 45, Count: 2}}},
+		{name: "pounds with fewest plates", target: 155, barWeight: 45, plates: lbPlates, wantLoaded: 155, wantPlates: 2, wantSide: []dto.PlateCountResponse{{Weight: 45, Count: 1}, {Weight: 10, Count: 1}}},
+		{name: "pounds not exact", target: 136, barWeight: 45, plates: lbPlates, wantLoaded: 135, wantPlates: 1, wantSide: []dto.PlateCountResponse{{Weight: 45, Count: 1}}},
+		{name: "pounds with change plates", target: 100, barWeight: 45, plates: lbPlates, wantLoaded: 100, wantPlates: 2, wantSide: []dto.PlateCountResponse{{Weight: 25, Count: 1}, {Weight: 2.5, Count: 1}}},
+		{
+			name:       "limited pairs",
+			target:     100,
+			barWeight:  20,
+			plates:     []dto.PlateResponse{{Weight: 20, PairCount: 1}, {Weight: 10, PairCount: 1}},
+			wantLoaded: 80,
+			wantPlates: 2,
+			wantSide:   []dto.PlateCountResponse{{Weight: 20, Count: 1}, {Weight: 10, Count: 1}},
+		},
+		{
+			// 5 lb plates shown in kilograms, whose hundredths floating point cannot represent exactly
+			name:       "pound plates in kilograms",
+			target:     24.54,
+			barWeight:  20,
+			plates:     []dto.PlateResponse{{Weight: 2.27, PairCount: 2}},
+			wantLoaded: 24.54,
+			wantPlates: 1,
+			wantSide:   []dto.PlateCountResponse{{Weight: 2.27, Count: 1}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			loaded, perSide := loadPlates(tt.target, tt.barWeight, tt.plates)
+			if loaded != tt.wantLoaded {
+				t.Errorf("loaded = %v, want %v", loaded, tt.wantLoaded)
+			}
+
+			plateCount, sideWeight := 0, 0.0
+			for _, plate := range perSide {
+				plateCount += plate.Count
+				sideWeight += plate.Weight * float64(plate.Count)
+			}
+			if plateCount != tt.wantPlates {
+				t.Errorf("plates per side = %d (%v), want %d", plateCount, perSide, tt.wantPlates)
+			}
+			if loaded >= tt.barWeight && math.Abs(tt.barWeight+2*sideWeight-loaded) > 0.005 {
+				t.Errorf("bar and plates weigh %v, not the loaded %v", tt.barWeight+2*sideWeight, loaded)
+			}
+			if tt.wantSide != nil && !reflect.DeepEqual(perSide, tt.wantSide) {
+				t.Errorf("per side = %v, want %v", perSide, tt.wantSide)
+			}
+		})
+	}
+}
+
+func TestWarmupSets(t *testing.T) {
+	tests := []struct {
+		name          string
+		workingWeight float64
+		barWeight     float64
+		plates        []dto.PlateResponse
+		wantWeights   []float64
+		wantReps      []int
+		wantPercents  []int
+	}{
+		{
+			name:          "full ramp in kilograms",
+			workingWeight: 100,
+			barWeight:     20,
+			plates:        defaultPlates[util.WeightUnitKg],
+			wantWeights:   []float64{20, 40, 60, 80},
+			wantReps:      []int{10, 5, 3, 2},
+			wantPercents:  []int{20, 40, 60, 80},
+		},
+		{
+			name:          "full ramp in pounds",
+			workingWeight: 225,
+			barWeight:     45,
+			plates:        defaultPlates[util.WeightUnitLb],
+			wantWeights:   []float64{45, 90, 135, 180},
+			wantReps:      []int{10, 5, 3, 2},
+			wantPercents:  []int{20, 40, 60, 80},
+		},
+		{
+			name:          "steps below the bar are dropped",
+			workingWeight: 30,
+			barWeight:     20,
+			plates:        defaultPlates[util.WeightUnitKg],
+			wantWeights:   []float64{20, 22.5},
+			wantReps:      []int{10, 2},
+			wantPercents:  []int{67, 75},
+		},
+		{
+			name:          "steps rounding to the same load are dropped",
+			workingWeight: 60,
+			barWeight:     20,
+			plates:        []dto.PlateResponse{{Weight: 10, PairCount: 5}},
+			wantWeights:   []float64{20, 40},
+			wantReps:      []int{10, 2},
+			wantPercents:  []int{33, 67},
+		},
+		{
+			name:          "working weight of the bar",
+			workingWeight: 20,
+			barWeight:     20,
+			plates:        defaultPlates[util.WeightUnitKg],
+		},
+		{
+			name:          "bar heavier than the working weight",
+			workingWeight: 15,
+			barWeight:     20,
+			plates:        defaultPlates[util.WeightUnitKg],
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sets := warmupSets(tt.workingWeight, tt.barWeight, tt.plates)
+
+			weights, reps, percents := []float64{}, []int{}, []int{}
+			for i, set := range sets {
+				if set.SetNumber != i+1 {
+					t.Errorf("set %d has number %d", i+1, set.SetNumber)
+				}
+				weights = append(weights, set.Weight)
+				reps = append(reps, set.Reps)
+				percents = append(percents, set.Percent)
+			}
+
+			if len(sets) != len(tt.wantWeights) || (len(sets) > 0 && !reflect.DeepEqual(weights, tt.wantWeights)) {
+				t.Fatalf("weights = %v, want %v", weights, tt.wantWeights)
+			}
+			if len(sets) > 0 && !reflect.DeepEqual(reps, tt.wantReps) {
+				t.Errorf("reps = %v, want %v", reps, tt.wantReps)
+			}
+			if len(sets) > 0 && !reflect.DeepEqual(percents, tt.wantPercents) {
+				t.Errorf("percents = %v, want %v", percents, tt.wantPercents)
+			}
+		})
+	}
+}
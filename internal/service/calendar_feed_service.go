@@ -0,0 +1,378 @@
+package service
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/brightside-dev/ronin-fitness-be/database/client"
+	"github.com/brightside-dev/ronin-fitness-be/internal/handler/dto"
+	customError "github.com/brightside-dev/ronin-fitness-be/internal/handler/error"
+	"github.com/brightside-dev/ronin-fitness-be/internal/model"
+	"github.com/brightside-dev/ronin-fitness-be/internal/repository"
+	"github.com/brightside-dev/ronin-fitness-be/internal/util"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+)
+
+const (
+	// calendarFeedHistoryDays is how far back the feed lists completed workouts
+	calendarFeedHistoryDays = 365
+)
+
+type CalendarFeedService interface {
+	GetFeed(w http.ResponseWriter, r *http.Request) (*dto.CalendarFeedResponse, error)
+	CreateFeed(w http.ResponseWriter, r *http.Request) (*dto.CalendarFeedResponse, error)
+	DeleteFeed(w http.ResponseWriter, r *http.Request) error
+	ServeFeed(w http.ResponseWriter, r *http.Request) error
+}
+
+type calendarFeedService struct {
+	DB                          client.DatabaseService
+	DBLogger                    *slog.Logger
+	Validate                    *validator.Validate
+	CalendarFeedRepository      repository.CalendarFeedRepository
+	ProfileRepository           repository.ProfileRepository
+	WorkoutRepository           repository.WorkoutRepository
+	ProgramRepository           repository.ProgramRepository
+	ProgramEnrollmentRepository repository.ProgramEnrollmentRepository
+	WorkoutTemplateRepository   repository.WorkoutTemplateRepository
+}
+
+func NewCalendarFeedService(
+	db client.DatabaseService,
+	dbLogger *slog.Logger,
+	validator *validator.Validate,
+	calendarFeedRepository repository.CalendarFeedRepository,
+	profileRepository repository.ProfileRepository,
+	workoutRepository repository.WorkoutRepository,
+	programRepository repository.ProgramRepository,
+	programEnrollmentRepository repository.ProgramEnrollmentRepository,
+	workoutTemplateRepository repository.WorkoutTemplateRepository,
+) CalendarFeedService {
+	return &calendarFeedService{
+		DB:                          db,
+		DBLogger:                    dbLogger,
+		Validate:                    validator,
+		CalendarFeedRepository:      calendarFeedRepository,
+		ProfileRepository:           profileRepository,
+		WorkoutRepository:           workoutRepository,
+		ProgramRepository:           programRepository,
+		ProgramEnrollmentRepository: programEnrollmentRepository,
+		WorkoutTemplateRepository:   workoutTemplateRepository,
+	}
+}
+
+func (s *calendarFeedService) GetFeed(w http.ResponseWriter, r *http.Request) (*dto.CalendarFeedResponse, error) {
+	profile, err := getAuthProfile(r, s.ProfileRepository)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrUnAuthorized
+	}
+
+	feed, err := s.CalendarFeedRepository.GetByProfileID(r.Context(), profile.ID)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrInternalServerError
+	}
+
+	if feed == nil {
+		return nil, customError.ErrNotFound
+	}
+
+	return toCalendarFeedResponse(*feed), nil
+}
+
+// CreateFeed gives the caller a new feed URL. An existing URL is revoked, so calendars subscribed
+// to it stop updating.
+func (s *calendarFeedService) CreateFeed(w http.ResponseWriter, r *http.Request) (*dto.CalendarFeedResponse, error) {
+	profile, err := getAuthProfile(r, s.ProfileRepository)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrUnAuthorized
+	}
+
+	token, err := generateRandomToken()
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrInternalServerError
+	}
+
+	feed, err := s.CalendarFeedRepository.Upsert(r.Context(), profile.ID, token)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrInternalServerError
+	}
+
+	return toCalendarFeedResponse(*feed), nil
+}
+
+func (s *calendarFeedService) DeleteFeed(w http.ResponseWriter, r *http.Request) error {
+	profile, err := getAuthProfile(r, s.ProfileRepository)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return customError.ErrUnAuthorized
+	}
+
+	err = s.CalendarFeedRepository.Delete(r.Context(), profile.ID)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return customError.ErrInternalServerError
+	}
+
+	return nil
+}
+
+// ServeFeed writes the calendar of the feed named by the "token" URL parameter: the workouts
+// completed over the last year and the upcoming days of active programs. Calendar clients poll
+// feeds, so the feed's version is worked out from a cheap summary of its content first and
+// unchanged feeds are answered with 304 Not Modified without being built.
+func (s *calendarFeedService) ServeFeed(w http.ResponseWriter, r *http.Request) error {
+	feed, err := s.CalendarFeedRepository.GetByToken(r.Context(), chi.URLParam(r, "token"))
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return customError.ErrInternalServerError
+	}
+
+	if feed == nil {
+		return customError.ErrNotFound
+	}
+
+	profile, err := s.ProfileRepository.GetByID(r.Context(), feed.ProfileID)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return customError.ErrInternalServerError
+	}
+
+	now := time.Now().UTC()
+	today := now.Truncate(24 * time.Hour)
+	from := today.AddDate(0, 0, -calendarFeedHistoryDays)
+
+	state, err := s.CalendarFeedRepository.GetState(r.Context(), profile.ID, from)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return customError.ErrInternalServerError
+	}
+
+	version := calendarFeedVersion(state, profile, today)
+	lastModified := feed.ChangedAt
+	if feed.Version == nil || *feed.Version != version {
+		lastModified = now
+		err = s.CalendarFeedRepository.UpdateVersion(r.Context(), profile.ID, version, lastModified)
+		if err != nil {
+			util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+			return customError.ErrInternalServerError
+		}
+	}
+
+	etag := fmt.Sprintf(`"%s"`, version)
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Last-Modified", lastModified.UTC().Format(http.TimeFormat))
+	w.Header().Set("Cache-Control", "private, no-cache")
+
+	if util.IsNotModified(r, etag, lastModified) {
+		w.WriteHeader(http.StatusNotModified)
+		return nil
+	}
+
+	// The calendar is built in full before it is sent, a client given a truncated feed would drop
+	// the missing events
+	calendar := bytes.Buffer{}
+	err = s.writeFeed(r, &calendar, profile, from, today)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return customError.ErrInternalServerError
+	}
+
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Content-Disposition", `inline; filename="ronin-fitness.ics"`)
+	w.WriteHeader(http.StatusOK)
+	_, err = calendar.WriteTo(w)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+	}
+
+	return nil
+}
+
+func (s *calendarFeedService) writeFeed(r *http.Request, calendar *bytes.Buffer, profile *model.ProfileWithUser, from time.Time, today time.Time) error {
+	err := util.WriteICalHeader(calendar, "Ronin Fitness")
+	if err != nil {
+		return err
+	}
+
+	var workout *model.Workout
+	sets := []model.WorkoutExportRow{}
+	writeWorkout := func() error {
+		if workout == nil {
+			return nil
+		}
+
+		return util.WriteICalEvent(calendar, workoutICalEvent(*workout, sets, profile.WeightUnit))
+	}
+
+	err = s.WorkoutRepository.ExportByProfileID(r.Context(), profile.ID, from, today.AddDate(0, 0, 1), func(row *model.WorkoutExportRow) error {
+		if workout == nil || row.ID != workout.ID {
+			err := writeWorkout()
+			if err != nil {
+				return err
+			}
+
+			workout = &row.Workout
+			sets = sets[:0]
+		}
+
+		if row.Set != nil {
+			sets = append(sets, *row)
+		}
+
+		return nil
+	})
+	if err == nil {
+		err = writeWorkout()
+	}
+	if err != nil {
+		return err
+	}
+
+	enrollments, err := s.ProgramEnrollmentRepository.GetByProfileID(r.Context(), profile.ID)
+	if err != nil {
+		return err
+	}
+
+	templateNames := map[int]string{}
+	for _, enrollment := range enrollments {
+		if enrollment.Status != model.ProgramEnrollmentStatusActive {
+			continue
+		}
+
+		program, err := s.ProgramRepository.GetByID(r.Context(), enrollment.ProgramID)
+		if err != nil {
+			return err
+		}
+
+		if program == nil {
+			continue
+		}
+
+		days, err := s.ProgramRepository.GetDays(r.Context(), program.ID)
+		if err != nil {
+			return err
+		}
+
+		for _, day := range days {
+			if _, ok := templateNames[day.TemplateID]; ok {
+				continue
+			}
+
+			template, err := s.WorkoutTemplateRepository.GetByID(r.Context(), day.TemplateID)
+			if err != nil {
+				return err
+			}
+
+			if template != nil {
+				templateNames[day.TemplateID] = template.Name
+			}
+		}
+
+		for _, event := range programDayICalEvents(enrollment, *program, days, templateNames, today) {
+			err = util.WriteICalEvent(calendar, event)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return util.WriteICalFooter(calendar)
+}
+
+// programDayICalEvents schedules the enrollment's remaining program days as all day events. Week 1
+// of the program is the week it was started in, with weeks starting on Monday. An enrollment that
+// fell behind has its remaining days pushed back by whole weeks so the next one is not in the past.
+func programDayICalEvents(
+	enrollment model.ProgramEnrollment,
+	program model.Program,
+	days []model.ProgramDay,
+	templateNames map[int]string,
+	today time.Time,
+) []util.ICalEvent {
+	if enrollment.CurrentSession >= len(days) {
+		return nil
+	}
+
+	programStart := statsBucketStart(enrollment.StartedAt, statsBucketWeek)
+	dayDate := func(day model.ProgramDay) time.Time {
+		return programStart.AddDate(0, 0, (day.WeekNumber-1)*7+day.DayNumber-1)
+	}
+
+	weeksBehind := 0
+	if next := dayDate(days[enrollment.CurrentSession]); next.Before(today) {
+		weeksBehind = (int(today.Sub(next).Hours()/24) + 6) / 7
+	}
+
+	lastModified := enrollment.UpdatedAt
+	if program.UpdatedAt.After(lastModified) {
+		lastModified = program.UpdatedAt
+	}
+
+	events := make([]util.ICalEvent, 0, len(days)-enrollment.CurrentSession)
+	for _, day := range days[enrollment.CurrentSession:] {
+		start := dayDate(day).AddDate(0, 0, weeksBehind*7)
+
+		summary := program.Name
+		if name, ok := templateNames[day.TemplateID]; ok {
+			summary = fmt.Sprintf("%s: %s", program.Name, name)
+		}
+
+		events = append(events, util.ICalEvent{
+			UID:          fmt.Sprintf("program-enrollment-%d-day-%d@ronin-fitness", enrollment.ID, day.ID),
+			Start:        start,
+			End:          start.AddDate(0, 0, 1),
+			AllDay:       true,
+			Summary:      summary,
+			Description:  fmt.Sprintf("Week %d, day %d of %s", day.WeekNumber, day.DayNumber, program.Name),
+			LastModified: lastModified,
+		})
+	}
+
+	return events
+}
+
+// calendarFeedVersion fingerprints the feed's content. Upcoming program days move with the
+// current date, so the date is part of the version while the profile has programs.
+func calendarFeedVersion(state *model.CalendarFeedState, profile *model.ProfileWithUser, today time.Time) string {
+	formatTime := func(t *time.Time) string {
+		if t == nil {
+			return ""
+		}
+
+		return t.UTC().Format(time.RFC3339)
+	}
+
+	parts := []string{
+		profile.WeightUnit,
+		fmt.Sprint(state.WorkoutCount),
+		formatTime(state.WorkoutsUpdatedAt),
+		fmt.Sprint(state.EnrollmentCount),
+		formatTime(state.EnrollmentsUpdatedAt),
+		formatTime(state.TemplatesUpdatedAt),
+	}
+	if state.EnrollmentCount > 0 {
+		parts = append(parts, today.Format("2006-01-02"))
+	}
+
+	return fmt.Sprintf("%x", sha256.Sum256([]byte(strings.Join(parts, "|"))))
+}
+
+func toCalendarFeedResponse(feed model.CalendarFeed) *dto.CalendarFeedResponse {
+	return &dto.CalendarFeedResponse{
+		Token:     feed.Token,
+		URL:       fmt.Sprintf("%s/ical/%s.ics", strings.TrimRight(os.Getenv("APP_URL"), "/"), feed.Token),
+		CreatedAt: feed.CreatedAt,
+	}
+}
@@ -0,0 +1,335 @@
+package service
+
+import (
+	"crypto/sha256"
+	"database/sql"
+	"fmt"
+	"io"
+	"log/slog"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/brightside-dev/ronin-fitness-be/database/client"
+	"github.com/brightside-dev/ronin-fitness-be/internal/cardio"
+	"github.com/brightside-dev/ronin-fitness-be/internal/handler/dto"
+	customError "github.com/brightside-dev/ronin-fitness-be/internal/handler/error"
+	"github.com/brightside-dev/ronin-fitness-be/internal/model"
+	"github.com/brightside-dev/ronin-fitness-be/internal/repository"
+	"github.com/brightside-dev/ronin-fitness-be/internal/util"
+	"github.com/go-playground/validator/v10"
+)
+
+const (
+	maxCardioFileSize = 25 << 20
+	// maxTrackPoints bounds the stored track, enough to draw any activity on a phone sized map
+	maxTrackPoints = 2000
+)
+
+var cardioWorkoutNames = map[string]string{
+	cardio.ActivityRun:   "Run",
+	cardio.ActivityRide:  "Ride",
+	cardio.ActivityWalk:  "Walk",
+	cardio.ActivityHike:  "Hike",
+	cardio.ActivitySwim:  "Swim",
+	cardio.ActivityRow:   "Row",
+	cardio.ActivityOther: "Cardio",
+}
+
+type CardioService interface {
+	UploadCardio(w http.ResponseWriter, r *http.Request) (*dto.CardioUploadResponse, error)
+	GetCardioTrack(w http.ResponseWriter, r *http.Request) (*dto.CardioTrackResponse, error)
+}
+
+type cardioService struct {
+	DB                      client.DatabaseService
+	DBLogger                *slog.Logger
+	Validate                *validator.Validate
+	CardioSessionRepository repository.CardioSessionRepository
+	WorkoutRepository       repository.WorkoutRepository
+	ProfileRepository       repository.ProfileRepository
+	ProfileFollowRepository repository.ProfileFollowRepository
+	ProfileBlockRepository  repository.ProfileBlockRepository
+	WorkoutService          WorkoutService
+	GoalService             GoalService
+	ExperiencePointService  ExperiencePointService
+	AchievementService      AchievementService
+}
+
+func NewCardioService(
+	db client.DatabaseService,
+	dbLogger *slog.Logger,
+	validator *validator.Validate,
+	cardioSessionRepository repository.CardioSessionRepository,
+	workoutRepository repository.WorkoutRepository,
+	profileRepository repository.ProfileRepository,
+	profileFollowRepository repository.ProfileFollowRepository,
+	profileBlockRepository repository.ProfileBlockRepository,
+	workoutService WorkoutService,
+	goalService GoalService,
+	experiencePointService ExperiencePointService,
+	achievementService AchievementService,
+) CardioService {
+	return &cardioService{
+		DB:                      db,
+		DBLogger:                dbLogger,
+		Validate:                validator,
+		CardioSessionRepository: cardioSessionRepository,
+		WorkoutRepository:       workoutRepository,
+		ProfileRepository:       profileRepository,
+		ProfileFollowRepository: profileFollowRepository,
+		ProfileBlockRepository:  profileBlockRepository,
+		WorkoutService:          workoutService,
+		GoalService:             goalService,
+		ExperiencePointService:  experiencePointService,
+		AchievementService:      achievementService,
+	}
+}
+
+// UploadCardio creates a workout from a FIT, TCX or GPX file uploaded as the "file" form field.
+// The same file can only be uploaded once.
+func (s *cardioService) UploadCardio(w http.ResponseWriter, r *http.Request) (*dto.CardioUploadResponse, error) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxCardioFileSize)
+	err := r.ParseMultipartForm(maxCardioFileSize)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, fmt.Errorf("file must be uploaded as multipart form data of at most %dMB", maxCardioFileSize>>20)
+	}
+
+	req := dto.CardioUploadRequest{
+		Name:         r.FormValue("name"),
+		ActivityType: r.FormValue("activity_type"),
+		Visibility:   r.FormValue("visibility"),
+	}
+	for name, value := range map[string]*int{
+		"mental_energy_level":   &req.MentalEnergyLevel,
+		"physical_energy_level": &req.PhysicalEnergyLevel,
+	} {
+		if r.FormValue(name) == "" {
+			continue
+		}
+
+		*value, err = strconv.Atoi(r.FormValue(name))
+		if err != nil {
+			return nil, fmt.Errorf("%s must be a valid integer", name)
+		}
+	}
+
+	err = s.Validate.Struct(req)
+	if err != nil {
+		return nil, util.FormatValidationError(err.(validator.ValidationErrors))
+	}
+
+	file, _, err := r.FormFile("file")
+	if err != nil {
+		return nil, fmt.Errorf("missing file")
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrInvalidRequestBody
+	}
+
+	activity, err := cardio.Parse(data)
+	if err != nil {
+		return nil, err
+	}
+
+	profile, err := getAuthProfile(r, s.ProfileRepository)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrUnAuthorized
+	}
+
+	// The file hash doubles as the workout's import key, which stops the same file being saved twice
+	importKey := fmt.Sprintf("%x", sha256.Sum256(data))
+	existing, err := s.WorkoutRepository.GetExistingImportKeys(r.Context(), profile.ID, []string{importKey})
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrInternalServerError
+	}
+
+	if existing[importKey] {
+		return nil, fmt.Errorf("%w: this activity file was already uploaded", customError.ErrConflict)
+	}
+
+	if req.ActivityType != "" {
+		activity.ActivityType = req.ActivityType
+	}
+
+	workout := &model.Workout{
+		Name:                req.Name,
+		Visibility:          req.Visibility,
+		MentalEnergyLevel:   req.MentalEnergyLevel,
+		PhysicalEnergyLevel: req.PhysicalEnergyLevel,
+		StartDate:           activity.StartTime,
+		EndDate:             activity.EndTime,
+		ImportKey:           &importKey,
+	}
+	if workout.Name == "" {
+		workout.Name = activity.Name
+	}
+	if workout.Name == "" {
+		workout.Name = cardioWorkoutNames[activity.ActivityType]
+	}
+	if workout.MentalEnergyLevel == 0 {
+		workout.MentalEnergyLevel = defaultEnergyLevel
+	}
+	if workout.PhysicalEnergyLevel == 0 {
+		workout.PhysicalEnergyLevel = defaultEnergyLevel
+	}
+
+	session := toCardioSession(activity)
+	track := cardio.SimplifyTrack(activity.Points, maxTrackPoints)
+
+	_, err = util.WithTransaction(r.Context(), s.DB.GetDB(), func(tx *sql.Tx) (interface{}, error) {
+		_, err := s.WorkoutService.SaveWorkout(r.Context(), tx, profile, workout, []model.Set{})
+		if err != nil {
+			return nil, err
+		}
+
+		err = s.ExperiencePointService.AwardWorkout(r.Context(), tx, workout, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		session.WorkoutID = workout.ID
+		_, err = s.CardioSessionRepository.Create(r.Context(), tx, session)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(track) < 2 {
+			return nil, nil
+		}
+
+		return nil, s.CardioSessionRepository.CreateTrack(r.Context(), tx, &model.CardioTrack{
+			CardioSessionID:    session.ID,
+			Coordinates:        trackCoordinates(track),
+			OriginalPointCount: len(activity.Points),
+		})
+	})
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrInternalServerError
+	}
+
+	session.HasTrack = len(track) >= 2
+
+	err = s.GoalService.EvaluateGoals(r.Context(), profile.ID)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+	}
+
+	err = s.AchievementService.EvaluateAchievements(r.Context(), profile.ID, model.AchievementEventWorkout)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+	}
+
+	return &dto.CardioUploadResponse{
+		WorkoutID: workout.ID,
+		Name:      workout.Name,
+		StartDate: workout.StartDate,
+		EndDate:   workout.EndDate,
+		Cardio:    toCardioSessionResponse(*session),
+	}, nil
+}
+
+// GetCardioTrack returns the simplified track of a workout as a GeoJSON Feature.
+func (s *cardioService) GetCardioTrack(w http.ResponseWriter, r *http.Request) (*dto.CardioTrackResponse, error) {
+	_, workout, err := getViewableWorkout(r, s.DBLogger, s.WorkoutRepository, s.ProfileRepository, s.ProfileFollowRepository, s.ProfileBlockRepository)
+	if err != nil {
+		return nil, err
+	}
+
+	track, err := s.CardioSessionRepository.GetTrackByWorkoutID(r.Context(), workout.ID)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrInternalServerError
+	}
+
+	if track == nil {
+		return nil, customError.ErrNotFound
+	}
+
+	sessions, err := s.CardioSessionRepository.GetByWorkoutIDs(r.Context(), []int{workout.ID})
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrInternalServerError
+	}
+
+	return &dto.CardioTrackResponse{
+		Type: "Feature",
+		Geometry: dto.CardioTrackGeometryResponse{
+			Type:        "LineString",
+			Coordinates: track.Coordinates,
+		},
+		Properties: dto.CardioTrackPropertiesResponse{
+			WorkoutID:          workout.ID,
+			ActivityType:       sessions[workout.ID].ActivityType,
+			PointCount:         len(track.Coordinates),
+			OriginalPointCount: track.OriginalPointCount,
+		},
+	}, nil
+}
+
+func toCardioSession(activity *cardio.Activity) *model.CardioSession {
+	session := &model.CardioSession{
+		ActivityType:    activity.ActivityType,
+		SourceFormat:    activity.Format,
+		DistanceM:       math.Round(activity.DistanceM*100) / 100,
+		DurationSeconds: activity.DurationSeconds,
+	}
+
+	// Pace over a few metres is meaningless, e.g. for indoor activities without distance
+	if activity.DistanceM >= 10 && activity.DurationSeconds > 0 {
+		pace := math.Round(float64(activity.DurationSeconds)/(activity.DistanceM/1000)*100) / 100
+		session.PaceSecondsPerKm = &pace
+	}
+
+	if activity.HasElevation {
+		gain := math.Round(activity.ElevationGainM*100) / 100
+		loss := math.Round(activity.ElevationLossM*100) / 100
+		session.ElevationGainM, session.ElevationLossM = &gain, &loss
+	}
+
+	if activity.AvgHeartRate > 0 {
+		session.AvgHeartRate = &activity.AvgHeartRate
+	}
+	if activity.MaxHeartRate > 0 {
+		session.MaxHeartRate = &activity.MaxHeartRate
+	}
+
+	return session
+}
+
+// trackCoordinates converts points to GeoJSON positions, rounded to about 10cm.
+func trackCoordinates(points []cardio.Point) [][]float64 {
+	coordinates := make([][]float64, 0, len(points))
+	for _, point := range points {
+		position := []float64{math.Round(point.Lon*1e6) / 1e6, math.Round(point.Lat*1e6) / 1e6}
+		if point.HasElevation {
+			position = append(position, math.Round(point.Elevation*10)/10)
+		}
+		coordinates = append(coordinates, position)
+	}
+
+	return coordinates
+}
+
+func toCardioSessionResponse(session model.CardioSession) dto.CardioSessionResponse {
+	return dto.CardioSessionResponse{
+		ActivityType:     session.ActivityType,
+		SourceFormat:     session.SourceFormat,
+		DistanceM:        session.DistanceM,
+		DurationSeconds:  session.DurationSeconds,
+		PaceSecondsPerKm: session.PaceSecondsPerKm,
+		ElevationGainM:   session.ElevationGainM,
+		ElevationLossM:   session.ElevationLossM,
+		AvgHeartRate:     session.AvgHeartRate,
+		MaxHeartRate:     session.MaxHeartRate,
+		HasTrack:         session.HasTrack,
+	}
+}
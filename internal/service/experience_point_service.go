@@ -0,0 +1,390 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"math"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/brightside-dev/ronin-fitness-be/database/client"
+	"github.com/brightside-dev/ronin-fitness-be/internal/handler/dto"
+	customError "github.com/brightside-dev/ronin-fitness-be/internal/handler/error"
+	"github.com/brightside-dev/ronin-fitness-be/internal/model"
+	"github.com/brightside-dev/ronin-fitness-be/internal/repository"
+	"github.com/brightside-dev/ronin-fitness-be/internal/util"
+)
+
+// The points of each event and the level curve apply when the matching XP_* environment variable is
+// not set to a valid value. Setting an event's points to 0 stops it being awarded.
+const (
+	defaultWorkoutCompletedExperiencePoints  = 50
+	defaultPersonalRecordSetExperiencePoints = 25
+	defaultStreakKeptExperiencePoints        = 20
+	defaultGoalReachedExperiencePoints       = 100
+	defaultLevelBase                         = 100
+	defaultLevelGrowth                       = 1.5
+	// recalculateBatchSize is how many profiles RecalculateAll loads at a time
+	recalculateBatchSize = 100
+)
+
+var personalRecordNames = map[string]string{
+	model.PersonalRecordHeaviestWeight: "heaviest weight",
+	model.PersonalRecordBestE1RM:       "best estimated 1RM",
+	model.PersonalRecordMostReps:       "most reps",
+	model.PersonalRecordBestVolume:     "best volume",
+}
+
+type ExperiencePointService interface {
+	Award(ctx context.Context, tx *sql.Tx, profileID int, eventType string, source string, reason string) (int, error)
+	AwardWorkout(ctx context.Context, tx *sql.Tx, workout *model.Workout, personalRecords []model.PersonalRecord) error
+	RevokeWorkout(ctx context.Context, tx *sql.Tx, workout *model.Workout) error
+	Recalculate(ctx context.Context, profileID int) (int, int, error)
+	RecalculateAll(ctx context.Context) (int, error)
+	GetMyExperiencePoints(w http.ResponseWriter, r *http.Request) (*dto.ExperiencePointsResponse, error)
+}
+
+type experiencePointService struct {
+	DB                        client.DatabaseService
+	DBLogger                  *slog.Logger
+	ExperiencePointRepository repository.ExperiencePointRepository
+	ProfileRepository         repository.ProfileRepository
+	ProfileStatsRepository    repository.ProfileStatsRepository
+	EventPoints               map[string]int
+	LevelCurve                levelCurve
+}
+
+func NewExperiencePointService(
+	db client.DatabaseService,
+	dbLogger *slog.Logger,
+	experiencePointRepository repository.ExperiencePointRepository,
+	profileRepository repository.ProfileRepository,
+	profileStatsRepository repository.ProfileStatsRepository,
+) ExperiencePointService {
+	levelCurve := levelCurve{Base: defaultLevelBase, Growth: defaultLevelGrowth}
+	if base, err := strconv.ParseFloat(os.Getenv("XP_LEVEL_BASE"), 64); err == nil && base >= 1 {
+		levelCurve.Base = base
+	}
+	if growth, err := strconv.ParseFloat(os.Getenv("XP_LEVEL_GROWTH"), 64); err == nil && growth >= 0 {
+		levelCurve.Growth = growth
+	}
+
+	return &experiencePointService{
+		DB:                        db,
+		DBLogger:                  dbLogger,
+		ExperiencePointRepository: experiencePointRepository,
+		ProfileRepository:         profileRepository,
+		ProfileStatsRepository:    profileStatsRepository,
+		EventPoints: map[string]int{
+			model.ExperiencePointEventWorkoutCompleted:  experiencePointsSetting("XP_WORKOUT_COMPLETED", defaultWorkoutCompletedExperiencePoints),
+			model.ExperiencePointEventPersonalRecordSet: experiencePointsSetting("XP_PERSONAL_RECORD_SET", defaultPersonalRecordSetExperiencePoints),
+			model.ExperiencePointEventStreakKept:        experiencePointsSetting("XP_STREAK_KEPT", defaultStreakKeptExperiencePoints),
+			model.ExperiencePointEventGoalReached:       experiencePointsSetting("XP_GOAL_REACHED", defaultGoalReachedExperiencePoints),
+		},
+		LevelCurve: levelCurve,
+	}
+}
+
+// Award records the points of the event in the ledger and adds them to the profile's total and
+// level. The source identifies the event, e.g. "workout:42"; an event that was already rewarded
+// is skipped, so callers can award again safely. It returns the points awarded.
+func (s *experiencePointService) Award(ctx context.Context, tx *sql.Tx, profileID int, eventType string, source string, reason string) (int, error) {
+	points := s.EventPoints[eventType]
+	if points == 0 {
+		return 0, nil
+	}
+
+	// The profile is locked before the ledger is touched so concurrent awards queue up on it
+	total, err := s.ProfileRepository.GetExperiencePointsForUpdate(ctx, tx, profileID)
+	if err != nil {
+		return 0, err
+	}
+
+	created, err := s.ExperiencePointRepository.Create(ctx, tx, &model.ExperiencePointEntry{
+		ProfileID: profileID,
+		EventType: eventType,
+		Source:    source,
+		Reason:    reason,
+		Points:    points,
+	})
+	if err != nil || !created {
+		return 0, err
+	}
+
+	return points, s.setExperiencePoints(ctx, tx, profileID, total+points)
+}
+
+// AwardWorkout rewards a workout the profile just completed, each personal record set in it and,
+// when the profile also trained the day before, keeping a streak going. Workouts imported from other
+// apps are history rather than training done now, so callers leave them out.
+func (s *experiencePointService) AwardWorkout(ctx context.Context, tx *sql.Tx, workout *model.Workout, personalRecords []model.PersonalRecord) error {
+	_, err := s.Award(
+		ctx, tx, workout.ProfileID,
+		model.ExperiencePointEventWorkoutCompleted,
+		fmt.Sprintf("workout:%d", workout.ID),
+		fmt.Sprintf("Completed %s", workout.Name),
+	)
+	if err != nil {
+		return err
+	}
+
+	for _, personalRecord := range personalRecords {
+		_, err := s.Award(
+			ctx, tx, workout.ProfileID,
+			model.ExperiencePointEventPersonalRecordSet,
+			fmt.Sprintf("personal_record:%d", personalRecord.ID),
+			fmt.Sprintf("New %s personal record", personalRecordNames[personalRecord.RecordType]),
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	day := utcDay(workout.StartDate)
+	if !keepsStreakGoing(day, time.Now()) {
+		return nil
+	}
+	previousDay := day.AddDate(0, 0, -1)
+
+	workoutDates, err := s.ProfileStatsRepository.GetWorkoutDates(ctx, workout.ProfileID, previousDay, 1)
+	if err != nil {
+		return err
+	}
+
+	if len(workoutDates) == 0 || !workoutDates[0].Equal(previousDay) {
+		return nil
+	}
+
+	// Any number of workouts on a day keep the streak going once, and a day's streak is only ever
+	// awarded once, even if it was revoked with a deleted workout
+	_, err = s.Award(
+		ctx, tx, workout.ProfileID,
+		model.ExperiencePointEventStreakKept,
+		fmt.Sprintf("streak:%s", day.Format("2006-01-02")),
+		fmt.Sprintf("Trained on consecutive days on %s", day.Format("2006-01-02")),
+	)
+
+	return err
+}
+
+// RevokeWorkout offsets what the workout earned before it is deleted: the workout itself, the
+// personal records set in it and, when it was the day's only workout, the streaks of that day and of
+// the next, which the day kept going. The awards stay in the ledger next to the negative entries.
+func (s *experiencePointService) RevokeWorkout(ctx context.Context, tx *sql.Tx, workout *model.Workout) error {
+	total, err := s.ProfileRepository.GetExperiencePointsForUpdate(ctx, tx, workout.ProfileID)
+	if err != nil {
+		return err
+	}
+
+	entries, err := s.ExperiencePointRepository.GetWorkoutEntries(ctx, tx, workout.ProfileID, workout.ID)
+	if err != nil {
+		return err
+	}
+
+	// The day's stats still count the workout
+	day := utcDay(workout.StartDate)
+	workoutCount, err := s.ProfileStatsRepository.GetWorkoutCount(ctx, tx, workout.ProfileID, day)
+	if err != nil {
+		return err
+	}
+
+	if workoutCount <= 1 {
+		streakEntries, err := s.ExperiencePointRepository.GetBySources(ctx, tx, workout.ProfileID, []string{
+			fmt.Sprintf("streak:%s", day.Format("2006-01-02")),
+			fmt.Sprintf("streak:%s", day.AddDate(0, 0, 1).Format("2006-01-02")),
+		})
+		if err != nil {
+			return err
+		}
+		entries = append(entries, streakEntries...)
+	}
+
+	revoked := 0
+	for _, entry := range entries {
+		if entry.Points <= 0 {
+			continue
+		}
+
+		created, err := s.ExperiencePointRepository.Create(ctx, tx, &model.ExperiencePointEntry{
+			ProfileID: workout.ProfileID,
+			EventType: entry.EventType,
+			Source:    "revoke:" + entry.Source,
+			Reason:    "Reversed: " + entry.Reason,
+			Points:    -entry.Points,
+		})
+		if err != nil {
+			return err
+		}
+
+		if created {
+			revoked += entry.Points
+		}
+	}
+
+	if revoked == 0 {
+		return nil
+	}
+
+	return s.setExperiencePoints(ctx, tx, workout.ProfileID, max(total-revoked, 0))
+}
+
+// Recalculate resets the profile's total to the sum of its ledger and its level to the one the
+// current curve gives that total. It returns the total and level.
+func (s *experiencePointService) Recalculate(ctx context.Context, profileID int) (int, int, error) {
+	var total, level int
+	_, err := util.WithTransaction(ctx, s.DB.GetDB(), func(tx *sql.Tx) (interface{}, error) {
+		_, err := s.ProfileRepository.GetExperiencePointsForUpdate(ctx, tx, profileID)
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("profile %d not found", profileID)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		total, err = s.ExperiencePointRepository.GetTotal(ctx, tx, profileID)
+		if err != nil {
+			return nil, err
+		}
+
+		level, _, _ = s.LevelCurve.level(total)
+
+		return nil, s.setExperiencePoints(ctx, tx, profileID, total)
+	})
+
+	return total, level, err
+}
+
+// RecalculateAll recalculates every profile, e.g. after the level curve changed, and returns how many
+// were recalculated.
+func (s *experiencePointService) RecalculateAll(ctx context.Context) (int, error) {
+	recalculated := 0
+	afterID := 0
+	for {
+		profileIDs, err := s.ProfileRepository.GetIDs(ctx, afterID, recalculateBatchSize)
+		if err != nil {
+			return recalculated, err
+		}
+
+		for _, profileID := range profileIDs {
+			_, _, err := s.Recalculate(ctx, profileID)
+			if err != nil {
+				return recalculated, err
+			}
+			recalculated++
+		}
+
+		if len(profileIDs) < recalculateBatchSize {
+			return recalculated, nil
+		}
+		afterID = profileIDs[len(profileIDs)-1]
+	}
+}
+
+// GetMyExperiencePoints returns the caller's total and progress towards the next level with the
+// ledger entries behind it, newest first.
+func (s *experiencePointService) GetMyExperiencePoints(w http.ResponseWriter, r *http.Request) (*dto.ExperiencePointsResponse, error) {
+	beforeTime, beforeID, err := util.DecodeCursor(r.URL.Query().Get("cursor"))
+	if err != nil {
+		return nil, customError.ErrInvalidCursor
+	}
+
+	profile, err := getAuthProfile(r, s.ProfileRepository)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrUnAuthorized
+	}
+
+	limit := util.GetPageLimit(r, 20, 50)
+	entries, err := s.ExperiencePointRepository.GetByProfileID(r.Context(), profile.ID, beforeTime, beforeID, limit)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrInternalServerError
+	}
+
+	// The stored level is what the profile shows everywhere else, the curve only places it within it
+	_, levelPoints, nextLevelPoints := s.LevelCurve.level(profile.ExperiencePoints)
+
+	res := &dto.ExperiencePointsResponse{
+		ExperiencePoints:          profile.ExperiencePoints,
+		Level:                     profile.Level,
+		LevelExperiencePoints:     levelPoints,
+		NextLevelExperiencePoints: nextLevelPoints,
+		Entries:                   make([]dto.ExperiencePointEntryResponse, 0, len(entries)),
+	}
+	for _, entry := range entries {
+		res.Entries = append(res.Entries, dto.ExperiencePointEntryResponse{
+			ID:        entry.ID,
+			EventType: entry.EventType,
+			Source:    entry.Source,
+			Reason:    entry.Reason,
+			Points:    entry.Points,
+			CreatedAt: entry.CreatedAt,
+		})
+	}
+
+	if len(entries) == limit {
+		last := entries[len(entries)-1]
+		res.NextCursor = util.EncodeCursor(last.CreatedAt, last.ID)
+	}
+
+	return res, nil
+}
+
+// setExperiencePoints stores the profile's total with the level the curve gives it. The profile must
+// be locked in the transaction.
+func (s *experiencePointService) setExperiencePoints(ctx context.Context, tx *sql.Tx, profileID int, total int) error {
+	level, _, _ := s.LevelCurve.level(total)
+
+	return s.ProfileRepository.SetExperiencePoints(ctx, tx, profileID, total, level)
+}
+
+// keepsStreakGoing reports whether training on day can still keep a streak going at now. Backdated
+// workouts fill in the history rather than keep a streak going, only training today or yesterday
+// does. Days are in UTC, as for the stats streaks.
+func keepsStreakGoing(day time.Time, now time.Time) bool {
+	today := utcDay(now)
+
+	return !day.After(today) && !day.Before(today.AddDate(0, 0, -1))
+}
+
+// utcDay is the UTC day the time falls on.
+func utcDay(t time.Time) time.Time {
+	t = t.UTC()
+
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// levelCurve turns a total of experience points into a level. Going from level n to n+1 takes
+// round(Base * n^Growth) points, so with a Growth above 0 every level takes longer than the last.
+type levelCurve struct {
+	Base   float64
+	Growth float64
+}
+
+// level returns the level the points reach with the totals that level and the next one start at.
+func (c levelCurve) level(points int) (int, int, int) {
+	level, start := 1, 0
+	for {
+		next := start + max(int(math.Round(c.Base*math.Pow(float64(level), c.Growth))), 1)
+		if points < next {
+			return level, start, next
+		}
+
+		level, start = level+1, next
+	}
+}
+
+// experiencePointsSetting reads the points of an event from the environment variable, falling back
+// to def when it is not a non-negative integer.
+func experiencePointsSetting(name string, def int) int {
+	points, err := strconv.Atoi(os.Getenv(name))
+	if err != nil || points < 0 {
+		return def
+	}
+
+	return points
+}
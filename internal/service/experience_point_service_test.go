@@ -0,0 +1,250 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/brightside-dev/ronin-fitness-be/internal/model"
+	"github.com/brightside-dev/ronin-fitness-be/internal/repository"
+)
+
+func TestLevelCurve(t *testing.T) {
+	tests := []struct {
+		name      string
+		curve     levelCurve
+		points    int
+		wantLevel int
+		wantStart int
+		wantNext  int
+	}{
+		{name: "no points", curve: levelCurve{Base: 100, Growth: 1.5}, points: 0, wantLevel: 1, wantStart: 0, wantNext: 100},
+		{name: "one short of level 2", curve: levelCurve{Base: 100, Growth: 1.5}, points: 99, wantLevel: 1, wantStart: 0, wantNext: 100},
+		{name: "exactly level 2", curve: levelCurve{Base: 100, Growth: 1.5}, points: 100, wantLevel: 2, wantStart: 100, wantNext: 383},
+		{name: "one short of level 3", curve: levelCurve{Base: 100, Growth: 1.5}, points: 382, wantLevel: 2, wantStart: 100, wantNext: 383},
+		{name: "exactly level 3", curve: levelCurve{Base: 100, Growth: 1.5}, points: 383, wantLevel: 3, wantStart: 383, wantNext: 903},
+		{name: "flat curve", curve: levelCurve{Base: 100, Growth: 0}, points: 250, wantLevel: 3, wantStart: 200, wantNext: 300},
+		{name: "every point a level", curve: levelCurve{Base: 1, Growth: 0}, points: 5, wantLevel: 6, wantStart: 5, wantNext: 6},
+		{name: "levels take at least a point", curve: levelCurve{Base: 0.1, Growth: 0}, points: 5, wantLevel: 6, wantStart: 5, wantNext: 6},
+		{name: "negative total", curve: levelCurve{Base: 100, Growth: 1.5}, points: -20, wantLevel: 1, wantStart: 0, wantNext: 100},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			level, start, next := tt.curve.level(tt.points)
+			if level != tt.wantLevel || start != tt.wantStart || next != tt.wantNext {
+				t.Errorf("level(%d) = %d, %d, %d, want %d, %d, %d", tt.points, level, start, next, tt.wantLevel, tt.wantStart, tt.wantNext)
+			}
+		})
+	}
+}
+
+func TestNewExperiencePointServiceSettings(t *testing.T) {
+	tests := []struct {
+		name          string
+		base          string
+		growth        string
+		workoutPoints string
+		wantCurve     levelCurve
+		wantPoints    int
+	}{
+		{name: "defaults", wantCurve: levelCurve{Base: defaultLevelBase, Growth: defaultLevelGrowth}, wantPoints: defaultWorkoutCompletedExperiencePoints},
+		{name: "overridden", base: "50", growth: "0", workoutPoints: "0", wantCurve: levelCurve{Base: 50, Growth: 0}, wantPoints: 0},
+		{name: "invalid values", base: "0.5", growth: "-1", workoutPoints: "-10", wantCurve: levelCurve{Base: defaultLevelBase, Growth: defaultLevelGrowth}, wantPoints: defaultWorkoutCompletedExperiencePoints},
+		{name: "not numbers", base: "high", growth: "steep", workoutPoints: "lots", wantCurve: levelCurve{Base: defaultLevelBase, Growth: defaultLevelGrowth}, wantPoints: defaultWorkoutCompletedExperiencePoints},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("XP_LEVEL_BASE", tt.base)
+			t.Setenv("XP_LEVEL_GROWTH", tt.growth)
+			t.Setenv("XP_WORKOUT_COMPLETED", tt.workoutPoints)
+
+			s := NewExperiencePointService(nil, nil, nil, nil, nil).(*experiencePointService)
+			if s.LevelCurve != tt.wantCurve {
+				t.Errorf("LevelCurve = %+v, want %+v", s.LevelCurve, tt.wantCurve)
+			}
+			if got := s.EventPoints[model.ExperiencePointEventWorkoutCompleted]; got != tt.wantPoints {
+				t.Errorf("workout completed points = %d, want %d", got, tt.wantPoints)
+			}
+		})
+	}
+}
+
+func TestKeepsStreakGoing(t *testing.T) {
+	now := time.Date(2024, 3, 13, 0, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		day  time.Time
+		want bool
+	}{
+		{name: "today", day: utcDate(2024, 3, 13), want: true},
+		{name: "yesterday", day: utcDate(2024, 3, 12), want: true},
+		{name: "two days ago", day: utcDate(2024, 3, 11), want: false},
+		{name: "last year", day: utcDate(2023, 3, 13), want: false},
+		{name: "tomorrow", day: utcDate(2024, 3, 14), want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := keepsStreakGoing(tt.day, now); got != tt.want {
+				t.Errorf("keepsStreakGoing(%s) = %v, want %v", tt.day.Format("2006-01-02"), got, tt.want)
+			}
+		})
+	}
+
+	// Late in the evening west of UTC, the next UTC day has already started
+	evening := time.Date(2024, 3, 12, 20, 0, 0, 0, time.FixedZone("EDT", -4*3600))
+	if !keepsStreakGoing(utcDate(2024, 3, 13), evening) {
+		t.Errorf("keepsStreakGoing() is false for the UTC day of the time")
+	}
+}
+
+type fakeExperiencePointRepository struct {
+	repository.ExperiencePointRepository
+	entries []model.ExperiencePointEntry
+}
+
+func (f *fakeExperiencePointRepository) Create(ctx context.Context, tx *sql.Tx, entry *model.ExperiencePointEntry) (bool, error) {
+	for _, existing := range f.entries {
+		if existing.ProfileID == entry.ProfileID && existing.Source == entry.Source {
+			return false, nil
+		}
+	}
+	f.entries = append(f.entries, *entry)
+
+	return true, nil
+}
+
+type fakeExperiencePointProfileRepository struct {
+	repository.ProfileRepository
+	points int
+	level  int
+}
+
+func (f *fakeExperiencePointProfileRepository) GetExperiencePointsForUpdate(ctx context.Context, tx *sql.Tx, profileID int) (int, error) {
+	return f.points, nil
+}
+
+func (f *fakeExperiencePointProfileRepository) SetExperiencePoints(ctx context.Context, tx *sql.Tx, profileID int, points int, level int) error {
+	f.points, f.level = points, level
+	return nil
+}
+
+type fakeWorkoutDatesRepository struct {
+	repository.ProfileStatsRepository
+	workoutDates []time.Time // most recent first
+}
+
+func (f *fakeWorkoutDatesRepository) GetWorkoutDates(ctx context.Context, profileID int, to time.Time, limit int) ([]time.Time, error) {
+	dates := []time.Time{}
+	for _, date := range f.workoutDates {
+		if !date.After(to) && len(dates) < limit {
+			dates = append(dates, date)
+		}
+	}
+
+	return dates, nil
+}
+
+func TestAwardWorkout(t *testing.T) {
+	today := utcDay(time.Now())
+	daysAgo := func(days int) time.Time {
+		return today.AddDate(0, 0, -days)
+	}
+
+	tests := []struct {
+		name            string
+		start           time.Time
+		workoutDates    []time.Time
+		personalRecords []model.PersonalRecord
+		wantSources     []string
+		wantPoints      int
+	}{
+		{
+			name:         "today after training yesterday",
+			start:        today.Add(7 * time.Hour),
+			workoutDates: []time.Time{daysAgo(1), daysAgo(2)},
+			wantSources:  []string{"workout:7", "streak:" + today.Format("2006-01-02")},
+			wantPoints:   70,
+		},
+		{
+			name:         "yesterday's workout saved today",
+			start:        daysAgo(1).Add(20 * time.Hour),
+			workoutDates: []time.Time{daysAgo(2)},
+			wantSources:  []string{"workout:7", "streak:" + daysAgo(1).Format("2006-01-02")},
+			wantPoints:   70,
+		},
+		{
+			name:         "today after a day off",
+			start:        today.Add(7 * time.Hour),
+			workoutDates: []time.Time{daysAgo(2)},
+			wantSources:  []string{"workout:7"},
+			wantPoints:   50,
+		},
+		{
+			name:         "backdated after training the day before it",
+			start:        daysAgo(3).Add(7 * time.Hour),
+			workoutDates: []time.Time{daysAgo(4)},
+			wantSources:  []string{"workout:7"},
+			wantPoints:   50,
+		},
+		{
+			name:         "dated tomorrow",
+			start:        daysAgo(-1).Add(7 * time.Hour),
+			workoutDates: []time.Time{today},
+			wantSources:  []string{"workout:7"},
+			wantPoints:   50,
+		},
+		{
+			name:            "with personal records",
+			start:           daysAgo(5),
+			personalRecords: []model.PersonalRecord{{ID: 3, RecordType: model.PersonalRecordBestE1RM}, {ID: 4, RecordType: model.PersonalRecordMostReps}},
+			wantSources:     []string{"workout:7", "personal_record:3", "personal_record:4"},
+			wantPoints:      100,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			experiencePoints := &fakeExperiencePointRepository{}
+			profiles := &fakeExperiencePointProfileRepository{}
+			s := &experiencePointService{
+				ExperiencePointRepository: experiencePoints,
+				ProfileRepository:         profiles,
+				ProfileStatsRepository:    &fakeWorkoutDatesRepository{workoutDates: tt.workoutDates},
+				EventPoints: map[string]int{
+					model.ExperiencePointEventWorkoutCompleted:  50,
+					model.ExperiencePointEventPersonalRecordSet: 25,
+					model.ExperiencePointEventStreakKept:        20,
+				},
+				LevelCurve: levelCurve{Base: 60, Growth: 0},
+			}
+			workout := &model.Workout{ID: 7, ProfileID: 1, Name: "Push Day", StartDate: tt.start}
+
+			// Awarding the same workout twice, e.g. when a save is retried, does not add to the total
+			for i := 0; i < 2; i++ {
+				if err := s.AwardWorkout(context.Background(), nil, workout, tt.personalRecords); err != nil {
+					t.Fatalf("AwardWorkout() error = %v", err)
+				}
+			}
+
+			sources := []string{}
+			for _, entry := range experiencePoints.entries {
+				sources = append(sources, entry.Source)
+			}
+			if !reflect.DeepEqual(sources, tt.wantSources) {
+				t.Errorf("ledger sources = %v, want %v", sources, tt.wantSources)
+			}
+			if profiles.points != tt.wantPoints {
+				t.Errorf("total = %d, want %d", profiles.points, tt.wantPoints)
+			}
+			if wantLevel, _, _ := s.LevelCurve.level(tt.wantPoints); profiles.level != wantLevel {
+				t.Errorf("level = %d, want %d", profiles.level, wantLevel)
+			}
+		})
+	}
+}
@@ -0,0 +1,544 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/brightside-dev/ronin-fitness-be/database/client"
+	"github.com/brightside-dev/ronin-fitness-be/internal/handler/dto"
+	customError "github.com/brightside-dev/ronin-fitness-be/internal/handler/error"
+	"github.com/brightside-dev/ronin-fitness-be/internal/model"
+	"github.com/brightside-dev/ronin-fitness-be/internal/repository"
+	"github.com/brightside-dev/ronin-fitness-be/internal/util"
+	"github.com/go-playground/validator/v10"
+)
+
+const (
+	maxActiveGoals   = 20
+	goalUnitWorkouts = "workouts"
+)
+
+type GoalService interface {
+	CreateGoal(w http.ResponseWriter, r *http.Request) (*dto.GoalResponse, error)
+	GetGoals(w http.ResponseWriter, r *http.Request) (*dto.GoalsListResponse, error)
+	GetGoal(w http.ResponseWriter, r *http.Request) (*dto.GoalResponse, error)
+	DeleteGoal(w http.ResponseWriter, r *http.Request) error
+	EvaluateGoals(ctx context.Context, profileID int) error
+}
+
+type goalService struct {
+	DB                        client.DatabaseService
+	DBLogger                  *slog.Logger
+	Validate                  *validator.Validate
+	GoalRepository            repository.GoalRepository
+	SetRepository             repository.SetRepository
+	ProfileStatsRepository    repository.ProfileStatsRepository
+	BodyMeasurementRepository repository.BodyMeasurementRepository
+	ExerciseRepository        repository.ExerciseRepository
+	ProfileRepository         repository.ProfileRepository
+	NotificationService       NotificationService
+	ExperiencePointService    ExperiencePointService
+}
+
+func NewGoalService(
+	db client.DatabaseService,
+	dbLogger *slog.Logger,
+	validator *validator.Validate,
+	goalRepository repository.GoalRepository,
+	setRepository repository.SetRepository,
+	profileStatsRepository repository.ProfileStatsRepository,
+	bodyMeasurementRepository repository.BodyMeasurementRepository,
+	exerciseRepository repository.ExerciseRepository,
+	profileRepository repository.ProfileRepository,
+	notificationService NotificationService,
+	experiencePointService ExperiencePointService,
+) GoalService {
+	return &goalService{
+		DB:                        db,
+		DBLogger:                  dbLogger,
+		Validate:                  validator,
+		GoalRepository:            goalRepository,
+		SetRepository:             setRepository,
+		ProfileStatsRepository:    profileStatsRepository,
+		BodyMeasurementRepository: bodyMeasurementRepository,
+		ExerciseRepository:        exerciseRepository,
+		ProfileRepository:         profileRepository,
+		NotificationService:       notificationService,
+		ExperiencePointService:    experiencePointService,
+	}
+}
+
+func (s *goalService) CreateGoal(w http.ResponseWriter, r *http.Request) (*dto.GoalResponse, error) {
+	req := dto.GoalCreateRequest{}
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrInvalidRequestBody
+	}
+
+	err = s.Validate.Struct(req)
+	if err != nil {
+		return nil, util.FormatValidationError(err.(validator.ValidationErrors))
+	}
+
+	profile, err := getAuthProfile(r, s.ProfileRepository)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrUnAuthorized
+	}
+
+	activeGoals, err := s.GoalRepository.GetActiveByProfileID(r.Context(), profile.ID)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrInternalServerError
+	}
+
+	if len(activeGoals) >= maxActiveGoals {
+		return nil, fmt.Errorf("%w: a profile can have at most %d active goals", customError.ErrConflict, maxActiveGoals)
+	}
+
+	now := time.Now().UTC()
+	goal := &model.Goal{
+		ProfileID:   profile.ID,
+		Type:        req.Type,
+		TargetWeeks: 1,
+		CreatedAt:   now,
+	}
+
+	if req.Deadline != "" {
+		deadline, _ := time.Parse("2006-01-02", req.Deadline)
+		if deadline.Before(now.Truncate(24 * time.Hour)) {
+			return nil, fmt.Errorf("deadline must not be in the past")
+		}
+		goal.Deadline = &deadline
+	}
+
+	unit := req.Unit
+	if unit == "" {
+		unit = profile.WeightUnit
+	}
+
+	switch req.Type {
+	case model.GoalTypeLiftWeight, model.GoalTypeLiftE1RM:
+		if req.ExerciseID == nil {
+			return nil, fmt.Errorf("exercise_id is required for lift goals")
+		}
+
+		exercise, err := s.ExerciseRepository.GetByID(r.Context(), *req.ExerciseID)
+		if err != nil {
+			util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+			return nil, customError.ErrInternalServerError
+		}
+
+		if exercise == nil || (exercise.ProfileID != nil && *exercise.ProfileID != profile.ID) {
+			return nil, fmt.Errorf("exercise %d not found", *req.ExerciseID)
+		}
+
+		goal.ExerciseID = &exercise.ID
+		goal.TargetValue = util.ToKg(req.Target, unit)
+	case model.GoalTypeWorkoutsPerWeek:
+		if req.Target != math.Trunc(req.Target) || req.Target > 14 {
+			return nil, fmt.Errorf("target must be a whole number of workouts, at most 14")
+		}
+
+		goal.TargetValue = req.Target
+		if req.TargetWeeks > 0 {
+			goal.TargetWeeks = req.TargetWeeks
+		}
+	case model.GoalTypeBodyweight:
+		// Progress is measured from the bodyweight when the goal is set, which also tells whether
+		// the goal is to lose or to gain weight
+		startValue, err := s.BodyMeasurementRepository.GetLatestWeightKg(r.Context(), profile.ID, now.AddDate(0, 0, 1))
+		if err != nil {
+			util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+			return nil, customError.ErrInternalServerError
+		}
+
+		if startValue == nil {
+			return nil, fmt.Errorf("log your bodyweight before setting a bodyweight goal")
+		}
+
+		goal.StartValue = startValue
+		goal.TargetValue = util.ToKg(req.Target, unit)
+	}
+
+	currentValue, progress, reached, err := s.evaluateGoal(r.Context(), goal, now)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrInternalServerError
+	}
+
+	// Workouts per week goals are the only ones that can still be worked towards when already met
+	if reached && goal.Type != model.GoalTypeWorkoutsPerWeek {
+		return nil, fmt.Errorf("goal is already reached")
+	}
+
+	goal.CurrentValue = currentValue
+	goal.Progress = progress
+
+	goal, err = s.GoalRepository.Create(r.Context(), goal)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrInternalServerError
+	}
+
+	// A workouts per week goal can be met by the workouts already logged this week
+	if reached {
+		err = s.EvaluateGoals(r.Context(), profile.ID)
+		if err != nil {
+			util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+			return nil, customError.ErrInternalServerError
+		}
+
+		goal, err = s.GoalRepository.GetByID(r.Context(), goal.ID)
+		if err != nil {
+			util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+			return nil, customError.ErrInternalServerError
+		}
+
+		if goal == nil {
+			return nil, customError.ErrNotFound
+		}
+	}
+
+	res := toGoalResponse(*goal, profile.WeightUnit)
+	return &res, nil
+}
+
+// GetGoals lists the caller's goals, filtered by the status query parameter, after bringing the
+// progress of the active ones up to date.
+func (s *goalService) GetGoals(w http.ResponseWriter, r *http.Request) (*dto.GoalsListResponse, error) {
+	status := r.URL.Query().Get("status")
+	if status != "" && status != model.GoalStatusActive && status != model.GoalStatusCompleted && status != model.GoalStatusExpired {
+		return nil, fmt.Errorf("status must be one of active, completed or expired")
+	}
+
+	beforeTime, beforeID, err := util.DecodeCursor(r.URL.Query().Get("cursor"))
+	if err != nil {
+		return nil, customError.ErrInvalidCursor
+	}
+
+	profile, err := getAuthProfile(r, s.ProfileRepository)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrUnAuthorized
+	}
+
+	err = s.EvaluateGoals(r.Context(), profile.ID)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrInternalServerError
+	}
+
+	limit := util.GetPageLimit(r, 20, 50)
+	goals, err := s.GoalRepository.GetByProfileID(r.Context(), profile.ID, status, beforeTime, beforeID, limit)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrInternalServerError
+	}
+
+	res := &dto.GoalsListResponse{
+		Goals: make([]dto.GoalResponse, 0, len(goals)),
+	}
+	for _, goal := range goals {
+		res.Goals = append(res.Goals, toGoalResponse(goal, profile.WeightUnit))
+	}
+
+	if len(goals) == limit {
+		last := goals[len(goals)-1]
+		res.NextCursor = util.EncodeCursor(last.CreatedAt, last.ID)
+	}
+
+	return res, nil
+}
+
+func (s *goalService) GetGoal(w http.ResponseWriter, r *http.Request) (*dto.GoalResponse, error) {
+	profile, goal, err := s.getOwnGoal(r)
+	if err != nil {
+		return nil, err
+	}
+
+	if goal.Status == model.GoalStatusActive {
+		err = s.EvaluateGoals(r.Context(), profile.ID)
+		if err != nil {
+			util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+			return nil, customError.ErrInternalServerError
+		}
+
+		_, goal, err = s.getOwnGoal(r)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	res := toGoalResponse(*goal, profile.WeightUnit)
+	return &res, nil
+}
+
+func (s *goalService) DeleteGoal(w http.ResponseWriter, r *http.Request) error {
+	_, goal, err := s.getOwnGoal(r)
+	if err != nil {
+		return err
+	}
+
+	err = s.GoalRepository.Delete(r.Context(), goal.ID)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return customError.ErrInternalServerError
+	}
+
+	return nil
+}
+
+// EvaluateGoals brings the progress of the profile's active goals up to date. Goals that are reached
+// are completed, which awards experience points and notifies the profile once; goals past their
+// deadline expire. It is called whenever workouts or measurements change and before goals are read.
+func (s *goalService) EvaluateGoals(ctx context.Context, profileID int) error {
+	goals, err := s.GoalRepository.GetActiveByProfileID(ctx, profileID)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	for _, goal := range goals {
+		currentValue, progress, reached, err := s.evaluateGoal(ctx, &goal, now)
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case reached:
+			err = s.completeGoal(ctx, &goal, currentValue, now)
+		case goal.Deadline != nil && !now.Before(goal.Deadline.AddDate(0, 0, 1)):
+			err = s.GoalRepository.Expire(ctx, goal.ID, currentValue, progress)
+		case currentValue != goal.CurrentValue || progress != goal.Progress:
+			err = s.GoalRepository.UpdateProgress(ctx, goal.ID, currentValue, progress)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *goalService) completeGoal(ctx context.Context, goal *model.Goal, currentValue float64, now time.Time) error {
+	profile, err := s.ProfileRepository.GetByID(ctx, goal.ProfileID)
+	if err != nil {
+		return err
+	}
+
+	if profile == nil {
+		return fmt.Errorf("profile %d of goal %d not found", goal.ProfileID, goal.ID)
+	}
+
+	_, err = util.WithTransaction(ctx, s.DB.GetDB(), func(tx *sql.Tx) (interface{}, error) {
+		completed, err := s.GoalRepository.Complete(ctx, tx, goal.ID, currentValue, now)
+		if err != nil {
+			return nil, err
+		}
+
+		// A concurrent evaluation got there first
+		if !completed {
+			return nil, nil
+		}
+
+		title := goalTitle(*goal, profile.WeightUnit)
+		points, err := s.ExperiencePointService.Award(
+			ctx, tx, goal.ProfileID,
+			model.ExperiencePointEventGoalReached,
+			fmt.Sprintf("goal:%d", goal.ID),
+			fmt.Sprintf("Reached goal: %s", title),
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		body := fmt.Sprintf("You reached your goal: %s.", title)
+		if points > 0 {
+			body = fmt.Sprintf("%s +%d XP", body, points)
+		}
+
+		return nil, s.NotificationService.Notify(
+			ctx, tx, goal.ProfileID,
+			model.NotificationTypeGoalCompleted,
+			"Goal reached",
+			body,
+			map[string]interface{}{
+				"goal_id":           goal.ID,
+				"experience_points": points,
+			},
+		)
+	})
+
+	return err
+}
+
+// evaluateGoal measures a goal against what the profile has logged. Progress is a percentage
+// rounded to 2 decimals.
+func (s *goalService) evaluateGoal(ctx context.Context, goal *model.Goal, now time.Time) (float64, float64, bool, error) {
+	var currentValue, progress float64
+	var reached bool
+
+	switch goal.Type {
+	case model.GoalTypeLiftWeight, model.GoalTypeLiftE1RM:
+		bests, err := s.SetRepository.GetBestsBefore(ctx, nil, goal.ProfileID, *goal.ExerciseID, now, nil)
+		if err != nil {
+			return 0, 0, false, err
+		}
+
+		currentValue = bests.HeaviestWeightKg
+		if goal.Type == model.GoalTypeLiftE1RM {
+			currentValue = bests.BestE1RMKg
+		}
+
+		reached = currentValue >= goal.TargetValue
+		progress = currentValue / goal.TargetValue
+	case model.GoalTypeBodyweight:
+		latest, err := s.BodyMeasurementRepository.GetLatestWeightKg(ctx, goal.ProfileID, now.AddDate(0, 0, 1))
+		if err != nil {
+			return 0, 0, false, err
+		}
+
+		startValue := *goal.StartValue
+		currentValue = startValue
+		if latest != nil {
+			currentValue = *latest
+		}
+
+		if goal.TargetValue < startValue {
+			reached = currentValue <= goal.TargetValue
+			progress = (startValue - currentValue) / (startValue - goal.TargetValue)
+		} else {
+			reached = currentValue >= goal.TargetValue
+			progress = (currentValue - startValue) / (goal.TargetValue - startValue)
+		}
+	case model.GoalTypeWorkoutsPerWeek:
+		firstWeek := statsBucketStart(goal.CreatedAt.UTC(), statsBucketWeek)
+		currentWeek := statsBucketStart(now, statsBucketWeek)
+
+		dailyStats, err := s.ProfileStatsRepository.GetDailyStats(ctx, goal.ProfileID, firstWeek, now)
+		if err != nil {
+			return 0, 0, false, err
+		}
+
+		weeklyCounts := map[time.Time]int{}
+		for _, day := range dailyStats {
+			weeklyCounts[statsBucketStart(day.StatDate, statsBucketWeek)] += day.WorkoutCount
+		}
+
+		currentValue = float64(weeklyCounts[currentWeek])
+
+		// The streak is made of the weeks that met the target, up to this week or, while this week
+		// is still short of it, up to last week
+		week := currentWeek
+		partial := 0.0
+		if currentValue < goal.TargetValue {
+			partial = currentValue / goal.TargetValue
+			week = week.AddDate(0, 0, -7)
+		}
+
+		streak := 0
+		for !week.Before(firstWeek) && float64(weeklyCounts[week]) >= goal.TargetValue {
+			streak++
+			week = week.AddDate(0, 0, -7)
+		}
+
+		reached = streak >= goal.TargetWeeks
+		progress = (float64(streak) + partial) / float64(goal.TargetWeeks)
+	}
+
+	progress = math.Round(math.Max(0, math.Min(progress, 1))*10000) / 100
+
+	return currentValue, progress, reached, nil
+}
+
+// getOwnGoal loads the goal named by the goalId URL parameter, failing unless it belongs to the caller.
+func (s *goalService) getOwnGoal(r *http.Request) (*model.ProfileWithUser, *model.Goal, error) {
+	goalID, err := getIDParam(r, "goalId")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	profile, err := getAuthProfile(r, s.ProfileRepository)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, nil, customError.ErrUnAuthorized
+	}
+
+	goal, err := s.GoalRepository.GetByID(r.Context(), goalID)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, nil, customError.ErrInternalServerError
+	}
+
+	if goal == nil || goal.ProfileID != profile.ID {
+		return nil, nil, customError.ErrNotFound
+	}
+
+	return profile, goal, nil
+}
+
+// goalTitle describes a goal, e.g. "Bench Press 100 kg" or "4 workouts per week for 3 weeks".
+func goalTitle(goal model.Goal, weightUnit string) string {
+	weight := strconv.FormatFloat(util.FromKg(goal.TargetValue, weightUnit), 'f', -1, 64)
+
+	switch goal.Type {
+	case model.GoalTypeLiftWeight:
+		return fmt.Sprintf("%s %s %s", goal.ExerciseName, weight, weightUnit)
+	case model.GoalTypeLiftE1RM:
+		return fmt.Sprintf("%s %s %s estimated one rep max", goal.ExerciseName, weight, weightUnit)
+	case model.GoalTypeBodyweight:
+		return fmt.Sprintf("Reach %s %s bodyweight", weight, weightUnit)
+	}
+
+	title := fmt.Sprintf("%d workouts per week", int(goal.TargetValue))
+	if goal.TargetValue == 1 {
+		title = "1 workout per week"
+	}
+	if goal.TargetWeeks > 1 {
+		title += fmt.Sprintf(" for %d weeks", goal.TargetWeeks)
+	}
+
+	return title
+}
+
+func toGoalResponse(goal model.Goal, weightUnit string) dto.GoalResponse {
+	res := dto.GoalResponse{
+		ID:           goal.ID,
+		Type:         goal.Type,
+		Title:        goalTitle(goal, weightUnit),
+		ExerciseID:   goal.ExerciseID,
+		ExerciseName: goal.ExerciseName,
+		Target:       goal.TargetValue,
+		Unit:         goalUnitWorkouts,
+		TargetWeeks:  goal.TargetWeeks,
+		Current:      goal.CurrentValue,
+		Progress:     goal.Progress,
+		Status:       goal.Status,
+		CompletedAt:  goal.CompletedAt,
+		CreatedAt:    goal.CreatedAt,
+	}
+
+	if goal.Type != model.GoalTypeWorkoutsPerWeek {
+		fromKg := func(weightKg float64) float64 { return util.FromKg(weightKg, weightUnit) }
+		res.Unit = weightUnit
+		res.Target = fromKg(goal.TargetValue)
+		res.Start = convertOptional(goal.StartValue, fromKg)
+		res.Current = fromKg(goal.CurrentValue)
+	}
+
+	if goal.Deadline != nil {
+		deadline := goal.Deadline.Format("2006-01-02")
+		res.Deadline = &deadline
+	}
+
+	return res
+}
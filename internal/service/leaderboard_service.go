@@ -0,0 +1,175 @@
+package service
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/brightside-dev/ronin-fitness-be/database/client"
+	"github.com/brightside-dev/ronin-fitness-be/internal/handler/dto"
+	customError "github.com/brightside-dev/ronin-fitness-be/internal/handler/error"
+	"github.com/brightside-dev/ronin-fitness-be/internal/model"
+	"github.com/brightside-dev/ronin-fitness-be/internal/repository"
+	"github.com/brightside-dev/ronin-fitness-be/internal/util"
+	"github.com/go-playground/validator/v10"
+)
+
+type LeaderboardService interface {
+	GetLeaderboard(w http.ResponseWriter, r *http.Request) (*dto.LeaderboardResponse, error)
+}
+
+type leaderboardService struct {
+	DB                    client.DatabaseService
+	DBLogger              *slog.Logger
+	Validate              *validator.Validate
+	LeaderboardRepository repository.LeaderboardRepository
+	ProfileRepository     repository.ProfileRepository
+}
+
+func NewLeaderboardService(
+	db client.DatabaseService,
+	dbLogger *slog.Logger,
+	validator *validator.Validate,
+	leaderboardRepository repository.LeaderboardRepository,
+	profileRepository repository.ProfileRepository,
+) LeaderboardService {
+	return &leaderboardService{
+		DB:                    db,
+		DBLogger:              dbLogger,
+		Validate:              validator,
+		LeaderboardRepository: leaderboardRepository,
+		ProfileRepository:     profileRepository,
+	}
+}
+
+// GetLeaderboard ranks public profiles by experience points, across everyone, the caller's country
+// or the profiles the caller follows per the "scope" query parameter. The "period" query parameter
+// ranks by the points earned this week or month instead of in total. Weeks start on Monday, both
+// in UTC.
+func (s *leaderboardService) GetLeaderboard(w http.ResponseWriter, r *http.Request) (*dto.LeaderboardResponse, error) {
+	req := dto.LeaderboardRequest{
+		Scope:  r.URL.Query().Get("scope"),
+		Period: r.URL.Query().Get("period"),
+	}
+
+	err := s.Validate.Struct(req)
+	if err != nil {
+		return nil, util.FormatValidationError(err.(validator.ValidationErrors))
+	}
+
+	if req.Scope == "" {
+		req.Scope = model.LeaderboardScopeGlobal
+	}
+	if req.Period == "" {
+		req.Period = model.LeaderboardPeriodAllTime
+	}
+
+	beforePoints, beforeID, err := util.DecodeScoreCursor(r.URL.Query().Get("cursor"))
+	if err != nil {
+		return nil, customError.ErrInvalidCursor
+	}
+
+	profile, err := getAuthProfile(r, s.ProfileRepository)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrUnAuthorized
+	}
+
+	filter := model.LeaderboardFilter{
+		ViewerProfileID: profile.ID,
+		Scope:           req.Scope,
+		Country:         profile.Country,
+		Since:           leaderboardPeriodStart(req.Period, time.Now().UTC()),
+	}
+
+	limit := util.GetPageLimit(r, 50, 100)
+	entries, err := s.LeaderboardRepository.GetEntries(r.Context(), filter, int(beforePoints), beforeID, limit)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrInternalServerError
+	}
+
+	res := &dto.LeaderboardResponse{
+		Scope:   req.Scope,
+		Period:  req.Period,
+		Entries: make([]dto.LeaderboardEntryResponse, 0, len(entries)),
+	}
+	if !filter.Since.IsZero() {
+		res.Since = &filter.Since
+	}
+
+	if len(entries) > 0 {
+		// Only the first entry needs counting, the page is in rank order
+		ahead, err := s.LeaderboardRepository.CountAhead(r.Context(), filter, entries[0].Points)
+		if err != nil {
+			util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+			return nil, customError.ErrInternalServerError
+		}
+
+		for i := range entries {
+			entries[i].Rank = ahead + 1 + i
+			if i > 0 && entries[i].Points == entries[i-1].Points {
+				entries[i].Rank = entries[i-1].Rank
+			}
+
+			res.Entries = append(res.Entries, toLeaderboardEntryResponse(entries[i]))
+		}
+	}
+
+	me := model.LeaderboardEntry{
+		ProfileID:     profile.ID,
+		DisplayName:   profile.DisplayName,
+		Handle:        profile.Handle,
+		AvatarVersion: profile.AvatarVersion,
+	}
+
+	me.Points, err = s.LeaderboardRepository.GetPoints(r.Context(), filter, profile.ID)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrInternalServerError
+	}
+
+	ahead, err := s.LeaderboardRepository.CountAhead(r.Context(), filter, me.Points)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrInternalServerError
+	}
+
+	me.Rank = ahead + 1
+	res.Me = toLeaderboardEntryResponse(me)
+
+	if len(entries) == limit {
+		last := entries[len(entries)-1]
+		res.NextCursor = util.EncodeScoreCursor(int64(last.Points), last.ProfileID)
+	}
+
+	return res, nil
+}
+
+// leaderboardPeriodStart returns when the points of the period started counting, or the zero time
+// for all time.
+func leaderboardPeriodStart(period string, now time.Time) time.Time {
+	today := now.Truncate(24 * time.Hour)
+
+	switch period {
+	case model.LeaderboardPeriodWeek:
+		return today.AddDate(0, 0, -((int(today.Weekday()) + 6) % 7))
+	case model.LeaderboardPeriodMonth:
+		return time.Date(today.Year(), today.Month(), 1, 0, 0, 0, 0, time.UTC)
+	default:
+		return time.Time{}
+	}
+}
+
+func toLeaderboardEntryResponse(entry model.LeaderboardEntry) dto.LeaderboardEntryResponse {
+	return dto.LeaderboardEntryResponse{
+		Rank: entry.Rank,
+		Profile: dto.ProfileSummaryResponse{
+			ProfileID:     entry.ProfileID,
+			DisplayName:   entry.DisplayName,
+			AvatarVersion: entry.AvatarVersion,
+		},
+		Handle: entry.Handle,
+		Points: entry.Points,
+	}
+}
@@ -0,0 +1,196 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/brightside-dev/ronin-fitness-be/database/client"
+	"github.com/brightside-dev/ronin-fitness-be/internal/handler/dto"
+	customError "github.com/brightside-dev/ronin-fitness-be/internal/handler/error"
+	"github.com/brightside-dev/ronin-fitness-be/internal/model"
+	"github.com/brightside-dev/ronin-fitness-be/internal/repository"
+	"github.com/brightside-dev/ronin-fitness-be/internal/util"
+)
+
+type NotificationService interface {
+	Notify(ctx context.Context, tx *sql.Tx, profileID int, notificationType string, title string, body string, data map[string]interface{}) error
+	NotifyFrom(ctx context.Context, tx *sql.Tx, actorProfileID int, profileID int, notificationType string, title string, body string, data map[string]interface{}) error
+	GetNotifications(w http.ResponseWriter, r *http.Request) (*dto.NotificationsListResponse, error)
+	MarkRead(w http.ResponseWriter, r *http.Request) error
+	MarkAllRead(w http.ResponseWriter, r *http.Request) error
+}
+
+type notificationService struct {
+	DB                     client.DatabaseService
+	DBLogger               *slog.Logger
+	NotificationRepository repository.NotificationRepository
+	ProfileRepository      repository.ProfileRepository
+}
+
+func NewNotificationService(
+	db client.DatabaseService,
+	dbLogger *slog.Logger,
+	notificationRepository repository.NotificationRepository,
+	profileRepository repository.ProfileRepository,
+) NotificationService {
+	return &notificationService{
+		DB:                     db,
+		DBLogger:               dbLogger,
+		NotificationRepository: notificationRepository,
+		ProfileRepository:      profileRepository,
+	}
+}
+
+// Notify adds a notification to the profile's inbox as part of the transaction that caused it, so
+// that it is only sent if the event is committed.
+func (s *notificationService) Notify(
+	ctx context.Context,
+	tx *sql.Tx,
+	profileID int,
+	notificationType string,
+	title string,
+	body string,
+	data map[string]interface{},
+) error {
+	return s.notify(ctx, tx, nil, profileID, notificationType, title, body, data)
+}
+
+// NotifyFrom is Notify for notifications caused by another profile's action. They are hidden
+// while the recipient mutes that profile or either blocks the other.
+func (s *notificationService) NotifyFrom(
+	ctx context.Context,
+	tx *sql.Tx,
+	actorProfileID int,
+	profileID int,
+	notificationType string,
+	title string,
+	body string,
+	data map[string]interface{},
+) error {
+	return s.notify(ctx, tx, &actorProfileID, profileID, notificationType, title, body, data)
+}
+
+func (s *notificationService) notify(
+	ctx context.Context,
+	tx *sql.Tx,
+	actorProfileID *int,
+	profileID int,
+	notificationType string,
+	title string,
+	body string,
+	data map[string]interface{},
+) error {
+	var rawData json.RawMessage
+	if data != nil {
+		var err error
+		rawData, err = json.Marshal(data)
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err := s.NotificationRepository.Create(ctx, tx, &model.Notification{
+		ProfileID:      profileID,
+		ActorProfileID: actorProfileID,
+		Type:           notificationType,
+		Title:          title,
+		Body:           body,
+		Data:           rawData,
+	})
+
+	return err
+}
+
+func (s *notificationService) GetNotifications(w http.ResponseWriter, r *http.Request) (*dto.NotificationsListResponse, error) {
+	beforeTime, beforeID, err := util.DecodeCursor(r.URL.Query().Get("cursor"))
+	if err != nil {
+		return nil, customError.ErrInvalidCursor
+	}
+
+	profile, err := getAuthProfile(r, s.ProfileRepository)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrUnAuthorized
+	}
+
+	limit := util.GetPageLimit(r, 20, 50)
+	notifications, err := s.NotificationRepository.GetByProfileID(r.Context(), profile.ID, beforeTime, beforeID, limit)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrInternalServerError
+	}
+
+	unreadCount, err := s.NotificationRepository.CountUnread(r.Context(), profile.ID)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrInternalServerError
+	}
+
+	res := &dto.NotificationsListResponse{
+		Notifications: make([]dto.NotificationResponse, 0, len(notifications)),
+		UnreadCount:   unreadCount,
+	}
+	for _, notification := range notifications {
+		res.Notifications = append(res.Notifications, dto.NotificationResponse{
+			ID:        notification.ID,
+			Type:      notification.Type,
+			Title:     notification.Title,
+			Body:      notification.Body,
+			Data:      notification.Data,
+			ReadAt:    notification.ReadAt,
+			CreatedAt: notification.CreatedAt,
+		})
+	}
+
+	if len(notifications) == limit {
+		last := notifications[len(notifications)-1]
+		res.NextCursor = util.EncodeCursor(last.CreatedAt, last.ID)
+	}
+
+	return res, nil
+}
+
+func (s *notificationService) MarkRead(w http.ResponseWriter, r *http.Request) error {
+	notificationID, err := getIDParam(r, "notificationId")
+	if err != nil {
+		return err
+	}
+
+	profile, err := getAuthProfile(r, s.ProfileRepository)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return customError.ErrUnAuthorized
+	}
+
+	found, err := s.NotificationRepository.MarkRead(r.Context(), profile.ID, notificationID, time.Now().UTC())
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return customError.ErrInternalServerError
+	}
+
+	if !found {
+		return customError.ErrNotFound
+	}
+
+	return nil
+}
+
+func (s *notificationService) MarkAllRead(w http.ResponseWriter, r *http.Request) error {
+	profile, err := getAuthProfile(r, s.ProfileRepository)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return customError.ErrUnAuthorized
+	}
+
+	err = s.NotificationRepository.MarkAllRead(r.Context(), profile.ID, time.Now().UTC())
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return customError.ErrInternalServerError
+	}
+
+	return nil
+}
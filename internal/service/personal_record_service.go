@@ -0,0 +1,293 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+	"math"
+	"net/http"
+
+	"github.com/brightside-dev/ronin-fitness-be/database/client"
+	"github.com/brightside-dev/ronin-fitness-be/internal/handler/dto"
+	customError "github.com/brightside-dev/ronin-fitness-be/internal/handler/error"
+	"github.com/brightside-dev/ronin-fitness-be/internal/model"
+	"github.com/brightside-dev/ronin-fitness-be/internal/repository"
+	"github.com/brightside-dev/ronin-fitness-be/internal/util"
+)
+
+type PersonalRecordService interface {
+	DetectPersonalRecords(ctx context.Context, tx *sql.Tx, workout *model.Workout, sets []model.Set) ([]model.PersonalRecord, error)
+	RecalculateOneRepMax(ctx context.Context, tx *sql.Tx, profileID int, formula string) error
+	GetExerciseRecords(w http.ResponseWriter, r *http.Request) (*dto.ExerciseRecordsResponse, error)
+}
+
+type personalRecordService struct {
+	DB                       client.DatabaseService
+	DBLogger                 *slog.Logger
+	PersonalRecordRepository repository.PersonalRecordRepository
+	SetRepository            repository.SetRepository
+	ExerciseRepository       repository.ExerciseRepository
+	ProfileRepository        repository.ProfileRepository
+}
+
+func NewPersonalRecordService(
+	db client.DatabaseService,
+	dbLogger *slog.Logger,
+	personalRecordRepository repository.PersonalRecordRepository,
+	setRepository repository.SetRepository,
+	exerciseRepository repository.ExerciseRepository,
+	profileRepository repository.ProfileRepository,
+) PersonalRecordService {
+	return &personalRecordService{
+		DB:                       db,
+		DBLogger:                 dbLogger,
+		PersonalRecordRepository: personalRecordRepository,
+		SetRepository:            setRepository,
+		ExerciseRepository:       exerciseRepository,
+		ProfileRepository:        profileRepository,
+	}
+}
+
+// EstimateOneRepMax estimates the one-rep max of a set with the given formula, rounded to 2 decimals.
+// Brzycki is undefined from 37 reps upwards, so Epley is used for those sets.
+func EstimateOneRepMax(formula string, weightKg float64, reps int) float64 {
+	if reps <= 0 || weightKg <= 0 {
+		return 0
+	}
+
+	if reps == 1 {
+		return weightKg
+	}
+
+	var e1rm float64
+	if formula == model.OneRepMaxFormulaBrzycki && reps < 37 {
+		e1rm = weightKg * 36 / float64(37-reps)
+	} else {
+		e1rm = weightKg * float64(30+reps) / 30
+	}
+
+	return math.Round(e1rm*100) / 100
+}
+
+// DetectPersonalRecords compares the saved sets of a workout with the profile's history for each
+// exercise and stores a record for every value that was beaten. Only the best set of the workout is
+// recorded per record type. Heaviest weight, best e1RM and best volume count on the first session of
+// an exercise; most reps only counts at weights that have been lifted before.
+func (s *personalRecordService) DetectPersonalRecords(
+	ctx context.Context,
+	tx *sql.Tx,
+	workout *model.Workout,
+	sets []model.Set,
+) ([]model.PersonalRecord, error) {
+	setsByExercise := map[int][]model.Set{}
+	exerciseIDs := []int{}
+	for _, set := range sets {
+		if _, ok := setsByExercise[set.ExerciseID]; !ok {
+			exerciseIDs = append(exerciseIDs, set.ExerciseID)
+		}
+		setsByExercise[set.ExerciseID] = append(setsByExercise[set.ExerciseID], set)
+	}
+
+	personalRecords := []model.PersonalRecord{}
+	for _, exerciseID := range exerciseIDs {
+		exerciseSets := setsByExercise[exerciseID]
+
+		weights := []float64{}
+		seenWeights := map[float64]bool{}
+		for _, set := range exerciseSets {
+			if !seenWeights[set.WeightKg] {
+				seenWeights[set.WeightKg] = true
+				weights = append(weights, set.WeightKg)
+			}
+		}
+
+		bests, err := s.SetRepository.GetBestsBefore(ctx, tx, workout.ProfileID, exerciseID, workout.StartDate, weights)
+		if err != nil {
+			return nil, err
+		}
+
+		candidates := []model.PersonalRecord{}
+		newRecord := func(recordType string, set model.Set, value float64, previous float64, hasPrevious bool) model.PersonalRecord {
+			personalRecord := model.PersonalRecord{
+				ProfileID:  workout.ProfileID,
+				ExerciseID: exerciseID,
+				WorkoutID:  workout.ID,
+				SetID:      set.ID,
+				RecordType: recordType,
+				WeightKg:   set.WeightKg,
+				Reps:       set.Reps,
+				Value:      value,
+				AchievedAt: workout.StartDate,
+			}
+			if hasPrevious {
+				personalRecord.PreviousValue = &previous
+			}
+			return personalRecord
+		}
+
+		heaviest, bestE1RM, bestVolume := -1, -1, -1
+		mostReps := map[float64]int{}
+		for i, set := range exerciseSets {
+			if set.Reps <= 0 {
+				continue
+			}
+			if set.WeightKg > 0 && (heaviest < 0 || set.WeightKg > exerciseSets[heaviest].WeightKg) {
+				heaviest = i
+			}
+			if set.E1RMKg > 0 && (bestE1RM < 0 || set.E1RMKg > exerciseSets[bestE1RM].E1RMKg) {
+				bestE1RM = i
+			}
+			if set.WeightKg > 0 && (bestVolume < 0 || setVolume(set) > setVolume(exerciseSets[bestVolume])) {
+				bestVolume = i
+			}
+			if best, ok := mostReps[set.WeightKg]; !ok || set.Reps > exerciseSets[best].Reps {
+				mostReps[set.WeightKg] = i
+			}
+		}
+
+		if heaviest >= 0 && exerciseSets[heaviest].WeightKg > bests.HeaviestWeightKg {
+			set := exerciseSets[heaviest]
+			candidates = append(candidates, newRecord(model.PersonalRecordHeaviestWeight, set, set.WeightKg, bests.HeaviestWeightKg, bests.HeaviestWeightKg > 0))
+		}
+
+		if bestE1RM >= 0 && exerciseSets[bestE1RM].E1RMKg > bests.BestE1RMKg {
+			set := exerciseSets[bestE1RM]
+			candidates = append(candidates, newRecord(model.PersonalRecordBestE1RM, set, set.E1RMKg, bests.BestE1RMKg, bests.BestE1RMKg > 0))
+		}
+
+		if bestVolume >= 0 && setVolume(exerciseSets[bestVolume]) > bests.BestVolumeKg {
+			set := exerciseSets[bestVolume]
+			candidates = append(candidates, newRecord(model.PersonalRecordBestVolume, set, setVolume(set), bests.BestVolumeKg, bests.BestVolumeKg > 0))
+		}
+
+		for _, weight := range weights {
+			i, ok := mostReps[weight]
+			if !ok {
+				continue
+			}
+
+			previousReps, liftedBefore := bests.MostRepsByWeight[weight]
+			if liftedBefore && exerciseSets[i].Reps > previousReps {
+				set := exerciseSets[i]
+				candidates = append(candidates, newRecord(model.PersonalRecordMostReps, set, float64(set.Reps), float64(previousReps), true))
+			}
+		}
+
+		for _, candidate := range candidates {
+			personalRecord, err := s.PersonalRecordRepository.Create(ctx, tx, &candidate)
+			if err != nil {
+				return nil, err
+			}
+			personalRecords = append(personalRecords, *personalRecord)
+		}
+	}
+
+	return personalRecords, nil
+}
+
+func (s *personalRecordService) GetExerciseRecords(w http.ResponseWriter, r *http.Request) (*dto.ExerciseRecordsResponse, error) {
+	exerciseID, err := getIDParam(r, "exerciseId")
+	if err != nil {
+		return nil, err
+	}
+
+	profile, err := getAuthProfile(r, s.ProfileRepository)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrUnAuthorized
+	}
+
+	exercise, err := s.ExerciseRepository.GetByID(r.Context(), exerciseID)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrInternalServerError
+	}
+
+	if exercise == nil || (exercise.ProfileID != nil && *exercise.ProfileID != profile.ID) {
+		return nil, customError.ErrNotFound
+	}
+
+	personalRecords, err := s.PersonalRecordRepository.GetByExerciseID(r.Context(), profile.ID, exerciseID)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrInternalServerError
+	}
+
+	res := &dto.ExerciseRecordsResponse{
+		ExerciseID:       exerciseID,
+		OneRepMaxFormula: profile.OneRepMaxFormula,
+		MostReps:         []dto.PersonalRecordResponse{},
+		History:          make([]dto.PersonalRecordResponse, 0, len(personalRecords)),
+	}
+
+	// History is newest first, so the first record seen of each type (and weight) is the current one
+	seenMostReps := map[float64]bool{}
+	for _, personalRecord := range personalRecords {
+		personalRecordDTO := toPersonalRecordResponse(personalRecord, profile.WeightUnit)
+		res.History = append(res.History, personalRecordDTO)
+
+		switch personalRecord.RecordType {
+		case model.PersonalRecordHeaviestWeight:
+			if res.HeaviestWeight == nil {
+				res.HeaviestWeight = &personalRecordDTO
+			}
+		case model.PersonalRecordBestE1RM:
+			if res.BestE1RM == nil {
+				res.BestE1RM = &personalRecordDTO
+			}
+		case model.PersonalRecordBestVolume:
+			if res.BestVolume == nil {
+				res.BestVolume = &personalRecordDTO
+			}
+		case model.PersonalRecordMostReps:
+			if !seenMostReps[personalRecord.WeightKg] {
+				seenMostReps[personalRecord.WeightKg] = true
+				res.MostReps = append(res.MostReps, personalRecordDTO)
+			}
+		}
+	}
+
+	return res, nil
+}
+
+func setVolume(set model.Set) float64 {
+	return math.Round(set.WeightKg*float64(set.Reps)*100) / 100
+}
+
+// RecalculateOneRepMax re-estimates the one-rep max of the profile's sets and best e1RM records with
+// a newly chosen formula, so later sets are compared with history estimated the same way.
+func (s *personalRecordService) RecalculateOneRepMax(ctx context.Context, tx *sql.Tx, profileID int, formula string) error {
+	err := s.SetRepository.RecalculateOneRepMax(ctx, tx, profileID, formula)
+	if err != nil {
+		return err
+	}
+
+	return s.PersonalRecordRepository.RecalculateOneRepMax(ctx, tx, profileID, formula)
+}
+
+// toPersonalRecordResponse renders the record in the given weight unit. Most-reps records keep their value as a rep count.
+func toPersonalRecordResponse(personalRecord model.PersonalRecord, weightUnit string) dto.PersonalRecordResponse {
+	value := personalRecord.Value
+	previousValue := personalRecord.PreviousValue
+	if personalRecord.RecordType != model.PersonalRecordMostReps {
+		value = util.FromKg(value, weightUnit)
+		if previousValue != nil {
+			converted := util.FromKg(*previousValue, weightUnit)
+			previousValue = &converted
+		}
+	}
+
+	return dto.PersonalRecordResponse{
+		ID:            personalRecord.ID,
+		ExerciseID:    personalRecord.ExerciseID,
+		WorkoutID:     personalRecord.WorkoutID,
+		SetID:         personalRecord.SetID,
+		RecordType:    personalRecord.RecordType,
+		Weight:        util.FromKg(personalRecord.WeightKg, weightUnit),
+		Reps:          personalRecord.Reps,
+		Value:         value,
+		PreviousValue: previousValue,
+		WeightUnit:    weightUnit,
+		AchievedAt:    personalRecord.AchievedAt,
+	}
+}
@@ -0,0 +1,240 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/brightside-dev/ronin-fitness-be/database/client"
+	"github.com/brightside-dev/ronin-fitness-be/internal/handler/dto"
+	customError "github.com/brightside-dev/ronin-fitness-be/internal/handler/error"
+	"github.com/brightside-dev/ronin-fitness-be/internal/model"
+	"github.com/brightside-dev/ronin-fitness-be/internal/repository"
+	"github.com/brightside-dev/ronin-fitness-be/internal/util"
+	"github.com/go-playground/validator/v10"
+)
+
+type ProfileBlockService interface {
+	BlockProfile(w http.ResponseWriter, r *http.Request) error
+	UnblockProfile(w http.ResponseWriter, r *http.Request) error
+	GetBlockedProfiles(w http.ResponseWriter, r *http.Request) (*dto.RestrictedProfilesListResponse, error)
+	MuteProfile(w http.ResponseWriter, r *http.Request) error
+	UnmuteProfile(w http.ResponseWriter, r *http.Request) error
+	GetMutedProfiles(w http.ResponseWriter, r *http.Request) (*dto.RestrictedProfilesListResponse, error)
+}
+
+type profileBlockService struct {
+	DB                      client.DatabaseService
+	DBLogger                *slog.Logger
+	Validate                *validator.Validate
+	ProfileBlockRepository  repository.ProfileBlockRepository
+	ProfileMuteRepository   repository.ProfileMuteRepository
+	ProfileFollowRepository repository.ProfileFollowRepository
+	FollowRequestRepository repository.FollowRequestRepository
+	ProfileRepository       repository.ProfileRepository
+}
+
+func NewProfileBlockService(
+	db client.DatabaseService,
+	dbLogger *slog.Logger,
+	validator *validator.Validate,
+	profileBlockRepository repository.ProfileBlockRepository,
+	profileMuteRepository repository.ProfileMuteRepository,
+	profileFollowRepository repository.ProfileFollowRepository,
+	followRequestRepository repository.FollowRequestRepository,
+	profileRepository repository.ProfileRepository,
+) ProfileBlockService {
+	return &profileBlockService{
+		DB:                      db,
+		DBLogger:                dbLogger,
+		Validate:                validator,
+		ProfileBlockRepository:  profileBlockRepository,
+		ProfileMuteRepository:   profileMuteRepository,
+		ProfileFollowRepository: profileFollowRepository,
+		FollowRequestRepository: followRequestRepository,
+		ProfileRepository:       profileRepository,
+	}
+}
+
+// BlockProfile hides the caller and the profile from each other everywhere. Follows and pending
+// follow requests between them are removed in both directions and are not restored by unblocking.
+func (s *profileBlockService) BlockProfile(w http.ResponseWriter, r *http.Request) error {
+	profile, target, err := s.decodeRestrictionRequest(r)
+	if err != nil {
+		return err
+	}
+
+	_, err = util.WithTransaction(r.Context(), s.DB.GetDB(), func(tx *sql.Tx) (interface{}, error) {
+		err := s.ProfileBlockRepository.Create(r.Context(), tx, &model.ProfileBlock{
+			ProfileID:        profile.ID,
+			BlockedProfileID: target.ID,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		err = s.ProfileFollowRepository.DeleteBetween(r.Context(), tx, profile.ID, target.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		return nil, s.FollowRequestRepository.DeleteBetween(r.Context(), tx, profile.ID, target.ID)
+	})
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return customError.ErrInternalServerError
+	}
+
+	return nil
+}
+
+// UnblockProfile lifts the caller's block of the profile named by the "profileId" URL parameter.
+// Unblocking a profile that is not blocked is a no-op.
+func (s *profileBlockService) UnblockProfile(w http.ResponseWriter, r *http.Request) error {
+	return s.deleteRestriction(r, s.ProfileBlockRepository.Delete)
+}
+
+// GetBlockedProfiles lists the profiles the caller blocked, most recently blocked first.
+func (s *profileBlockService) GetBlockedProfiles(w http.ResponseWriter, r *http.Request) (*dto.RestrictedProfilesListResponse, error) {
+	return s.getRestrictedProfiles(r, s.ProfileBlockRepository.GetByProfileID)
+}
+
+// MuteProfile hides the profile's workouts from the caller's feed and its activity from the
+// caller's notifications. Unlike blocking, the profile is not told apart from any other and
+// follows are kept.
+func (s *profileBlockService) MuteProfile(w http.ResponseWriter, r *http.Request) error {
+	profile, target, err := s.decodeRestrictionRequest(r)
+	if err != nil {
+		return err
+	}
+
+	err = s.ProfileMuteRepository.Create(r.Context(), &model.ProfileMute{
+		ProfileID:      profile.ID,
+		MutedProfileID: target.ID,
+	})
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return customError.ErrInternalServerError
+	}
+
+	return nil
+}
+
+// UnmuteProfile lifts the caller's mute of the profile named by the "profileId" URL parameter.
+func (s *profileBlockService) UnmuteProfile(w http.ResponseWriter, r *http.Request) error {
+	return s.deleteRestriction(r, s.ProfileMuteRepository.Delete)
+}
+
+// GetMutedProfiles lists the profiles the caller muted, most recently muted first.
+func (s *profileBlockService) GetMutedProfiles(w http.ResponseWriter, r *http.Request) (*dto.RestrictedProfilesListResponse, error) {
+	return s.getRestrictedProfiles(r, s.ProfileMuteRepository.GetByProfileID)
+}
+
+// decodeRestrictionRequest returns the caller and the profile they want to block or mute.
+func (s *profileBlockService) decodeRestrictionRequest(r *http.Request) (*model.ProfileWithUser, *model.ProfileWithUser, error) {
+	req := dto.ProfileRestrictionRequest{}
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, nil, customError.ErrInvalidRequestBody
+	}
+
+	err = s.Validate.Struct(req)
+	if err != nil {
+		return nil, nil, util.FormatValidationError(err.(validator.ValidationErrors))
+	}
+
+	profile, err := getAuthProfile(r, s.ProfileRepository)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, nil, customError.ErrUnAuthorized
+	}
+
+	if req.ProfileID == profile.ID {
+		return nil, nil, fmt.Errorf("you cannot block or mute yourself")
+	}
+
+	target, err := s.ProfileRepository.GetByID(r.Context(), req.ProfileID)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, nil, customError.ErrInternalServerError
+	}
+
+	if target == nil {
+		return nil, nil, customError.ErrNotFound
+	}
+
+	return profile, target, nil
+}
+
+func (s *profileBlockService) deleteRestriction(
+	r *http.Request,
+	deleteRestriction func(ctx context.Context, profileID int, otherProfileID int) error,
+) error {
+	otherProfileID, err := getIDParam(r, "profileId")
+	if err != nil {
+		return err
+	}
+
+	profile, err := getAuthProfile(r, s.ProfileRepository)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return customError.ErrUnAuthorized
+	}
+
+	err = deleteRestriction(r.Context(), profile.ID, otherProfileID)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return customError.ErrInternalServerError
+	}
+
+	return nil
+}
+
+func (s *profileBlockService) getRestrictedProfiles(
+	r *http.Request,
+	getPage func(ctx context.Context, profileID int, beforeTime time.Time, beforeID int, limit int) ([]model.RestrictedProfile, error),
+) (*dto.RestrictedProfilesListResponse, error) {
+	beforeTime, beforeID, err := util.DecodeCursor(r.URL.Query().Get("cursor"))
+	if err != nil {
+		return nil, customError.ErrInvalidCursor
+	}
+
+	profile, err := getAuthProfile(r, s.ProfileRepository)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrUnAuthorized
+	}
+
+	limit := util.GetPageLimit(r, 20, 100)
+	restricted, err := getPage(r.Context(), profile.ID, beforeTime, beforeID, limit)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrInternalServerError
+	}
+
+	res := &dto.RestrictedProfilesListResponse{
+		Profiles: make([]dto.RestrictedProfileResponse, 0, len(restricted)),
+	}
+	for _, rp := range restricted {
+		res.Profiles = append(res.Profiles, dto.RestrictedProfileResponse{
+			Profile: dto.ProfileSummaryResponse{
+				ProfileID:     rp.ProfileID,
+				DisplayName:   rp.DisplayName,
+				AvatarVersion: rp.AvatarVersion,
+			},
+			CreatedAt: rp.CreatedAt,
+		})
+	}
+
+	if len(restricted) == limit {
+		last := restricted[len(restricted)-1]
+		res.NextCursor = util.EncodeCursor(last.CreatedAt, last.ID)
+	}
+
+	return res, nil
+}
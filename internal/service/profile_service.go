@@ -1,12 +1,16 @@
 package service
 
 import (
+	"context"
+	"database/sql"
 	"encoding/json"
 	"fmt"
 	"log/slog"
 	"net/http"
+	"regexp"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/brightside-dev/ronin-fitness-be/database/client"
 	"github.com/brightside-dev/ronin-fitness-be/internal/handler/dto"
@@ -18,12 +22,49 @@ import (
 	"github.com/go-playground/validator/v10"
 )
 
+// maxProfileSearchLength bounds search queries, display names are rarely longer
+const maxProfileSearchLength = 50
+
+const (
+	// handleChangeCooldown is how long a profile keeps a handle it changed to before changing it again
+	handleChangeCooldown = 30 * 24 * time.Hour
+	// oldHandleGracePeriod is how long an old handle keeps leading to the profile, and is held for it
+	oldHandleGracePeriod = 14 * 24 * time.Hour
+)
+
+var (
+	handlePattern        = regexp.MustCompile(`^[a-z][a-z0-9_]{2,29}$`)
+	defaultHandlePattern = regexp.MustCompile(`^` + model.DefaultHandlePrefix + `[0-9]+$`)
+)
+
+// reservedHandles can't be taken as they would be confused with the app, its staff or its routes.
+var reservedHandles = map[string]bool{
+	"about": true, "account": true, "admin": true, "administrator": true, "api": true, "app": true,
+	"blocks": true, "everyone": true, "feed": true, "follow": true, "followers": true, "following": true,
+	"help": true, "here": true, "ical": true, "login": true, "logout": true, "media": true, "me": true,
+	"moderator": true, "mutes": true, "null": true, "official": true, "profile": true, "profiles": true,
+	"programs": true, "register": true, "ronin": true, "roninfitness": true, "root": true, "search": true,
+	"security": true, "settings": true, "signup": true, "staff": true, "support": true, "system": true,
+	"team": true, "templates": true, "undefined": true, "workouts": true,
+}
+
 type ProfileService interface {
 	GetMyProfileByUserID(w http.ResponseWriter, r *http.Request, userID int) (*dto.MyProfileResponse, error)
 	GetProfileByUserID(w http.ResponseWriter, r *http.Request) (*dto.ProfileResponse, error)
+	GetProfileByHandle(w http.ResponseWriter, r *http.Request) (*dto.ProfileResponse, error)
+	ChangeHandle(w http.ResponseWriter, r *http.Request) (*dto.MyProfileResponse, error)
 	FollowProfile(w http.ResponseWriter, r *http.Request) (*dto.FollowProfilesResponse, error)
 	UnfollowProfile(w http.ResponseWriter, r *http.Request) (*dto.FollowProfilesResponse, error)
 	UpdateProfile(w http.ResponseWriter, r *http.Request) (*dto.MyProfileResponse, error)
+	GetFollowRequests(w http.ResponseWriter, r *http.Request) (*dto.FollowRequestsListResponse, error)
+	GetSentFollowRequests(w http.ResponseWriter, r *http.Request) (*dto.FollowRequestsListResponse, error)
+	ApproveFollowRequest(w http.ResponseWriter, r *http.Request) error
+	RejectFollowRequest(w http.ResponseWriter, r *http.Request) error
+	CancelFollowRequest(w http.ResponseWriter, r *http.Request) error
+	GetFollowers(w http.ResponseWriter, r *http.Request) (*dto.ProfileFollowsListResponse, error)
+	GetFollowing(w http.ResponseWriter, r *http.Request) (*dto.ProfileFollowsListResponse, error)
+	RemoveFollower(w http.ResponseWriter, r *http.Request) error
+	SearchProfiles(w http.ResponseWriter, r *http.Request) (*dto.ProfileSearchResponse, error)
 }
 
 type profileService struct {
@@ -33,6 +74,12 @@ type profileService struct {
 	ProfileRepository        repository.ProfileRepository
 	ProfilesFollowRepository repository.ProfileFollowRepository
 	UserRepository           repository.UserRepository
+	FollowRequestRepository  repository.FollowRequestRepository
+	ProfileBlockRepository   repository.ProfileBlockRepository
+	ProfileHandleRepository  repository.ProfileHandleRepository
+	NotificationService      NotificationService
+	AchievementService       AchievementService
+	PersonalRecordService    PersonalRecordService
 }
 
 func NewProfileService(
@@ -42,13 +89,26 @@ func NewProfileService(
 	profileRepository repository.ProfileRepository,
 	profilesFollowRepository repository.ProfileFollowRepository,
 	userRepository repository.UserRepository,
+	followRequestRepository repository.FollowRequestRepository,
+	profileBlockRepository repository.ProfileBlockRepository,
+	profileHandleRepository repository.ProfileHandleRepository,
+	notificationService NotificationService,
+	achievementService AchievementService,
+	personalRecordService PersonalRecordService,
 ) ProfileService {
 	return &profileService{
-		DB:                db,
-		DBLogger:          dbLogger,
-		Validate:          validator,
-		ProfileRepository: profileRepository,
-		UserRepository:    userRepository,
+		DB:                       db,
+		DBLogger:                 dbLogger,
+		Validate:                 validator,
+		ProfileRepository:        profileRepository,
+		ProfilesFollowRepository: profilesFollowRepository,
+		UserRepository:           userRepository,
+		FollowRequestRepository:  followRequestRepository,
+		ProfileBlockRepository:   profileBlockRepository,
+		ProfileHandleRepository:  profileHandleRepository,
+		NotificationService:      notificationService,
+		AchievementService:       achievementService,
+		PersonalRecordService:    personalRecordService,
 	}
 }
 
@@ -59,14 +119,7 @@ func (s *profileService) GetMyProfileByUserID(w http.ResponseWriter, r *http.Req
 		return nil, customError.ErrInternalServerError
 	}
 
-	return &dto.MyProfileResponse{
-		ProfileID:         profileWithUser.ID,
-		DisplayName:       profileWithUser.DisplayName,
-		AvatarVersion:     profileWithUser.AvatarVersion,
-		Privacy:           profileWithUser.Privacy,
-		FitnessExperience: profileWithUser.FitnessExperience,
-		ExperiencePoints:  profileWithUser.ExperiencePoints,
-	}, nil
+	return toMyProfileResponse(profileWithUser.Profile), nil
 }
 
 func (s *profileService) GetProfileByUserID(w http.ResponseWriter, r *http.Request) (*dto.ProfileResponse, error) {
@@ -83,55 +136,331 @@ func (s *profileService) GetProfileByUserID(w http.ResponseWriter, r *http.Reque
 	}
 
 	profileWithUser, err := s.ProfileRepository.GetByUserID(r.Context(), id)
+	if err == sql.ErrNoRows {
+		return nil, customError.ErrNotFound
+	}
 	if err != nil {
-		return nil, err
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrInternalServerError
+	}
+
+	return s.buildProfileResponse(r, profileWithUser)
+}
+
+// buildProfileResponse shows the profile to the caller with its follow counts and how the two are
+// connected.
+func (s *profileService) buildProfileResponse(r *http.Request, profileWithUser *model.ProfileWithUser) (*dto.ProfileResponse, error) {
+	viewer, err := getAuthProfile(r, s.ProfileRepository)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrUnAuthorized
+	}
+
+	blocked, err := s.ProfileBlockRepository.IsBlocked(r.Context(), profileWithUser.ID, viewer.ID)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrInternalServerError
+	}
+
+	// Blocked profiles look like they do not exist to each other
+	if blocked {
+		return nil, customError.ErrNotFound
+	}
+
+	followersCount, followingCount, err := s.ProfilesFollowRepository.GetCounts(r.Context(), profileWithUser.ID)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrInternalServerError
+	}
+
+	relationship, err := s.getRelationship(r, viewer.ID, profileWithUser.ID)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrInternalServerError
 	}
 
 	return &dto.ProfileResponse{
 		ProfileID:         profileWithUser.ID,
 		DisplayName:       profileWithUser.DisplayName,
+		Handle:            profileWithUser.Handle,
 		AvatarVersion:     profileWithUser.AvatarVersion,
 		Privacy:           profileWithUser.Privacy,
+		Role:              profileWithUser.Role,
 		FitnessExperience: profileWithUser.FitnessExperience,
 		ExperiencePoints:  profileWithUser.ExperiencePoints,
+		Level:             profileWithUser.Level,
 		User: dto.UserResponse{
 			UserID:  profileWithUser.UserID,
 			Name:    fmt.Sprintf("%s.%s", strings.ToUpper(string(profileWithUser.FirstName[0])), profileWithUser.LastName),
 			Country: profileWithUser.Country,
 		},
+		FollowersCount: followersCount,
+		FollowingCount: followingCount,
+		Relationship:   *relationship,
 	}, nil
 }
 
-func (s *profileService) FollowProfile(w http.ResponseWriter, r *http.Request) (*dto.FollowProfilesResponse, error) {
-	req := dto.FollowProfilesRequest{}
-	decoder := json.NewDecoder(r.Body)
-	err := decoder.Decode(&req)
+// GetProfileByHandle shows the profile with the "handle" URL parameter. A handle the profile changed
+// away from still finds it during the grace period, flagged in RedirectedFrom so links can be updated.
+func (s *profileService) GetProfileByHandle(w http.ResponseWriter, r *http.Request) (*dto.ProfileResponse, error) {
+	handle := normalizeHandle(chi.URLParam(r, "handle"))
+	if handle == "" {
+		return nil, fmt.Errorf("missing handle parameter")
+	}
+
+	profileWithUser, err := s.ProfileRepository.GetByHandle(r.Context(), handle)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrInternalServerError
+	}
+
+	redirectedFrom := ""
+	if profileWithUser == nil {
+		oldHandle, err := s.ProfileHandleRepository.GetActiveByHandle(r.Context(), handle, time.Now().UTC())
+		if err != nil {
+			util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+			return nil, customError.ErrInternalServerError
+		}
+
+		if oldHandle == nil {
+			return nil, customError.ErrNotFound
+		}
+
+		profileWithUser, err = s.ProfileRepository.GetByID(r.Context(), oldHandle.ProfileID)
+		if err != nil || profileWithUser == nil {
+			util.LogWithContext(s.DBLogger, slog.LevelError, "failed to get profile of old handle", nil, r)
+			return nil, customError.ErrInternalServerError
+		}
+
+		redirectedFrom = handle
+	}
+
+	res, err := s.buildProfileResponse(r, profileWithUser)
+	if err != nil {
+		return nil, err
+	}
+
+	res.RedirectedFrom = redirectedFrom
+
+	return res, nil
+}
+
+// ChangeHandle gives the caller a new handle. Handles can be changed once per cooldown period and
+// the old one is held for the caller during a grace period, so links and mentions keep working
+// and it can't be taken over to impersonate them.
+func (s *profileService) ChangeHandle(w http.ResponseWriter, r *http.Request) (*dto.MyProfileResponse, error) {
+	req := dto.ProfileHandleRequest{}
+	err := json.NewDecoder(r.Body).Decode(&req)
 	if err != nil {
 		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
 		return nil, customError.ErrInvalidRequestBody
 	}
 
-	if req.FollowingProfileID == 0 || req.FollowerProfileID == 0 {
-		util.LogWithContext(s.DBLogger, slog.LevelError, "following_profile_id or follower_profile_id parameter missing", nil, r)
-		return nil, fmt.Errorf("following_profile_id or follower_profile_id parameter missing")
+	err = s.Validate.Struct(req)
+	if err != nil {
+		return nil, util.FormatValidationError(err.(validator.ValidationErrors))
 	}
 
-	profileFollow := &model.ProfileFollow{
-		ProfileID:         req.FollowingProfileID,
-		FollowerProfileID: req.FollowerProfileID,
+	profile, err := getAuthProfile(r, s.ProfileRepository)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrUnAuthorized
+	}
+
+	handle := normalizeHandle(req.Handle)
+	if handle == profile.Handle {
+		return toMyProfileResponse(profile.Profile), nil
+	}
+
+	err = validateHandle(handle)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	if profile.HandleChangedAt != nil && now.Before(profile.HandleChangedAt.Add(handleChangeCooldown)) {
+		return nil, fmt.Errorf(
+			"handle can only be changed once every %d days, next after %s",
+			int(handleChangeCooldown.Hours()/24),
+			profile.HandleChangedAt.Add(handleChangeCooldown).Format(time.RFC3339),
+		)
 	}
 
-	newProfileFollow, err := s.ProfilesFollowRepository.Create(r.Context(), profileFollow)
+	owner, err := s.ProfileRepository.GetByHandle(r.Context(), handle)
 	if err != nil {
 		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
 		return nil, customError.ErrInternalServerError
 	}
 
-	return &dto.FollowProfilesResponse{
-		FollowingProfileID: newProfileFollow.ProfileID,
+	oldHandle, err := s.ProfileHandleRepository.GetActiveByHandle(r.Context(), handle, now)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrInternalServerError
+	}
+
+	// The caller may take back their own old handle
+	if owner != nil || (oldHandle != nil && oldHandle.ProfileID != profile.ID) {
+		return nil, fmt.Errorf("%w: handle is already taken", customError.ErrConflict)
+	}
+
+	_, err = util.WithTransaction(r.Context(), s.DB.GetDB(), func(tx *sql.Tx) (interface{}, error) {
+		err := s.ProfileHandleRepository.Delete(r.Context(), tx, handle)
+		if err != nil {
+			return nil, err
+		}
+
+		err = s.ProfileRepository.UpdateHandle(r.Context(), tx, profile.ID, handle, now)
+		if err != nil {
+			return nil, err
+		}
+
+		return nil, s.ProfileHandleRepository.Create(r.Context(), tx, &model.ProfileHandle{
+			ProfileID: profile.ID,
+			Handle:    profile.Handle,
+			ExpiresAt: now.Add(oldHandleGracePeriod),
+		})
+	})
+	// Another profile claimed the handle since it was checked
+	if util.IsDuplicateKeyError(err) {
+		return nil, fmt.Errorf("%w: handle is already taken", customError.ErrConflict)
+	}
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrInternalServerError
+	}
+
+	profile.Handle = handle
+	profile.HandleChangedAt = &now
+
+	return toMyProfileResponse(profile.Profile), nil
+}
+
+// normalizeHandle lowercases the handle, as they are case-insensitive, and drops a leading @.
+func normalizeHandle(handle string) string {
+	return strings.ToLower(strings.TrimPrefix(strings.TrimSpace(handle), "@"))
+}
+
+// validateHandle checks a normalized handle is well formed and not reserved.
+func validateHandle(handle string) error {
+	if !handlePattern.MatchString(handle) {
+		return fmt.Errorf("handle must be 3 to 30 letters, digits or underscores, starting with a letter")
+	}
+
+	// Generated handles are left free for the profiles they will be generated for
+	if reservedHandles[handle] || defaultHandlePattern.MatchString(handle) {
+		return fmt.Errorf("handle %q is reserved", handle)
+	}
+
+	return nil
+}
+
+// getRelationship works out how the viewer and the profile follow each other.
+func (s *profileService) getRelationship(r *http.Request, viewerProfileID int, profileID int) (*dto.ProfileRelationshipResponse, error) {
+	if viewerProfileID == profileID {
+		return &dto.ProfileRelationshipResponse{IsSelf: true}, nil
+	}
+
+	following, err := s.ProfilesFollowRepository.IsFollowing(r.Context(), profileID, viewerProfileID)
+	if err != nil {
+		return nil, err
+	}
+
+	followedBy, err := s.ProfilesFollowRepository.IsFollowing(r.Context(), viewerProfileID, profileID)
+	if err != nil {
+		return nil, err
+	}
+
+	followRequest, err := s.FollowRequestRepository.GetByProfiles(r.Context(), profileID, viewerProfileID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.ProfileRelationshipResponse{
+		Following:  following,
+		FollowedBy: followedBy,
+		Requested:  followRequest != nil,
 	}, nil
 }
 
+// FollowProfile follows a public profile straight away. Following a private profile creates a
+// follow request instead, its content stays hidden until the request is approved.
+func (s *profileService) FollowProfile(w http.ResponseWriter, r *http.Request) (*dto.FollowProfilesResponse, error) {
+	req, follower, err := s.decodeFollowRequest(r)
+	if err != nil {
+		return nil, err
+	}
+
+	profile, err := s.ProfileRepository.GetByID(r.Context(), req.FollowingProfileID)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrInternalServerError
+	}
+
+	if profile == nil {
+		return nil, customError.ErrNotFound
+	}
+
+	if profile.ID == follower.ID {
+		return nil, fmt.Errorf("you cannot follow yourself")
+	}
+
+	blocked, err := s.ProfileBlockRepository.IsBlocked(r.Context(), profile.ID, follower.ID)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrInternalServerError
+	}
+
+	if blocked {
+		return nil, customError.ErrNotFound
+	}
+
+	res := &dto.FollowProfilesResponse{
+		FollowingProfileID: profile.ID,
+		Status:             model.FollowStatusFollowing,
+	}
+
+	isFollowing, err := s.ProfilesFollowRepository.IsFollowing(r.Context(), profile.ID, follower.ID)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrInternalServerError
+	}
+
+	if isFollowing {
+		return res, nil
+	}
+
+	if profile.Privacy == "private" {
+		followRequest, err := s.FollowRequestRepository.Create(r.Context(), &model.FollowRequest{
+			ProfileID:          profile.ID,
+			RequesterProfileID: follower.ID,
+		})
+		if err != nil {
+			util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+			return nil, customError.ErrInternalServerError
+		}
+
+		res.Status = model.FollowStatusRequested
+		res.FollowRequestID = &followRequest.ID
+
+		return res, nil
+	}
+
+	_, err = util.WithTransaction(r.Context(), s.DB.GetDB(), func(tx *sql.Tx) (interface{}, error) {
+		return s.ProfilesFollowRepository.Create(r.Context(), tx, &model.ProfileFollow{
+			ProfileID:         profile.ID,
+			FollowerProfileID: follower.ID,
+		})
+	})
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrInternalServerError
+	}
+
+	s.evaluateFollowAchievements(r, profile.ID, follower.ID)
+
+	return res, nil
+}
+
 func (s *profileService) UpdateProfile(w http.ResponseWriter, r *http.Request) (*dto.MyProfileResponse, error) {
 	req := dto.ProfileUpdateRequest{}
 	decoder := json.NewDecoder(r.Body)
@@ -143,27 +472,16 @@ func (s *profileService) UpdateProfile(w http.ResponseWriter, r *http.Request) (
 
 	err = s.Validate.Struct(req)
 	if err != nil {
-		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
-		return nil, err
+		return nil, util.FormatValidationError(err.(validator.ValidationErrors))
 	}
 
-	profileWithUser, err := s.ProfileRepository.GetByUserID(r.Context(), req.UserID)
+	profileWithUser, err := getAuthProfile(r, s.ProfileRepository)
 	if err != nil {
 		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
 		return nil, customError.ErrInternalServerError
 	}
 
-	profile := model.Profile{
-		ID:                     req.ProfileID,
-		UserID:                 req.UserID,
-		DisplayName:            profileWithUser.DisplayName,
-		Privacy:                profileWithUser.Privacy,
-		AvatarVersion:          profileWithUser.AvatarVersion,
-		IsNotificationsEnabled: profileWithUser.IsNotificationsEnabled,
-		FitnessExperience:      profileWithUser.FitnessExperience,
-		ExperiencePoints:       profileWithUser.ExperiencePoints,
-	}
-
+	profile := profileWithUser.Profile
 	isUpdating := false
 
 	if (profile.DisplayName != req.DisplayName) && req.DisplayName != "" {
@@ -176,8 +494,8 @@ func (s *profileService) UpdateProfile(w http.ResponseWriter, r *http.Request) (
 		isUpdating = true
 	}
 
-	if (profile.IsNotificationsEnabled != req.IsNotificationsEnabled) && req.IsNotificationsEnabled {
-		profile.IsNotificationsEnabled = req.IsNotificationsEnabled
+	if req.IsNotificationsEnabled != nil && profile.IsNotificationsEnabled != *req.IsNotificationsEnabled {
+		profile.IsNotificationsEnabled = *req.IsNotificationsEnabled
 		isUpdating = true
 	}
 
@@ -191,65 +509,553 @@ func (s *profileService) UpdateProfile(w http.ResponseWriter, r *http.Request) (
 		isUpdating = true
 	}
 
+	formulaChanged := false
+	if (profile.OneRepMaxFormula != req.OneRepMaxFormula) && req.OneRepMaxFormula != "" {
+		profile.OneRepMaxFormula = req.OneRepMaxFormula
+		formulaChanged = true
+		isUpdating = true
+	}
+
+	if (profile.WeightUnit != req.WeightUnit) && req.WeightUnit != "" {
+		profile.WeightUnit = req.WeightUnit
+		// An increment in the old unit makes no sense in the new one
+		profile.WeightIncrement = util.DefaultWeightIncrement(req.WeightUnit)
+		isUpdating = true
+	}
+
+	if req.WeightIncrement != nil && profile.WeightIncrement != *req.WeightIncrement {
+		profile.WeightIncrement = *req.WeightIncrement
+		isUpdating = true
+	}
+
 	if !isUpdating {
 		return nil, customError.ErrNothingToUpdate
 	}
 
-	tx, err := s.DB.BeginTx(r.Context(), nil)
+	_, err = util.WithTransaction(r.Context(), s.DB.GetDB(), func(tx *sql.Tx) (interface{}, error) {
+		if formulaChanged {
+			err := s.PersonalRecordService.RecalculateOneRepMax(r.Context(), tx, profile.ID, profile.OneRepMaxFormula)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		return s.ProfileRepository.Update(r.Context(), tx, &profile)
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrInternalServerError
 	}
-	defer func() {
-		if p := recover(); p != nil {
-			tx.Rollback()
-			panic(p)
-		} else if err != nil {
-			tx.Rollback()
-		}
-	}()
 
-	updatedProfile, err := s.ProfileRepository.Update(r.Context(), tx, &profile)
+	return toMyProfileResponse(profile), nil
+}
+
+func (s *profileService) UnfollowProfile(w http.ResponseWriter, r *http.Request) (*dto.FollowProfilesResponse, error) {
+	req, follower, err := s.decodeFollowRequest(r)
+	if err != nil {
+		return nil, err
+	}
+
+	profileFollow := &model.ProfileFollow{
+		ProfileID:         req.FollowingProfileID,
+		FollowerProfileID: follower.ID,
+	}
+
+	err = s.ProfilesFollowRepository.Delete(r.Context(), profileFollow)
 	if err != nil {
 		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
 		return nil, customError.ErrInternalServerError
 	}
 
-	return &dto.MyProfileResponse{
-		ProfileID:         updatedProfile.ID,
-		DisplayName:       updatedProfile.DisplayName,
-		AvatarVersion:     updatedProfile.AvatarVersion,
-		Privacy:           updatedProfile.Privacy,
-		FitnessExperience: updatedProfile.FitnessExperience,
-		ExperiencePoints:  updatedProfile.ExperiencePoints,
+	return &dto.FollowProfilesResponse{
+		FollowingProfileID: profileFollow.ProfileID,
 	}, nil
 }
 
-func (s *profileService) UnfollowProfile(w http.ResponseWriter, r *http.Request) (*dto.FollowProfilesResponse, error) {
+// decodeFollowRequest reads a follow or unfollow request body and loads the caller, who is the follower.
+func (s *profileService) decodeFollowRequest(r *http.Request) (*dto.FollowProfilesRequest, *model.ProfileWithUser, error) {
 	req := dto.FollowProfilesRequest{}
 	decoder := json.NewDecoder(r.Body)
 	err := decoder.Decode(&req)
 	if err != nil {
 		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
-		return nil, customError.ErrInvalidRequestBody
+		return nil, nil, customError.ErrInvalidRequestBody
 	}
 
-	if req.FollowingProfileID == 0 || req.FollowerProfileID == 0 {
-		util.LogWithContext(s.DBLogger, slog.LevelError, "following_profile_id or follower_profile_id parameter missing", nil, r)
-		return nil, fmt.Errorf("following_profile_id or follower_profile_id parameter missing")
+	err = s.Validate.Struct(req)
+	if err != nil {
+		return nil, nil, util.FormatValidationError(err.(validator.ValidationErrors))
 	}
 
-	profileFollow := &model.ProfileFollow{
-		ProfileID:         req.FollowingProfileID,
-		FollowerProfileID: req.FollowerProfileID,
+	follower, err := getAuthProfile(r, s.ProfileRepository)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, nil, customError.ErrUnAuthorized
 	}
 
-	err = s.ProfilesFollowRepository.Delete(r.Context(), profileFollow)
+	if req.FollowerProfileID != 0 && req.FollowerProfileID != follower.ID {
+		return nil, nil, customError.ErrForbidden
+	}
+
+	return &req, follower, nil
+}
+
+// GetFollowRequests lists the requests to follow the caller, newest first.
+func (s *profileService) GetFollowRequests(w http.ResponseWriter, r *http.Request) (*dto.FollowRequestsListResponse, error) {
+	return s.getFollowRequests(r, s.FollowRequestRepository.GetIncoming)
+}
+
+// GetSentFollowRequests lists the caller's own pending follow requests, newest first.
+func (s *profileService) GetSentFollowRequests(w http.ResponseWriter, r *http.Request) (*dto.FollowRequestsListResponse, error) {
+	return s.getFollowRequests(r, s.FollowRequestRepository.GetSent)
+}
+
+func (s *profileService) getFollowRequests(
+	r *http.Request,
+	getPage func(ctx context.Context, profileID int, beforeTime time.Time, beforeID int, limit int) ([]model.FollowRequestWithProfile, error),
+) (*dto.FollowRequestsListResponse, error) {
+	beforeTime, beforeID, err := util.DecodeCursor(r.URL.Query().Get("cursor"))
+	if err != nil {
+		return nil, customError.ErrInvalidCursor
+	}
+
+	profile, err := getAuthProfile(r, s.ProfileRepository)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrUnAuthorized
+	}
+
+	limit := util.GetPageLimit(r, 20, 100)
+	followRequests, err := getPage(r.Context(), profile.ID, beforeTime, beforeID, limit)
 	if err != nil {
 		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
 		return nil, customError.ErrInternalServerError
 	}
 
-	return &dto.FollowProfilesResponse{
-		FollowingProfileID: profileFollow.ProfileID,
-	}, nil
+	res := &dto.FollowRequestsListResponse{
+		FollowRequests: make([]dto.FollowRequestResponse, 0, len(followRequests)),
+	}
+	for _, fr := range followRequests {
+		otherProfileID := fr.RequesterProfileID
+		if otherProfileID == profile.ID {
+			otherProfileID = fr.ProfileID
+		}
+
+		res.FollowRequests = append(res.FollowRequests, dto.FollowRequestResponse{
+			ID: fr.ID,
+			Profile: dto.ProfileSummaryResponse{
+				ProfileID:     otherProfileID,
+				DisplayName:   fr.DisplayName,
+				AvatarVersion: fr.AvatarVersion,
+			},
+			CreatedAt: fr.CreatedAt,
+		})
+	}
+
+	if len(followRequests) == limit {
+		last := followRequests[len(followRequests)-1]
+		res.NextCursor = util.EncodeCursor(last.CreatedAt, last.ID)
+	}
+
+	return res, nil
+}
+
+// ApproveFollowRequest makes the requester a follower of the caller and lets them know.
+func (s *profileService) ApproveFollowRequest(w http.ResponseWriter, r *http.Request) error {
+	profile, followRequest, err := s.getFollowRequest(r, false)
+	if err != nil {
+		return err
+	}
+
+	_, err = util.WithTransaction(r.Context(), s.DB.GetDB(), func(tx *sql.Tx) (interface{}, error) {
+		deleted, err := s.FollowRequestRepository.Delete(r.Context(), tx, followRequest.ID)
+		if err != nil || !deleted {
+			return nil, err
+		}
+
+		_, err = s.ProfilesFollowRepository.Create(r.Context(), tx, &model.ProfileFollow{
+			ProfileID:         profile.ID,
+			FollowerProfileID: followRequest.RequesterProfileID,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		return nil, s.NotificationService.NotifyFrom(
+			r.Context(), tx,
+			profile.ID,
+			followRequest.RequesterProfileID,
+			model.NotificationTypeFollowRequestApproved,
+			"Follow request approved",
+			fmt.Sprintf("%s approved your follow request", profile.DisplayName),
+			map[string]interface{}{"profile_id": profile.ID},
+		)
+	})
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return customError.ErrInternalServerError
+	}
+
+	s.evaluateFollowAchievements(r, profile.ID, followRequest.RequesterProfileID)
+
+	return nil
+}
+
+// evaluateFollowAchievements checks the achievements of both sides of a new follow. A failure is only
+// logged as the follow itself succeeded.
+func (s *profileService) evaluateFollowAchievements(r *http.Request, profileID int, followerProfileID int) {
+	for _, id := range []int{profileID, followerProfileID} {
+		err := s.AchievementService.EvaluateAchievements(r.Context(), id, model.AchievementEventFollow)
+		if err != nil {
+			util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		}
+	}
+}
+
+func (s *profileService) RejectFollowRequest(w http.ResponseWriter, r *http.Request) error {
+	_, followRequest, err := s.getFollowRequest(r, false)
+	if err != nil {
+		return err
+	}
+
+	return s.deleteFollowRequest(r, followRequest)
+}
+
+func (s *profileService) CancelFollowRequest(w http.ResponseWriter, r *http.Request) error {
+	_, followRequest, err := s.getFollowRequest(r, true)
+	if err != nil {
+		return err
+	}
+
+	return s.deleteFollowRequest(r, followRequest)
+}
+
+// getFollowRequest loads the follow request named by the "requestId" URL parameter along with the
+// caller, who must be the requested profile, or the requester if asRequester is set.
+func (s *profileService) getFollowRequest(r *http.Request, asRequester bool) (*model.ProfileWithUser, *model.FollowRequest, error) {
+	id, err := getIDParam(r, "requestId")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	profile, err := getAuthProfile(r, s.ProfileRepository)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, nil, customError.ErrUnAuthorized
+	}
+
+	followRequest, err := s.FollowRequestRepository.GetByID(r.Context(), id)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, nil, customError.ErrInternalServerError
+	}
+
+	if followRequest == nil {
+		return nil, nil, customError.ErrNotFound
+	}
+
+	ownerID := followRequest.ProfileID
+	if asRequester {
+		ownerID = followRequest.RequesterProfileID
+	}
+
+	// Requests of other profiles are reported as missing rather than forbidden so they can't be probed
+	if ownerID != profile.ID {
+		return nil, nil, customError.ErrNotFound
+	}
+
+	return profile, followRequest, nil
+}
+
+func (s *profileService) deleteFollowRequest(r *http.Request, followRequest *model.FollowRequest) error {
+	_, err := util.WithTransaction(r.Context(), s.DB.GetDB(), func(tx *sql.Tx) (interface{}, error) {
+		return s.FollowRequestRepository.Delete(r.Context(), tx, followRequest.ID)
+	})
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return customError.ErrInternalServerError
+	}
+
+	return nil
+}
+
+// GetFollowers lists the followers of the profile named by the "profileId" URL parameter, most
+// recently followed first. The followers of a private profile are only shown to the profile and
+// its followers.
+func (s *profileService) GetFollowers(w http.ResponseWriter, r *http.Request) (*dto.ProfileFollowsListResponse, error) {
+	return s.getFollows(r, s.ProfilesFollowRepository.GetFollowers, func(pf model.ProfileFollowWithProfile) int {
+		return pf.FollowerProfileID
+	})
+}
+
+// GetFollowing lists the profiles followed by the profile named by the "profileId" URL parameter,
+// with the same visibility as GetFollowers.
+func (s *profileService) GetFollowing(w http.ResponseWriter, r *http.Request) (*dto.ProfileFollowsListResponse, error) {
+	return s.getFollows(r, s.ProfilesFollowRepository.GetFollowing, func(pf model.ProfileFollowWithProfile) int {
+		return pf.ProfileID
+	})
+}
+
+// getFollows pages through a follow list of the profile, flagging which listed profiles the caller
+// follows and which follow the caller back. listedProfileID picks the listed side of a follow.
+func (s *profileService) getFollows(
+	r *http.Request,
+	getPage func(ctx context.Context, profileID int, viewerProfileID int, beforeTime time.Time, beforeID int, limit int) ([]model.ProfileFollowWithProfile, error),
+	listedProfileID func(pf model.ProfileFollowWithProfile) int,
+) (*dto.ProfileFollowsListResponse, error) {
+	beforeTime, beforeID, err := util.DecodeCursor(r.URL.Query().Get("cursor"))
+	if err != nil {
+		return nil, customError.ErrInvalidCursor
+	}
+
+	profileID, err := getIDParam(r, "profileId")
+	if err != nil {
+		return nil, err
+	}
+
+	viewer, err := getAuthProfile(r, s.ProfileRepository)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrUnAuthorized
+	}
+
+	profile, err := s.ProfileRepository.GetByID(r.Context(), profileID)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrInternalServerError
+	}
+
+	if profile == nil {
+		return nil, customError.ErrNotFound
+	}
+
+	blocked, err := s.ProfileBlockRepository.IsBlocked(r.Context(), profile.ID, viewer.ID)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrInternalServerError
+	}
+
+	if blocked {
+		return nil, customError.ErrNotFound
+	}
+
+	canView, err := canViewProfileContent(r, s.ProfilesFollowRepository, s.ProfileBlockRepository, viewer.ID, profile)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrInternalServerError
+	}
+
+	if !canView {
+		return nil, customError.ErrForbidden
+	}
+
+	limit := util.GetPageLimit(r, 20, 100)
+	follows, err := getPage(r.Context(), profile.ID, viewer.ID, beforeTime, beforeID, limit)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrInternalServerError
+	}
+
+	listedIDs := make([]int, 0, len(follows))
+	for _, pf := range follows {
+		listedIDs = append(listedIDs, listedProfileID(pf))
+	}
+
+	followedByViewer, err := s.ProfilesFollowRepository.GetFollowedProfileIDs(r.Context(), viewer.ID, listedIDs)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrInternalServerError
+	}
+
+	followingViewer, err := s.ProfilesFollowRepository.GetFollowerProfileIDs(r.Context(), viewer.ID, listedIDs)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrInternalServerError
+	}
+
+	res := &dto.ProfileFollowsListResponse{
+		Profiles: make([]dto.ProfileFollowResponse, 0, len(follows)),
+	}
+	for i, pf := range follows {
+		listedID := listedIDs[i]
+		res.Profiles = append(res.Profiles, dto.ProfileFollowResponse{
+			Profile: dto.ProfileSummaryResponse{
+				ProfileID:     listedID,
+				DisplayName:   pf.DisplayName,
+				AvatarVersion: pf.AvatarVersion,
+			},
+			FollowedAt:  pf.CreatedAt,
+			IsFollowing: followedByViewer[listedID],
+			IsMutual:    followedByViewer[listedID] && followingViewer[listedID],
+		})
+	}
+
+	if len(follows) == limit {
+		last := follows[len(follows)-1]
+		res.NextCursor = util.EncodeCursor(last.CreatedAt, last.ID)
+	}
+
+	return res, nil
+}
+
+// RemoveFollower stops the profile named by the "profileId" URL parameter following the caller.
+func (s *profileService) RemoveFollower(w http.ResponseWriter, r *http.Request) error {
+	followerProfileID, err := getIDParam(r, "profileId")
+	if err != nil {
+		return err
+	}
+
+	profile, err := getAuthProfile(r, s.ProfileRepository)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return customError.ErrUnAuthorized
+	}
+
+	err = s.ProfilesFollowRepository.Delete(r.Context(), &model.ProfileFollow{
+		ProfileID:         profile.ID,
+		FollowerProfileID: followerProfileID,
+	})
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return customError.ErrInternalServerError
+	}
+
+	return nil
+}
+
+// getAuthProfile loads the profile of the user making the request.
+func getAuthProfile(r *http.Request, profileRepository repository.ProfileRepository) (*model.ProfileWithUser, error) {
+	userID, err := util.GetAuthUserID(r)
+	if err != nil {
+		return nil, customError.ErrUnAuthorized
+	}
+
+	profile, err := profileRepository.GetByUserID(r.Context(), userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return profile, nil
+}
+
+// SearchProfiles finds profiles by display name for the "q" query parameter, tolerating typos. Best
+// matches come first, favouring profiles the caller is connected to and profiles in their country.
+func (s *profileService) SearchProfiles(w http.ResponseWriter, r *http.Request) (*dto.ProfileSearchResponse, error) {
+	query := strings.TrimSpace(r.URL.Query().Get("q"))
+	if query == "" {
+		return nil, fmt.Errorf("missing q parameter")
+	}
+
+	if len([]rune(query)) > maxProfileSearchLength {
+		return nil, fmt.Errorf("q must be at most %d characters", maxProfileSearchLength)
+	}
+
+	beforeScore, beforeID, err := util.DecodeScoreCursor(r.URL.Query().Get("cursor"))
+	if err != nil {
+		return nil, customError.ErrInvalidCursor
+	}
+
+	viewer, err := getAuthProfile(r, s.ProfileRepository)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrUnAuthorized
+	}
+
+	limit := util.GetPageLimit(r, 20, 50)
+	results, err := s.ProfileRepository.Search(r.Context(), query, viewer.ID, viewer.Country, beforeScore, beforeID, limit)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrInternalServerError
+	}
+
+	res := &dto.ProfileSearchResponse{
+		Profiles: make([]dto.ProfileSearchResultResponse, 0, len(results)),
+	}
+	for _, result := range results {
+		res.Profiles = append(res.Profiles, dto.ProfileSearchResultResponse{
+			Profile: dto.ProfileSummaryResponse{
+				ProfileID:     result.ID,
+				DisplayName:   result.DisplayName,
+				AvatarVersion: result.AvatarVersion,
+			},
+			Handle:               result.Handle,
+			Privacy:              result.Privacy,
+			IsFollowing:          result.Following,
+			MutualFollowersCount: result.MutualFollowersCount,
+		})
+	}
+
+	if len(results) == limit {
+		last := results[len(results)-1]
+		res.NextCursor = util.EncodeScoreCursor(last.Score, last.ID)
+	}
+
+	return res, nil
+}
+
+func toMyProfileResponse(profile model.Profile) *dto.MyProfileResponse {
+	res := &dto.MyProfileResponse{
+		ProfileID:         profile.ID,
+		DisplayName:       profile.DisplayName,
+		Handle:            profile.Handle,
+		AvatarVersion:     profile.AvatarVersion,
+		Privacy:           profile.Privacy,
+		Role:              profile.Role,
+		FitnessExperience: profile.FitnessExperience,
+		OneRepMaxFormula:  profile.OneRepMaxFormula,
+		WeightUnit:        profile.WeightUnit,
+		WeightIncrement:   profile.WeightIncrement,
+		ExperiencePoints:  profile.ExperiencePoints,
+		Level:             profile.Level,
+	}
+
+	if profile.HandleChangedAt != nil {
+		changeAfter := profile.HandleChangedAt.Add(handleChangeCooldown)
+		if time.Now().Before(changeAfter) {
+			res.HandleChangeAfter = &changeAfter
+		}
+	}
+
+	return res
+}
+
+// canViewProfileContent reports whether the viewer may see workouts and other content owned by owner.
+// Public profiles are visible to everyone, private ones only to the owner and their followers.
+// Nothing is visible between profiles when either has blocked the other.
+func canViewProfileContent(
+	r *http.Request,
+	profileFollowRepository repository.ProfileFollowRepository,
+	profileBlockRepository repository.ProfileBlockRepository,
+	viewerProfileID int,
+	owner *model.ProfileWithUser,
+) (bool, error) {
+	if owner.ID == viewerProfileID {
+		return true, nil
+	}
+
+	blocked, err := profileBlockRepository.IsBlocked(r.Context(), owner.ID, viewerProfileID)
+	if err != nil || blocked {
+		return false, err
+	}
+
+	if owner.Privacy == "public" {
+		return true, nil
+	}
+
+	return profileFollowRepository.IsFollowing(r.Context(), owner.ID, viewerProfileID)
+}
+
+// getIDParam reads a URL parameter and converts it to an int.
+func getIDParam(r *http.Request, name string) (int, error) {
+	param := chi.URLParam(r, name)
+	if param == "" {
+		return 0, fmt.Errorf("missing %s parameter", name)
+	}
+
+	id, err := strconv.Atoi(param)
+	if err != nil {
+		return 0, fmt.Errorf("%s must be a valid integer", name)
+	}
+
+	return id, nil
 }
@@ -0,0 +1,934 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"math"
+	"net/http"
+	"time"
+
+	"github.com/brightside-dev/ronin-fitness-be/database/client"
+	"github.com/brightside-dev/ronin-fitness-be/internal/handler/dto"
+	customError "github.com/brightside-dev/ronin-fitness-be/internal/handler/error"
+	"github.com/brightside-dev/ronin-fitness-be/internal/model"
+	"github.com/brightside-dev/ronin-fitness-be/internal/repository"
+	"github.com/brightside-dev/ronin-fitness-be/internal/util"
+	"github.com/go-playground/validator/v10"
+)
+
+type ProgramService interface {
+	CreateProgram(w http.ResponseWriter, r *http.Request) (*dto.ProgramResponse, error)
+	GetPrograms(w http.ResponseWriter, r *http.Request) (*dto.ProgramsListResponse, error)
+	GetProgram(w http.ResponseWriter, r *http.Request) (*dto.ProgramResponse, error)
+	UpdateProgram(w http.ResponseWriter, r *http.Request) (*dto.ProgramResponse, error)
+	PublishProgram(w http.ResponseWriter, r *http.Request) (*dto.ProgramResponse, error)
+	Enroll(w http.ResponseWriter, r *http.Request) (*dto.ProgramEnrollmentResponse, error)
+	GetEnrollments(w http.ResponseWriter, r *http.Request) (*dto.ProgramEnrollmentsListResponse, error)
+	PauseEnrollment(w http.ResponseWriter, r *http.Request) (*dto.ProgramEnrollmentResponse, error)
+	ResumeEnrollment(w http.ResponseWriter, r *http.Request) (*dto.ProgramEnrollmentResponse, error)
+	RestartEnrollment(w http.ResponseWriter, r *http.Request) (*dto.ProgramEnrollmentResponse, error)
+	GetNextSession(w http.ResponseWriter, r *http.Request) (*dto.ProgramSessionResponse, error)
+	CompleteSession(ctx context.Context, tx *sql.Tx, profileID int, enrollmentID int, workoutID int) error
+}
+
+type programService struct {
+	DB                          client.DatabaseService
+	DBLogger                    *slog.Logger
+	Validate                    *validator.Validate
+	ProgramRepository           repository.ProgramRepository
+	ProgramEnrollmentRepository repository.ProgramEnrollmentRepository
+	WorkoutTemplateRepository   repository.WorkoutTemplateRepository
+	SetRepository               repository.SetRepository
+	ExerciseRepository          repository.ExerciseRepository
+	ProfileRepository           repository.ProfileRepository
+	ProfileBlockRepository      repository.ProfileBlockRepository
+}
+
+func NewProgramService(
+	db client.DatabaseService,
+	dbLogger *slog.Logger,
+	validator *validator.Validate,
+	programRepository repository.ProgramRepository,
+	programEnrollmentRepository repository.ProgramEnrollmentRepository,
+	workoutTemplateRepository repository.WorkoutTemplateRepository,
+	setRepository repository.SetRepository,
+	exerciseRepository repository.ExerciseRepository,
+	profileRepository repository.ProfileRepository,
+	profileBlockRepository repository.ProfileBlockRepository,
+) ProgramService {
+	return &programService{
+		DB:                          db,
+		DBLogger:                    dbLogger,
+		Validate:                    validator,
+		ProgramRepository:           programRepository,
+		ProgramEnrollmentRepository: programEnrollmentRepository,
+		WorkoutTemplateRepository:   workoutTemplateRepository,
+		SetRepository:               setRepository,
+		ExerciseRepository:          exerciseRepository,
+		ProfileRepository:           profileRepository,
+		ProfileBlockRepository:      profileBlockRepository,
+	}
+}
+
+func (s *programService) CreateProgram(w http.ResponseWriter, r *http.Request) (*dto.ProgramResponse, error) {
+	req := dto.ProgramCreateRequest{}
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrInvalidRequestBody
+	}
+
+	err = s.Validate.Struct(req)
+	if err != nil {
+		return nil, util.FormatValidationError(err.(validator.ValidationErrors))
+	}
+
+	profile, err := getAuthProfile(r, s.ProfileRepository)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrUnAuthorized
+	}
+
+	err = s.validateProgramRequest(r, profile.ID, &req)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := util.WithTransaction(r.Context(), s.DB.GetDB(), func(tx *sql.Tx) (interface{}, error) {
+		program, err := s.ProgramRepository.Create(r.Context(), tx, &model.Program{
+			ProfileID:     profile.ID,
+			Name:          req.Name,
+			Description:   req.Description,
+			DurationWeeks: req.DurationWeeks,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		return program, s.replaceProgramDetails(r.Context(), tx, program.ID, &req, programRequestUnit(&req, profile))
+	})
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrInternalServerError
+	}
+
+	return s.getProgramResponse(r, result.(*model.Program).ID, profile)
+}
+
+func (s *programService) GetPrograms(w http.ResponseWriter, r *http.Request) (*dto.ProgramsListResponse, error) {
+	beforeTime, beforeID, err := util.DecodeCursor(r.URL.Query().Get("cursor"))
+	if err != nil {
+		return nil, customError.ErrInvalidCursor
+	}
+
+	profile, err := getAuthProfile(r, s.ProfileRepository)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrUnAuthorized
+	}
+
+	limit := util.GetPageLimit(r, 20, 50)
+	programs, err := s.ProgramRepository.GetCatalogue(r.Context(), profile.ID, beforeTime, beforeID, limit)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrInternalServerError
+	}
+
+	res := &dto.ProgramsListResponse{
+		Programs: make([]dto.ProgramResponse, 0, len(programs)),
+	}
+	for _, program := range programs {
+		res.Programs = append(res.Programs, toProgramResponse(program))
+	}
+
+	if len(programs) == limit {
+		last := programs[len(programs)-1]
+		res.NextCursor = util.EncodeCursor(last.CreatedAt, last.ID)
+	}
+
+	return res, nil
+}
+
+func (s *programService) GetProgram(w http.ResponseWriter, r *http.Request) (*dto.ProgramResponse, error) {
+	profile, program, err := s.getViewableProgram(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.getProgramResponse(r, program.ID, profile)
+}
+
+func (s *programService) UpdateProgram(w http.ResponseWriter, r *http.Request) (*dto.ProgramResponse, error) {
+	req := dto.ProgramCreateRequest{}
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrInvalidRequestBody
+	}
+
+	err = s.Validate.Struct(req)
+	if err != nil {
+		return nil, util.FormatValidationError(err.(validator.ValidationErrors))
+	}
+
+	profile, program, err := s.getViewableProgram(r)
+	if err != nil {
+		return nil, err
+	}
+
+	if program.ProfileID != profile.ID {
+		return nil, customError.ErrForbidden
+	}
+
+	err = s.validateProgramRequest(r, profile.ID, &req)
+	if err != nil {
+		return nil, err
+	}
+
+	if program.Status == model.ProgramStatusPublished {
+		err = s.validatePublishable(r, req.Days, req.Rules)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	program.Name = req.Name
+	program.Description = req.Description
+	program.DurationWeeks = req.DurationWeeks
+
+	_, err = util.WithTransaction(r.Context(), s.DB.GetDB(), func(tx *sql.Tx) (interface{}, error) {
+		err := s.ProgramRepository.Update(r.Context(), tx, program)
+		if err != nil {
+			return nil, err
+		}
+
+		return nil, s.replaceProgramDetails(r.Context(), tx, program.ID, &req, programRequestUnit(&req, profile))
+	})
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrInternalServerError
+	}
+
+	return s.getProgramResponse(r, program.ID, profile)
+}
+
+// PublishProgram adds the program to the catalogue. Coaches can publish their own programs and
+// admins can publish any program.
+func (s *programService) PublishProgram(w http.ResponseWriter, r *http.Request) (*dto.ProgramResponse, error) {
+	profile, program, err := s.getViewableProgram(r)
+	if err != nil {
+		return nil, err
+	}
+
+	canPublish := profile.Role == model.ProfileRoleAdmin ||
+		(profile.Role == model.ProfileRoleCoach && program.ProfileID == profile.ID)
+	if !canPublish {
+		return nil, customError.ErrForbidden
+	}
+
+	days, err := s.ProgramRepository.GetDays(r.Context(), program.ID)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrInternalServerError
+	}
+
+	rules, err := s.ProgramRepository.GetRules(r.Context(), program.ID)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrInternalServerError
+	}
+
+	dayRequests := make([]dto.ProgramDayRequest, 0, len(days))
+	for _, day := range days {
+		dayRequests = append(dayRequests, dto.ProgramDayRequest{TemplateID: day.TemplateID})
+	}
+
+	ruleRequests := make([]dto.ProgressionRuleRequest, 0, len(rules))
+	for _, rule := range rules {
+		ruleRequests = append(ruleRequests, dto.ProgressionRuleRequest{ExerciseID: rule.ExerciseID})
+	}
+
+	err = s.validatePublishable(r, dayRequests, ruleRequests)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.ProgramRepository.Publish(r.Context(), program.ID)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrInternalServerError
+	}
+
+	return s.getProgramResponse(r, program.ID, profile)
+}
+
+// Enroll starts the program for the caller. Enrolling again returns the existing enrollment.
+func (s *programService) Enroll(w http.ResponseWriter, r *http.Request) (*dto.ProgramEnrollmentResponse, error) {
+	profile, program, err := s.getViewableProgram(r)
+	if err != nil {
+		return nil, err
+	}
+
+	enrollment, err := s.ProgramEnrollmentRepository.GetByProfileAndProgram(r.Context(), profile.ID, program.ID)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrInternalServerError
+	}
+
+	if enrollment == nil {
+		enrollment, err = s.ProgramEnrollmentRepository.Create(r.Context(), &model.ProgramEnrollment{
+			ProfileID: profile.ID,
+			ProgramID: program.ID,
+		})
+		if err != nil {
+			util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+			return nil, customError.ErrInternalServerError
+		}
+	}
+
+	return s.buildEnrollmentResponse(r, enrollment)
+}
+
+func (s *programService) GetEnrollments(w http.ResponseWriter, r *http.Request) (*dto.ProgramEnrollmentsListResponse, error) {
+	profile, err := getAuthProfile(r, s.ProfileRepository)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrUnAuthorized
+	}
+
+	enrollments, err := s.ProgramEnrollmentRepository.GetByProfileID(r.Context(), profile.ID)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrInternalServerError
+	}
+
+	res := &dto.ProgramEnrollmentsListResponse{
+		Enrollments: make([]dto.ProgramEnrollmentResponse, 0, len(enrollments)),
+	}
+	for _, enrollment := range enrollments {
+		enrollmentResponse, err := s.buildEnrollmentResponse(r, &enrollment)
+		if err != nil {
+			return nil, err
+		}
+		res.Enrollments = append(res.Enrollments, *enrollmentResponse)
+	}
+
+	return res, nil
+}
+
+func (s *programService) PauseEnrollment(w http.ResponseWriter, r *http.Request) (*dto.ProgramEnrollmentResponse, error) {
+	_, enrollment, err := s.getOwnEnrollment(r)
+	if err != nil {
+		return nil, err
+	}
+
+	if enrollment.Status != model.ProgramEnrollmentStatusActive {
+		return nil, fmt.Errorf("%w: only active enrollments can be paused", customError.ErrConflict)
+	}
+
+	err = s.ProgramEnrollmentRepository.Pause(r.Context(), enrollment.ID)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrInternalServerError
+	}
+
+	return s.getEnrollmentResponse(r, enrollment.ID)
+}
+
+func (s *programService) ResumeEnrollment(w http.ResponseWriter, r *http.Request) (*dto.ProgramEnrollmentResponse, error) {
+	_, enrollment, err := s.getOwnEnrollment(r)
+	if err != nil {
+		return nil, err
+	}
+
+	if enrollment.Status != model.ProgramEnrollmentStatusPaused {
+		return nil, fmt.Errorf("%w: only paused enrollments can be resumed", customError.ErrConflict)
+	}
+
+	err = s.ProgramEnrollmentRepository.Resume(r.Context(), enrollment.ID)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrInternalServerError
+	}
+
+	return s.getEnrollmentResponse(r, enrollment.ID)
+}
+
+// RestartEnrollment goes back to the first session. Training maxes are recalculated from the
+// history logged before the restart.
+func (s *programService) RestartEnrollment(w http.ResponseWriter, r *http.Request) (*dto.ProgramEnrollmentResponse, error) {
+	_, enrollment, err := s.getOwnEnrollment(r)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.ProgramEnrollmentRepository.Restart(r.Context(), enrollment.ID)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrInternalServerError
+	}
+
+	return s.getEnrollmentResponse(r, enrollment.ID)
+}
+
+// GetNextSession builds the enrollment's next workout from its program day template, replacing the
+// template weights of exercises that have a progression rule.
+func (s *programService) GetNextSession(w http.ResponseWriter, r *http.Request) (*dto.ProgramSessionResponse, error) {
+	profile, enrollment, err := s.getOwnEnrollment(r)
+	if err != nil {
+		return nil, err
+	}
+
+	if enrollment.Status != model.ProgramEnrollmentStatusActive {
+		return nil, fmt.Errorf("%w: enrollment is %s", customError.ErrConflict, enrollment.Status)
+	}
+
+	days, err := s.ProgramRepository.GetDays(r.Context(), enrollment.ProgramID)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrInternalServerError
+	}
+
+	if enrollment.CurrentSession >= len(days) {
+		return nil, fmt.Errorf("%w: program has no remaining sessions", customError.ErrConflict)
+	}
+	day := days[enrollment.CurrentSession]
+
+	template, err := s.WorkoutTemplateRepository.GetByID(r.Context(), day.TemplateID)
+	if err != nil || template == nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, "failed to get program day template", nil, r)
+		return nil, customError.ErrInternalServerError
+	}
+
+	setsByTemplate, err := s.WorkoutTemplateRepository.GetSetsByTemplateIDs(r.Context(), []int{template.ID})
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrInternalServerError
+	}
+
+	rules, err := s.ProgramRepository.GetRules(r.Context(), enrollment.ProgramID)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrInternalServerError
+	}
+
+	targets, err := s.calculateTargetWeights(r.Context(), enrollment, day, setsByTemplate[template.ID], rules)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrInternalServerError
+	}
+
+	res := &dto.ProgramSessionResponse{
+		WorkoutDraftResponse: dto.WorkoutDraftResponse{
+			TemplateID:  template.ID,
+			Name:        template.Name,
+			Description: template.Description,
+			Sets:        make([]dto.SetRequest, 0, len(targets)),
+		},
+		ProgramEnrollmentID: enrollment.ID,
+		ProgramDayID:        day.ID,
+		WeekNumber:          day.WeekNumber,
+		DayNumber:           day.DayNumber,
+	}
+	for i, set := range setsByTemplate[template.ID] {
+		weight := util.FromKg(targets[i], profile.WeightUnit)
+		if targets[i] != set.TargetWeightKg {
+			// Computed weights have to be loadable with the profile's plates or dumbbells
+			weight = util.RoundToIncrement(weight, profile.WeightIncrement)
+		}
+
+		res.Sets = append(res.Sets, dto.SetRequest{
+			ExerciseID: set.ExerciseID,
+			SetNumber:  set.SetNumber,
+			Duration:   set.TargetDuration,
+			Weight:     weight,
+			Unit:       profile.WeightUnit,
+			Reps:       set.TargetReps,
+		})
+	}
+
+	return res, nil
+}
+
+// CompleteSession records the workout as the enrollment's current session and moves on to the next one.
+// It runs inside the transaction that creates the workout.
+func (s *programService) CompleteSession(ctx context.Context, tx *sql.Tx, profileID int, enrollmentID int, workoutID int) error {
+	enrollment, err := s.ProgramEnrollmentRepository.GetByIDForUpdate(ctx, tx, enrollmentID)
+	if err != nil {
+		return err
+	}
+
+	if enrollment == nil || enrollment.ProfileID != profileID {
+		return customError.ErrNotFound
+	}
+
+	if enrollment.Status != model.ProgramEnrollmentStatusActive {
+		return fmt.Errorf("%w: enrollment is %s", customError.ErrConflict, enrollment.Status)
+	}
+
+	days, err := s.ProgramRepository.GetDays(ctx, enrollment.ProgramID)
+	if err != nil {
+		return err
+	}
+
+	if enrollment.CurrentSession >= len(days) {
+		return fmt.Errorf("%w: program has no remaining sessions", customError.ErrConflict)
+	}
+
+	err = s.ProgramEnrollmentRepository.CreateSession(ctx, tx, enrollment.ID, days[enrollment.CurrentSession].ID, workoutID)
+	if err != nil {
+		return err
+	}
+
+	return s.ProgramEnrollmentRepository.Advance(ctx, tx, enrollment.ID, len(days))
+}
+
+// calculateTargetWeights returns the target weight for each template set, in order.
+func (s *programService) calculateTargetWeights(
+	ctx context.Context,
+	enrollment *model.ProgramEnrollment,
+	day model.ProgramDay,
+	templateSets []model.WorkoutTemplateSet,
+	rules []model.ProgressionRule,
+) ([]float64, error) {
+	targets := make([]float64, len(templateSets))
+	setIndexesByExercise := map[int][]int{}
+	for i, set := range templateSets {
+		targets[i] = set.TargetWeightKg
+		setIndexesByExercise[set.ExerciseID] = append(setIndexesByExercise[set.ExerciseID], i)
+	}
+
+	for _, rule := range rules {
+		setIndexes, ok := setIndexesByExercise[rule.ExerciseID]
+		if !ok {
+			continue
+		}
+
+		exerciseSets := make([]model.WorkoutTemplateSet, 0, len(setIndexes))
+		for _, i := range setIndexes {
+			exerciseSets = append(exerciseSets, templateSets[i])
+		}
+
+		var exerciseTargets []float64
+		switch rule.RuleType {
+		case model.ProgressionRuleLinear:
+			lastSets, err := s.SetRepository.GetLastSessionSets(ctx, enrollment.ProfileID, rule.ExerciseID)
+			if err != nil {
+				return nil, err
+			}
+			exerciseTargets = linearTargets(exerciseSets, lastSets, rule.IncrementKg)
+		case model.ProgressionRulePercentOfTrainingMax:
+			bests, err := s.SetRepository.GetBestsBefore(ctx, nil, enrollment.ProfileID, rule.ExerciseID, enrollment.StartedAt, nil)
+			if err != nil {
+				return nil, err
+			}
+
+			// Without history from before the enrollment, calibrate from everything logged so far
+			if bests.BestE1RMKg == 0 {
+				bests, err = s.SetRepository.GetBestsBefore(ctx, nil, enrollment.ProfileID, rule.ExerciseID, time.Now(), nil)
+				if err != nil {
+					return nil, err
+				}
+			}
+			exerciseTargets = percentTargets(exerciseSets, bests.BestE1RMKg, rule, day.WeekNumber)
+		}
+
+		for j, i := range setIndexes {
+			if exerciseTargets != nil {
+				targets[i] = exerciseTargets[j]
+			}
+		}
+	}
+
+	return targets, nil
+}
+
+// linearTargets adds incrementKg to the heaviest weight of the last session when every set hit its
+// target reps, and repeats it otherwise. Lighter template sets keep their ratio to the top set.
+func linearTargets(templateSets []model.WorkoutTemplateSet, lastSets []model.Set, incrementKg float64) []float64 {
+	if len(lastSets) == 0 {
+		return nil
+	}
+
+	topKg := 0.0
+	for _, set := range lastSets {
+		topKg = math.Max(topKg, set.WeightKg)
+	}
+
+	allRepsHit := len(lastSets) >= len(templateSets)
+	for i, set := range templateSets {
+		if i < len(lastSets) && lastSets[i].Reps < set.TargetReps {
+			allRepsHit = false
+		}
+	}
+
+	if allRepsHit {
+		topKg += incrementKg
+	}
+
+	templateTopKg := 0.0
+	for _, set := range templateSets {
+		templateTopKg = math.Max(templateTopKg, set.TargetWeightKg)
+	}
+
+	targets := make([]float64, 0, len(templateSets))
+	for _, set := range templateSets {
+		targetKg := topKg
+		if templateTopKg > 0 {
+			targetKg = topKg * set.TargetWeightKg / templateTopKg
+		}
+		targets = append(targets, targetKg)
+	}
+
+	return targets
+}
+
+// percentTargets applies the rule's percentages for the week of the cycle to the training max.
+// The training max grows by the rule's increment after every completed cycle.
+func percentTargets(templateSets []model.WorkoutTemplateSet, bestE1RMKg float64, rule model.ProgressionRule, weekNumber int) []float64 {
+	if bestE1RMKg == 0 || len(rule.WeekPercentages) == 0 {
+		return nil
+	}
+
+	cycleWeeks := len(rule.WeekPercentages)
+	completedCycles := (weekNumber - 1) / cycleWeeks
+	percentages := rule.WeekPercentages[(weekNumber-1)%cycleWeeks]
+	if len(percentages) == 0 {
+		return nil
+	}
+
+	trainingMaxKg := bestE1RMKg*rule.TrainingMaxPercent/100 + rule.IncrementKg*float64(completedCycles)
+
+	targets := make([]float64, 0, len(templateSets))
+	for i := range templateSets {
+		percent := percentages[min(i, len(percentages)-1)]
+		targets = append(targets, trainingMaxKg*percent/100)
+	}
+
+	return targets
+}
+
+// validateProgramRequest checks that the days fit the program and only use the caller's templates,
+// and that rules reference usable exercises at most once each.
+func (s *programService) validateProgramRequest(r *http.Request, profileID int, req *dto.ProgramCreateRequest) error {
+	scheduled := map[[2]int]bool{}
+	for _, day := range req.Days {
+		if day.WeekNumber > req.DurationWeeks {
+			return fmt.Errorf("week %d is beyond the program duration", day.WeekNumber)
+		}
+
+		key := [2]int{day.WeekNumber, day.DayNumber}
+		if scheduled[key] {
+			return fmt.Errorf("week %d day %d is scheduled twice", day.WeekNumber, day.DayNumber)
+		}
+		scheduled[key] = true
+	}
+
+	checkedTemplates := map[int]bool{}
+	for _, day := range req.Days {
+		if checkedTemplates[day.TemplateID] {
+			continue
+		}
+		checkedTemplates[day.TemplateID] = true
+
+		template, err := s.WorkoutTemplateRepository.GetByID(r.Context(), day.TemplateID)
+		if err != nil {
+			util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+			return customError.ErrInternalServerError
+		}
+
+		if template == nil || template.ProfileID != profileID {
+			return fmt.Errorf("template %d not found", day.TemplateID)
+		}
+	}
+
+	exerciseIDs := make([]int, 0, len(req.Rules))
+	for i, rule := range req.Rules {
+		for _, exerciseID := range exerciseIDs {
+			if exerciseID == rule.ExerciseID {
+				return fmt.Errorf("exercise %d has more than one progression rule", rule.ExerciseID)
+			}
+		}
+		exerciseIDs = append(exerciseIDs, rule.ExerciseID)
+
+		if rule.RuleType == model.ProgressionRulePercentOfTrainingMax && rule.TrainingMaxPercent == 0 {
+			req.Rules[i].TrainingMaxPercent = 90
+		}
+	}
+
+	exercises, err := s.ExerciseRepository.GetByIDs(r.Context(), exerciseIDs)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return customError.ErrInternalServerError
+	}
+
+	for _, exerciseID := range exerciseIDs {
+		exercise, ok := exercises[exerciseID]
+		if !ok || (exercise.ProfileID != nil && *exercise.ProfileID != profileID) {
+			return fmt.Errorf("exercise %d not found", exerciseID)
+		}
+	}
+
+	return nil
+}
+
+// validatePublishable checks that a program only uses default exercises so every profile that
+// enrolls can log its workouts.
+func (s *programService) validatePublishable(r *http.Request, days []dto.ProgramDayRequest, rules []dto.ProgressionRuleRequest) error {
+	if len(days) == 0 {
+		return fmt.Errorf("program has no days to publish")
+	}
+
+	templateIDs := make([]int, 0, len(days))
+	for _, day := range days {
+		templateIDs = append(templateIDs, day.TemplateID)
+	}
+
+	setsByTemplate, err := s.WorkoutTemplateRepository.GetSetsByTemplateIDs(r.Context(), templateIDs)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return customError.ErrInternalServerError
+	}
+
+	exerciseIDs := []int{}
+	for _, sets := range setsByTemplate {
+		for _, set := range sets {
+			exerciseIDs = append(exerciseIDs, set.ExerciseID)
+		}
+	}
+	for _, rule := range rules {
+		exerciseIDs = append(exerciseIDs, rule.ExerciseID)
+	}
+
+	exercises, err := s.ExerciseRepository.GetByIDs(r.Context(), exerciseIDs)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return customError.ErrInternalServerError
+	}
+
+	for _, exercise := range exercises {
+		if exercise.ProfileID != nil {
+			return fmt.Errorf("published programs can only use default exercises, %q is a custom exercise", exercise.Name)
+		}
+	}
+
+	return nil
+}
+
+func (s *programService) replaceProgramDetails(ctx context.Context, tx *sql.Tx, programID int, req *dto.ProgramCreateRequest, unit string) error {
+	days := make([]model.ProgramDay, 0, len(req.Days))
+	for _, day := range req.Days {
+		days = append(days, model.ProgramDay{
+			WeekNumber: day.WeekNumber,
+			DayNumber:  day.DayNumber,
+			TemplateID: day.TemplateID,
+		})
+	}
+
+	err := s.ProgramRepository.ReplaceDays(ctx, tx, programID, days)
+	if err != nil {
+		return err
+	}
+
+	rules := make([]model.ProgressionRule, 0, len(req.Rules))
+	for _, rule := range req.Rules {
+		rules = append(rules, model.ProgressionRule{
+			ExerciseID:         rule.ExerciseID,
+			RuleType:           rule.RuleType,
+			IncrementKg:        util.ToKg(rule.Increment, unit),
+			TrainingMaxPercent: rule.TrainingMaxPercent,
+			WeekPercentages:    rule.WeekPercentages,
+		})
+	}
+
+	return s.ProgramRepository.ReplaceRules(ctx, tx, programID, rules)
+}
+
+// getViewableProgram loads the program named by the programId URL parameter. Drafts are only
+// visible to their author.
+func (s *programService) getViewableProgram(r *http.Request) (*model.ProfileWithUser, *model.Program, error) {
+	programID, err := getIDParam(r, "programId")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	profile, err := getAuthProfile(r, s.ProfileRepository)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, nil, customError.ErrUnAuthorized
+	}
+
+	program, err := s.ProgramRepository.GetByID(r.Context(), programID)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, nil, customError.ErrInternalServerError
+	}
+
+	if program == nil {
+		return nil, nil, customError.ErrNotFound
+	}
+
+	// Admins review drafts before publishing them
+	isVisible := program.Status == model.ProgramStatusPublished ||
+		program.ProfileID == profile.ID ||
+		profile.Role == model.ProfileRoleAdmin
+	if !isVisible {
+		return nil, nil, customError.ErrNotFound
+	}
+
+	blocked, err := s.ProfileBlockRepository.IsBlocked(r.Context(), program.ProfileID, profile.ID)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, nil, customError.ErrInternalServerError
+	}
+
+	if blocked {
+		return nil, nil, customError.ErrNotFound
+	}
+
+	return profile, program, nil
+}
+
+func (s *programService) getOwnEnrollment(r *http.Request) (*model.ProfileWithUser, *model.ProgramEnrollment, error) {
+	enrollmentID, err := getIDParam(r, "enrollmentId")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	profile, err := getAuthProfile(r, s.ProfileRepository)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, nil, customError.ErrUnAuthorized
+	}
+
+	enrollment, err := s.ProgramEnrollmentRepository.GetByID(r.Context(), enrollmentID)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, nil, customError.ErrInternalServerError
+	}
+
+	if enrollment == nil || enrollment.ProfileID != profile.ID {
+		return nil, nil, customError.ErrNotFound
+	}
+
+	return profile, enrollment, nil
+}
+
+func (s *programService) getEnrollmentResponse(r *http.Request, enrollmentID int) (*dto.ProgramEnrollmentResponse, error) {
+	enrollment, err := s.ProgramEnrollmentRepository.GetByID(r.Context(), enrollmentID)
+	if err != nil || enrollment == nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, "failed to get program enrollment", nil, r)
+		return nil, customError.ErrInternalServerError
+	}
+
+	return s.buildEnrollmentResponse(r, enrollment)
+}
+
+func (s *programService) buildEnrollmentResponse(r *http.Request, enrollment *model.ProgramEnrollment) (*dto.ProgramEnrollmentResponse, error) {
+	program, err := s.ProgramRepository.GetByID(r.Context(), enrollment.ProgramID)
+	if err != nil || program == nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, "failed to get enrollment program", nil, r)
+		return nil, customError.ErrInternalServerError
+	}
+
+	days, err := s.ProgramRepository.GetDays(r.Context(), program.ID)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrInternalServerError
+	}
+
+	return &dto.ProgramEnrollmentResponse{
+		ID:             enrollment.ID,
+		ProgramID:      program.ID,
+		ProgramName:    program.Name,
+		Status:         enrollment.Status,
+		CurrentSession: enrollment.CurrentSession,
+		TotalSessions:  len(days),
+		StartedAt:      enrollment.StartedAt,
+		PausedAt:       enrollment.PausedAt,
+		CompletedAt:    enrollment.CompletedAt,
+	}, nil
+}
+
+func (s *programService) getProgramResponse(r *http.Request, programID int, viewer *model.ProfileWithUser) (*dto.ProgramResponse, error) {
+	program, err := s.ProgramRepository.GetByID(r.Context(), programID)
+	if err != nil || program == nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, "failed to get program", nil, r)
+		return nil, customError.ErrInternalServerError
+	}
+
+	days, err := s.ProgramRepository.GetDays(r.Context(), program.ID)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrInternalServerError
+	}
+
+	rules, err := s.ProgramRepository.GetRules(r.Context(), program.ID)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrInternalServerError
+	}
+
+	res := toProgramResponse(*program)
+	res.WeightUnit = viewer.WeightUnit
+	res.Days = make([]dto.ProgramDayResponse, 0, len(days))
+	templateNames := map[int]string{}
+	for _, day := range days {
+		name, ok := templateNames[day.TemplateID]
+		if !ok {
+			template, err := s.WorkoutTemplateRepository.GetByID(r.Context(), day.TemplateID)
+			if err != nil || template == nil {
+				util.LogWithContext(s.DBLogger, slog.LevelError, "failed to get program day template", nil, r)
+				return nil, customError.ErrInternalServerError
+			}
+			name = template.Name
+			templateNames[day.TemplateID] = name
+		}
+
+		res.Days = append(res.Days, dto.ProgramDayResponse{
+			ID:           day.ID,
+			WeekNumber:   day.WeekNumber,
+			DayNumber:    day.DayNumber,
+			TemplateID:   day.TemplateID,
+			TemplateName: name,
+		})
+	}
+
+	res.Rules = make([]dto.ProgressionRuleResponse, 0, len(rules))
+	for _, rule := range rules {
+		res.Rules = append(res.Rules, dto.ProgressionRuleResponse{
+			ExerciseID:         rule.ExerciseID,
+			RuleType:           rule.RuleType,
+			Increment:          util.FromKg(rule.IncrementKg, viewer.WeightUnit),
+			TrainingMaxPercent: rule.TrainingMaxPercent,
+			WeekPercentages:    rule.WeekPercentages,
+		})
+	}
+
+	return &res, nil
+}
+
+func toProgramResponse(program model.Program) dto.ProgramResponse {
+	return dto.ProgramResponse{
+		ID:            program.ID,
+		ProfileID:     program.ProfileID,
+		Name:          program.Name,
+		Description:   program.Description,
+		DurationWeeks: program.DurationWeeks,
+		Status:        program.Status,
+		PublishedAt:   program.PublishedAt,
+		CreatedAt:     program.CreatedAt,
+		UpdatedAt:     program.UpdatedAt,
+	}
+}
+
+func programRequestUnit(req *dto.ProgramCreateRequest, profile *model.ProfileWithUser) string {
+	if req.Unit != "" {
+		return req.Unit
+	}
+
+	return profile.WeightUnit
+}
@@ -0,0 +1,364 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"math"
+	"net/http"
+	"time"
+
+	"github.com/brightside-dev/ronin-fitness-be/database/client"
+	"github.com/brightside-dev/ronin-fitness-be/internal/handler/dto"
+	customError "github.com/brightside-dev/ronin-fitness-be/internal/handler/error"
+	"github.com/brightside-dev/ronin-fitness-be/internal/model"
+	"github.com/brightside-dev/ronin-fitness-be/internal/repository"
+	"github.com/brightside-dev/ronin-fitness-be/internal/util"
+	"github.com/go-playground/validator/v10"
+)
+
+const (
+	statsBucketDay   = "day"
+	statsBucketWeek  = "week"
+	statsBucketMonth = "month"
+
+	// maxStatsBuckets bounds the size of a stats response, e.g. a little over a year of daily buckets
+	maxStatsBuckets = 400
+	// maxStreakLookbackDays bounds how far back the current streak is followed
+	maxStreakLookbackDays = 1000
+	// maxRelativeStrengthLifts is how many of the heaviest lifts are compared to bodyweight
+	maxRelativeStrengthLifts = 5
+)
+
+type StatsService interface {
+	ApplyWorkout(ctx context.Context, tx *sql.Tx, workout *model.Workout, sets []model.Set, sign int) error
+	GetStats(w http.ResponseWriter, r *http.Request) (*dto.StatsResponse, error)
+}
+
+type statsService struct {
+	DB                        client.DatabaseService
+	DBLogger                  *slog.Logger
+	Validate                  *validator.Validate
+	ProfileStatsRepository    repository.ProfileStatsRepository
+	ExerciseRepository        repository.ExerciseRepository
+	ProfileRepository         repository.ProfileRepository
+	SetRepository             repository.SetRepository
+	BodyMeasurementRepository repository.BodyMeasurementRepository
+}
+
+func NewStatsService(
+	db client.DatabaseService,
+	dbLogger *slog.Logger,
+	validator *validator.Validate,
+	profileStatsRepository repository.ProfileStatsRepository,
+	exerciseRepository repository.ExerciseRepository,
+	profileRepository repository.ProfileRepository,
+	setRepository repository.SetRepository,
+	bodyMeasurementRepository repository.BodyMeasurementRepository,
+) StatsService {
+	return &statsService{
+		DB:                        db,
+		DBLogger:                  dbLogger,
+		Validate:                  validator,
+		ProfileStatsRepository:    profileStatsRepository,
+		ExerciseRepository:        exerciseRepository,
+		ProfileRepository:         profileRepository,
+		SetRepository:             setRepository,
+		BodyMeasurementRepository: bodyMeasurementRepository,
+	}
+}
+
+// ApplyWorkout adds a workout and its sets to the daily aggregates of its owner. Use a sign of 1
+// when a workout is saved and -1 when it is removed; an edit is a removal followed by an addition.
+func (s *statsService) ApplyWorkout(ctx context.Context, tx *sql.Tx, workout *model.Workout, sets []model.Set, sign int) error {
+	statDate := workout.StartDate.UTC()
+
+	exerciseIDs := []int{}
+	volume := 0.0
+	for _, set := range sets {
+		exerciseIDs = append(exerciseIDs, set.ExerciseID)
+		volume += set.WeightKg * float64(set.Reps)
+	}
+
+	err := s.ProfileStatsRepository.AddDailyStats(ctx, tx, &model.ProfileDailyStats{
+		ProfileID:            workout.ProfileID,
+		StatDate:             statDate,
+		WorkoutCount:         sign,
+		TotalDurationSeconds: sign * int(workout.EndDate.Sub(workout.StartDate).Seconds()),
+		TotalVolumeKg:        float64(sign) * math.Round(volume*100) / 100,
+		TotalSets:            sign * len(sets),
+		MentalEnergySum:      sign * workout.MentalEnergyLevel,
+		PhysicalEnergySum:    sign * workout.PhysicalEnergyLevel,
+	})
+	if err != nil {
+		return err
+	}
+
+	exercises, err := s.ExerciseRepository.GetByIDs(ctx, exerciseIDs)
+	if err != nil {
+		return err
+	}
+
+	setsPerMuscleGroup := map[string]int{}
+	for _, set := range sets {
+		setsPerMuscleGroup[exercises[set.ExerciseID].MuscleGroup]++
+	}
+
+	for muscleGroup, setCount := range setsPerMuscleGroup {
+		if muscleGroup == "" {
+			muscleGroup = "other"
+		}
+
+		err := s.ProfileStatsRepository.AddDailyMuscleGroupStats(ctx, tx, &model.ProfileDailyMuscleGroupStats{
+			ProfileID:   workout.ProfileID,
+			StatDate:    statDate,
+			MuscleGroup: muscleGroup,
+			SetCount:    sign * setCount,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *statsService) GetStats(w http.ResponseWriter, r *http.Request) (*dto.StatsResponse, error) {
+	req := dto.StatsRequest{
+		From:   r.URL.Query().Get("from"),
+		To:     r.URL.Query().Get("to"),
+		Bucket: r.URL.Query().Get("bucket"),
+	}
+
+	err := s.Validate.Struct(req)
+	if err != nil {
+		return nil, util.FormatValidationError(err.(validator.ValidationErrors))
+	}
+
+	to := time.Now().UTC().Truncate(24 * time.Hour)
+	if req.To != "" {
+		to, _ = time.Parse("2006-01-02", req.To)
+	}
+
+	from := to.AddDate(0, 0, -29)
+	if req.From != "" {
+		from, _ = time.Parse("2006-01-02", req.From)
+	}
+
+	if from.After(to) {
+		return nil, fmt.Errorf("from must not be after to")
+	}
+
+	bucket := req.Bucket
+	if bucket == "" {
+		bucket = statsBucketDay
+	}
+
+	bucketStarts := statsBucketStarts(from, to, bucket)
+	if len(bucketStarts) > maxStatsBuckets {
+		return nil, fmt.Errorf("date range is too large for %s buckets", bucket)
+	}
+
+	profile, err := getAuthProfile(r, s.ProfileRepository)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrUnAuthorized
+	}
+
+	dailyStats, err := s.ProfileStatsRepository.GetDailyStats(r.Context(), profile.ID, from, to)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrInternalServerError
+	}
+
+	muscleGroupStats, err := s.ProfileStatsRepository.GetDailyMuscleGroupStats(r.Context(), profile.ID, from, to)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrInternalServerError
+	}
+
+	workoutDates, err := s.ProfileStatsRepository.GetWorkoutDates(r.Context(), profile.ID, to, maxStreakLookbackDays)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrInternalServerError
+	}
+
+	bodyweightKg, err := s.BodyMeasurementRepository.GetLatestWeightKg(r.Context(), profile.ID, to.AddDate(0, 0, 1))
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrInternalServerError
+	}
+
+	// Ratios use the last bodyweight recorded by the end of the range, as one is rarely logged on the day of a lift
+	relativeStrength := []dto.StatsRelativeStrengthResponse{}
+	if bodyweightKg != nil {
+		topE1RMs, err := s.SetRepository.GetTopE1RMs(r.Context(), profile.ID, from, to.AddDate(0, 0, 1), maxRelativeStrengthLifts)
+		if err != nil {
+			util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+			return nil, customError.ErrInternalServerError
+		}
+
+		for _, best := range topE1RMs {
+			relativeStrength = append(relativeStrength, dto.StatsRelativeStrengthResponse{
+				ExerciseID:      best.ExerciseID,
+				Name:            best.Name,
+				BestE1RM:        util.FromKg(best.E1RMKg, profile.WeightUnit),
+				BodyweightRatio: math.Round(best.E1RMKg / *bodyweightKg * 100) / 100,
+			})
+		}
+	}
+
+	// Sums are accumulated first and the energy averages derived at the end
+	type bucketTotals struct {
+		dto.StatsBucketResponse
+		mentalEnergySum   int
+		physicalEnergySum int
+	}
+
+	newTotals := func(start string) *bucketTotals {
+		return &bucketTotals{StatsBucketResponse: dto.StatsBucketResponse{
+			Start:              start,
+			SetsPerMuscleGroup: map[string]int{},
+		}}
+	}
+
+	totals := newTotals("")
+	buckets := make(map[string]*bucketTotals, len(bucketStarts))
+	for _, start := range bucketStarts {
+		buckets[start.Format("2006-01-02")] = newTotals(start.Format("2006-01-02"))
+	}
+
+	for _, day := range dailyStats {
+		for _, t := range []*bucketTotals{totals, buckets[statsBucketStart(day.StatDate, bucket).Format("2006-01-02")]} {
+			t.WorkoutCount += day.WorkoutCount
+			t.TotalDurationSeconds += day.TotalDurationSeconds
+			t.TotalVolume += day.TotalVolumeKg
+			t.TotalSets += day.TotalSets
+			t.mentalEnergySum += day.MentalEnergySum
+			t.physicalEnergySum += day.PhysicalEnergySum
+		}
+	}
+
+	for _, day := range muscleGroupStats {
+		totals.SetsPerMuscleGroup[day.MuscleGroup] += day.SetCount
+		buckets[statsBucketStart(day.StatDate, bucket).Format("2006-01-02")].SetsPerMuscleGroup[day.MuscleGroup] += day.SetCount
+	}
+
+	finalize := func(t *bucketTotals) dto.StatsBucketResponse {
+		t.TotalVolume = util.FromKg(t.TotalVolume, profile.WeightUnit)
+		if t.WorkoutCount > 0 {
+			t.AvgMentalEnergy = math.Round(float64(t.mentalEnergySum)/float64(t.WorkoutCount)*100) / 100
+			t.AvgPhysicalEnergy = math.Round(float64(t.physicalEnergySum)/float64(t.WorkoutCount)*100) / 100
+		}
+		return t.StatsBucketResponse
+	}
+
+	res := &dto.StatsResponse{
+		From:    from.Format("2006-01-02"),
+		To:      to.Format("2006-01-02"),
+		Bucket:  bucket,
+		Unit:    profile.WeightUnit,
+		Totals:  finalize(totals),
+		Buckets: make([]dto.StatsBucketResponse, 0, len(bucketStarts)),
+		Streaks: calculateStreaks(dailyStats, workoutDates, to),
+		Bodyweight: convertOptional(bodyweightKg, func(weightKg float64) float64 {
+			return util.FromKg(weightKg, profile.WeightUnit)
+		}),
+		RelativeStrength: relativeStrength,
+	}
+	for _, start := range bucketStarts {
+		res.Buckets = append(res.Buckets, finalize(buckets[start.Format("2006-01-02")]))
+	}
+
+	return res, nil
+}
+
+// statsBucketStart returns the first day of the bucket containing date. Weeks start on Monday.
+func statsBucketStart(date time.Time, bucket string) time.Time {
+	date = time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+
+	switch bucket {
+	case statsBucketWeek:
+		return date.AddDate(0, 0, -((int(date.Weekday()) + 6) % 7))
+	case statsBucketMonth:
+		return date.AddDate(0, 0, 1-date.Day())
+	default:
+		return date
+	}
+}
+
+func statsBucketStarts(from time.Time, to time.Time, bucket string) []time.Time {
+	starts := []time.Time{}
+	for start := statsBucketStart(from, bucket); !start.After(to); {
+		starts = append(starts, start)
+
+		switch bucket {
+		case statsBucketWeek:
+			start = start.AddDate(0, 0, 7)
+		case statsBucketMonth:
+			start = start.AddDate(0, 1, 0)
+		default:
+			start = start.AddDate(0, 0, 1)
+		}
+
+		if len(starts) > maxStatsBuckets {
+			break
+		}
+	}
+
+	return starts
+}
+
+// calculateStreaks derives the streaks from the days trained. The current streaks are still alive
+// if the last workout was the day (or week) before "to", as the user may not have trained yet today.
+func calculateStreaks(dailyStats []model.ProfileDailyStats, workoutDatesDesc []time.Time, to time.Time) dto.StatsStreaksResponse {
+	streaks := dto.StatsStreaksResponse{}
+
+	current := 0
+	var previous time.Time
+	for _, day := range dailyStats {
+		if !previous.IsZero() && day.StatDate.Sub(previous) == 24*time.Hour {
+			current++
+		} else {
+			current = 1
+		}
+		previous = day.StatDate
+		streaks.LongestDays = max(streaks.LongestDays, current)
+	}
+
+	expected := statsBucketStart(to, statsBucketDay)
+	for i, date := range workoutDatesDesc {
+		date = statsBucketStart(date, statsBucketDay)
+		if i == 0 && date.Equal(expected.AddDate(0, 0, -1)) {
+			expected = date
+		}
+		if !date.Equal(expected) {
+			break
+		}
+		streaks.CurrentDays++
+		expected = expected.AddDate(0, 0, -1)
+	}
+
+	weeks := []time.Time{}
+	for _, date := range workoutDatesDesc {
+		week := statsBucketStart(date, statsBucketWeek)
+		if len(weeks) == 0 || !weeks[len(weeks)-1].Equal(week) {
+			weeks = append(weeks, week)
+		}
+	}
+
+	expectedWeek := statsBucketStart(to, statsBucketWeek)
+	for i, week := range weeks {
+		if i == 0 && week.Equal(expectedWeek.AddDate(0, 0, -7)) {
+			expectedWeek = week
+		}
+		if !week.Equal(expectedWeek) {
+			break
+		}
+		streaks.CurrentWeeks++
+		expectedWeek = expectedWeek.AddDate(0, 0, -7)
+	}
+
+	return streaks
+}
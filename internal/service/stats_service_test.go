@@ -0,0 +1,144 @@
+package service
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/brightside-dev/ronin-fitness-be/internal/handler/dto"
+	"github.com/brightside-dev/ronin-fitness-be/internal/model"
+)
+
+func utcDate(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func TestStatsBucketStart(t *testing.T) {
+	brisbane := time.FixedZone("AEST", 10*3600)
+
+	tests := []struct {
+		name   string
+		date   time.Time
+		bucket string
+		want   time.Time
+	}{
+		{name: "day drops the time", date: time.Date(2024, 3, 13, 15, 4, 5, 0, time.UTC), bucket: statsBucketDay, want: utcDate(2024, 3, 13)},
+		{name: "unknown bucket is a day", date: time.Date(2024, 3, 13, 15, 4, 5, 0, time.UTC), bucket: "year", want: utcDate(2024, 3, 13)},
+		{name: "week from a Wednesday", date: utcDate(2024, 3, 13), bucket: statsBucketWeek, want: utcDate(2024, 3, 11)},
+		{name: "week from a Monday", date: time.Date(2024, 3, 11, 0, 0, 0, 0, time.UTC), bucket: statsBucketWeek, want: utcDate(2024, 3, 11)},
+		{name: "week from a Sunday night", date: time.Date(2024, 3, 17, 23, 59, 59, 0, time.UTC), bucket: statsBucketWeek, want: utcDate(2024, 3, 11)},
+		{name: "week across months in a leap year", date: utcDate(2024, 3, 1), bucket: statsBucketWeek, want: utcDate(2024, 2, 26)},
+		{name: "week across years", date: utcDate(2025, 1, 1), bucket: statsBucketWeek, want: utcDate(2024, 12, 30)},
+		{name: "month", date: utcDate(2024, 2, 29), bucket: statsBucketMonth, want: utcDate(2024, 2, 1)},
+		{name: "month from its first day", date: time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC), bucket: statsBucketMonth, want: utcDate(2024, 3, 1)},
+		// The calendar day is the one of the time's own zone: Monday 00:30 in Brisbane is Sunday in UTC
+		{name: "week in another zone", date: time.Date(2024, 3, 11, 0, 30, 0, 0, brisbane), bucket: statsBucketWeek, want: utcDate(2024, 3, 11)},
+		{name: "month in another zone", date: time.Date(2024, 4, 1, 5, 0, 0, 0, brisbane), bucket: statsBucketMonth, want: utcDate(2024, 4, 1)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := statsBucketStart(tt.date, tt.bucket)
+			if !got.Equal(tt.want) || got.Location() != time.UTC {
+				t.Errorf("statsBucketStart() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStatsBucketStarts(t *testing.T) {
+	tests := []struct {
+		name   string
+		from   time.Time
+		to     time.Time
+		bucket string
+		want   []time.Time
+	}{
+		{name: "days", from: utcDate(2024, 2, 28), to: utcDate(2024, 3, 1), bucket: statsBucketDay, want: []time.Time{utcDate(2024, 2, 28), utcDate(2024, 2, 29), utcDate(2024, 3, 1)}},
+		{name: "single day", from: utcDate(2024, 3, 1), to: utcDate(2024, 3, 1), bucket: statsBucketDay, want: []time.Time{utcDate(2024, 3, 1)}},
+		{name: "weeks start on the Monday before from", from: utcDate(2024, 1, 3), to: utcDate(2024, 1, 15), bucket: statsBucketWeek, want: []time.Time{utcDate(2024, 1, 1), utcDate(2024, 1, 8), utcDate(2024, 1, 15)}},
+		{name: "months from the end of one", from: utcDate(2024, 1, 31), to: utcDate(2024, 3, 1), bucket: statsBucketMonth, want: []time.Time{utcDate(2024, 1, 1), utcDate(2024, 2, 1), utcDate(2024, 3, 1)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := statsBucketStarts(tt.from, tt.to, tt.bucket); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("statsBucketStarts() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if got := statsBucketStarts(utcDate(2020, 1, 1), utcDate(2024, 1, 1), statsBucketDay); len(got) != maxStatsBuckets+1 {
+		t.Errorf("statsBucketStarts() over the limit returned %d buckets, want it to stop at %d", len(got), maxStatsBuckets+1)
+	}
+}
+
+func TestCalculateStreaks(t *testing.T) {
+	// A Wednesday
+	to := utcDate(2024, 3, 13)
+	day := func(d int) time.Time {
+		return to.AddDate(0, 0, d-13)
+	}
+
+	tests := []struct {
+		name string
+		days []time.Time // trained, most recent first
+		want dto.StatsStreaksResponse
+	}{
+		{name: "never trained", days: nil, want: dto.StatsStreaksResponse{}},
+		{
+			name: "trained today and the days before",
+			days: []time.Time{day(13), day(12), day(11)},
+			want: dto.StatsStreaksResponse{CurrentDays: 3, LongestDays: 3, CurrentWeeks: 1},
+		},
+		{
+			name: "not trained yet today",
+			days: []time.Time{day(12), day(11), day(10)},
+			want: dto.StatsStreaksResponse{CurrentDays: 3, LongestDays: 3, CurrentWeeks: 2},
+		},
+		{
+			name: "a day off breaks the days",
+			days: []time.Time{day(13), day(11), day(10)},
+			want: dto.StatsStreaksResponse{CurrentDays: 1, LongestDays: 2, CurrentWeeks: 2},
+		},
+		{
+			name: "last trained two days ago",
+			days: []time.Time{day(11), day(5), day(-2)},
+			want: dto.StatsStreaksResponse{CurrentDays: 0, LongestDays: 1, CurrentWeeks: 3},
+		},
+		{
+			name: "last trained the week before",
+			days: []time.Time{day(8), day(7)},
+			want: dto.StatsStreaksResponse{CurrentDays: 0, LongestDays: 2, CurrentWeeks: 1},
+		},
+		{
+			name: "a week off breaks the weeks",
+			days: []time.Time{day(13), day(1)},
+			want: dto.StatsStreaksResponse{CurrentDays: 1, LongestDays: 1, CurrentWeeks: 1},
+		},
+		{
+			name: "last trained two weeks before",
+			days: []time.Time{day(1)},
+			want: dto.StatsStreaksResponse{CurrentDays: 0, LongestDays: 1, CurrentWeeks: 0},
+		},
+		{
+			name: "longest run across the end of a leap February",
+			days: []time.Time{day(1), day(0), day(-1), day(-2), day(-10)},
+			want: dto.StatsStreaksResponse{CurrentDays: 0, LongestDays: 4, CurrentWeeks: 0},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dailyStats := []model.ProfileDailyStats{}
+			for i := len(tt.days) - 1; i >= 0; i-- {
+				dailyStats = append(dailyStats, model.ProfileDailyStats{StatDate: tt.days[i], WorkoutCount: 1})
+			}
+
+			got := calculateStreaks(dailyStats, tt.days, to)
+			if got != tt.want {
+				t.Errorf("calculateStreaks() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,671 @@
+package service
+
+import (
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/brightside-dev/ronin-fitness-be/database/client"
+	"github.com/brightside-dev/ronin-fitness-be/internal/handler/dto"
+	customError "github.com/brightside-dev/ronin-fitness-be/internal/handler/error"
+	"github.com/brightside-dev/ronin-fitness-be/internal/model"
+	"github.com/brightside-dev/ronin-fitness-be/internal/repository"
+	"github.com/brightside-dev/ronin-fitness-be/internal/util"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+)
+
+type TemplateService interface {
+	CreateTemplate(w http.ResponseWriter, r *http.Request) (*dto.TemplateResponse, error)
+	CreateTemplateFromWorkout(w http.ResponseWriter, r *http.Request) (*dto.TemplateResponse, error)
+	GetTemplates(w http.ResponseWriter, r *http.Request) (*dto.TemplatesListResponse, error)
+	GetTemplate(w http.ResponseWriter, r *http.Request) (*dto.TemplateResponse, error)
+	UpdateTemplate(w http.ResponseWriter, r *http.Request) (*dto.TemplateResponse, error)
+	DeleteTemplate(w http.ResponseWriter, r *http.Request) error
+	ShareTemplate(w http.ResponseWriter, r *http.Request) (*dto.TemplateShareResponse, error)
+	UnshareTemplate(w http.ResponseWriter, r *http.Request) error
+	GetSharedTemplate(w http.ResponseWriter, r *http.Request) (*dto.TemplateResponse, error)
+	CopySharedTemplate(w http.ResponseWriter, r *http.Request) (*dto.TemplateResponse, error)
+	StartWorkout(w http.ResponseWriter, r *http.Request) (*dto.WorkoutDraftResponse, error)
+}
+
+type templateService struct {
+	DB                        client.DatabaseService
+	DBLogger                  *slog.Logger
+	Validate                  *validator.Validate
+	WorkoutTemplateRepository repository.WorkoutTemplateRepository
+	WorkoutRepository         repository.WorkoutRepository
+	SetRepository             repository.SetRepository
+	ExerciseRepository        repository.ExerciseRepository
+	ProfileRepository         repository.ProfileRepository
+	ProfileBlockRepository    repository.ProfileBlockRepository
+}
+
+func NewTemplateService(
+	db client.DatabaseService,
+	dbLogger *slog.Logger,
+	validator *validator.Validate,
+	workoutTemplateRepository repository.WorkoutTemplateRepository,
+	workoutRepository repository.WorkoutRepository,
+	setRepository repository.SetRepository,
+	exerciseRepository repository.ExerciseRepository,
+	profileRepository repository.ProfileRepository,
+	profileBlockRepository repository.ProfileBlockRepository,
+) TemplateService {
+	return &templateService{
+		DB:                        db,
+		DBLogger:                  dbLogger,
+		Validate:                  validator,
+		WorkoutTemplateRepository: workoutTemplateRepository,
+		WorkoutRepository:         workoutRepository,
+		SetRepository:             setRepository,
+		ExerciseRepository:        exerciseRepository,
+		ProfileRepository:         profileRepository,
+		ProfileBlockRepository:    profileBlockRepository,
+	}
+}
+
+func (s *templateService) CreateTemplate(w http.ResponseWriter, r *http.Request) (*dto.TemplateResponse, error) {
+	req := dto.TemplateCreateRequest{}
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrInvalidRequestBody
+	}
+
+	err = s.Validate.Struct(req)
+	if err != nil {
+		return nil, util.FormatValidationError(err.(validator.ValidationErrors))
+	}
+
+	profile, err := getAuthProfile(r, s.ProfileRepository)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrUnAuthorized
+	}
+
+	sets := templateSetsFromRequest(req.Sets, templateRequestUnit(req, profile))
+	err = s.validateExercises(r, profile.ID, sets)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.createTemplate(r, profile, &model.WorkoutTemplate{
+		ProfileID:   profile.ID,
+		Name:        req.Name,
+		Description: req.Description,
+	}, sets)
+}
+
+func (s *templateService) CreateTemplateFromWorkout(w http.ResponseWriter, r *http.Request) (*dto.TemplateResponse, error) {
+	workoutID, err := getIDParam(r, "workoutId")
+	if err != nil {
+		return nil, err
+	}
+
+	req := dto.TemplateFromWorkoutRequest{}
+	if r.ContentLength != 0 {
+		err = json.NewDecoder(r.Body).Decode(&req)
+		if err != nil {
+			util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+			return nil, customError.ErrInvalidRequestBody
+		}
+	}
+
+	err = s.Validate.Struct(req)
+	if err != nil {
+		return nil, util.FormatValidationError(err.(validator.ValidationErrors))
+	}
+
+	profile, err := getAuthProfile(r, s.ProfileRepository)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrUnAuthorized
+	}
+
+	workout, err := s.WorkoutRepository.GetByID(r.Context(), workoutID)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrInternalServerError
+	}
+
+	// Only the owner can turn a workout into a template
+	if workout == nil || workout.ProfileID != profile.ID {
+		return nil, customError.ErrNotFound
+	}
+
+	workoutSets, err := s.SetRepository.GetByWorkoutID(r.Context(), workout.ID)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrInternalServerError
+	}
+
+	if len(workoutSets) == 0 {
+		return nil, fmt.Errorf("workout has no sets to save as a template")
+	}
+
+	setRequests := make([]dto.TemplateSetRequest, 0, len(workoutSets))
+	for _, set := range workoutSets {
+		setRequests = append(setRequests, dto.TemplateSetRequest{
+			ExerciseID:     set.ExerciseID,
+			SetNumber:      set.SetNumber,
+			TargetReps:     set.Reps,
+			TargetWeight:   set.WeightKg,
+			TargetDuration: set.Duration,
+		})
+	}
+
+	name := req.Name
+	if name == "" {
+		name = workout.Name
+	}
+
+	return s.createTemplate(r, profile, &model.WorkoutTemplate{
+		ProfileID:   profile.ID,
+		Name:        name,
+		Description: workout.Description,
+	}, templateSetsFromRequest(setRequests, util.WeightUnitKg))
+}
+
+func (s *templateService) GetTemplates(w http.ResponseWriter, r *http.Request) (*dto.TemplatesListResponse, error) {
+	profile, err := getAuthProfile(r, s.ProfileRepository)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrUnAuthorized
+	}
+
+	templates, err := s.WorkoutTemplateRepository.GetByProfileID(r.Context(), profile.ID)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrInternalServerError
+	}
+
+	templateResponses, err := s.buildTemplateResponses(r, templates, profile)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.TemplatesListResponse{Templates: templateResponses}, nil
+}
+
+func (s *templateService) GetTemplate(w http.ResponseWriter, r *http.Request) (*dto.TemplateResponse, error) {
+	profile, template, err := s.getOwnTemplate(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.buildTemplateResponse(r, template, profile)
+}
+
+func (s *templateService) UpdateTemplate(w http.ResponseWriter, r *http.Request) (*dto.TemplateResponse, error) {
+	req := dto.TemplateCreateRequest{}
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrInvalidRequestBody
+	}
+
+	err = s.Validate.Struct(req)
+	if err != nil {
+		return nil, util.FormatValidationError(err.(validator.ValidationErrors))
+	}
+
+	profile, template, err := s.getOwnTemplate(r)
+	if err != nil {
+		return nil, err
+	}
+
+	sets := templateSetsFromRequest(req.Sets, templateRequestUnit(req, profile))
+	err = s.validateExercises(r, profile.ID, sets)
+	if err != nil {
+		return nil, err
+	}
+
+	template.Name = req.Name
+	template.Description = req.Description
+
+	_, err = util.WithTransaction(r.Context(), s.DB.GetDB(), func(tx *sql.Tx) (interface{}, error) {
+		err := s.WorkoutTemplateRepository.Update(r.Context(), tx, template)
+		if err != nil {
+			return nil, err
+		}
+
+		return nil, s.WorkoutTemplateRepository.ReplaceSets(r.Context(), tx, template.ID, sets)
+	})
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrInternalServerError
+	}
+
+	return s.getTemplateResponse(r, template.ID, profile)
+}
+
+func (s *templateService) DeleteTemplate(w http.ResponseWriter, r *http.Request) error {
+	_, template, err := s.getOwnTemplate(r)
+	if err != nil {
+		return err
+	}
+
+	isUsed, err := s.WorkoutTemplateRepository.IsUsedByProgram(r.Context(), template.ID)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return customError.ErrInternalServerError
+	}
+
+	if isUsed {
+		return fmt.Errorf("%w: template is used by a program", customError.ErrConflict)
+	}
+
+	err = s.WorkoutTemplateRepository.Delete(r.Context(), template.ID)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return customError.ErrInternalServerError
+	}
+
+	return nil
+}
+
+// ShareTemplate creates a share link for the template, reusing the existing one if it is already shared.
+func (s *templateService) ShareTemplate(w http.ResponseWriter, r *http.Request) (*dto.TemplateShareResponse, error) {
+	_, template, err := s.getOwnTemplate(r)
+	if err != nil {
+		return nil, err
+	}
+
+	if template.ShareToken == nil {
+		shareToken, err := generateRandomToken()
+		if err != nil {
+			util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+			return nil, customError.ErrInternalServerError
+		}
+
+		err = s.WorkoutTemplateRepository.UpdateShareToken(r.Context(), template.ID, &shareToken)
+		if err != nil {
+			util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+			return nil, customError.ErrInternalServerError
+		}
+
+		template.ShareToken = &shareToken
+	}
+
+	return &dto.TemplateShareResponse{
+		ShareToken: *template.ShareToken,
+		ShareURL:   fmt.Sprintf("%s/templates/shared/%s", strings.TrimRight(os.Getenv("APP_URL"), "/"), *template.ShareToken),
+	}, nil
+}
+
+func (s *templateService) UnshareTemplate(w http.ResponseWriter, r *http.Request) error {
+	_, template, err := s.getOwnTemplate(r)
+	if err != nil {
+		return err
+	}
+
+	err = s.WorkoutTemplateRepository.UpdateShareToken(r.Context(), template.ID, nil)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return customError.ErrInternalServerError
+	}
+
+	return nil
+}
+
+func (s *templateService) GetSharedTemplate(w http.ResponseWriter, r *http.Request) (*dto.TemplateResponse, error) {
+	profile, template, err := s.getSharedTemplate(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.buildTemplateResponse(r, template, profile)
+}
+
+// CopySharedTemplate saves a copy of a shared template to the caller's profile. Custom exercises
+// of the template owner are copied along so the new template only references exercises the
+// caller can log.
+func (s *templateService) CopySharedTemplate(w http.ResponseWriter, r *http.Request) (*dto.TemplateResponse, error) {
+	profile, source, err := s.getSharedTemplate(r)
+	if err != nil {
+		return nil, err
+	}
+
+	setsByTemplate, err := s.WorkoutTemplateRepository.GetSetsByTemplateIDs(r.Context(), []int{source.ID})
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrInternalServerError
+	}
+	sets := setsByTemplate[source.ID]
+
+	exerciseIDs := make([]int, 0, len(sets))
+	for _, set := range sets {
+		exerciseIDs = append(exerciseIDs, set.ExerciseID)
+	}
+
+	exercises, err := s.ExerciseRepository.GetByIDs(r.Context(), exerciseIDs)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrInternalServerError
+	}
+
+	result, err := util.WithTransaction(r.Context(), s.DB.GetDB(), func(tx *sql.Tx) (interface{}, error) {
+		copiedExerciseIDs := map[int]int{}
+		for i, set := range sets {
+			exercise := exercises[set.ExerciseID]
+			if exercise.ProfileID != nil && *exercise.ProfileID != profile.ID {
+				copiedID, ok := copiedExerciseIDs[exercise.ID]
+				if !ok {
+					copied, err := s.ExerciseRepository.Create(r.Context(), tx, &model.Exercise{
+						ProfileID:   &profile.ID,
+						Name:        exercise.Name,
+						Description: exercise.Description,
+						IconName:    exercise.IconName,
+						MuscleGroup: exercise.MuscleGroup,
+					})
+					if err != nil {
+						return nil, err
+					}
+					copiedID = copied.ID
+					copiedExerciseIDs[exercise.ID] = copiedID
+				}
+				sets[i].ExerciseID = copiedID
+			}
+		}
+
+		template, err := s.WorkoutTemplateRepository.Create(r.Context(), tx, &model.WorkoutTemplate{
+			ProfileID:        profile.ID,
+			Name:             source.Name,
+			Description:      source.Description,
+			SourceTemplateID: &source.ID,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		err = s.WorkoutTemplateRepository.ReplaceSets(r.Context(), tx, template.ID, sets)
+		if err != nil {
+			return nil, err
+		}
+
+		return template, nil
+	})
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrInternalServerError
+	}
+
+	return s.getTemplateResponse(r, result.(*model.WorkoutTemplate).ID, profile)
+}
+
+// StartWorkout returns a workout draft pre-filled with the template's target sets.
+func (s *templateService) StartWorkout(w http.ResponseWriter, r *http.Request) (*dto.WorkoutDraftResponse, error) {
+	profile, template, err := s.getOwnTemplate(r)
+	if err != nil {
+		return nil, err
+	}
+
+	setsByTemplate, err := s.WorkoutTemplateRepository.GetSetsByTemplateIDs(r.Context(), []int{template.ID})
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrInternalServerError
+	}
+
+	res := &dto.WorkoutDraftResponse{
+		TemplateID:  template.ID,
+		Name:        template.Name,
+		Description: template.Description,
+		Sets:        make([]dto.SetRequest, 0, len(setsByTemplate[template.ID])),
+	}
+	for _, set := range setsByTemplate[template.ID] {
+		res.Sets = append(res.Sets, dto.SetRequest{
+			ExerciseID: set.ExerciseID,
+			SetNumber:  set.SetNumber,
+			Duration:   set.TargetDuration,
+			Weight:     util.FromKg(set.TargetWeightKg, profile.WeightUnit),
+			Unit:       profile.WeightUnit,
+			Reps:       set.TargetReps,
+		})
+	}
+
+	return res, nil
+}
+
+func (s *templateService) createTemplate(r *http.Request, profile *model.ProfileWithUser, template *model.WorkoutTemplate, sets []model.WorkoutTemplateSet) (*dto.TemplateResponse, error) {
+	result, err := util.WithTransaction(r.Context(), s.DB.GetDB(), func(tx *sql.Tx) (interface{}, error) {
+		template, err := s.WorkoutTemplateRepository.Create(r.Context(), tx, template)
+		if err != nil {
+			return nil, err
+		}
+
+		err = s.WorkoutTemplateRepository.ReplaceSets(r.Context(), tx, template.ID, sets)
+		if err != nil {
+			return nil, err
+		}
+
+		return template, nil
+	})
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrInternalServerError
+	}
+
+	return s.getTemplateResponse(r, result.(*model.WorkoutTemplate).ID, profile)
+}
+
+// validateExercises checks that every set references a default exercise or one of the profile's own.
+func (s *templateService) validateExercises(r *http.Request, profileID int, sets []model.WorkoutTemplateSet) error {
+	exerciseIDs := make([]int, 0, len(sets))
+	for _, set := range sets {
+		exerciseIDs = append(exerciseIDs, set.ExerciseID)
+	}
+
+	exercises, err := s.ExerciseRepository.GetByIDs(r.Context(), exerciseIDs)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return customError.ErrInternalServerError
+	}
+
+	for _, exerciseID := range exerciseIDs {
+		exercise, ok := exercises[exerciseID]
+		if !ok || (exercise.ProfileID != nil && *exercise.ProfileID != profileID) {
+			return fmt.Errorf("exercise %d not found", exerciseID)
+		}
+	}
+
+	return nil
+}
+
+// getOwnTemplate loads the template named by the templateId URL parameter, failing unless it
+// belongs to the caller.
+func (s *templateService) getOwnTemplate(r *http.Request) (*model.ProfileWithUser, *model.WorkoutTemplate, error) {
+	templateID, err := getIDParam(r, "templateId")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	profile, err := getAuthProfile(r, s.ProfileRepository)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, nil, customError.ErrUnAuthorized
+	}
+
+	template, err := s.WorkoutTemplateRepository.GetByID(r.Context(), templateID)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, nil, customError.ErrInternalServerError
+	}
+
+	if template == nil || template.ProfileID != profile.ID {
+		return nil, nil, customError.ErrNotFound
+	}
+
+	return profile, template, nil
+}
+
+// getSharedTemplate loads the template named by the shareToken URL parameter.
+func (s *templateService) getSharedTemplate(r *http.Request) (*model.ProfileWithUser, *model.WorkoutTemplate, error) {
+	profile, err := getAuthProfile(r, s.ProfileRepository)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, nil, customError.ErrUnAuthorized
+	}
+
+	template, err := s.WorkoutTemplateRepository.GetByShareToken(r.Context(), chi.URLParam(r, "shareToken"))
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, nil, customError.ErrInternalServerError
+	}
+
+	if template == nil {
+		return nil, nil, customError.ErrNotFound
+	}
+
+	// A share link stops working between profiles that blocked each other
+	blocked, err := s.ProfileBlockRepository.IsBlocked(r.Context(), template.ProfileID, profile.ID)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, nil, customError.ErrInternalServerError
+	}
+
+	if blocked {
+		return nil, nil, customError.ErrNotFound
+	}
+
+	return profile, template, nil
+}
+
+func (s *templateService) getTemplateResponse(r *http.Request, templateID int, viewer *model.ProfileWithUser) (*dto.TemplateResponse, error) {
+	template, err := s.WorkoutTemplateRepository.GetByID(r.Context(), templateID)
+	if err != nil || template == nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, "failed to get template", nil, r)
+		return nil, customError.ErrInternalServerError
+	}
+
+	return s.buildTemplateResponse(r, template, viewer)
+}
+
+func (s *templateService) buildTemplateResponse(r *http.Request, template *model.WorkoutTemplate, viewer *model.ProfileWithUser) (*dto.TemplateResponse, error) {
+	templateResponses, err := s.buildTemplateResponses(r, []model.WorkoutTemplate{*template}, viewer)
+	if err != nil {
+		return nil, err
+	}
+
+	return &templateResponses[0], nil
+}
+
+// buildTemplateResponses groups the target sets of each template by exercise, with weights in the viewer's unit.
+func (s *templateService) buildTemplateResponses(r *http.Request, templates []model.WorkoutTemplate, viewer *model.ProfileWithUser) ([]dto.TemplateResponse, error) {
+	templateIDs := make([]int, 0, len(templates))
+	for _, template := range templates {
+		templateIDs = append(templateIDs, template.ID)
+	}
+
+	setsByTemplate, err := s.WorkoutTemplateRepository.GetSetsByTemplateIDs(r.Context(), templateIDs)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrInternalServerError
+	}
+
+	exerciseIDs := []int{}
+	for _, sets := range setsByTemplate {
+		for _, set := range sets {
+			exerciseIDs = append(exerciseIDs, set.ExerciseID)
+		}
+	}
+
+	exercises, err := s.ExerciseRepository.GetByIDs(r.Context(), exerciseIDs)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrInternalServerError
+	}
+
+	templateResponses := make([]dto.TemplateResponse, 0, len(templates))
+	for _, template := range templates {
+		templateExercises := []dto.TemplateExerciseResponse{}
+		for _, set := range setsByTemplate[template.ID] {
+			last := len(templateExercises) - 1
+			if last < 0 || templateExercises[last].ExerciseID != set.ExerciseID {
+				exercise := exercises[set.ExerciseID]
+				templateExercises = append(templateExercises, dto.TemplateExerciseResponse{
+					ExerciseID: set.ExerciseID,
+					Name:       exercise.Name,
+					IconName:   exercise.IconName,
+					Sets:       []dto.TemplateSetResponse{},
+				})
+				last++
+			}
+
+			templateExercises[last].Sets = append(templateExercises[last].Sets, dto.TemplateSetResponse{
+				SetNumber:      set.SetNumber,
+				TargetReps:     set.TargetReps,
+				TargetWeight:   util.FromKg(set.TargetWeightKg, viewer.WeightUnit),
+				TargetDuration: set.TargetDuration,
+			})
+		}
+
+		templateResponse := dto.TemplateResponse{
+			ID:               template.ID,
+			ProfileID:        template.ProfileID,
+			Name:             template.Name,
+			Description:      template.Description,
+			SourceTemplateID: template.SourceTemplateID,
+			Exercises:        templateExercises,
+			WeightUnit:       viewer.WeightUnit,
+			CreatedAt:        template.CreatedAt,
+			UpdatedAt:        template.UpdatedAt,
+		}
+		if template.ProfileID == viewer.ID {
+			templateResponse.ShareToken = template.ShareToken
+		}
+
+		templateResponses = append(templateResponses, templateResponse)
+	}
+
+	return templateResponses, nil
+}
+
+// templateSetsFromRequest converts the requested sets to kilograms, numbering exercises in the order they first appear.
+func templateSetsFromRequest(setRequests []dto.TemplateSetRequest, unit string) []model.WorkoutTemplateSet {
+	exerciseOrder := map[int]int{}
+	sets := make([]model.WorkoutTemplateSet, 0, len(setRequests))
+	for _, setReq := range setRequests {
+		order, ok := exerciseOrder[setReq.ExerciseID]
+		if !ok {
+			order = len(exerciseOrder) + 1
+			exerciseOrder[setReq.ExerciseID] = order
+		}
+
+		sets = append(sets, model.WorkoutTemplateSet{
+			ExerciseID:     setReq.ExerciseID,
+			ExerciseOrder:  order,
+			SetNumber:      setReq.SetNumber,
+			TargetReps:     setReq.TargetReps,
+			TargetWeightKg: util.ToKg(setReq.TargetWeight, unit),
+			TargetDuration: setReq.TargetDuration,
+		})
+	}
+
+	return sets
+}
+
+// generateRandomToken returns 32 hex characters that are impractical to guess.
+func generateRandomToken() (string, error) {
+	b := make([]byte, 16)
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(b), nil
+}
+
+func templateRequestUnit(req dto.TemplateCreateRequest, profile *model.ProfileWithUser) string {
+	if req.Unit != "" {
+		return req.Unit
+	}
+
+	return profile.WeightUnit
+}
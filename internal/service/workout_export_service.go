@@ -0,0 +1,380 @@
+package service
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/brightside-dev/ronin-fitness-be/database/client"
+	"github.com/brightside-dev/ronin-fitness-be/internal/handler/dto"
+	customError "github.com/brightside-dev/ronin-fitness-be/internal/handler/error"
+	"github.com/brightside-dev/ronin-fitness-be/internal/model"
+	"github.com/brightside-dev/ronin-fitness-be/internal/repository"
+	"github.com/brightside-dev/ronin-fitness-be/internal/util"
+	"github.com/go-playground/validator/v10"
+)
+
+const (
+	workoutExportFormatCSV  = "csv"
+	workoutExportFormatJSON = "json"
+	workoutExportFormatICS  = "ics"
+
+	// exportFlushInterval is how many workouts are written between flushes to the client
+	exportFlushInterval = 50
+	// exportWriteTimeout is how long the client gets to receive each flushed chunk, the server's
+	// write timeout would otherwise cut off large exports
+	exportWriteTimeout = 30 * time.Second
+)
+
+var workoutExportContentTypes = map[string]string{
+	workoutExportFormatCSV:  "text/csv; charset=utf-8",
+	workoutExportFormatJSON: "application/json",
+	workoutExportFormatICS:  "text/calendar; charset=utf-8",
+}
+
+type WorkoutExportService interface {
+	ExportWorkouts(w http.ResponseWriter, r *http.Request) error
+}
+
+type workoutExportService struct {
+	DB                client.DatabaseService
+	DBLogger          *slog.Logger
+	Validate          *validator.Validate
+	WorkoutRepository repository.WorkoutRepository
+	ProfileRepository repository.ProfileRepository
+}
+
+func NewWorkoutExportService(
+	db client.DatabaseService,
+	dbLogger *slog.Logger,
+	validator *validator.Validate,
+	workoutRepository repository.WorkoutRepository,
+	profileRepository repository.ProfileRepository,
+) WorkoutExportService {
+	return &workoutExportService{
+		DB:                db,
+		DBLogger:          dbLogger,
+		Validate:          validator,
+		WorkoutRepository: workoutRepository,
+		ProfileRepository: profileRepository,
+	}
+}
+
+// ExportWorkouts streams the caller's workouts with their sets in the requested format, holding
+// only one workout in memory at a time. Errors are returned until the response starts; after that
+// they can only be logged and the download ends early.
+func (s *workoutExportService) ExportWorkouts(w http.ResponseWriter, r *http.Request) error {
+	req := dto.WorkoutExportRequest{
+		Format: r.URL.Query().Get("format"),
+		From:   r.URL.Query().Get("from"),
+		To:     r.URL.Query().Get("to"),
+	}
+
+	err := s.Validate.Struct(req)
+	if err != nil {
+		return util.FormatValidationError(err.(validator.ValidationErrors))
+	}
+
+	from := time.Unix(0, 0).UTC()
+	if req.From != "" {
+		from, _ = time.Parse("2006-01-02", req.From)
+	}
+
+	// to is inclusive, the query takes the start of the next day
+	to := time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, 1)
+	if req.To != "" {
+		to, _ = time.Parse("2006-01-02", req.To)
+		to = to.AddDate(0, 0, 1)
+	}
+
+	if !from.Before(to) {
+		return fmt.Errorf("from must not be after to")
+	}
+
+	profile, err := getAuthProfile(r, s.ProfileRepository)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return customError.ErrUnAuthorized
+	}
+
+	w.Header().Set("Content-Type", workoutExportContentTypes[req.Format])
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="workouts-%s.%s"`, time.Now().UTC().Format("2006-01-02"), req.Format))
+	w.WriteHeader(http.StatusOK)
+
+	controller := http.NewResponseController(w)
+	controller.SetWriteDeadline(time.Now().Add(exportWriteTimeout))
+
+	buffered := bufio.NewWriter(w)
+	exporter := newWorkoutExporter(req.Format, buffered, profile.WeightUnit)
+
+	var workout *model.Workout
+	sets := []model.WorkoutExportRow{}
+	workoutCount := 0
+
+	writeWorkout := func() error {
+		if workout == nil {
+			return nil
+		}
+
+		err := exporter.WriteWorkout(*workout, sets)
+		if err != nil {
+			return err
+		}
+
+		workoutCount++
+		if workoutCount%exportFlushInterval == 0 {
+			err = buffered.Flush()
+			if err != nil {
+				return err
+			}
+			controller.Flush()
+			controller.SetWriteDeadline(time.Now().Add(exportWriteTimeout))
+		}
+
+		return nil
+	}
+
+	err = exporter.Begin()
+	if err == nil {
+		err = s.WorkoutRepository.ExportByProfileID(r.Context(), profile.ID, from, to, func(row *model.WorkoutExportRow) error {
+			if workout == nil || row.ID != workout.ID {
+				err := writeWorkout()
+				if err != nil {
+					return err
+				}
+
+				workout = &row.Workout
+				sets = sets[:0]
+			}
+
+			if row.Set != nil {
+				sets = append(sets, *row)
+			}
+
+			return nil
+		})
+	}
+	if err == nil {
+		err = writeWorkout()
+	}
+	if err == nil {
+		err = exporter.End()
+	}
+	if err == nil {
+		err = buffered.Flush()
+	}
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+	}
+
+	return nil
+}
+
+// workoutExporter writes workouts in one export format. WriteWorkout receives the rows of the
+// workout's sets in the order they were logged.
+type workoutExporter interface {
+	Begin() error
+	WriteWorkout(workout model.Workout, sets []model.WorkoutExportRow) error
+	End() error
+}
+
+func newWorkoutExporter(format string, w io.Writer, unit string) workoutExporter {
+	switch format {
+	case workoutExportFormatJSON:
+		return &jsonWorkoutExporter{w: w, unit: unit}
+	case workoutExportFormatICS:
+		return &icsWorkoutExporter{w: w, unit: unit}
+	default:
+		return &csvWorkoutExporter{w: csv.NewWriter(w), unit: unit}
+	}
+}
+
+// csvWorkoutExporter writes one row per set. Workouts without sets get a row with empty set columns.
+type csvWorkoutExporter struct {
+	w    *csv.Writer
+	unit string
+}
+
+func (e *csvWorkoutExporter) Begin() error {
+	return e.w.Write([]string{
+		"workout_id", "workout_name", "workout_description", "start_date", "end_date",
+		"mental_energy_level", "physical_energy_level", "exercise_id", "exercise_name", "muscle_group",
+		"set_number", "reps", "weight", "weight_unit", "duration_seconds", "estimated_one_rep_max",
+	})
+}
+
+func (e *csvWorkoutExporter) WriteWorkout(workout model.Workout, sets []model.WorkoutExportRow) error {
+	workoutColumns := []string{
+		strconv.Itoa(workout.ID),
+		workout.Name,
+		workout.Description,
+		workout.StartDate.UTC().Format(time.RFC3339),
+		workout.EndDate.UTC().Format(time.RFC3339),
+		strconv.Itoa(workout.MentalEnergyLevel),
+		strconv.Itoa(workout.PhysicalEnergyLevel),
+	}
+
+	if len(sets) == 0 {
+		return e.w.Write(append(workoutColumns, "", "", "", "", "", "", "", "", ""))
+	}
+
+	for _, row := range sets {
+		err := e.w.Write(append(workoutColumns,
+			strconv.Itoa(row.Set.ExerciseID),
+			row.ExerciseName,
+			row.MuscleGroup,
+			strconv.Itoa(row.Set.SetNumber),
+			strconv.Itoa(row.Set.Reps),
+			strconv.FormatFloat(util.FromKg(row.Set.WeightKg, e.unit), 'f', -1, 64),
+			e.unit,
+			strconv.Itoa(row.Set.Duration),
+			strconv.FormatFloat(util.FromKg(row.Set.E1RMKg, e.unit), 'f', -1, 64),
+		))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (e *csvWorkoutExporter) End() error {
+	e.w.Flush()
+
+	return e.w.Error()
+}
+
+// jsonWorkoutExporter writes an object holding the weight unit and the array of workouts,
+// one workout at a time.
+type jsonWorkoutExporter struct {
+	w            io.Writer
+	unit         string
+	workoutCount int
+}
+
+func (e *jsonWorkoutExporter) Begin() error {
+	_, err := fmt.Fprintf(e.w, `{"weight_unit":%q,"workouts":[`, e.unit)
+
+	return err
+}
+
+func (e *jsonWorkoutExporter) WriteWorkout(workout model.Workout, sets []model.WorkoutExportRow) error {
+	res := dto.WorkoutExportResponse{
+		ID:                  workout.ID,
+		Name:                workout.Name,
+		Description:         workout.Description,
+		MentalEnergyLevel:   workout.MentalEnergyLevel,
+		PhysicalEnergyLevel: workout.PhysicalEnergyLevel,
+		StartDate:           workout.StartDate,
+		EndDate:             workout.EndDate,
+		Sets:                make([]dto.WorkoutExportSetResponse, 0, len(sets)),
+	}
+	for _, row := range sets {
+		res.Sets = append(res.Sets, dto.WorkoutExportSetResponse{
+			ExerciseID:         row.Set.ExerciseID,
+			ExerciseName:       row.ExerciseName,
+			MuscleGroup:        row.MuscleGroup,
+			SetNumber:          row.Set.SetNumber,
+			Reps:               row.Set.Reps,
+			Weight:             util.FromKg(row.Set.WeightKg, e.unit),
+			Duration:           row.Set.Duration,
+			EstimatedOneRepMax: util.FromKg(row.Set.E1RMKg, e.unit),
+		})
+	}
+
+	data, err := json.Marshal(res)
+	if err != nil {
+		return err
+	}
+
+	if e.workoutCount > 0 {
+		_, err = io.WriteString(e.w, ",")
+		if err != nil {
+			return err
+		}
+	}
+	e.workoutCount++
+
+	_, err = e.w.Write(data)
+
+	return err
+}
+
+func (e *jsonWorkoutExporter) End() error {
+	_, err := io.WriteString(e.w, "]}\n")
+
+	return err
+}
+
+// icsWorkoutExporter writes a calendar with one event per workout.
+type icsWorkoutExporter struct {
+	w    io.Writer
+	unit string
+}
+
+func (e *icsWorkoutExporter) Begin() error {
+	return util.WriteICalHeader(e.w, "Ronin Fitness workouts")
+}
+
+func (e *icsWorkoutExporter) WriteWorkout(workout model.Workout, sets []model.WorkoutExportRow) error {
+	return util.WriteICalEvent(e.w, workoutICalEvent(workout, sets, e.unit))
+}
+
+func (e *icsWorkoutExporter) End() error {
+	return util.WriteICalFooter(e.w)
+}
+
+// workoutICalEvent turns a workout into a calendar event whose description lists its sets per exercise.
+func workoutICalEvent(workout model.Workout, sets []model.WorkoutExportRow, unit string) util.ICalEvent {
+	exerciseNames := []string{}
+	setsByExercise := map[string][]string{}
+	for _, row := range sets {
+		if _, ok := setsByExercise[row.ExerciseName]; !ok {
+			exerciseNames = append(exerciseNames, row.ExerciseName)
+		}
+		setsByExercise[row.ExerciseName] = append(setsByExercise[row.ExerciseName], formatExportSet(*row.Set, unit))
+	}
+
+	lines := []string{}
+	if workout.Description != "" {
+		lines = append(lines, workout.Description)
+		if len(exerciseNames) > 0 {
+			lines = append(lines, "")
+		}
+	}
+	for _, name := range exerciseNames {
+		lines = append(lines, fmt.Sprintf("%s: %s", name, strings.Join(setsByExercise[name], ", ")))
+	}
+
+	return util.ICalEvent{
+		UID:          fmt.Sprintf("workout-%d@ronin-fitness", workout.ID),
+		Start:        workout.StartDate,
+		End:          workout.EndDate,
+		Summary:      workout.Name,
+		Description:  strings.Join(lines, "\n"),
+		LastModified: workout.UpdatedAt,
+	}
+}
+
+// formatExportSet describes a set briefly, e.g. "100 kg x 5" or "60 s".
+func formatExportSet(set model.Set, unit string) string {
+	parts := []string{}
+	if set.WeightKg > 0 {
+		parts = append(parts, fmt.Sprintf("%s %s", strconv.FormatFloat(util.FromKg(set.WeightKg, unit), 'f', -1, 64), unit))
+	}
+	if set.Reps > 0 {
+		parts = append(parts, fmt.Sprintf("x %d", set.Reps))
+	}
+	if set.Duration > 0 {
+		parts = append(parts, fmt.Sprintf("%d s", set.Duration))
+	}
+
+	return strings.Join(parts, " ")
+}
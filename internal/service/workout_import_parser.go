@@ -0,0 +1,354 @@
+package service
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/brightside-dev/ronin-fitness-be/internal/model"
+	"github.com/brightside-dev/ronin-fitness-be/internal/util"
+)
+
+const (
+	// maxImportErrors bounds how many failed rows are kept per import, the rest are only counted
+	maxImportErrors = 500
+	// maxImportRawRowLength bounds the copy of a failed row stored with its error
+	maxImportRawRowLength      = 1000
+	defaultImportedWorkoutName = "Imported workout"
+)
+
+// importColumns lists the accepted header names of each field per source; the first one present is used.
+var importColumns = map[string]map[string][]string{
+	model.WorkoutImportSourceStrong: {
+		"workout_name": {"workout name"},
+		"start":        {"date"},
+		"duration":     {"duration", "duration (sec)"},
+		"description":  {"workout notes"},
+		"exercise":     {"exercise name"},
+		"set_order":    {"set order"},
+		"weight":       {"weight", "weight (kg)", "weight (lbs)"},
+		"weight_unit":  {"weight unit"},
+		"reps":         {"reps"},
+		"seconds":      {"seconds"},
+	},
+	model.WorkoutImportSourceHevy: {
+		"workout_name": {"title"},
+		"start":        {"start_time"},
+		"end":          {"end_time"},
+		"description":  {"description"},
+		"exercise":     {"exercise_title"},
+		"weight":       {"weight_kg", "weight_lbs"},
+		"reps":         {"reps"},
+		"seconds":      {"duration_seconds"},
+	},
+}
+
+var importDateLayouts = map[string][]string{
+	model.WorkoutImportSourceStrong: {"2006-01-02 15:04:05", "2006-01-02 15:04", time.RFC3339},
+	model.WorkoutImportSourceHevy:   {"2 Jan 2006, 15:04", "2 Jan 2006 15:04", "2006-01-02 15:04:05", time.RFC3339},
+}
+
+// Header names that say which unit the weight column is in
+var importWeightHeaderUnits = map[string]string{
+	"weight (kg)":  util.WeightUnitKg,
+	"weight (lbs)": util.WeightUnitLb,
+	"weight_kg":    util.WeightUnitKg,
+	"weight_lbs":   util.WeightUnitLb,
+}
+
+var importDurationPattern = regexp.MustCompile(`(\d+)\s*([hms])`)
+
+type importedSet struct {
+	LineNumber   int
+	ExerciseName string
+	SetNumber    int
+	WeightKg     float64
+	Reps         int
+	Duration     int
+}
+
+type importedWorkout struct {
+	Name        string
+	Description string
+	StartDate   time.Time
+	EndDate     time.Time
+	Sets        []importedSet
+}
+
+// importKey identifies a workout across imports, so the same history uploaded twice (even from a
+// different export) is only saved once.
+func (w importedWorkout) importKey() string {
+	sum := sha256.Sum256([]byte(w.StartDate.UTC().Format(time.RFC3339) + "|" + strings.ToLower(strings.TrimSpace(w.Name))))
+	return fmt.Sprintf("%x", sum)
+}
+
+type importFile struct {
+	Source     string
+	TotalRows  int
+	Workouts   []importedWorkout // oldest first
+	Errors     []model.WorkoutImportError
+	ErrorCount int
+}
+
+// detectImportSource recognises the export a header row comes from.
+func detectImportSource(columns map[string]int) string {
+	for _, source := range []string{model.WorkoutImportSourceStrong, model.WorkoutImportSourceHevy} {
+		if importColumn(columns, source, "exercise") >= 0 && importColumn(columns, source, "start") >= 0 {
+			return source
+		}
+	}
+
+	return ""
+}
+
+func importColumn(columns map[string]int, source string, field string) int {
+	for _, name := range importColumns[source][field] {
+		if i, ok := columns[name]; ok {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// parseImportFile reads a Strong or Hevy CSV export. Rows that cannot be imported are reported as
+// errors and skipped; an error is only returned when the file itself cannot be read. Dates without
+// a time zone are taken as UTC, and weights the file does not label are in unit.
+func parseImportFile(data []byte, source string, unit string) (*importFile, error) {
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+
+	// Some locales export with semicolons, as commas are used for decimals
+	headerLine, _, _ := bytes.Cut(data, []byte("\n"))
+	delimiter := ','
+	if bytes.Count(headerLine, []byte(";")) > bytes.Count(headerLine, []byte(",")) {
+		delimiter = ';'
+	}
+
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.Comma = delimiter
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("file is not a readable CSV file")
+	}
+
+	columns := map[string]int{}
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+
+	detected := detectImportSource(columns)
+	if detected == "" || (source != "" && source != detected) {
+		if source == "" {
+			return nil, fmt.Errorf("file is not a Strong or Hevy export")
+		}
+		return nil, fmt.Errorf("file is not a %s export", source)
+	}
+
+	weightColumn := importColumn(columns, detected, "weight")
+	for name, headerUnit := range importWeightHeaderUnits {
+		if i, ok := columns[name]; ok && i == weightColumn {
+			unit = headerUnit
+		}
+	}
+
+	parsed := &importFile{Source: detected}
+	addError := func(line int, record []string, message string) {
+		parsed.ErrorCount++
+		if len(parsed.Errors) >= maxImportErrors {
+			return
+		}
+
+		if len(message) > 255 {
+			message = message[:255]
+		}
+
+		rawRow := strings.Join(record, string(delimiter))
+		if len(rawRow) > maxImportRawRowLength {
+			rawRow = rawRow[:maxImportRawRowLength]
+		}
+		parsed.Errors = append(parsed.Errors, model.WorkoutImportError{LineNumber: line, Message: message, RawRow: rawRow})
+	}
+
+	workoutsByKey := map[string]*importedWorkout{}
+	workoutKeys := []string{}
+	setNumbers := map[string]int{}
+
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+
+		parsed.TotalRows++
+
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			addError(parseErr.StartLine, record, "row is not valid CSV")
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("file is not a readable CSV file")
+		}
+
+		line, _ := reader.FieldPos(0)
+		field := func(name string) string {
+			i := importColumn(columns, detected, name)
+			if i < 0 || i >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[i])
+		}
+
+		// Strong logs rest timers as rows of their own
+		if strings.EqualFold(field("set_order"), "rest timer") {
+			continue
+		}
+
+		exerciseName := field("exercise")
+		if exerciseName == "" {
+			addError(line, record, "exercise name is missing")
+			continue
+		}
+
+		startDate, ok := parseImportDate(field("start"), detected)
+		if !ok {
+			addError(line, record, fmt.Sprintf("invalid date %q", field("start")))
+			continue
+		}
+
+		weight, err := parseImportNumber(field("weight"))
+		if err != nil || weight < 0 {
+			addError(line, record, fmt.Sprintf("invalid weight %q", field("weight")))
+			continue
+		}
+
+		reps, err := parseImportNumber(field("reps"))
+		if err != nil || reps < 0 {
+			addError(line, record, fmt.Sprintf("invalid reps %q", field("reps")))
+			continue
+		}
+
+		seconds, err := parseImportNumber(field("seconds"))
+		if err != nil || seconds < 0 {
+			addError(line, record, fmt.Sprintf("invalid duration %q", field("seconds")))
+			continue
+		}
+
+		if weight == 0 && reps == 0 && seconds == 0 {
+			addError(line, record, "set has no weight, reps or duration")
+			continue
+		}
+
+		rowUnit := unit
+		switch strings.ToLower(field("weight_unit")) {
+		case "kg", "kgs":
+			rowUnit = util.WeightUnitKg
+		case "lb", "lbs":
+			rowUnit = util.WeightUnitLb
+		}
+
+		key := field("start") + "|" + field("workout_name")
+		workout, ok := workoutsByKey[key]
+		if !ok {
+			name := field("workout_name")
+			if name == "" {
+				name = defaultImportedWorkoutName
+			}
+
+			workout = &importedWorkout{
+				Name:        name,
+				Description: field("description"),
+				StartDate:   startDate,
+				EndDate:     startDate,
+				Sets:        []importedSet{},
+			}
+
+			if endDate, ok := parseImportDate(field("end"), detected); ok && endDate.After(startDate) {
+				workout.EndDate = endDate
+			}
+			if duration := parseImportDuration(field("duration")); duration > 0 {
+				workout.EndDate = startDate.Add(time.Duration(duration) * time.Second)
+			}
+
+			workoutsByKey[key] = workout
+			workoutKeys = append(workoutKeys, key)
+		}
+
+		setKey := key + "|" + strings.ToLower(exerciseName)
+		setNumbers[setKey]++
+
+		workout.Sets = append(workout.Sets, importedSet{
+			LineNumber:   line,
+			ExerciseName: exerciseName,
+			SetNumber:    setNumbers[setKey],
+			WeightKg:     util.ToKg(weight, rowUnit),
+			Reps:         int(reps),
+			Duration:     int(seconds),
+		})
+	}
+
+	parsed.Workouts = make([]importedWorkout, 0, len(workoutKeys))
+	for _, key := range workoutKeys {
+		parsed.Workouts = append(parsed.Workouts, *workoutsByKey[key])
+	}
+
+	// Saving oldest first lets personal records be detected as they happened
+	sort.SliceStable(parsed.Workouts, func(i, j int) bool {
+		return parsed.Workouts[i].StartDate.Before(parsed.Workouts[j].StartDate)
+	})
+
+	return parsed, nil
+}
+
+func parseImportDate(value string, source string) (time.Time, bool) {
+	for _, layout := range importDateLayouts[source] {
+		date, err := time.Parse(layout, value)
+		if err == nil {
+			return date.UTC(), true
+		}
+	}
+
+	return time.Time{}, false
+}
+
+// parseImportNumber reads a number that may be empty or use a decimal comma.
+func parseImportNumber(value string) (float64, error) {
+	if value == "" {
+		return 0, nil
+	}
+
+	return strconv.ParseFloat(strings.Replace(value, ",", ".", 1), 64)
+}
+
+// parseImportDuration reads a duration in seconds from either a number of seconds or Strong's "1h 5m" format.
+func parseImportDuration(value string) int {
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return seconds
+	}
+
+	seconds := 0
+	for _, match := range importDurationPattern.FindAllStringSubmatch(value, -1) {
+		amount, _ := strconv.Atoi(match[1])
+		switch match[2] {
+		case "h":
+			seconds += amount * 3600
+		case "m":
+			seconds += amount * 60
+		default:
+			seconds += amount
+		}
+	}
+
+	return seconds
+}
@@ -0,0 +1,345 @@
+package service
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/brightside-dev/ronin-fitness-be/internal/model"
+	"github.com/brightside-dev/ronin-fitness-be/internal/util"
+)
+
+func readImportFixture(t *testing.T, name string) []byte {
+	t.Helper()
+
+	data, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatalf("reading fixture %s: %v", name, err)
+	}
+
+	return data
+}
+
+func TestParseImportFile(t *testing.T) {
+	at := func(day int, hour int, minute int) time.Time {
+		return time.Date(2024, 1, day, hour, minute, 0, 0, time.UTC)
+	}
+
+	strongKg := []importedWorkout{
+		{
+			Name:      "Legs",
+			StartDate: at(18, 18, 30),
+			EndDate:   at(18, 19, 15),
+			Sets: []importedSet{
+				{LineNumber: 6, ExerciseName: "Squat (Barbell)", SetNumber: 1, WeightKg: 100, Reps: 5},
+				{LineNumber: 7, ExerciseName: "Squat (Barbell)", SetNumber: 2, WeightKg: 102.5, Reps: 5},
+			},
+		},
+		{
+			Name:        "Push Day",
+			Description: "Felt strong",
+			StartDate:   at(20, 8, 0),
+			EndDate:     at(20, 9, 5),
+			Sets: []importedSet{
+				{LineNumber: 2, ExerciseName: "Bench Press (Barbell)", SetNumber: 1, WeightKg: 60, Reps: 10},
+				{LineNumber: 3, ExerciseName: "Bench Press (Barbell)", SetNumber: 2, WeightKg: 80, Reps: 5},
+				{LineNumber: 5, ExerciseName: "Plank", SetNumber: 1, Duration: 60},
+			},
+		},
+	}
+
+	strongLb := []importedWorkout{
+		{
+			Name:      "Legs",
+			StartDate: at(18, 18, 30),
+			EndDate:   at(18, 19, 15),
+			Sets: []importedSet{
+				{LineNumber: 6, ExerciseName: "Squat (Barbell)", SetNumber: 1, WeightKg: 45.3592, Reps: 5},
+				{LineNumber: 7, ExerciseName: "Squat (Barbell)", SetNumber: 2, WeightKg: 46.4932, Reps: 5},
+			},
+		},
+		{
+			Name:        "Push Day",
+			Description: "Felt strong",
+			StartDate:   at(20, 8, 0),
+			EndDate:     at(20, 9, 5),
+			Sets: []importedSet{
+				{LineNumber: 2, ExerciseName: "Bench Press (Barbell)", SetNumber: 1, WeightKg: 27.2155, Reps: 10},
+				{LineNumber: 3, ExerciseName: "Bench Press (Barbell)", SetNumber: 2, WeightKg: 36.2874, Reps: 5},
+				{LineNumber: 5, ExerciseName: "Plank", SetNumber: 1, Duration: 60},
+			},
+		},
+	}
+
+	hevy := []importedWorkout{
+		{
+			Name:      defaultImportedWorkoutName,
+			StartDate: at(21, 7, 0),
+			EndDate:   at(21, 7, 0), // the end before the start is ignored
+			Sets: []importedSet{
+				{LineNumber: 7, ExerciseName: "Treadmill", SetNumber: 1, Duration: 1200},
+			},
+		},
+		{
+			Name:        "Upper",
+			Description: "Deload week",
+			StartDate:   at(22, 18, 30),
+			EndDate:     at(22, 19, 30),
+			Sets: []importedSet{
+				{LineNumber: 2, ExerciseName: "Bench Press (Barbell)", SetNumber: 1, WeightKg: 80, Reps: 8},
+				{LineNumber: 3, ExerciseName: "Bench Press (Barbell)", SetNumber: 2, WeightKg: 82.5, Reps: 6},
+				{LineNumber: 4, ExerciseName: "Pull Up", SetNumber: 1, Reps: 10},
+			},
+		},
+	}
+
+	strongSemicolons := "\xef\xbb\xbfDate;Workout Name;Duration (sec);Exercise Name;Set Order;Weight;Weight Unit;Reps;Seconds\n" +
+		"2024-01-20 08:00;Push Day;3600;Bench Press;1;135;lbs;5;0\n" +
+		"2024-01-20 08:00;Push Day;3600;Bench Press;2;60;kg;5;0\n"
+
+	hevyPounds := "title,start_time,end_time,exercise_title,weight_lbs,reps\n" +
+		"Upper,2024-01-22 18:30:00,2024-01-22 19:30:00,Bench Press,100,5\n"
+
+	tests := []struct {
+		name       string
+		data       []byte
+		source     string
+		unit       string
+		wantSource string
+		totalRows  int
+		errorLines []int
+		workouts   []importedWorkout
+	}{
+		{
+			name:       "strong",
+			data:       readImportFixture(t, "strong.csv"),
+			unit:       util.WeightUnitKg,
+			wantSource: model.WorkoutImportSourceStrong,
+			totalRows:  8,
+			errorLines: []int{8, 9},
+			workouts:   strongKg,
+		},
+		{
+			name:       "strong weights without a unit are in the profile's unit",
+			data:       readImportFixture(t, "strong.csv"),
+			source:     model.WorkoutImportSourceStrong,
+			unit:       util.WeightUnitLb,
+			wantSource: model.WorkoutImportSourceStrong,
+			totalRows:  8,
+			errorLines: []int{8, 9},
+			workouts:   strongLb,
+		},
+		{
+			name:       "hevy",
+			data:       readImportFixture(t, "hevy.csv"),
+			unit:       util.WeightUnitKg,
+			wantSource: model.WorkoutImportSourceHevy,
+			totalRows:  6,
+			errorLines: []int{5, 6},
+			workouts:   hevy,
+		},
+		{
+			name:       "hevy weight header wins over the profile's unit",
+			data:       readImportFixture(t, "hevy.csv"),
+			source:     model.WorkoutImportSourceHevy,
+			unit:       util.WeightUnitLb,
+			wantSource: model.WorkoutImportSourceHevy,
+			totalRows:  6,
+			errorLines: []int{5, 6},
+			workouts:   hevy,
+		},
+		{
+			name:       "strong with semicolons, a byte order mark and a unit per row",
+			data:       []byte(strongSemicolons),
+			unit:       util.WeightUnitKg,
+			wantSource: model.WorkoutImportSourceStrong,
+			totalRows:  2,
+			workouts: []importedWorkout{
+				{
+					Name:      "Push Day",
+					StartDate: at(20, 8, 0),
+					EndDate:   at(20, 9, 0),
+					Sets: []importedSet{
+						{LineNumber: 2, ExerciseName: "Bench Press", SetNumber: 1, WeightKg: 61.235, Reps: 5},
+						{LineNumber: 3, ExerciseName: "Bench Press", SetNumber: 2, WeightKg: 60, Reps: 5},
+					},
+				},
+			},
+		},
+		{
+			name:       "hevy in pounds",
+			data:       []byte(hevyPounds),
+			unit:       util.WeightUnitKg,
+			wantSource: model.WorkoutImportSourceHevy,
+			totalRows:  1,
+			workouts: []importedWorkout{
+				{
+					Name:      "Upper",
+					StartDate: at(22, 18, 30),
+					EndDate:   at(22, 19, 30),
+					Sets: []importedSet{
+						{LineNumber: 2, ExerciseName: "Bench Press", SetNumber: 1, WeightKg: 45.3592, Reps: 5},
+					},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parsed, err := parseImportFile(tt.data, tt.source, tt.unit)
+			if err != nil {
+				t.Fatalf("parseImportFile() error = %v", err)
+			}
+
+			if parsed.Source != tt.wantSource {
+				t.Errorf("Source = %q, want %q", parsed.Source, tt.wantSource)
+			}
+			if parsed.TotalRows != tt.totalRows {
+				t.Errorf("TotalRows = %d, want %d", parsed.TotalRows, tt.totalRows)
+			}
+
+			errorLines := []int{}
+			for _, importErr := range parsed.Errors {
+				errorLines = append(errorLines, importErr.LineNumber)
+			}
+			if parsed.ErrorCount != len(tt.errorLines) || (len(errorLines) > 0 && !reflect.DeepEqual(errorLines, tt.errorLines)) {
+				t.Errorf("errors on lines %v (%d counted), want %v", errorLines, parsed.ErrorCount, tt.errorLines)
+			}
+
+			if !reflect.DeepEqual(parsed.Workouts, tt.workouts) {
+				t.Errorf("Workouts = %+v, want %+v", parsed.Workouts, tt.workouts)
+			}
+		})
+	}
+}
+
+func TestParseImportFileRejected(t *testing.T) {
+	tests := []struct {
+		name   string
+		data   []byte
+		source string
+	}{
+		{name: "empty", data: nil},
+		{name: "unknown columns", data: []byte("day,lift,kg\n2024-01-20,Squat,100\n")},
+		{name: "strong file as hevy", data: readImportFixture(t, "strong.csv"), source: model.WorkoutImportSourceHevy},
+		{name: "hevy file as strong", data: readImportFixture(t, "hevy.csv"), source: model.WorkoutImportSourceStrong},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if parsed, err := parseImportFile(tt.data, tt.source, util.WeightUnitKg); err == nil {
+				t.Errorf("parseImportFile() = %+v, want an error", parsed)
+			}
+		})
+	}
+}
+
+func TestParseImportDate(t *testing.T) {
+	tests := []struct {
+		value  string
+		source string
+		want   time.Time
+		wantOk bool
+	}{
+		{value: "2024-01-20 08:00:00", source: model.WorkoutImportSourceStrong, want: time.Date(2024, 1, 20, 8, 0, 0, 0, time.UTC), wantOk: true},
+		{value: "2024-01-20 08:00", source: model.WorkoutImportSourceStrong, want: time.Date(2024, 1, 20, 8, 0, 0, 0, time.UTC), wantOk: true},
+		{value: "2024-01-20T08:00:00+02:00", source: model.WorkoutImportSourceStrong, want: time.Date(2024, 1, 20, 6, 0, 0, 0, time.UTC), wantOk: true},
+		{value: "22 Jan 2024, 18:30", source: model.WorkoutImportSourceHevy, want: time.Date(2024, 1, 22, 18, 30, 0, 0, time.UTC), wantOk: true},
+		{value: "22 Jan 2024 18:30", source: model.WorkoutImportSourceHevy, want: time.Date(2024, 1, 22, 18, 30, 0, 0, time.UTC), wantOk: true},
+		{value: "22 Jan 2024, 18:30", source: model.WorkoutImportSourceStrong},
+		{value: "20/01/2024 08:00", source: model.WorkoutImportSourceStrong},
+		{value: "", source: model.WorkoutImportSourceHevy},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.source+" "+tt.value, func(t *testing.T) {
+			got, ok := parseImportDate(tt.value, tt.source)
+			if ok != tt.wantOk || !got.Equal(tt.want) {
+				t.Errorf("parseImportDate() = %v, %v, want %v, %v", got, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+}
+
+func TestParseImportNumber(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    float64
+		wantErr bool
+	}{
+		{value: "", want: 0},
+		{value: "82.5", want: 82.5},
+		{value: "82,5", want: 82.5},
+		{value: "-5", want: -5},
+		{value: "1,000.5", wantErr: true},
+		{value: "heavy", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := parseImportNumber(tt.value)
+			if (err != nil) != tt.wantErr || (!tt.wantErr && got != tt.want) {
+				t.Errorf("parseImportNumber() = %v, %v, want %v, error %v", got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestParseImportDuration(t *testing.T) {
+	tests := []struct {
+		value string
+		want  int
+	}{
+		{value: "3600", want: 3600},
+		{value: "1h 5m", want: 3900},
+		{value: "45m", want: 2700},
+		{value: "1h 2m 3s", want: 3723},
+		{value: "", want: 0},
+		{value: "long", want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			if got := parseImportDuration(tt.value); got != tt.want {
+				t.Errorf("parseImportDuration() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFindDuplicateImports(t *testing.T) {
+	start := time.Date(2024, 1, 20, 8, 0, 0, 0, time.UTC)
+	pushDay := importedWorkout{Name: "Push Day", StartDate: start}
+	// The same workout written with another time zone and name casing
+	pushDayAgain := importedWorkout{Name: " push day", StartDate: start.In(time.FixedZone("CET", 3600))}
+	legDay := importedWorkout{Name: "Legs", StartDate: start.Add(48 * time.Hour)}
+	pullDay := importedWorkout{Name: "Pull Day", StartDate: start.Add(24 * time.Hour)}
+
+	tests := []struct {
+		name         string
+		workouts     []importedWorkout
+		existingKeys map[string]bool
+		want         []bool
+	}{
+		{name: "all new", workouts: []importedWorkout{pushDay, pullDay, legDay}, existingKeys: map[string]bool{}, want: []bool{false, false, false}},
+		{name: "imported before", workouts: []importedWorkout{pushDay, pullDay}, existingKeys: map[string]bool{pullDay.importKey(): true}, want: []bool{false, true}},
+		{name: "repeated in the file", workouts: []importedWorkout{pushDay, legDay, pushDayAgain}, existingKeys: map[string]bool{}, want: []bool{false, false, true}},
+		{
+			name:         "repeated in the file and imported before",
+			workouts:     []importedWorkout{pushDay, pushDayAgain},
+			existingKeys: map[string]bool{pushDay.importKey(): true},
+			want:         []bool{true, true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := findDuplicateImports(tt.workouts, tt.existingKeys); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("findDuplicateImports() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
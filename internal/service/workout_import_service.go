@@ -0,0 +1,571 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/brightside-dev/ronin-fitness-be/database/client"
+	"github.com/brightside-dev/ronin-fitness-be/internal/handler/dto"
+	customError "github.com/brightside-dev/ronin-fitness-be/internal/handler/error"
+	"github.com/brightside-dev/ronin-fitness-be/internal/model"
+	"github.com/brightside-dev/ronin-fitness-be/internal/repository"
+	"github.com/brightside-dev/ronin-fitness-be/internal/util"
+	"github.com/go-playground/validator/v10"
+)
+
+const (
+	maxImportFileSize = 10 << 20
+	// importBatchSize is how many workouts are saved per transaction
+	importBatchSize = 25
+	// maxImportPreviewWorkouts bounds the workouts listed in a dry run
+	maxImportPreviewWorkouts = 50
+)
+
+var importEquipmentSuffix = regexp.MustCompile(`\s*\([^)]*\)$`)
+
+type WorkoutImportService interface {
+	ImportWorkouts(w http.ResponseWriter, r *http.Request) (*dto.WorkoutImportResponse, error)
+	GetImports(w http.ResponseWriter, r *http.Request) (*dto.WorkoutImportsListResponse, error)
+	GetImport(w http.ResponseWriter, r *http.Request) (*dto.WorkoutImportResponse, error)
+}
+
+type workoutImportService struct {
+	DB                      client.DatabaseService
+	DBLogger                *slog.Logger
+	Validate                *validator.Validate
+	WorkoutImportRepository repository.WorkoutImportRepository
+	WorkoutRepository       repository.WorkoutRepository
+	ExerciseRepository      repository.ExerciseRepository
+	ProfileRepository       repository.ProfileRepository
+	WorkoutService          WorkoutService
+	GoalService             GoalService
+	AchievementService      AchievementService
+}
+
+func NewWorkoutImportService(
+	db client.DatabaseService,
+	dbLogger *slog.Logger,
+	validator *validator.Validate,
+	workoutImportRepository repository.WorkoutImportRepository,
+	workoutRepository repository.WorkoutRepository,
+	exerciseRepository repository.ExerciseRepository,
+	profileRepository repository.ProfileRepository,
+	workoutService WorkoutService,
+	goalService GoalService,
+	achievementService AchievementService,
+) WorkoutImportService {
+	return &workoutImportService{
+		DB:                      db,
+		DBLogger:                dbLogger,
+		Validate:                validator,
+		WorkoutImportRepository: workoutImportRepository,
+		WorkoutRepository:       workoutRepository,
+		ExerciseRepository:      exerciseRepository,
+		ProfileRepository:       profileRepository,
+		WorkoutService:          workoutService,
+		GoalService:             goalService,
+		AchievementService:      achievementService,
+	}
+}
+
+// ImportWorkouts reads a CSV export uploaded as the "file" form field. With dry_run set it only
+// returns a preview, otherwise the workouts are saved in batches under a persistent import job.
+// Workouts that were imported before are skipped, so the same file can be uploaded again safely.
+func (s *workoutImportService) ImportWorkouts(w http.ResponseWriter, r *http.Request) (*dto.WorkoutImportResponse, error) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxImportFileSize)
+	err := r.ParseMultipartForm(maxImportFileSize)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, fmt.Errorf("file must be uploaded as multipart form data of at most %dMB", maxImportFileSize>>20)
+	}
+
+	req := dto.WorkoutImportRequest{
+		Source: r.FormValue("source"),
+		Unit:   r.FormValue("unit"),
+	}
+	if r.FormValue("dry_run") != "" {
+		req.DryRun, err = strconv.ParseBool(r.FormValue("dry_run"))
+		if err != nil {
+			return nil, fmt.Errorf("dry_run must be a boolean")
+		}
+	}
+
+	err = s.Validate.Struct(req)
+	if err != nil {
+		return nil, util.FormatValidationError(err.(validator.ValidationErrors))
+	}
+
+	file, fileHeader, err := r.FormFile("file")
+	if err != nil {
+		return nil, fmt.Errorf("missing file")
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrInvalidRequestBody
+	}
+
+	profile, err := getAuthProfile(r, s.ProfileRepository)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrUnAuthorized
+	}
+
+	unit := req.Unit
+	if unit == "" {
+		unit = profile.WeightUnit
+	}
+
+	parsed, err := parseImportFile(data, req.Source, unit)
+	if err != nil {
+		return nil, err
+	}
+
+	existingKeys, err := s.getExistingImportKeys(r.Context(), profile.ID, parsed.Workouts)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrInternalServerError
+	}
+
+	exerciseIDs, err := s.matchExercises(r.Context(), profile.ID, parsed.Workouts)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrInternalServerError
+	}
+
+	if req.DryRun {
+		return buildImportPreview(fileHeader.Filename, unit, parsed, existingKeys, exerciseIDs), nil
+	}
+
+	workoutImport, err := s.WorkoutImportRepository.Create(r.Context(), &model.WorkoutImport{
+		ProfileID:  profile.ID,
+		Source:     parsed.Source,
+		FileName:   truncateImportFileName(fileHeader.Filename),
+		FileHash:   fmt.Sprintf("%x", sha256.Sum256(data)),
+		WeightUnit: unit,
+		TotalRows:  parsed.TotalRows,
+	})
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrInternalServerError
+	}
+
+	// The job is finished even if the client goes away part way through
+	ctx := context.WithoutCancel(r.Context())
+
+	err = s.WorkoutImportRepository.CreateErrors(ctx, workoutImport.ID, parsed.Errors)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrInternalServerError
+	}
+
+	workoutImport.ErrorCount = parsed.ErrorCount
+	workoutImport.Status = model.WorkoutImportStatusCompleted
+
+	err = s.saveImportedWorkouts(ctx, profile, workoutImport, parsed.Workouts, existingKeys, exerciseIDs)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		workoutImport.Status = model.WorkoutImportStatusFailed
+		workoutImport.FailureReason = fmt.Sprintf("import stopped after %d workouts, upload the file again to resume", workoutImport.ImportedWorkouts)
+	}
+
+	err = s.WorkoutImportRepository.Finish(ctx, workoutImport)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrInternalServerError
+	}
+
+	err = s.GoalService.EvaluateGoals(ctx, profile.ID)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+	}
+
+	// Imported history counts towards achievements, even though it earns no experience points
+	err = s.AchievementService.EvaluateAchievements(ctx, profile.ID, model.AchievementEventWorkout, model.AchievementEventPersonalRecord)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+	}
+
+	return s.getImportResponse(r.WithContext(ctx), workoutImport.ID)
+}
+
+func (s *workoutImportService) GetImports(w http.ResponseWriter, r *http.Request) (*dto.WorkoutImportsListResponse, error) {
+	beforeTime, beforeID, err := util.DecodeCursor(r.URL.Query().Get("cursor"))
+	if err != nil {
+		return nil, customError.ErrInvalidCursor
+	}
+
+	profile, err := getAuthProfile(r, s.ProfileRepository)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrUnAuthorized
+	}
+
+	limit := util.GetPageLimit(r, 20, 50)
+	workoutImports, err := s.WorkoutImportRepository.GetByProfileID(r.Context(), profile.ID, beforeTime, beforeID, limit)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrInternalServerError
+	}
+
+	res := &dto.WorkoutImportsListResponse{
+		Imports: make([]dto.WorkoutImportResponse, 0, len(workoutImports)),
+	}
+	for _, workoutImport := range workoutImports {
+		res.Imports = append(res.Imports, toWorkoutImportResponse(workoutImport))
+	}
+
+	if len(workoutImports) == limit {
+		last := workoutImports[len(workoutImports)-1]
+		res.NextCursor = util.EncodeCursor(last.CreatedAt, last.ID)
+	}
+
+	return res, nil
+}
+
+func (s *workoutImportService) GetImport(w http.ResponseWriter, r *http.Request) (*dto.WorkoutImportResponse, error) {
+	importID, err := getIDParam(r, "importId")
+	if err != nil {
+		return nil, err
+	}
+
+	profile, err := getAuthProfile(r, s.ProfileRepository)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrUnAuthorized
+	}
+
+	workoutImport, err := s.WorkoutImportRepository.GetByID(r.Context(), importID)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrInternalServerError
+	}
+
+	if workoutImport == nil || workoutImport.ProfileID != profile.ID {
+		return nil, customError.ErrNotFound
+	}
+
+	return s.getImportResponse(r, workoutImport.ID)
+}
+
+// saveImportedWorkouts saves the new workouts in batches, creating the custom exercises they need.
+// Each batch commits with the job's counters, so a failure leaves the job describing what was saved.
+func (s *workoutImportService) saveImportedWorkouts(
+	ctx context.Context,
+	profile *model.ProfileWithUser,
+	workoutImport *model.WorkoutImport,
+	workouts []importedWorkout,
+	existingKeys map[string]bool,
+	exerciseIDs map[string]int,
+) error {
+	duplicates := findDuplicateImports(workouts, existingKeys)
+	newWorkouts := []importedWorkout{}
+	for i, workout := range workouts {
+		if duplicates[i] {
+			workoutImport.SkippedWorkouts++
+			continue
+		}
+		newWorkouts = append(newWorkouts, workout)
+	}
+
+	for start := 0; start < len(newWorkouts); start += importBatchSize {
+		batch := newWorkouts[start:min(start+importBatchSize, len(newWorkouts))]
+		progress := *workoutImport
+		createdExerciseIDs := map[string]int{}
+
+		_, err := util.WithTransaction(ctx, s.DB.GetDB(), func(tx *sql.Tx) (interface{}, error) {
+			for _, workout := range batch {
+				sets := make([]model.Set, 0, len(workout.Sets))
+				for _, set := range workout.Sets {
+					exerciseKey := normalizeExerciseName(set.ExerciseName)
+					exerciseID := exerciseIDs[exerciseKey]
+					if exerciseID == 0 {
+						exerciseID = createdExerciseIDs[exerciseKey]
+					}
+
+					if exerciseID == 0 {
+						exercise, err := s.ExerciseRepository.Create(ctx, tx, &model.Exercise{
+							ProfileID:   &profile.ID,
+							Name:        set.ExerciseName,
+							Description: fmt.Sprintf("Imported from %s", workoutImport.Source),
+						})
+						if err != nil {
+							return nil, err
+						}
+
+						exerciseID = exercise.ID
+						createdExerciseIDs[exerciseKey] = exerciseID
+						progress.CreatedExercises++
+					}
+
+					sets = append(sets, model.Set{
+						ExerciseID: exerciseID,
+						SetNumber:  set.SetNumber,
+						Duration:   set.Duration,
+						WeightKg:   set.WeightKg,
+						Reps:       set.Reps,
+					})
+				}
+
+				importKey := workout.importKey()
+				_, err := s.WorkoutService.SaveWorkout(ctx, tx, profile, &model.Workout{
+					Name:                workout.Name,
+					Description:         workout.Description,
+					MentalEnergyLevel:   defaultEnergyLevel,
+					PhysicalEnergyLevel: defaultEnergyLevel,
+					StartDate:           workout.StartDate,
+					EndDate:             workout.EndDate,
+					ImportKey:           &importKey,
+				}, sets)
+				if err != nil {
+					return nil, err
+				}
+
+				progress.ImportedWorkouts++
+				progress.ImportedSets += len(sets)
+			}
+
+			return nil, s.WorkoutImportRepository.UpdateProgress(ctx, tx, &progress)
+		})
+		if err != nil {
+			return err
+		}
+
+		*workoutImport = progress
+		for exerciseKey, exerciseID := range createdExerciseIDs {
+			exerciseIDs[exerciseKey] = exerciseID
+		}
+	}
+
+	return nil
+}
+
+// findDuplicateImports flags the workouts that were imported before, and those that share their import
+// key with an earlier workout of the file, e.g. the same workout written with another time format or
+// name casing. Only the first of those is saved, the unique import key would reject the rest.
+func findDuplicateImports(workouts []importedWorkout, existingKeys map[string]bool) []bool {
+	duplicates := make([]bool, len(workouts))
+	seenKeys := map[string]bool{}
+	for i, workout := range workouts {
+		importKey := workout.importKey()
+		duplicates[i] = existingKeys[importKey] || seenKeys[importKey]
+		seenKeys[importKey] = true
+	}
+
+	return duplicates
+}
+
+// getExistingImportKeys checks the workouts against earlier imports in chunks to keep the queries small.
+func (s *workoutImportService) getExistingImportKeys(ctx context.Context, profileID int, workouts []importedWorkout) (map[string]bool, error) {
+	existingKeys := map[string]bool{}
+	for start := 0; start < len(workouts); start += 500 {
+		importKeys := []string{}
+		for _, workout := range workouts[start:min(start+500, len(workouts))] {
+			importKeys = append(importKeys, workout.importKey())
+		}
+
+		existing, err := s.WorkoutRepository.GetExistingImportKeys(ctx, profileID, importKeys)
+		if err != nil {
+			return nil, err
+		}
+
+		for importKey := range existing {
+			existingKeys[importKey] = true
+		}
+	}
+
+	return existingKeys, nil
+}
+
+// matchExercises maps the exercise names of the file onto the profile's exercises and the default
+// ones, keyed by normalized name. Names are first matched as they are, then without the equipment
+// suffix the other apps add, e.g. "Bench Press (Barbell)". Unmatched names map to 0.
+func (s *workoutImportService) matchExercises(ctx context.Context, profileID int, workouts []importedWorkout) (map[string]int, error) {
+	exercises, err := s.ExerciseRepository.GetAvailableByProfileID(ctx, profileID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Exact names win over names without their equipment suffix, and own exercises over default ones
+	byName := map[string]int{}
+	byBaseName := map[string]int{}
+	for _, exercise := range exercises {
+		name := normalizeExerciseName(exercise.Name)
+		if _, ok := byName[name]; !ok || exercise.ProfileID != nil {
+			byName[name] = exercise.ID
+		}
+
+		baseName := normalizeExerciseName(importEquipmentSuffix.ReplaceAllString(exercise.Name, ""))
+		if _, ok := byBaseName[baseName]; !ok || exercise.ProfileID != nil {
+			byBaseName[baseName] = exercise.ID
+		}
+	}
+
+	exerciseIDs := map[string]int{}
+	for _, workout := range workouts {
+		for _, set := range workout.Sets {
+			name := normalizeExerciseName(set.ExerciseName)
+			if _, ok := exerciseIDs[name]; ok {
+				continue
+			}
+
+			baseName := normalizeExerciseName(importEquipmentSuffix.ReplaceAllString(set.ExerciseName, ""))
+			switch {
+			case byName[name] != 0:
+				exerciseIDs[name] = byName[name]
+			case byName[baseName] != 0:
+				exerciseIDs[name] = byName[baseName]
+			default:
+				exerciseIDs[name] = byBaseName[baseName]
+			}
+		}
+	}
+
+	return exerciseIDs, nil
+}
+
+func (s *workoutImportService) getImportResponse(r *http.Request, importID int) (*dto.WorkoutImportResponse, error) {
+	workoutImport, err := s.WorkoutImportRepository.GetByID(r.Context(), importID)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrInternalServerError
+	}
+
+	importErrors, err := s.WorkoutImportRepository.GetErrors(r.Context(), importID)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrInternalServerError
+	}
+
+	res := toWorkoutImportResponse(*workoutImport)
+	res.Errors = toWorkoutImportErrorResponses(importErrors)
+
+	return &res, nil
+}
+
+// buildImportPreview describes what importing the parsed file would do without saving anything.
+func buildImportPreview(
+	fileName string,
+	unit string,
+	parsed *importFile,
+	existingKeys map[string]bool,
+	exerciseIDs map[string]int,
+) *dto.WorkoutImportResponse {
+	res := &dto.WorkoutImportResponse{
+		DryRun:     true,
+		Source:     parsed.Source,
+		FileName:   fileName,
+		WeightUnit: unit,
+		TotalRows:  parsed.TotalRows,
+		ErrorCount: parsed.ErrorCount,
+		Exercises:  []dto.WorkoutImportExerciseResponse{},
+		Workouts:   []dto.WorkoutImportPreviewResponse{},
+		Errors:     toWorkoutImportErrorResponses(parsed.Errors),
+	}
+
+	duplicates := findDuplicateImports(parsed.Workouts, existingKeys)
+	exerciseIndexes := map[string]int{}
+	for i, workout := range parsed.Workouts {
+		isDuplicate := duplicates[i]
+		if isDuplicate {
+			res.SkippedWorkouts++
+		} else {
+			res.ImportedWorkouts++
+			res.ImportedSets += len(workout.Sets)
+		}
+
+		workoutExercises := map[string]bool{}
+		for _, set := range workout.Sets {
+			name := normalizeExerciseName(set.ExerciseName)
+			workoutExercises[name] = true
+
+			i, ok := exerciseIndexes[name]
+			if !ok {
+				i = len(res.Exercises)
+				exerciseIndexes[name] = i
+				res.Exercises = append(res.Exercises, dto.WorkoutImportExerciseResponse{
+					Name:       set.ExerciseName,
+					ExerciseID: exerciseIDs[name],
+					IsNew:      exerciseIDs[name] == 0,
+				})
+			}
+			if !isDuplicate {
+				res.Exercises[i].SetCount++
+			}
+		}
+
+		if len(res.Workouts) < maxImportPreviewWorkouts {
+			res.Workouts = append(res.Workouts, dto.WorkoutImportPreviewResponse{
+				Name:          workout.Name,
+				StartDate:     workout.StartDate,
+				EndDate:       workout.EndDate,
+				ExerciseCount: len(workoutExercises),
+				SetCount:      len(workout.Sets),
+				IsDuplicate:   isDuplicate,
+			})
+		}
+	}
+
+	// Exercises are only created for sets that will be saved
+	for _, exercise := range res.Exercises {
+		if exercise.IsNew && exercise.SetCount > 0 {
+			res.CreatedExercises++
+		}
+	}
+
+	return res
+}
+
+func toWorkoutImportResponse(workoutImport model.WorkoutImport) dto.WorkoutImportResponse {
+	return dto.WorkoutImportResponse{
+		ImportID:         workoutImport.ID,
+		Status:           workoutImport.Status,
+		Source:           workoutImport.Source,
+		FileName:         workoutImport.FileName,
+		WeightUnit:       workoutImport.WeightUnit,
+		TotalRows:        workoutImport.TotalRows,
+		ImportedWorkouts: workoutImport.ImportedWorkouts,
+		ImportedSets:     workoutImport.ImportedSets,
+		SkippedWorkouts:  workoutImport.SkippedWorkouts,
+		CreatedExercises: workoutImport.CreatedExercises,
+		ErrorCount:       workoutImport.ErrorCount,
+		FailureReason:    workoutImport.FailureReason,
+		CreatedAt:        &workoutImport.CreatedAt,
+		CompletedAt:      workoutImport.CompletedAt,
+	}
+}
+
+func toWorkoutImportErrorResponses(importErrors []model.WorkoutImportError) []dto.WorkoutImportErrorResponse {
+	res := make([]dto.WorkoutImportErrorResponse, 0, len(importErrors))
+	for _, importError := range importErrors {
+		res = append(res, dto.WorkoutImportErrorResponse{
+			LineNumber: importError.LineNumber,
+			Message:    importError.Message,
+			RawRow:     importError.RawRow,
+		})
+	}
+
+	return res
+}
+
+func normalizeExerciseName(name string) string {
+	return strings.ToLower(strings.Join(strings.Fields(name), " "))
+}
+
+func truncateImportFileName(fileName string) string {
+	if len(fileName) > 255 {
+		return fileName[:255]
+	}
+
+	return fileName
+}
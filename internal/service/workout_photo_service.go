@@ -0,0 +1,301 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/brightside-dev/ronin-fitness-be/database/client"
+	"github.com/brightside-dev/ronin-fitness-be/internal/handler/dto"
+	customError "github.com/brightside-dev/ronin-fitness-be/internal/handler/error"
+	"github.com/brightside-dev/ronin-fitness-be/internal/imaging"
+	"github.com/brightside-dev/ronin-fitness-be/internal/model"
+	"github.com/brightside-dev/ronin-fitness-be/internal/repository"
+	"github.com/brightside-dev/ronin-fitness-be/internal/storage"
+	"github.com/brightside-dev/ronin-fitness-be/internal/util"
+)
+
+const (
+	maxWorkoutPhotos  = 10
+	maxPhotoFileSize  = 15 << 20
+	photoMaxSize      = 2048 // longest side in pixels
+	thumbnailMaxSize  = 400
+	photoURLExpiresIn = time.Hour
+)
+
+type WorkoutPhotoService interface {
+	UploadPhoto(w http.ResponseWriter, r *http.Request) (*dto.WorkoutPhotoResponse, error)
+	GetPhotos(w http.ResponseWriter, r *http.Request) (*dto.WorkoutPhotosResponse, error)
+	DeletePhoto(w http.ResponseWriter, r *http.Request) error
+	DeleteFiles(ctx context.Context, photos []model.WorkoutPhoto)
+}
+
+type workoutPhotoService struct {
+	DB                      client.DatabaseService
+	DBLogger                *slog.Logger
+	WorkoutPhotoRepository  repository.WorkoutPhotoRepository
+	WorkoutRepository       repository.WorkoutRepository
+	ProfileRepository       repository.ProfileRepository
+	ProfileFollowRepository repository.ProfileFollowRepository
+	ProfileBlockRepository  repository.ProfileBlockRepository
+	Storage                 storage.Storage
+}
+
+func NewWorkoutPhotoService(
+	db client.DatabaseService,
+	dbLogger *slog.Logger,
+	workoutPhotoRepository repository.WorkoutPhotoRepository,
+	workoutRepository repository.WorkoutRepository,
+	profileRepository repository.ProfileRepository,
+	profileFollowRepository repository.ProfileFollowRepository,
+	profileBlockRepository repository.ProfileBlockRepository,
+	storage storage.Storage,
+) WorkoutPhotoService {
+	return &workoutPhotoService{
+		DB:                      db,
+		DBLogger:                dbLogger,
+		WorkoutPhotoRepository:  workoutPhotoRepository,
+		WorkoutRepository:       workoutRepository,
+		ProfileRepository:       profileRepository,
+		ProfileFollowRepository: profileFollowRepository,
+		ProfileBlockRepository:  profileBlockRepository,
+		Storage:                 storage,
+	}
+}
+
+// UploadPhoto attaches the image in the "photo" form field to one of the caller's workouts. The
+// image is re-encoded, which strips its metadata, and stored along with a thumbnail.
+func (s *workoutPhotoService) UploadPhoto(w http.ResponseWriter, r *http.Request) (*dto.WorkoutPhotoResponse, error) {
+	workout, err := s.getOwnWorkout(r)
+	if err != nil {
+		return nil, err
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxPhotoFileSize)
+	err = r.ParseMultipartForm(maxPhotoFileSize)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, fmt.Errorf("photo must be uploaded as multipart form data of at most %dMB", maxPhotoFileSize>>20)
+	}
+
+	file, _, err := r.FormFile("photo")
+	if err != nil {
+		return nil, fmt.Errorf("missing photo")
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrInvalidRequestBody
+	}
+
+	img, err := imaging.Decode(data)
+	if err != nil {
+		return nil, err
+	}
+
+	photo := img.Fit(photoMaxSize)
+	photoData, err := photo.Encode()
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrInternalServerError
+	}
+
+	thumbnailData, err := img.Fit(thumbnailMaxSize).Encode()
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrInternalServerError
+	}
+
+	token, err := generateRandomToken()
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrInternalServerError
+	}
+
+	extension := ".jpg"
+	if photo.ContentType == imaging.ContentTypePNG {
+		extension = ".png"
+	}
+
+	workoutPhoto := model.WorkoutPhoto{
+		WorkoutID:    workout.ID,
+		StorageKey:   fmt.Sprintf("workouts/%d/%s%s", workout.ID, token, extension),
+		ThumbnailKey: fmt.Sprintf("workouts/%d/%s_thumb%s", workout.ID, token, extension),
+		ContentType:  photo.ContentType,
+		Width:        photo.Bounds().Dx(),
+		Height:       photo.Bounds().Dy(),
+		SizeBytes:    len(photoData),
+	}
+
+	err = s.Storage.Put(r.Context(), workoutPhoto.StorageKey, photoData, photo.ContentType)
+	if err == nil {
+		err = s.Storage.Put(r.Context(), workoutPhoto.ThumbnailKey, thumbnailData, photo.ContentType)
+	}
+	var created *model.WorkoutPhoto
+	if err == nil {
+		// The limit is checked with the workout locked, so concurrent uploads cannot both take the last place
+		_, err = util.WithTransaction(r.Context(), s.DB.GetDB(), func(tx *sql.Tx) (interface{}, error) {
+			count, err := s.WorkoutPhotoRepository.CountByWorkoutIDForUpdate(r.Context(), tx, workout.ID)
+			if err == sql.ErrNoRows {
+				// The workout was deleted while the photo was processed
+				return nil, customError.ErrNotFound
+			}
+			if err != nil {
+				return nil, err
+			}
+
+			if count >= maxWorkoutPhotos {
+				return nil, fmt.Errorf("%w: a workout can have at most %d photos", customError.ErrConflict, maxWorkoutPhotos)
+			}
+
+			created, err = s.WorkoutPhotoRepository.Create(r.Context(), tx, &workoutPhoto)
+
+			return nil, err
+		})
+	}
+	if err != nil {
+		s.DeleteFiles(context.WithoutCancel(r.Context()), []model.WorkoutPhoto{workoutPhoto})
+		if errors.Is(err, customError.ErrNotFound) || errors.Is(err, customError.ErrConflict) {
+			return nil, err
+		}
+
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrInternalServerError
+	}
+
+	res, err := s.toWorkoutPhotoResponse(*created)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrInternalServerError
+	}
+
+	return res, nil
+}
+
+// GetPhotos lists the photos of a workout the caller can see, with URLs valid for an hour.
+func (s *workoutPhotoService) GetPhotos(w http.ResponseWriter, r *http.Request) (*dto.WorkoutPhotosResponse, error) {
+	_, workout, err := getViewableWorkout(r, s.DBLogger, s.WorkoutRepository, s.ProfileRepository, s.ProfileFollowRepository, s.ProfileBlockRepository)
+	if err != nil {
+		return nil, err
+	}
+
+	photos, err := s.WorkoutPhotoRepository.GetByWorkoutID(r.Context(), workout.ID)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrInternalServerError
+	}
+
+	res := &dto.WorkoutPhotosResponse{Photos: make([]dto.WorkoutPhotoResponse, 0, len(photos))}
+	for _, photo := range photos {
+		photoResponse, err := s.toWorkoutPhotoResponse(photo)
+		if err != nil {
+			util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+			return nil, customError.ErrInternalServerError
+		}
+		res.Photos = append(res.Photos, *photoResponse)
+	}
+
+	return res, nil
+}
+
+func (s *workoutPhotoService) DeletePhoto(w http.ResponseWriter, r *http.Request) error {
+	photoID, err := getIDParam(r, "photoId")
+	if err != nil {
+		return err
+	}
+
+	workout, err := s.getOwnWorkout(r)
+	if err != nil {
+		return err
+	}
+
+	photo, err := s.WorkoutPhotoRepository.GetByID(r.Context(), photoID)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return customError.ErrInternalServerError
+	}
+
+	if photo == nil || photo.WorkoutID != workout.ID {
+		return customError.ErrNotFound
+	}
+
+	err = s.WorkoutPhotoRepository.Delete(r.Context(), photo.ID)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return customError.ErrInternalServerError
+	}
+
+	s.DeleteFiles(r.Context(), []model.WorkoutPhoto{*photo})
+
+	return nil
+}
+
+// DeleteFiles removes the stored files of photos whose rows are gone. Failures are only logged,
+// leaving unreachable files behind rather than failing a deletion that already happened.
+func (s *workoutPhotoService) DeleteFiles(ctx context.Context, photos []model.WorkoutPhoto) {
+	for _, photo := range photos {
+		for _, key := range []string{photo.StorageKey, photo.ThumbnailKey} {
+			err := s.Storage.Delete(ctx, key)
+			if err != nil {
+				util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), map[string]interface{}{"storage_key": key}, nil)
+			}
+		}
+	}
+}
+
+// getOwnWorkout loads the workout named by the workoutId URL parameter, failing unless it belongs to the caller.
+func (s *workoutPhotoService) getOwnWorkout(r *http.Request) (*model.Workout, error) {
+	workoutID, err := getIDParam(r, "workoutId")
+	if err != nil {
+		return nil, err
+	}
+
+	profile, err := getAuthProfile(r, s.ProfileRepository)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrUnAuthorized
+	}
+
+	workout, err := s.WorkoutRepository.GetByID(r.Context(), workoutID)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrInternalServerError
+	}
+
+	if workout == nil || workout.ProfileID != profile.ID {
+		return nil, customError.ErrNotFound
+	}
+
+	return workout, nil
+}
+
+func (s *workoutPhotoService) toWorkoutPhotoResponse(photo model.WorkoutPhoto) (*dto.WorkoutPhotoResponse, error) {
+	url, err := s.Storage.SignedURL(photo.StorageKey, photoURLExpiresIn)
+	if err != nil {
+		return nil, err
+	}
+
+	thumbnailURL, err := s.Storage.SignedURL(photo.ThumbnailKey, photoURLExpiresIn)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.WorkoutPhotoResponse{
+		ID:           photo.ID,
+		WorkoutID:    photo.WorkoutID,
+		URL:          url,
+		ThumbnailURL: thumbnailURL,
+		ContentType:  photo.ContentType,
+		Width:        photo.Width,
+		Height:       photo.Height,
+		ExpiresAt:    time.Now().Add(photoURLExpiresIn).UTC().Truncate(time.Second),
+		CreatedAt:    photo.CreatedAt,
+	}, nil
+}
@@ -0,0 +1,773 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/brightside-dev/ronin-fitness-be/database/client"
+	"github.com/brightside-dev/ronin-fitness-be/internal/handler/dto"
+	customError "github.com/brightside-dev/ronin-fitness-be/internal/handler/error"
+	"github.com/brightside-dev/ronin-fitness-be/internal/model"
+	"github.com/brightside-dev/ronin-fitness-be/internal/repository"
+	"github.com/brightside-dev/ronin-fitness-be/internal/util"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+)
+
+// defaultEnergyLevel is used for both energy levels of workouts recorded by other apps and devices,
+// which do not track them
+const defaultEnergyLevel = 5
+
+type WorkoutService interface {
+	GetWorkout(w http.ResponseWriter, r *http.Request) (*dto.WorkoutResponse, error)
+	CreateWorkout(w http.ResponseWriter, r *http.Request) (*dto.WorkoutCreateResponse, error)
+	UpdateVisibility(w http.ResponseWriter, r *http.Request) (*dto.WorkoutResponse, error)
+	DeleteWorkout(w http.ResponseWriter, r *http.Request) error
+	SaveWorkout(ctx context.Context, tx *sql.Tx, profile *model.ProfileWithUser, workout *model.Workout, sets []model.Set) ([]model.PersonalRecord, error)
+	BuildWorkoutResponses(r *http.Request, workouts []model.Workout, viewer *model.ProfileWithUser) ([]dto.WorkoutResponse, error)
+	PutReaction(w http.ResponseWriter, r *http.Request) (*dto.WorkoutReactionsSummary, error)
+	RemoveReaction(w http.ResponseWriter, r *http.Request) (*dto.WorkoutReactionsSummary, error)
+	GetReactions(w http.ResponseWriter, r *http.Request) (*dto.WorkoutReactionsListResponse, error)
+	GetFeed(w http.ResponseWriter, r *http.Request) (*dto.FeedResponse, error)
+}
+
+type workoutService struct {
+	DB                        client.DatabaseService
+	DBLogger                  *slog.Logger
+	Validate                  *validator.Validate
+	WorkoutRepository         repository.WorkoutRepository
+	WorkoutReactionRepository repository.WorkoutReactionRepository
+	WorkoutCommentRepository  repository.WorkoutCommentRepository
+	SetRepository             repository.SetRepository
+	ExerciseRepository        repository.ExerciseRepository
+	ProfileRepository         repository.ProfileRepository
+	ProfileFollowRepository   repository.ProfileFollowRepository
+	ProfileBlockRepository    repository.ProfileBlockRepository
+	CardioSessionRepository   repository.CardioSessionRepository
+	WorkoutPhotoRepository    repository.WorkoutPhotoRepository
+	PersonalRecordService     PersonalRecordService
+	StatsService              StatsService
+	ProgramService            ProgramService
+	WorkoutPhotoService       WorkoutPhotoService
+	GoalService               GoalService
+	ExperiencePointService    ExperiencePointService
+	AchievementService        AchievementService
+}
+
+func NewWorkoutService(
+	db client.DatabaseService,
+	dbLogger *slog.Logger,
+	validator *validator.Validate,
+	workoutRepository repository.WorkoutRepository,
+	workoutReactionRepository repository.WorkoutReactionRepository,
+	workoutCommentRepository repository.WorkoutCommentRepository,
+	setRepository repository.SetRepository,
+	exerciseRepository repository.ExerciseRepository,
+	profileRepository repository.ProfileRepository,
+	profileFollowRepository repository.ProfileFollowRepository,
+	profileBlockRepository repository.ProfileBlockRepository,
+	cardioSessionRepository repository.CardioSessionRepository,
+	workoutPhotoRepository repository.WorkoutPhotoRepository,
+	personalRecordService PersonalRecordService,
+	statsService StatsService,
+	programService ProgramService,
+	workoutPhotoService WorkoutPhotoService,
+	goalService GoalService,
+	experiencePointService ExperiencePointService,
+	achievementService AchievementService,
+) WorkoutService {
+	return &workoutService{
+		DB:                        db,
+		DBLogger:                  dbLogger,
+		Validate:                  validator,
+		WorkoutRepository:         workoutRepository,
+		WorkoutReactionRepository: workoutReactionRepository,
+		WorkoutCommentRepository:  workoutCommentRepository,
+		SetRepository:             setRepository,
+		ExerciseRepository:        exerciseRepository,
+		ProfileRepository:         profileRepository,
+		ProfileFollowRepository:   profileFollowRepository,
+		ProfileBlockRepository:    profileBlockRepository,
+		CardioSessionRepository:   cardioSessionRepository,
+		WorkoutPhotoRepository:    workoutPhotoRepository,
+		PersonalRecordService:     personalRecordService,
+		StatsService:              statsService,
+		ProgramService:            programService,
+		WorkoutPhotoService:       workoutPhotoService,
+		GoalService:               goalService,
+		ExperiencePointService:    experiencePointService,
+		AchievementService:        achievementService,
+	}
+}
+
+func (s *workoutService) GetWorkout(w http.ResponseWriter, r *http.Request) (*dto.WorkoutResponse, error) {
+	viewer, workout, err := s.getViewableWorkout(r)
+	if err != nil {
+		return nil, err
+	}
+
+	workoutResponses, err := s.BuildWorkoutResponses(r, []model.Workout{*workout}, viewer)
+	if err != nil {
+		return nil, err
+	}
+
+	return &workoutResponses[0], nil
+}
+
+func (s *workoutService) CreateWorkout(w http.ResponseWriter, r *http.Request) (*dto.WorkoutCreateResponse, error) {
+	req := dto.WorkoutCreateRequest{}
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrInvalidRequestBody
+	}
+
+	err = s.Validate.Struct(req)
+	if err != nil {
+		return nil, util.FormatValidationError(err.(validator.ValidationErrors))
+	}
+
+	profile, err := getAuthProfile(r, s.ProfileRepository)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrUnAuthorized
+	}
+
+	err = s.validateExercises(r, profile.ID, req.Sets)
+	if err != nil {
+		return nil, err
+	}
+
+	type createResult struct {
+		workout         *model.Workout
+		personalRecords []model.PersonalRecord
+	}
+
+	result, err := util.WithTransaction(r.Context(), s.DB.GetDB(), func(tx *sql.Tx) (interface{}, error) {
+		workout := &model.Workout{
+			ProfileID:           profile.ID,
+			Name:                req.Name,
+			Description:         req.Description,
+			Visibility:          req.Visibility,
+			MentalEnergyLevel:   req.MentalEnergyLevel,
+			PhysicalEnergyLevel: req.PhysicalEnergyLevel,
+			StartDate:           req.StartDate,
+			EndDate:             req.EndDate,
+		}
+
+		sets := make([]model.Set, 0, len(req.Sets))
+		for _, setReq := range req.Sets {
+			unit := setReq.Unit
+			if unit == "" {
+				unit = profile.WeightUnit
+			}
+
+			sets = append(sets, model.Set{
+				ExerciseID: setReq.ExerciseID,
+				SetNumber:  setReq.SetNumber,
+				Duration:   setReq.Duration,
+				WeightKg:   util.ToKg(setReq.Weight, unit),
+				Reps:       setReq.Reps,
+			})
+		}
+
+		personalRecords, err := s.SaveWorkout(r.Context(), tx, profile, workout, sets)
+		if err != nil {
+			return nil, err
+		}
+
+		err = s.ExperiencePointService.AwardWorkout(r.Context(), tx, workout, personalRecords)
+		if err != nil {
+			return nil, err
+		}
+
+		if req.ProgramEnrollmentID != nil {
+			err = s.ProgramService.CompleteSession(r.Context(), tx, profile.ID, *req.ProgramEnrollmentID, workout.ID)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		return &createResult{workout: workout, personalRecords: personalRecords}, nil
+	})
+	if errors.Is(err, customError.ErrNotFound) || errors.Is(err, customError.ErrConflict) {
+		return nil, err
+	}
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrInternalServerError
+	}
+
+	created := result.(*createResult)
+
+	// Goals and achievements are evaluated against the committed workout, a failure there does not
+	// fail the request
+	err = s.GoalService.EvaluateGoals(r.Context(), profile.ID)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+	}
+
+	err = s.AchievementService.EvaluateAchievements(r.Context(), profile.ID, workoutAchievementEvents(created.personalRecords)...)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+	}
+
+	workoutResponses, err := s.BuildWorkoutResponses(r, []model.Workout{*created.workout}, profile)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &dto.WorkoutCreateResponse{
+		WorkoutResponse: workoutResponses[0],
+		PersonalRecords: make([]dto.PersonalRecordResponse, 0, len(created.personalRecords)),
+	}
+	for _, personalRecord := range created.personalRecords {
+		res.PersonalRecords = append(res.PersonalRecords, toPersonalRecordResponse(personalRecord, profile.WeightUnit))
+	}
+
+	return res, nil
+}
+
+// SaveWorkout stores a workout with its sets and updates everything derived from them: personal
+// records and the daily stats. Set weights must already be in kg; the workout and sets get their IDs.
+// Workouts without a visibility get the default of the profile. Experience points are left to the
+// callers, as imported workouts do not earn any.
+func (s *workoutService) SaveWorkout(
+	ctx context.Context,
+	tx *sql.Tx,
+	profile *model.ProfileWithUser,
+	workout *model.Workout,
+	sets []model.Set,
+) ([]model.PersonalRecord, error) {
+	workout.ProfileID = profile.ID
+	if workout.Visibility == "" {
+		workout.Visibility = defaultWorkoutVisibility(profile)
+	}
+
+	_, err := s.WorkoutRepository.Create(ctx, tx, workout)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range sets {
+		sets[i].WorkoutID = workout.ID
+		sets[i].WeightLb = util.KgToLb(sets[i].WeightKg)
+		sets[i].E1RMKg = EstimateOneRepMax(profile.OneRepMaxFormula, sets[i].WeightKg, sets[i].Reps)
+
+		_, err := s.SetRepository.Create(ctx, tx, &sets[i])
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	personalRecords, err := s.PersonalRecordService.DetectPersonalRecords(ctx, tx, workout, sets)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.StatsService.ApplyWorkout(ctx, tx, workout, sets, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	return personalRecords, nil
+}
+
+// validateExercises checks that every set references a default exercise or one of the profile's own.
+func (s *workoutService) validateExercises(r *http.Request, profileID int, sets []dto.SetRequest) error {
+	exerciseIDs := []int{}
+	for _, set := range sets {
+		exerciseIDs = append(exerciseIDs, set.ExerciseID)
+	}
+
+	exercises, err := s.ExerciseRepository.GetByIDs(r.Context(), exerciseIDs)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return customError.ErrInternalServerError
+	}
+
+	for _, exerciseID := range exerciseIDs {
+		exercise, ok := exercises[exerciseID]
+		if !ok || (exercise.ProfileID != nil && *exercise.ProfileID != profileID) {
+			return fmt.Errorf("exercise %d not found", exerciseID)
+		}
+	}
+
+	return nil
+}
+
+// UpdateVisibility changes who can see one of the caller's workouts.
+func (s *workoutService) UpdateVisibility(w http.ResponseWriter, r *http.Request) (*dto.WorkoutResponse, error) {
+	workoutID, err := getIDParam(r, "workoutId")
+	if err != nil {
+		return nil, err
+	}
+
+	req := dto.WorkoutVisibilityRequest{}
+	err = json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrInvalidRequestBody
+	}
+
+	err = s.Validate.Struct(req)
+	if err != nil {
+		return nil, util.FormatValidationError(err.(validator.ValidationErrors))
+	}
+
+	profile, err := getAuthProfile(r, s.ProfileRepository)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrUnAuthorized
+	}
+
+	workout, err := s.WorkoutRepository.GetByID(r.Context(), workoutID)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrInternalServerError
+	}
+
+	if workout == nil || workout.ProfileID != profile.ID {
+		return nil, customError.ErrNotFound
+	}
+
+	if workout.Visibility == req.Visibility {
+		return nil, customError.ErrNothingToUpdate
+	}
+
+	err = s.WorkoutRepository.UpdateVisibility(r.Context(), workout.ID, req.Visibility)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrInternalServerError
+	}
+
+	workout.Visibility = req.Visibility
+
+	workoutResponses, err := s.BuildWorkoutResponses(r, []model.Workout{*workout}, profile)
+	if err != nil {
+		return nil, err
+	}
+
+	return &workoutResponses[0], nil
+}
+
+// DeleteWorkout removes one of the caller's workouts along with everything attached to it, and
+// takes it back out of their stats. Photo files are deleted once the rows are gone.
+func (s *workoutService) DeleteWorkout(w http.ResponseWriter, r *http.Request) error {
+	workoutID, err := getIDParam(r, "workoutId")
+	if err != nil {
+		return err
+	}
+
+	profile, err := getAuthProfile(r, s.ProfileRepository)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return customError.ErrUnAuthorized
+	}
+
+	workout, err := s.WorkoutRepository.GetByID(r.Context(), workoutID)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return customError.ErrInternalServerError
+	}
+
+	if workout == nil || workout.ProfileID != profile.ID {
+		return customError.ErrNotFound
+	}
+
+	sets, err := s.SetRepository.GetByWorkoutID(r.Context(), workout.ID)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return customError.ErrInternalServerError
+	}
+
+	photos, err := s.WorkoutPhotoRepository.GetByWorkoutID(r.Context(), workout.ID)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return customError.ErrInternalServerError
+	}
+
+	_, err = util.WithTransaction(r.Context(), s.DB.GetDB(), func(tx *sql.Tx) (interface{}, error) {
+		// Revoked while the stats and personal records still hold the workout
+		err := s.ExperiencePointService.RevokeWorkout(r.Context(), tx, workout)
+		if err != nil {
+			return nil, err
+		}
+
+		err = s.StatsService.ApplyWorkout(r.Context(), tx, workout, sets, -1)
+		if err != nil {
+			return nil, err
+		}
+
+		return nil, s.WorkoutRepository.Delete(r.Context(), tx, workout.ID)
+	})
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return customError.ErrInternalServerError
+	}
+
+	s.WorkoutPhotoService.DeleteFiles(r.Context(), photos)
+
+	err = s.GoalService.EvaluateGoals(r.Context(), profile.ID)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+	}
+
+	return nil
+}
+
+func (s *workoutService) PutReaction(w http.ResponseWriter, r *http.Request) (*dto.WorkoutReactionsSummary, error) {
+	req := dto.WorkoutReactionRequest{Reaction: chi.URLParam(r, "reaction")}
+	err := s.Validate.Struct(req)
+	if err != nil {
+		return nil, util.FormatValidationError(err.(validator.ValidationErrors))
+	}
+
+	viewer, workout, err := s.getViewableWorkout(r)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.WorkoutReactionRepository.Create(r.Context(), &model.WorkoutReaction{
+		WorkoutID: workout.ID,
+		ProfileID: viewer.ID,
+		Reaction:  req.Reaction,
+	})
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrInternalServerError
+	}
+
+	return s.getReactionsSummary(r, workout.ID, viewer.ID)
+}
+
+func (s *workoutService) RemoveReaction(w http.ResponseWriter, r *http.Request) (*dto.WorkoutReactionsSummary, error) {
+	req := dto.WorkoutReactionRequest{Reaction: chi.URLParam(r, "reaction")}
+	err := s.Validate.Struct(req)
+	if err != nil {
+		return nil, util.FormatValidationError(err.(validator.ValidationErrors))
+	}
+
+	viewer, workout, err := s.getViewableWorkout(r)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.WorkoutReactionRepository.Delete(r.Context(), &model.WorkoutReaction{
+		WorkoutID: workout.ID,
+		ProfileID: viewer.ID,
+		Reaction:  req.Reaction,
+	})
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrInternalServerError
+	}
+
+	return s.getReactionsSummary(r, workout.ID, viewer.ID)
+}
+
+func (s *workoutService) GetReactions(w http.ResponseWriter, r *http.Request) (*dto.WorkoutReactionsListResponse, error) {
+	reaction := r.URL.Query().Get("reaction")
+	if reaction != "" {
+		err := s.Validate.Struct(dto.WorkoutReactionRequest{Reaction: reaction})
+		if err != nil {
+			return nil, util.FormatValidationError(err.(validator.ValidationErrors))
+		}
+	}
+
+	beforeTime, beforeID, err := util.DecodeCursor(r.URL.Query().Get("cursor"))
+	if err != nil {
+		return nil, customError.ErrInvalidCursor
+	}
+
+	viewer, workout, err := s.getViewableWorkout(r)
+	if err != nil {
+		return nil, err
+	}
+
+	limit := util.GetPageLimit(r, 20, 100)
+	reactions, err := s.WorkoutReactionRepository.GetByWorkoutID(r.Context(), workout.ID, viewer.ID, reaction, beforeTime, beforeID, limit)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrInternalServerError
+	}
+
+	res := &dto.WorkoutReactionsListResponse{
+		Reactions: make([]dto.WorkoutReactionProfileResponse, 0, len(reactions)),
+	}
+	for _, wr := range reactions {
+		res.Reactions = append(res.Reactions, dto.WorkoutReactionProfileResponse{
+			ProfileID:     wr.ProfileID,
+			DisplayName:   wr.DisplayName,
+			AvatarVersion: wr.AvatarVersion,
+			Reaction:      wr.Reaction,
+			CreatedAt:     wr.CreatedAt,
+		})
+	}
+
+	if len(reactions) == limit {
+		last := reactions[len(reactions)-1]
+		res.NextCursor = util.EncodeCursor(last.CreatedAt, last.ID)
+	}
+
+	return res, nil
+}
+
+func (s *workoutService) GetFeed(w http.ResponseWriter, r *http.Request) (*dto.FeedResponse, error) {
+	beforeTime, beforeID, err := util.DecodeCursor(r.URL.Query().Get("cursor"))
+	if err != nil {
+		return nil, customError.ErrInvalidCursor
+	}
+
+	viewer, err := getAuthProfile(r, s.ProfileRepository)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrUnAuthorized
+	}
+
+	limit := util.GetPageLimit(r, 20, 50)
+	feedWorkouts, err := s.WorkoutRepository.GetFeed(r.Context(), viewer.ID, beforeTime, beforeID, limit)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrInternalServerError
+	}
+
+	workouts := make([]model.Workout, 0, len(feedWorkouts))
+	for _, feedWorkout := range feedWorkouts {
+		workouts = append(workouts, feedWorkout.Workout)
+	}
+
+	workoutResponses, err := s.BuildWorkoutResponses(r, workouts, viewer)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &dto.FeedResponse{
+		Workouts: make([]dto.FeedWorkoutResponse, 0, len(feedWorkouts)),
+	}
+	for i, feedWorkout := range feedWorkouts {
+		res.Workouts = append(res.Workouts, dto.FeedWorkoutResponse{
+			WorkoutResponse: workoutResponses[i],
+			Profile: dto.ProfileSummaryResponse{
+				ProfileID:     feedWorkout.ProfileID,
+				DisplayName:   feedWorkout.DisplayName,
+				AvatarVersion: feedWorkout.AvatarVersion,
+			},
+		})
+	}
+
+	if len(feedWorkouts) == limit {
+		last := feedWorkouts[len(feedWorkouts)-1]
+		res.NextCursor = util.EncodeCursor(last.StartDate, last.ID)
+	}
+
+	return res, nil
+}
+
+// BuildWorkoutResponses attaches exercise summaries, reaction and comment counts to the workouts
+// using one query per relation regardless of how many workouts are passed. Weights are rendered
+// in the viewer's unit.
+func (s *workoutService) BuildWorkoutResponses(r *http.Request, workouts []model.Workout, viewer *model.ProfileWithUser) ([]dto.WorkoutResponse, error) {
+	workoutIDs := make([]int, 0, len(workouts))
+	for _, workout := range workouts {
+		workoutIDs = append(workoutIDs, workout.ID)
+	}
+
+	exerciseSummaries, err := s.SetRepository.GetExerciseSummariesByWorkoutIDs(r.Context(), workoutIDs)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrInternalServerError
+	}
+
+	reactionCounts, err := s.WorkoutReactionRepository.GetCountsByWorkoutIDs(r.Context(), workoutIDs, viewer.ID)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrInternalServerError
+	}
+
+	myReactions, err := s.WorkoutReactionRepository.GetReactionsByProfileID(r.Context(), workoutIDs, viewer.ID)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrInternalServerError
+	}
+
+	commentCounts, err := s.WorkoutCommentRepository.GetCountsByWorkoutIDs(r.Context(), workoutIDs, viewer.ID)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrInternalServerError
+	}
+
+	cardioSessions, err := s.CardioSessionRepository.GetByWorkoutIDs(r.Context(), workoutIDs)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrInternalServerError
+	}
+
+	photoCounts, err := s.WorkoutPhotoRepository.GetCountsByWorkoutIDs(r.Context(), workoutIDs)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrInternalServerError
+	}
+
+	workoutResponses := make([]dto.WorkoutResponse, 0, len(workouts))
+	for _, workout := range workouts {
+		exercises := make([]dto.WorkoutExerciseSummaryResponse, 0, len(exerciseSummaries[workout.ID]))
+		for _, summary := range exerciseSummaries[workout.ID] {
+			exercises = append(exercises, dto.WorkoutExerciseSummaryResponse{
+				ExerciseID: summary.ExerciseID,
+				Name:       summary.Name,
+				IconName:   summary.IconName,
+				SetCount:   summary.SetCount,
+				TotalReps:  summary.TotalReps,
+				MaxWeight:  util.FromKg(summary.MaxWeightKg, viewer.WeightUnit),
+			})
+		}
+
+		var cardio *dto.CardioSessionResponse
+		if cardioSession, ok := cardioSessions[workout.ID]; ok {
+			cardioResponse := toCardioSessionResponse(cardioSession)
+			cardio = &cardioResponse
+		}
+
+		workoutResponses = append(workoutResponses, dto.WorkoutResponse{
+			ID:                  workout.ID,
+			ProfileID:           workout.ProfileID,
+			Name:                workout.Name,
+			Description:         workout.Description,
+			Visibility:          workout.Visibility,
+			MentalEnergyLevel:   workout.MentalEnergyLevel,
+			PhysicalEnergyLevel: workout.PhysicalEnergyLevel,
+			StartDate:           workout.StartDate,
+			EndDate:             workout.EndDate,
+			Exercises:           exercises,
+			Reactions: dto.WorkoutReactionsSummary{
+				Counts:      reactionCounts[workout.ID],
+				MyReactions: myReactions[workout.ID],
+			},
+			CommentCount: commentCounts[workout.ID],
+			PhotoCount:   photoCounts[workout.ID],
+			WeightUnit:   viewer.WeightUnit,
+			Cardio:       cardio,
+		})
+	}
+
+	return workoutResponses, nil
+}
+
+// getViewableWorkout loads the workout named by the workoutId URL parameter along with the
+// caller's profile, failing if the caller is not allowed to see it.
+func (s *workoutService) getViewableWorkout(r *http.Request) (*model.ProfileWithUser, *model.Workout, error) {
+	return getViewableWorkout(r, s.DBLogger, s.WorkoutRepository, s.ProfileRepository, s.ProfileFollowRepository, s.ProfileBlockRepository)
+}
+
+// getViewableWorkout does the work of workoutService.getViewableWorkout for the other services
+// serving data that hangs off a workout.
+func getViewableWorkout(
+	r *http.Request,
+	dbLogger *slog.Logger,
+	workoutRepository repository.WorkoutRepository,
+	profileRepository repository.ProfileRepository,
+	profileFollowRepository repository.ProfileFollowRepository,
+	profileBlockRepository repository.ProfileBlockRepository,
+) (*model.ProfileWithUser, *model.Workout, error) {
+	workoutID, err := getIDParam(r, "workoutId")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	viewer, err := getAuthProfile(r, profileRepository)
+	if err != nil {
+		util.LogWithContext(dbLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, nil, customError.ErrUnAuthorized
+	}
+
+	workout, err := workoutRepository.GetByID(r.Context(), workoutID)
+	if err != nil {
+		util.LogWithContext(dbLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, nil, customError.ErrInternalServerError
+	}
+
+	if workout == nil {
+		return nil, nil, customError.ErrNotFound
+	}
+
+	owner, err := profileRepository.GetByID(r.Context(), workout.ProfileID)
+	if err != nil || owner == nil {
+		util.LogWithContext(dbLogger, slog.LevelError, "failed to get workout owner", nil, r)
+		return nil, nil, customError.ErrInternalServerError
+	}
+
+	canView, err := canViewWorkout(r, profileFollowRepository, profileBlockRepository, viewer.ID, owner, workout)
+	if err != nil {
+		util.LogWithContext(dbLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, nil, customError.ErrInternalServerError
+	}
+
+	if !canView {
+		// Hide the existence of workouts the caller cannot see
+		return nil, nil, customError.ErrNotFound
+	}
+
+	return viewer, workout, nil
+}
+
+// canViewWorkout reports whether the viewer may see the workout. Its visibility can only narrow
+// the audience of the owner's profile, so a public workout of a private profile is seen by followers.
+func canViewWorkout(
+	r *http.Request,
+	profileFollowRepository repository.ProfileFollowRepository,
+	profileBlockRepository repository.ProfileBlockRepository,
+	viewerProfileID int,
+	owner *model.ProfileWithUser,
+	workout *model.Workout,
+) (bool, error) {
+	if owner.ID == viewerProfileID {
+		return true, nil
+	}
+
+	if workout.Visibility == model.WorkoutVisibilityPrivate {
+		return false, nil
+	}
+
+	canView, err := canViewProfileContent(r, profileFollowRepository, profileBlockRepository, viewerProfileID, owner)
+	if err != nil || !canView || workout.Visibility != model.WorkoutVisibilityFollowers || owner.Privacy == "private" {
+		return canView, err
+	}
+
+	// A followers only workout of a public profile is still hidden from everyone else
+	return profileFollowRepository.IsFollowing(r.Context(), owner.ID, viewerProfileID)
+}
+
+// defaultWorkoutVisibility is the visibility of workouts saved without one: the audience of the
+// owner's profile.
+func defaultWorkoutVisibility(profile *model.ProfileWithUser) string {
+	if profile.Privacy == "private" {
+		return model.WorkoutVisibilityFollowers
+	}
+
+	return model.WorkoutVisibilityPublic
+}
+
+func (s *workoutService) getReactionsSummary(r *http.Request, workoutID int, viewerProfileID int) (*dto.WorkoutReactionsSummary, error) {
+	counts, err := s.WorkoutReactionRepository.GetCountsByWorkoutIDs(r.Context(), []int{workoutID}, viewerProfileID)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrInternalServerError
+	}
+
+	myReactions, err := s.WorkoutReactionRepository.GetReactionsByProfileID(r.Context(), []int{workoutID}, viewerProfileID)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrInternalServerError
+	}
+
+	return &dto.WorkoutReactionsSummary{
+		Counts:      counts[workoutID],
+		MyReactions: myReactions[workoutID],
+	}, nil
+}
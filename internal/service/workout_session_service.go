@@ -0,0 +1,781 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/brightside-dev/ronin-fitness-be/database/client"
+	"github.com/brightside-dev/ronin-fitness-be/internal/handler/dto"
+	customError "github.com/brightside-dev/ronin-fitness-be/internal/handler/error"
+	"github.com/brightside-dev/ronin-fitness-be/internal/model"
+	"github.com/brightside-dev/ronin-fitness-be/internal/repository"
+	"github.com/brightside-dev/ronin-fitness-be/internal/util"
+	"github.com/go-playground/validator/v10"
+)
+
+const (
+	// defaultWorkoutSessionTimeout applies when WORKOUT_SESSION_TIMEOUT is not set to a valid duration
+	defaultWorkoutSessionTimeout = 4 * time.Hour
+	// inactiveSessionBatchSize is how many sessions CloseInactiveSessions loads at a time
+	inactiveSessionBatchSize = 100
+)
+
+type WorkoutSessionService interface {
+	StartSession(w http.ResponseWriter, r *http.Request) (*dto.WorkoutSessionResponse, error)
+	GetActiveSession(w http.ResponseWriter, r *http.Request) (*dto.WorkoutSessionResponse, error)
+	AddSet(w http.ResponseWriter, r *http.Request) (*dto.WorkoutSessionResponse, error)
+	UpdateSet(w http.ResponseWriter, r *http.Request) (*dto.WorkoutSessionResponse, error)
+	DeleteSet(w http.ResponseWriter, r *http.Request) (*dto.WorkoutSessionResponse, error)
+	PauseSession(w http.ResponseWriter, r *http.Request) (*dto.WorkoutSessionResponse, error)
+	ResumeSession(w http.ResponseWriter, r *http.Request) (*dto.WorkoutSessionResponse, error)
+	DiscardSession(w http.ResponseWriter, r *http.Request) (*dto.WorkoutSessionResponse, error)
+	FinishSession(w http.ResponseWriter, r *http.Request) (*dto.WorkoutCreateResponse, error)
+	CloseInactiveSessions(ctx context.Context) (int, error)
+}
+
+type workoutSessionService struct {
+	DB                          client.DatabaseService
+	DBLogger                    *slog.Logger
+	Validate                    *validator.Validate
+	WorkoutSessionRepository    repository.WorkoutSessionRepository
+	ExerciseRepository          repository.ExerciseRepository
+	ProfileRepository           repository.ProfileRepository
+	ProgramEnrollmentRepository repository.ProgramEnrollmentRepository
+	WorkoutService              WorkoutService
+	ProgramService              ProgramService
+	GoalService                 GoalService
+	ExperiencePointService      ExperiencePointService
+	AchievementService          AchievementService
+	// SessionTimeout is how long a session may go without activity before it is closed
+	SessionTimeout time.Duration
+}
+
+func NewWorkoutSessionService(
+	db client.DatabaseService,
+	dbLogger *slog.Logger,
+	validator *validator.Validate,
+	workoutSessionRepository repository.WorkoutSessionRepository,
+	exerciseRepository repository.ExerciseRepository,
+	profileRepository repository.ProfileRepository,
+	programEnrollmentRepository repository.ProgramEnrollmentRepository,
+	workoutService WorkoutService,
+	programService ProgramService,
+	goalService GoalService,
+	experiencePointService ExperiencePointService,
+	achievementService AchievementService,
+) WorkoutSessionService {
+	sessionTimeout, err := time.ParseDuration(os.Getenv("WORKOUT_SESSION_TIMEOUT"))
+	if err != nil || sessionTimeout <= 0 {
+		sessionTimeout = defaultWorkoutSessionTimeout
+	}
+
+	return &workoutSessionService{
+		DB:                          db,
+		DBLogger:                    dbLogger,
+		Validate:                    validator,
+		WorkoutSessionRepository:    workoutSessionRepository,
+		ExerciseRepository:          exerciseRepository,
+		ProfileRepository:           profileRepository,
+		ProgramEnrollmentRepository: programEnrollmentRepository,
+		WorkoutService:              workoutService,
+		ProgramService:              programService,
+		GoalService:                 goalService,
+		ExperiencePointService:      experiencePointService,
+		AchievementService:          achievementService,
+		SessionTimeout:              sessionTimeout,
+	}
+}
+
+// StartSession opens a new session. A profile can only have one open session; one that has
+// timed out is closed first.
+func (s *workoutSessionService) StartSession(w http.ResponseWriter, r *http.Request) (*dto.WorkoutSessionResponse, error) {
+	req := dto.WorkoutSessionStartRequest{}
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrInvalidRequestBody
+	}
+
+	err = s.Validate.Struct(req)
+	if err != nil {
+		return nil, util.FormatValidationError(err.(validator.ValidationErrors))
+	}
+
+	now := time.Now().UTC().Truncate(time.Second)
+	startedAt := now
+	if req.StartedAt != nil {
+		startedAt = req.StartedAt.UTC().Truncate(time.Second)
+	}
+
+	if startedAt.After(now) {
+		return nil, fmt.Errorf("started_at must not be in the future")
+	}
+
+	if now.Sub(startedAt) > s.SessionTimeout {
+		return nil, fmt.Errorf("started_at must be within the last %s", s.SessionTimeout)
+	}
+
+	profile, err := s.getAuthProfile(r)
+	if err != nil {
+		return nil, err
+	}
+
+	openSession, err := s.getOpenSession(r, profile.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if openSession != nil {
+		return nil, fmt.Errorf("%w: workout session %d is already in progress", customError.ErrConflict, openSession.ID)
+	}
+
+	if req.ProgramEnrollmentID != nil {
+		enrollment, err := s.ProgramEnrollmentRepository.GetByID(r.Context(), *req.ProgramEnrollmentID)
+		if err != nil {
+			util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+			return nil, customError.ErrInternalServerError
+		}
+
+		if enrollment == nil || enrollment.ProfileID != profile.ID {
+			return nil, customError.ErrNotFound
+		}
+
+		if enrollment.Status != model.ProgramEnrollmentStatusActive {
+			return nil, fmt.Errorf("%w: enrollment is %s", customError.ErrConflict, enrollment.Status)
+		}
+	}
+
+	session, err := s.WorkoutSessionRepository.Create(r.Context(), &model.WorkoutSession{
+		ProfileID:           profile.ID,
+		Name:                req.Name,
+		Description:         req.Description,
+		ProgramEnrollmentID: req.ProgramEnrollmentID,
+		StartedAt:           startedAt,
+		LastActivityAt:      now,
+	})
+	// A concurrent start opened a session since it was checked, the unique open_profile_id key
+	// rejects the second one
+	if util.IsDuplicateKeyError(err) {
+		return nil, fmt.Errorf("%w: a workout session is already in progress", customError.ErrConflict)
+	}
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrInternalServerError
+	}
+
+	return s.toWorkoutSessionResponse(session, []model.WorkoutSessionSet{}, profile.WeightUnit, now), nil
+}
+
+// GetActiveSession returns the caller's open session so a client can pick up where it left off.
+func (s *workoutSessionService) GetActiveSession(w http.ResponseWriter, r *http.Request) (*dto.WorkoutSessionResponse, error) {
+	profile, err := s.getAuthProfile(r)
+	if err != nil {
+		return nil, err
+	}
+
+	session, err := s.getOpenSession(r, profile.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if session == nil {
+		return nil, customError.ErrNotFound
+	}
+
+	return s.getWorkoutSessionResponse(r, session.ID, profile.WeightUnit)
+}
+
+func (s *workoutSessionService) AddSet(w http.ResponseWriter, r *http.Request) (*dto.WorkoutSessionResponse, error) {
+	profile, err := s.getAuthProfile(r)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := s.getSetRequest(r, profile.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.updateOpenSession(r, profile, func(tx *sql.Tx, session *model.WorkoutSession, now time.Time) error {
+		set := toWorkoutSessionSet(req, profile.WeightUnit)
+		set.SessionID = session.ID
+
+		if set.SetNumber == 0 {
+			setNumber, err := s.WorkoutSessionRepository.GetNextSetNumber(r.Context(), tx, session.ID, set.ExerciseID)
+			if err != nil {
+				return err
+			}
+			set.SetNumber = setNumber
+		}
+
+		_, err := s.WorkoutSessionRepository.CreateSet(r.Context(), tx, set)
+
+		return err
+	})
+}
+
+func (s *workoutSessionService) UpdateSet(w http.ResponseWriter, r *http.Request) (*dto.WorkoutSessionResponse, error) {
+	setID, err := getIDParam(r, "setId")
+	if err != nil {
+		return nil, err
+	}
+
+	profile, err := s.getAuthProfile(r)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := s.getSetRequest(r, profile.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.updateOpenSession(r, profile, func(tx *sql.Tx, session *model.WorkoutSession, now time.Time) error {
+		existing, err := s.WorkoutSessionRepository.GetSetByID(r.Context(), tx, session.ID, setID)
+		if err != nil {
+			return err
+		}
+
+		if existing == nil {
+			return customError.ErrNotFound
+		}
+
+		set := toWorkoutSessionSet(req, profile.WeightUnit)
+		set.ID = existing.ID
+		set.SessionID = session.ID
+		if set.SetNumber == 0 {
+			set.SetNumber = existing.SetNumber
+		}
+
+		return s.WorkoutSessionRepository.UpdateSet(r.Context(), tx, set)
+	})
+}
+
+func (s *workoutSessionService) DeleteSet(w http.ResponseWriter, r *http.Request) (*dto.WorkoutSessionResponse, error) {
+	setID, err := getIDParam(r, "setId")
+	if err != nil {
+		return nil, err
+	}
+
+	profile, err := s.getAuthProfile(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.updateOpenSession(r, profile, func(tx *sql.Tx, session *model.WorkoutSession, now time.Time) error {
+		existing, err := s.WorkoutSessionRepository.GetSetByID(r.Context(), tx, session.ID, setID)
+		if err != nil {
+			return err
+		}
+
+		if existing == nil {
+			return customError.ErrNotFound
+		}
+
+		return s.WorkoutSessionRepository.DeleteSet(r.Context(), tx, session.ID, setID)
+	})
+}
+
+func (s *workoutSessionService) PauseSession(w http.ResponseWriter, r *http.Request) (*dto.WorkoutSessionResponse, error) {
+	profile, err := s.getAuthProfile(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.updateOpenSession(r, profile, func(tx *sql.Tx, session *model.WorkoutSession, now time.Time) error {
+		if session.Status != model.WorkoutSessionStatusActive {
+			return fmt.Errorf("%w: workout session is already paused", customError.ErrConflict)
+		}
+
+		return s.WorkoutSessionRepository.Pause(r.Context(), tx, session.ID, now)
+	})
+}
+
+func (s *workoutSessionService) ResumeSession(w http.ResponseWriter, r *http.Request) (*dto.WorkoutSessionResponse, error) {
+	profile, err := s.getAuthProfile(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.updateOpenSession(r, profile, func(tx *sql.Tx, session *model.WorkoutSession, now time.Time) error {
+		if session.Status != model.WorkoutSessionStatusPaused {
+			return fmt.Errorf("%w: workout session is not paused", customError.ErrConflict)
+		}
+
+		pausedSeconds := session.PausedSeconds + int(now.Sub(*session.PausedAt).Seconds())
+
+		return s.WorkoutSessionRepository.Resume(r.Context(), tx, session.ID, pausedSeconds, now)
+	})
+}
+
+// DiscardSession closes the session without saving a workout.
+func (s *workoutSessionService) DiscardSession(w http.ResponseWriter, r *http.Request) (*dto.WorkoutSessionResponse, error) {
+	profile, err := s.getAuthProfile(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.updateOpenSession(r, profile, func(tx *sql.Tx, session *model.WorkoutSession, now time.Time) error {
+		return s.WorkoutSessionRepository.Close(r.Context(), tx, session.ID, model.WorkoutSessionStatusAbandoned, now, nil)
+	})
+}
+
+// FinishSession saves the session's sets as a workout, which detects personal records, updates
+// the stats and puts the workout in the feed.
+func (s *workoutSessionService) FinishSession(w http.ResponseWriter, r *http.Request) (*dto.WorkoutCreateResponse, error) {
+	sessionID, err := getIDParam(r, "sessionId")
+	if err != nil {
+		return nil, err
+	}
+
+	req := dto.WorkoutSessionFinishRequest{}
+	err = json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrInvalidRequestBody
+	}
+
+	err = s.Validate.Struct(req)
+	if err != nil {
+		return nil, util.FormatValidationError(err.(validator.ValidationErrors))
+	}
+
+	profile, err := s.getAuthProfile(r)
+	if err != nil {
+		return nil, err
+	}
+
+	type finishResult struct {
+		workout         *model.Workout
+		personalRecords []model.PersonalRecord
+	}
+
+	result, err := util.WithTransaction(r.Context(), s.DB.GetDB(), func(tx *sql.Tx) (interface{}, error) {
+		session, err := s.WorkoutSessionRepository.GetByIDForUpdate(r.Context(), tx, sessionID)
+		if err != nil {
+			return nil, err
+		}
+
+		if session == nil || session.ProfileID != profile.ID {
+			return nil, customError.ErrNotFound
+		}
+
+		if !isWorkoutSessionOpen(session) {
+			return nil, fmt.Errorf("%w: workout session is %s", customError.ErrConflict, session.Status)
+		}
+
+		// A session finished long after it was left ends with its last activity rather than now
+		finishedAt := time.Now().UTC().Truncate(time.Second)
+		if s.isWorkoutSessionInactive(session, finishedAt) {
+			finishedAt = session.LastActivityAt
+		}
+
+		sets, err := s.WorkoutSessionRepository.GetSets(r.Context(), session.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(sets) == 0 {
+			return nil, fmt.Errorf("%w: workout session has no sets, discard it instead", customError.ErrConflict)
+		}
+
+		if req.Name != "" {
+			session.Name = req.Name
+		}
+		if req.Description != nil {
+			session.Description = *req.Description
+		}
+
+		workout, personalRecords, err := s.saveSessionWorkout(r.Context(), tx, profile, session, sets, &model.Workout{
+			Visibility:          req.Visibility,
+			MentalEnergyLevel:   req.MentalEnergyLevel,
+			PhysicalEnergyLevel: req.PhysicalEnergyLevel,
+		}, finishedAt)
+		if err != nil {
+			return nil, err
+		}
+
+		if session.ProgramEnrollmentID != nil {
+			err = s.ProgramService.CompleteSession(r.Context(), tx, profile.ID, *session.ProgramEnrollmentID, workout.ID)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		return &finishResult{workout: workout, personalRecords: personalRecords}, nil
+	})
+	if errors.Is(err, customError.ErrNotFound) || errors.Is(err, customError.ErrConflict) {
+		return nil, err
+	}
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrInternalServerError
+	}
+
+	finished := result.(*finishResult)
+
+	err = s.GoalService.EvaluateGoals(r.Context(), profile.ID)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+	}
+
+	err = s.AchievementService.EvaluateAchievements(r.Context(), profile.ID, workoutAchievementEvents(finished.personalRecords)...)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+	}
+
+	workoutResponses, err := s.WorkoutService.BuildWorkoutResponses(r, []model.Workout{*finished.workout}, profile)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &dto.WorkoutCreateResponse{
+		WorkoutResponse: workoutResponses[0],
+		PersonalRecords: make([]dto.PersonalRecordResponse, 0, len(finished.personalRecords)),
+	}
+	for _, personalRecord := range finished.personalRecords {
+		res.PersonalRecords = append(res.PersonalRecords, toPersonalRecordResponse(personalRecord, profile.WeightUnit))
+	}
+
+	return res, nil
+}
+
+// CloseInactiveSessions closes every open session that has gone without activity for longer than
+// the session timeout, returning how many were closed. It is meant to run periodically.
+func (s *workoutSessionService) CloseInactiveSessions(ctx context.Context) (int, error) {
+	closed := 0
+	for {
+		sessions, err := s.WorkoutSessionRepository.GetInactiveSince(ctx, time.Now().UTC().Add(-s.SessionTimeout), inactiveSessionBatchSize)
+		if err != nil {
+			return closed, err
+		}
+
+		for _, session := range sessions {
+			err := s.closeInactiveSession(ctx, session.ID)
+			if err != nil {
+				return closed, err
+			}
+			closed++
+		}
+
+		if len(sessions) < inactiveSessionBatchSize {
+			return closed, nil
+		}
+	}
+}
+
+// getOpenSession returns the profile's open session, closing it instead if it has timed out.
+func (s *workoutSessionService) getOpenSession(r *http.Request, profileID int) (*model.WorkoutSession, error) {
+	session, err := s.WorkoutSessionRepository.GetOpenByProfileID(r.Context(), profileID)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrInternalServerError
+	}
+
+	if session == nil || !s.isWorkoutSessionInactive(session, time.Now().UTC()) {
+		return session, nil
+	}
+
+	err = s.closeInactiveSession(r.Context(), session.ID)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrInternalServerError
+	}
+
+	return nil, nil
+}
+
+// updateOpenSession applies update to the caller's session named by the sessionId URL parameter
+// while holding its lock, records the activity and returns the updated session.
+func (s *workoutSessionService) updateOpenSession(
+	r *http.Request,
+	profile *model.ProfileWithUser,
+	update func(tx *sql.Tx, session *model.WorkoutSession, now time.Time) error,
+) (*dto.WorkoutSessionResponse, error) {
+	sessionID, err := getIDParam(r, "sessionId")
+	if err != nil {
+		return nil, err
+	}
+
+	openSession, err := s.getOpenSession(r, profile.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if openSession == nil || openSession.ID != sessionID {
+		return nil, fmt.Errorf("%w: workout session is not in progress", customError.ErrConflict)
+	}
+
+	_, err = util.WithTransaction(r.Context(), s.DB.GetDB(), func(tx *sql.Tx) (interface{}, error) {
+		session, err := s.WorkoutSessionRepository.GetByIDForUpdate(r.Context(), tx, sessionID)
+		if err != nil {
+			return nil, err
+		}
+
+		if !isWorkoutSessionOpen(session) {
+			return nil, fmt.Errorf("%w: workout session is %s", customError.ErrConflict, session.Status)
+		}
+
+		now := time.Now().UTC().Truncate(time.Second)
+		err = update(tx, session, now)
+		if err != nil {
+			return nil, err
+		}
+
+		return nil, s.WorkoutSessionRepository.Touch(r.Context(), tx, session.ID, now)
+	})
+	if errors.Is(err, customError.ErrNotFound) || errors.Is(err, customError.ErrConflict) {
+		return nil, err
+	}
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrInternalServerError
+	}
+
+	return s.getWorkoutSessionResponse(r, sessionID, profile.WeightUnit)
+}
+
+// closeInactiveSession saves a timed out session as a workout ending at its last activity, or
+// abandons it if no sets were logged. The program enrollment is left as it was, as the user never
+// confirmed the session was complete.
+func (s *workoutSessionService) closeInactiveSession(ctx context.Context, sessionID int) error {
+	result, err := util.WithTransaction(ctx, s.DB.GetDB(), func(tx *sql.Tx) (interface{}, error) {
+		session, err := s.WorkoutSessionRepository.GetByIDForUpdate(ctx, tx, sessionID)
+		if err != nil {
+			return nil, err
+		}
+
+		// Another request may have finished or resumed the session in the meantime
+		if session == nil || !isWorkoutSessionOpen(session) || !s.isWorkoutSessionInactive(session, time.Now().UTC()) {
+			return nil, nil
+		}
+
+		sets, err := s.WorkoutSessionRepository.GetSets(ctx, session.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(sets) == 0 {
+			return nil, s.WorkoutSessionRepository.Close(ctx, tx, session.ID, model.WorkoutSessionStatusAbandoned, session.LastActivityAt, nil)
+		}
+
+		profile, err := s.ProfileRepository.GetByID(ctx, session.ProfileID)
+		if err != nil {
+			return nil, err
+		}
+
+		if profile == nil {
+			return nil, fmt.Errorf("profile %d of workout session %d not found", session.ProfileID, session.ID)
+		}
+
+		_, _, err = s.saveSessionWorkout(ctx, tx, profile, session, sets, &model.Workout{
+			MentalEnergyLevel:   defaultEnergyLevel,
+			PhysicalEnergyLevel: defaultEnergyLevel,
+		}, session.LastActivityAt)
+		if err != nil {
+			return nil, err
+		}
+
+		return profile.ID, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// The result is only set when a workout was saved
+	if profileID, ok := result.(int); ok {
+		err = s.GoalService.EvaluateGoals(ctx, profileID)
+		if err != nil {
+			util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, nil)
+		}
+
+		// Whether the workout set personal records is not kept, measuring them anyway is harmless
+		err = s.AchievementService.EvaluateAchievements(ctx, profileID, model.AchievementEventWorkout, model.AchievementEventPersonalRecord)
+		if err != nil {
+			util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, nil)
+		}
+	}
+
+	return nil
+}
+
+// saveSessionWorkout saves the session and its sets as a workout and closes it. The workout only
+// needs the fields the session does not provide, e.g. the energy levels.
+func (s *workoutSessionService) saveSessionWorkout(
+	ctx context.Context,
+	tx *sql.Tx,
+	profile *model.ProfileWithUser,
+	session *model.WorkoutSession,
+	sessionSets []model.WorkoutSessionSet,
+	workout *model.Workout,
+	finishedAt time.Time,
+) (*model.Workout, []model.PersonalRecord, error) {
+	sets := make([]model.Set, 0, len(sessionSets))
+	for _, set := range sessionSets {
+		sets = append(sets, model.Set{
+			ExerciseID: set.ExerciseID,
+			SetNumber:  set.SetNumber,
+			Duration:   set.Duration,
+			WeightKg:   set.WeightKg,
+			Reps:       set.Reps,
+		})
+	}
+
+	workout.Name = session.Name
+	workout.Description = session.Description
+	workout.StartDate = session.StartedAt
+	// Pauses are left out so the workout's duration is the time actually spent training
+	workout.EndDate = session.StartedAt.Add(time.Duration(max(workoutSessionActiveSeconds(session, finishedAt), 1)) * time.Second)
+
+	personalRecords, err := s.WorkoutService.SaveWorkout(ctx, tx, profile, workout, sets)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	err = s.ExperiencePointService.AwardWorkout(ctx, tx, workout, personalRecords)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	err = s.WorkoutSessionRepository.Close(ctx, tx, session.ID, model.WorkoutSessionStatusFinished, finishedAt, &workout.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return workout, personalRecords, nil
+}
+
+// getSetRequest decodes a set and checks it references a default exercise or one of the profile's own.
+func (s *workoutSessionService) getSetRequest(r *http.Request, profileID int) (*dto.WorkoutSessionSetRequest, error) {
+	req := dto.WorkoutSessionSetRequest{}
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrInvalidRequestBody
+	}
+
+	err = s.Validate.Struct(req)
+	if err != nil {
+		return nil, util.FormatValidationError(err.(validator.ValidationErrors))
+	}
+
+	exercises, err := s.ExerciseRepository.GetByIDs(r.Context(), []int{req.ExerciseID})
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrInternalServerError
+	}
+
+	exercise, ok := exercises[req.ExerciseID]
+	if !ok || (exercise.ProfileID != nil && *exercise.ProfileID != profileID) {
+		return nil, fmt.Errorf("exercise %d not found", req.ExerciseID)
+	}
+
+	return &req, nil
+}
+
+func (s *workoutSessionService) getAuthProfile(r *http.Request) (*model.ProfileWithUser, error) {
+	profile, err := getAuthProfile(r, s.ProfileRepository)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrUnAuthorized
+	}
+
+	return profile, nil
+}
+
+func (s *workoutSessionService) getWorkoutSessionResponse(r *http.Request, sessionID int, unit string) (*dto.WorkoutSessionResponse, error) {
+	session, err := s.WorkoutSessionRepository.GetByID(r.Context(), sessionID)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrInternalServerError
+	}
+
+	sets, err := s.WorkoutSessionRepository.GetSets(r.Context(), sessionID)
+	if err != nil {
+		util.LogWithContext(s.DBLogger, slog.LevelError, err.Error(), nil, r)
+		return nil, customError.ErrInternalServerError
+	}
+
+	return s.toWorkoutSessionResponse(session, sets, unit, time.Now().UTC()), nil
+}
+
+func (s *workoutSessionService) isWorkoutSessionInactive(session *model.WorkoutSession, now time.Time) bool {
+	return now.Sub(session.LastActivityAt) > s.SessionTimeout
+}
+
+func (s *workoutSessionService) toWorkoutSessionResponse(
+	session *model.WorkoutSession,
+	sets []model.WorkoutSessionSet,
+	unit string,
+	now time.Time,
+) *dto.WorkoutSessionResponse {
+	activeUntil := now
+	if session.FinishedAt != nil {
+		activeUntil = *session.FinishedAt
+	}
+
+	res := &dto.WorkoutSessionResponse{
+		ID:                  session.ID,
+		Name:                session.Name,
+		Description:         session.Description,
+		Status:              session.Status,
+		ProgramEnrollmentID: session.ProgramEnrollmentID,
+		StartedAt:           session.StartedAt,
+		PausedAt:            session.PausedAt,
+		ActiveSeconds:       workoutSessionActiveSeconds(session, activeUntil),
+		ExpiresAt:           session.LastActivityAt.Add(s.SessionTimeout),
+		WorkoutID:           session.WorkoutID,
+		WeightUnit:          unit,
+		Sets:                make([]dto.WorkoutSessionSetResponse, 0, len(sets)),
+	}
+	for _, set := range sets {
+		res.Sets = append(res.Sets, dto.WorkoutSessionSetResponse{
+			ID:         set.ID,
+			ExerciseID: set.ExerciseID,
+			SetNumber:  set.SetNumber,
+			Duration:   set.Duration,
+			Weight:     util.FromKg(set.WeightKg, unit),
+			Reps:       set.Reps,
+			CreatedAt:  set.CreatedAt,
+		})
+	}
+
+	return res
+}
+
+func isWorkoutSessionOpen(session *model.WorkoutSession) bool {
+	return session.Status == model.WorkoutSessionStatusActive || session.Status == model.WorkoutSessionStatusPaused
+}
+
+// workoutSessionActiveSeconds returns the time spent training from the start of the session up
+// to at, leaving out pauses.
+func workoutSessionActiveSeconds(session *model.WorkoutSession, at time.Time) int {
+	seconds := int(at.Sub(session.StartedAt).Seconds()) - session.PausedSeconds
+	if session.PausedAt != nil && at.After(*session.PausedAt) {
+		seconds -= int(at.Sub(*session.PausedAt).Seconds())
+	}
+
+	return max(seconds, 0)
+}
+
+func toWorkoutSessionSet(req *dto.WorkoutSessionSetRequest, profileUnit string) *model.WorkoutSessionSet {
+	unit := req.Unit
+	if unit == "" {
+		unit = profileUnit
+	}
+
+	return &model.WorkoutSessionSet{
+		ExerciseID: req.ExerciseID,
+		SetNumber:  req.SetNumber,
+		Duration:   req.Duration,
+		WeightKg:   util.ToKg(req.Weight, unit),
+		Reps:       req.Reps,
+	}
+}
@@ -0,0 +1,101 @@
+package storage
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewLocalRequiresSecret(t *testing.T) {
+	if _, err := NewLocal(t.TempDir(), "http://localhost/media", ""); err == nil {
+		t.Fatalf("NewLocal() with an empty secret succeeded, want an error")
+	}
+}
+
+func TestNewRequiresSigningKey(t *testing.T) {
+	tests := []struct {
+		name       string
+		signingKey string
+		jwtSecret  string
+		wantErr    bool
+	}{
+		{name: "signing key", signingKey: "storage secret"},
+		{name: "falls back to the JWT secret", jwtSecret: "jwt secret"},
+		{name: "neither", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("STORAGE_DRIVER", DriverLocal)
+			t.Setenv("STORAGE_LOCAL_PATH", t.TempDir())
+			t.Setenv("STORAGE_SIGNING_KEY", tt.signingKey)
+			t.Setenv("JWT_SECRET", tt.jwtSecret)
+
+			_, err := New()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("New() error = %v, want error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLocalServeHTTP(t *testing.T) {
+	local, err := NewLocal(t.TempDir(), "http://localhost/media", "secret")
+	if err != nil {
+		t.Fatalf("NewLocal() error = %v", err)
+	}
+
+	// The key has a "media" segment of its own, which must not be mistaken for the route
+	key := "workouts/12/media/photo.jpg"
+	if err := local.Put(context.Background(), key, []byte("jpeg"), "image/jpeg"); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+
+	signedURL, err := local.SignedURL(key, time.Hour)
+	if err != nil {
+		t.Fatalf("SignedURL() error = %v", err)
+	}
+
+	expired, err := local.SignedURL(key, -time.Minute)
+	if err != nil {
+		t.Fatalf("SignedURL() error = %v", err)
+	}
+
+	parsed, _ := url.Parse(signedURL)
+	query := parsed.Query()
+	query.Set("signature", strings.Repeat("0", 64))
+	forged := *parsed
+	forged.RawQuery = query.Encode()
+
+	otherKey := strings.Replace(signedURL, "workouts/12/", "workouts/13/", 1)
+
+	tests := []struct {
+		name   string
+		url    string
+		status int
+	}{
+		{name: "signed", url: signedURL, status: http.StatusOK},
+		{name: "expired", url: expired, status: http.StatusForbidden},
+		{name: "forged signature", url: forged.String(), status: http.StatusForbidden},
+		{name: "signature of another key", url: otherKey, status: http.StatusForbidden},
+		{name: "escaping key", url: "http://localhost/media/../secret", status: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			local.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, tt.url, nil))
+
+			if recorder.Code != tt.status {
+				t.Fatalf("status = %d, want %d", recorder.Code, tt.status)
+			}
+			if tt.status == http.StatusOK && recorder.Body.String() != "jpeg" {
+				t.Errorf("body = %q, want %q", recorder.Body.String(), "jpeg")
+			}
+		})
+	}
+}
@@ -0,0 +1,71 @@
+// Package storage keeps uploaded files such as workout photos, on the local disk or in an
+// S3 compatible bucket, and hands out signed, expiring URLs to read them.
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+const (
+	DriverLocal = "local"
+	DriverS3    = "s3"
+)
+
+var ErrInvalidKey = errors.New("invalid storage key")
+
+// Storage stores files under slash separated keys such as "workouts/12/photo.jpg".
+type Storage interface {
+	Put(ctx context.Context, key string, data []byte, contentType string) error
+	// Delete removes the file, succeeding if it does not exist
+	Delete(ctx context.Context, key string) error
+	SignedURL(key string, expiresIn time.Duration) (string, error)
+}
+
+// New creates the storage selected by STORAGE_DRIVER, the local disk by default.
+func New() (Storage, error) {
+	switch driver := os.Getenv("STORAGE_DRIVER"); driver {
+	case "", DriverLocal:
+		root := os.Getenv("STORAGE_LOCAL_PATH")
+		if root == "" {
+			root = "./storage"
+		}
+
+		secret := os.Getenv("STORAGE_SIGNING_KEY")
+		if secret == "" {
+			secret = os.Getenv("JWT_SECRET")
+		}
+
+		return NewLocal(root, strings.TrimRight(os.Getenv("APP_URL"), "/")+"/media", secret)
+	case DriverS3:
+		return NewS3(S3Config{
+			Endpoint:        os.Getenv("S3_ENDPOINT"),
+			Region:          os.Getenv("S3_REGION"),
+			Bucket:          os.Getenv("S3_BUCKET"),
+			AccessKeyID:     os.Getenv("S3_ACCESS_KEY_ID"),
+			SecretAccessKey: os.Getenv("S3_SECRET_ACCESS_KEY"),
+			UsePathStyle:    os.Getenv("S3_USE_PATH_STYLE") == "true",
+		})
+	default:
+		return nil, fmt.Errorf("unknown storage driver %q", driver)
+	}
+}
+
+// validateKey rejects keys that could escape the storage root or be read differently by a backend.
+func validateKey(key string) error {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "\\") {
+		return ErrInvalidKey
+	}
+
+	for _, segment := range strings.Split(key, "/") {
+		if segment == "" || segment == "." || segment == ".." {
+			return ErrInvalidKey
+		}
+	}
+
+	return nil
+}
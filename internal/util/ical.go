@@ -0,0 +1,91 @@
+package util
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+const (
+	icalDateFormat = "20060102T150405Z"
+	icalDayFormat  = "20060102"
+	// icalLineLength is the longest a content line may be before it has to be folded, in octets
+	icalLineLength = 75
+)
+
+var icalTextEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+
+type ICalEvent struct {
+	UID   string
+	Start time.Time
+	// End is exclusive, for all day events the day after the last day
+	End time.Time
+	// AllDay events take the dates of Start and End, shown on that day in every timezone
+	AllDay       bool
+	Summary      string
+	Description  string
+	LastModified time.Time
+}
+
+// WriteICalHeader starts a calendar named name. Finish it with WriteICalFooter.
+func WriteICalHeader(w io.Writer, name string) error {
+	return writeICalLines(w,
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//Ronin Fitness//Workouts//EN",
+		"CALSCALE:GREGORIAN",
+		"METHOD:PUBLISH",
+		"X-WR-CALNAME:"+icalTextEscaper.Replace(name),
+	)
+}
+
+func WriteICalEvent(w io.Writer, event ICalEvent) error {
+	start := "DTSTART:" + event.Start.UTC().Format(icalDateFormat)
+	end := "DTEND:" + event.End.UTC().Format(icalDateFormat)
+	if event.AllDay {
+		start = "DTSTART;VALUE=DATE:" + event.Start.Format(icalDayFormat)
+		end = "DTEND;VALUE=DATE:" + event.End.Format(icalDayFormat)
+	}
+
+	return writeICalLines(w,
+		"BEGIN:VEVENT",
+		"UID:"+event.UID,
+		"DTSTAMP:"+event.LastModified.UTC().Format(icalDateFormat),
+		"LAST-MODIFIED:"+event.LastModified.UTC().Format(icalDateFormat),
+		start,
+		end,
+		"SUMMARY:"+icalTextEscaper.Replace(event.Summary),
+		"DESCRIPTION:"+icalTextEscaper.Replace(event.Description),
+		"END:VEVENT",
+	)
+}
+
+func WriteICalFooter(w io.Writer) error {
+	return writeICalLines(w, "END:VCALENDAR")
+}
+
+// writeICalLines writes content lines with CRLF endings, folding them at 75 octets without
+// splitting UTF-8 characters as RFC 5545 requires.
+func writeICalLines(w io.Writer, lines ...string) error {
+	for _, line := range lines {
+		folded := strings.Builder{}
+		length := 0
+		for _, char := range line {
+			size := len(string(char))
+			if length+size > icalLineLength {
+				folded.WriteString("\r\n ")
+				length = 1
+			}
+			folded.WriteRune(char)
+			length += size
+		}
+
+		_, err := fmt.Fprint(w, folded.String(), "\r\n")
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
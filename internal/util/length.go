@@ -0,0 +1,40 @@
+package util
+
+import "math"
+
+const (
+	LengthUnitCm = "cm"
+	LengthUnitIn = "in"
+
+	// cmPerIn is the exact definition of the international inch
+	cmPerIn = 2.54
+)
+
+// DefaultLengthUnit pairs inches with pounds and centimetres with kilograms, as there is no
+// separate length preference on a profile.
+func DefaultLengthUnit(weightUnit string) string {
+	if weightUnit == WeightUnitLb {
+		return LengthUnitIn
+	}
+
+	return LengthUnitCm
+}
+
+// ToCm converts a length in the given unit to the canonical centimetre value, keeping four decimals
+// like ToKg.
+func ToCm(length float64, unit string) float64 {
+	if unit == LengthUnitIn {
+		length *= cmPerIn
+	}
+
+	return math.Round(length*10000) / 10000
+}
+
+// FromCm converts a canonical centimetre value to the given unit, rounded to 2 decimals.
+func FromCm(lengthCm float64, unit string) float64 {
+	if unit == LengthUnitIn {
+		lengthCm /= cmPerIn
+	}
+
+	return math.Round(lengthCm*100) / 100
+}
@@ -0,0 +1,18 @@
+package util
+
+import (
+	"errors"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+// mysqlErrDuplicateEntry is the MySQL error number of a row breaking a unique index
+const mysqlErrDuplicateEntry = 1062
+
+// IsDuplicateKeyError reports whether MySQL rejected a write because it broke a unique index, e.g.
+// when a concurrent request claimed the same value between a check and the write.
+func IsDuplicateKeyError(err error) bool {
+	var mysqlErr *mysql.MySQLError
+
+	return errors.As(err, &mysqlErr) && mysqlErr.Number == mysqlErrDuplicateEntry
+}
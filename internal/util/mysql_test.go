@@ -0,0 +1,33 @@
+package util
+
+import (
+	"database/sql"
+	"fmt"
+	"testing"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+func TestIsDuplicateKeyError(t *testing.T) {
+	duplicate := &mysql.MySQLError{Number: 1062, Message: "Duplicate entry '12' for key 'workout_sessions.open_profile_id'"}
+
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "duplicate entry", err: duplicate, want: true},
+		{name: "wrapped duplicate entry", err: fmt.Errorf("creating session: %w", duplicate), want: true},
+		{name: "other mysql error", err: &mysql.MySQLError{Number: 1452, Message: "Cannot add or update a child row"}},
+		{name: "not found", err: sql.ErrNoRows},
+		{name: "nil", err: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsDuplicateKeyError(tt.err); got != tt.want {
+				t.Errorf("IsDuplicateKeyError() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
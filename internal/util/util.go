@@ -3,6 +3,7 @@ package util
 import (
 	"context"
 	"database/sql"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"log"
@@ -10,9 +11,11 @@ import (
 	"net/http"
 	"os"
 	"reflect"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/go-chi/jwtauth/v5"
 	"github.com/go-playground/validator/v10"
 	"golang.org/x/exp/rand"
 )
@@ -179,3 +182,109 @@ func FormatValidationError(errs validator.ValidationErrors) error {
 	}
 	return fmt.Errorf("%s", sb.String())
 }
+
+// GetAuthUserID returns the ID of the authenticated user from the "sub" claim of the request's JWT.
+func GetAuthUserID(r *http.Request) (int, error) {
+	_, claims, err := jwtauth.FromContext(r.Context())
+	if err != nil {
+		return 0, err
+	}
+
+	sub, ok := claims["sub"].(string)
+	if !ok {
+		return 0, fmt.Errorf("missing sub claim")
+	}
+
+	return strconv.Atoi(sub)
+}
+
+// EncodeCursor builds an opaque pagination cursor from the sort time and ID of the last row on a page.
+func EncodeCursor(t time.Time, id int) string {
+	return EncodeScoreCursor(t.UnixNano(), id)
+}
+
+// DecodeCursor reverses EncodeCursor. An empty cursor returns the zero time and ID.
+func DecodeCursor(cursor string) (time.Time, int, error) {
+	if cursor == "" {
+		return time.Time{}, 0, nil
+	}
+
+	nanos, id, err := DecodeScoreCursor(cursor)
+	if err != nil {
+		return time.Time{}, 0, err
+	}
+
+	return time.Unix(0, nanos).UTC(), id, nil
+}
+
+// EncodeScoreCursor builds an opaque pagination cursor for rows sorted by a number, such as a
+// relevance score, from the score and ID of the last row on a page.
+func EncodeScoreCursor(score int64, id int) string {
+	raw := fmt.Sprintf("%d:%d", score, id)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// DecodeScoreCursor reverses EncodeScoreCursor. An empty cursor returns a zero score and ID.
+func DecodeScoreCursor(cursor string) (int64, int, error) {
+	if cursor == "" {
+		return 0, 0, nil
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid cursor")
+	}
+
+	parts := strings.SplitN(string(raw), ":", 2)
+	if len(parts) != 2 {
+		return 0, 0, fmt.Errorf("invalid cursor")
+	}
+
+	score, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid cursor")
+	}
+
+	id, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid cursor")
+	}
+
+	return score, id, nil
+}
+
+// GetPageLimit reads the "limit" query parameter, falling back to def and capping it at max.
+func GetPageLimit(r *http.Request, def int, max int) int {
+	limit, err := strconv.Atoi(r.URL.Query().Get("limit"))
+	if err != nil || limit <= 0 {
+		return def
+	}
+
+	if limit > max {
+		return max
+	}
+
+	return limit
+}
+
+// IsNotModified reports whether the client's cached copy, named by its If-None-Match or
+// If-Modified-Since header, is still current. If-None-Match wins when both are sent.
+func IsNotModified(r *http.Request, etag string, lastModified time.Time) bool {
+	if ifNoneMatch := r.Header.Get("If-None-Match"); ifNoneMatch != "" {
+		for _, candidate := range strings.Split(ifNoneMatch, ",") {
+			candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+			if candidate == "*" || candidate == etag {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	ifModifiedSince, err := http.ParseTime(r.Header.Get("If-Modified-Since"))
+	if err != nil {
+		return false
+	}
+
+	return !lastModified.Truncate(time.Second).After(ifModifiedSince)
+}
@@ -0,0 +1,53 @@
+package util
+
+import "math"
+
+const (
+	WeightUnitKg = "kg"
+	WeightUnitLb = "lb"
+
+	// kgPerLb is the exact definition of the international pound
+	kgPerLb = 0.45359237
+)
+
+// DefaultWeightIncrement returns the smallest load jump available in a typical gym for the unit.
+func DefaultWeightIncrement(unit string) float64 {
+	if unit == WeightUnitLb {
+		return 5
+	}
+
+	return 2.5
+}
+
+// ToKg converts a weight in the given unit to the canonical kilogram value. Four decimals are kept so
+// that weights entered in pounds convert back without drift.
+func ToKg(weight float64, unit string) float64 {
+	if unit == WeightUnitLb {
+		weight *= kgPerLb
+	}
+
+	return math.Round(weight*10000) / 10000
+}
+
+// FromKg converts a canonical kilogram value to the given unit, rounded to 2 decimals.
+func FromKg(weightKg float64, unit string) float64 {
+	if unit == WeightUnitLb {
+		weightKg /= kgPerLb
+	}
+
+	return math.Round(weightKg*100) / 100
+}
+
+// KgToLb derives the pound value stored alongside every canonical kilogram weight.
+func KgToLb(weightKg float64) float64 {
+	return FromKg(weightKg, WeightUnitLb)
+}
+
+// RoundToIncrement rounds a weight to the nearest multiple of increment, both in the same unit.
+func RoundToIncrement(weight float64, increment float64) float64 {
+	if increment <= 0 {
+		return weight
+	}
+
+	return math.Round(math.Round(weight/increment)*increment*100) / 100
+}